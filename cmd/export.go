@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bookshelf/internal/config/db"
+	"bookshelf/internal/repository"
+	"bookshelf/internal/service"
+	"compress/gzip"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// runExport выгружает каталог в файл: go run ./cmd export -format ndjson -o books.ndjson.gz
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", service.ExportFormatCSV, "формат выгрузки: csv, ndjson, marc или marcxml")
+	genre := fs.String("genre", "", "фильтр по жанру")
+	output := fs.String("o", "", "путь к файлу (по умолчанию stdout), .gz включает сжатие")
+	compress := fs.Bool("gzip", false, "сжать вывод gzip")
+	fs.Parse(args)
+
+	if !service.IsExportFormat(*format) {
+		log.Fatalf("Invalid format %q, must be 'csv', 'ndjson', 'marc' or 'marcxml'", *format)
+	}
+
+	// Выгрузка только читает, поэтому схему не трогает: её может запускать роль с доступом на чтение
+	database, err := db.Connect()
+	if err != nil {
+		log.Fatalf("Could not connect to db: %s", err.Error())
+	}
+
+	var out io.Writer = os.Stdout
+	// closers закрываются в обратном порядке: сначала gzip дописывает хвост, потом закрывается файл
+	var closers []io.Closer
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Could not create output file: %s", err.Error())
+		}
+		closers = append(closers, file)
+		out = file
+	}
+
+	if *compress || strings.HasSuffix(*output, ".gz") {
+		gz := gzip.NewWriter(out)
+		closers = append(closers, gz)
+		out = gz
+	}
+
+	exportService := service.NewExportService(repository.NewBookRepository(database))
+	count, err := exportService.ExportBooks(out, *format, *genre)
+	if err != nil {
+		log.Fatalf("Export failed after %d books: %s", count, err.Error())
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			log.Fatalf("Export failed: could not finish output: %s", err.Error())
+		}
+	}
+	log.Printf("Exported %d books", count)
+}
@@ -19,10 +19,18 @@ import (
 	"bookshelf/internal/repository"
 	"bookshelf/internal/service"
 	"bookshelf/pkg/cache"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/notify"
+	"bookshelf/pkg/payment"
+	"bookshelf/pkg/scheduler"
+	"bookshelf/pkg/storage"
 	"bookshelf/pkg/utils"
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -36,6 +44,12 @@ func main() {
 		log.Fatalf("Error loading .env file: %s", err.Error())
 	}
 
+	// Подкоманды CLI
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
 	database, err := db.InitDB()
 	if err != nil {
 		log.Fatalf("Could not connect to db: %s", err.Error())
@@ -54,13 +68,210 @@ func main() {
 	authHandler := handlers.NewAuthHandler(authService)
 
 	bookRepo := repository.NewBookRepository(database)
-	bookService := service.NewBookService(bookRepo, redisCache)
-	bookHandler := handlers.NewBookHandler(bookService)
-
 	favRepo := repository.NewFavouriteRepository(database)
-	favService := service.NewFavouriteService(favRepo)
+
+	followRepo := repository.NewFollowRepository(database)
+	followService := service.NewFollowService(followRepo, authRepo)
+	followHandler := handlers.NewFollowHandler(followService)
+
+	// Уведомления всегда сохраняются в приложении, внешние каналы включаются переменными окружения
+	var channels []notify.Channel
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		channels = append(channels, notify.NewSMTPChannel(notify.SMTPConfig{
+			Host:     host,
+			Port:     envOr("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     envOr("SMTP_FROM", "bookshelf@localhost"),
+		}))
+	}
+	if secret := os.Getenv("NOTIFY_WEBHOOK_SECRET"); secret != "" {
+		channels = append(channels, notify.NewWebhookChannel(secret))
+	}
+	notificationRepo := repository.NewNotificationRepository(database)
+	notificationService := service.NewNotificationService(notificationRepo, favRepo, followRepo, channels...)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	// Журнал активности: события раскладываются по лентам подписчиков
+	activityRepo := repository.NewActivityRepository(database)
+	activityService := service.NewActivityService(activityRepo, bookRepo, authRepo)
+	activityHandler := handlers.NewActivityHandler(activityService)
+
+	similarService := service.NewSimilarService(bookRepo, redisCache)
+	similarHandler := handlers.NewSimilarHandler(similarService)
+	popularityRepo := repository.NewPopularityRepository(database)
+	popularityService := service.NewPopularityService(popularityRepo, bookRepo, redisCache)
+	popularityHandler := handlers.NewPopularityHandler(popularityService)
+	// Цены книг хранятся в их собственной валюте, курсы задаются к базовой валюте каталога
+	baseCurrency := db.BaseCurrency()
+	priceRepo := repository.NewPriceRepository(database)
+	pricingService := service.NewPricingService(priceRepo, bookRepo, redisCache, baseCurrency)
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	// Распродажи видны в каталоге, купоны применяются только при оформлении заказа
+	discountRepo := repository.NewDiscountRepository(database)
+	discountService := service.NewDiscountService(discountRepo, bookRepo, redisCache, baseCurrency)
+	discountHandler := handlers.NewDiscountHandler(discountService)
+	bookService := service.NewBookService(bookRepo, redisCache, discountService, baseCurrency, similarService, notificationService, activityService)
+	loanLimits, err := service.ParseLoanLimits(envOr("LOAN_LIMITS", "user=3,moderator=5,admin=10"))
+	if err != nil {
+		log.Fatalf("Invalid LOAN_LIMITS: %s", err.Error())
+	}
+	maxRenewals, err := strconv.Atoi(envOr("LOAN_MAX_RENEWALS", "2"))
+	if err != nil || maxRenewals < 0 {
+		log.Fatalf("Invalid LOAN_MAX_RENEWALS: %q", os.Getenv("LOAN_MAX_RENEWALS"))
+	}
+	// Штрафы ведутся в базовой валюте, суммы в настройках - десятичные
+	fineRates, err := service.ParseFineRates(envOr("FINE_RATES", "user=0.50"), baseCurrency)
+	if err != nil {
+		log.Fatalf("Invalid FINE_RATES: %s", err.Error())
+	}
+	fineMax, err := money.ParseIn(envOr("FINE_MAX_PER_LOAN", "50.00"), baseCurrency)
+	if err != nil || fineMax.Amount < 0 {
+		log.Fatalf("Invalid FINE_MAX_PER_LOAN: %q", os.Getenv("FINE_MAX_PER_LOAN"))
+	}
+	fineThreshold, err := money.ParseIn(envOr("FINE_BLOCK_THRESHOLD", "10.00"), baseCurrency)
+	if err != nil || fineThreshold.Amount < 0 {
+		log.Fatalf("Invalid FINE_BLOCK_THRESHOLD: %q", os.Getenv("FINE_BLOCK_THRESHOLD"))
+	}
+	fineRepo := repository.NewFineRepository(database)
+	fineService := service.NewFineService(fineRepo, notificationService, service.FinePolicy{
+		Currency:     baseCurrency,
+		Rates:        fineRates,
+		MaxPerLoan:   fineMax.Amount,
+		RemindBefore: envDuration("LOAN_REMIND_BEFORE", 48*time.Hour),
+		RemindEvery:  envDuration("OVERDUE_REMIND_EVERY", 7*24*time.Hour),
+	})
+	fineHandler := handlers.NewFineHandler(fineService)
+
+	lendingConfig := service.LendingConfig{
+		LoanPeriod:         envDuration("LOAN_PERIOD", 14*24*time.Hour),
+		MaxRenewals:        maxRenewals,
+		Limits:             loanLimits,
+		PickupWindow:       envDuration("HOLD_PICKUP_WINDOW", 72*time.Hour),
+		FineBlockThreshold: fineThreshold.Amount,
+		FineCurrency:       baseCurrency,
+	}
+	lendingRepo := repository.NewLendingRepository(database)
+	lendingService := service.NewLendingService(lendingRepo, bookRepo, fineRepo, lendingConfig)
+	lendingHandler := handlers.NewLendingHandler(lendingService)
+	holdRepo := repository.NewHoldRepository(database)
+	holdService := service.NewHoldService(holdRepo, lendingRepo, bookRepo, lendingConfig)
+	holdHandler := handlers.NewHoldHandler(holdService)
+
+	cartRepo := repository.NewCartRepository(database)
+	cartService := service.NewCartService(cartRepo, bookRepo, pricingService, discountService)
+	cartHandler := handlers.NewCartHandler(cartService)
+	orderRepo := repository.NewOrderRepository(database)
+	orderService := service.NewOrderService(orderRepo, cartRepo, pricingService, discountService, envDuration("STOCK_RESERVATION_TTL", 30*time.Minute))
+	orderHandler := handlers.NewOrderHandler(orderService)
+	lowStock, err := strconv.Atoi(envOr("LOW_STOCK_THRESHOLD", "3"))
+	if err != nil || lowStock < 0 {
+		log.Fatalf("Invalid LOW_STOCK_THRESHOLD: %q", os.Getenv("LOW_STOCK_THRESHOLD"))
+	}
+	stockRepo := repository.NewStockRepository(database)
+	stockService := service.NewStockService(stockRepo, orderRepo, bookRepo, notificationService, lowStock)
+	stockHandler := handlers.NewStockHandler(stockService)
+
+	// Оплата: PAYMENT_PROVIDER=stripe для боевого шлюза, fake - провайдер в памяти для разработки.
+	// Значения по умолчанию нет, чтобы production не запустился с фейковыми платежами по ошибке
+	var paymentProvider payment.Provider
+	var fakePayments *payment.FakeProvider
+	switch os.Getenv("PAYMENT_PROVIDER") {
+	case "stripe":
+		if os.Getenv("STRIPE_SECRET_KEY") == "" || os.Getenv("STRIPE_WEBHOOK_SECRET") == "" {
+			log.Fatal("PAYMENT_PROVIDER=stripe requires STRIPE_SECRET_KEY and STRIPE_WEBHOOK_SECRET")
+		}
+		paymentProvider = payment.NewStripeProvider(os.Getenv("STRIPE_SECRET_KEY"), os.Getenv("STRIPE_WEBHOOK_SECRET"))
+	case "fake":
+		fakePayments = payment.NewFakeProvider(os.Getenv("FAKE_PAYMENT_SECRET"))
+		paymentProvider = fakePayments
+	case "":
+		log.Fatal("PAYMENT_PROVIDER is required: stripe, or fake for development")
+	default:
+		log.Fatalf("Invalid PAYMENT_PROVIDER: %q", os.Getenv("PAYMENT_PROVIDER"))
+	}
+	paymentRepo := repository.NewPaymentRepository(database)
+	paymentService := service.NewPaymentService(paymentRepo, orderRepo, paymentProvider)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, fakePayments)
+	if fakePayments != nil {
+		// Фейковый провайдер доставляет вебхуки напрямую в сервис, минуя HTTP
+		fakePayments.SetWebhook(func(payload []byte, header http.Header) {
+			if err := paymentService.HandleWebhook(context.Background(), payload, header); err != nil {
+				log.Printf("payments: fake webhook: %v", err)
+			}
+		})
+	}
+
+	// Файлы книг лежат в STORAGE_DIR, скачиваются по подписанным ссылкам
+	fileStorage, err := storage.NewLocal(envOr("STORAGE_DIR", "./data/files"))
+	if err != nil {
+		log.Fatalf("Failed to open file storage: %s", err.Error())
+	}
+	fileSigner := storage.NewSigner(envOr("FILE_URL_SECRET", os.Getenv("JWT_SECRET")))
+	fileRepo := repository.NewFileRepository(database)
+	fileService := service.NewFileService(fileRepo, bookService, fileStorage, fileSigner, envDuration("FILE_URL_TTL", 5*time.Minute))
+	fileHandler := handlers.NewFileHandler(fileService)
+	// Позиции чтения синхронизируются между устройствами, в том числе через протокол KOReader
+	progressRepo := repository.NewProgressRepository(database)
+	progressService := service.NewProgressService(progressRepo, fileRepo, bookRepo, authRepo)
+	progressHandler := handlers.NewProgressHandler(progressService)
+	koSyncHandler := handlers.NewKOSyncHandler(progressService)
+
+	bookHandler := handlers.NewBookHandler(bookService, popularityService, lendingService, pricingService)
+
+	favService := service.NewFavouriteService(favRepo, popularityService, activityService)
 	favHandler := handlers.NewFavouriteHandler(favService)
 
+	importRepo := repository.NewImportRepository(database)
+	importService := service.NewImportService(importRepo, bookRepo, redisCache, baseCurrency, similarService, notificationService, activityService)
+	importHandler := handlers.NewImportHandler(importService)
+
+	exportService := service.NewExportService(bookRepo)
+	exportHandler := handlers.NewExportHandler(exportService)
+
+	reviewRepo := repository.NewReviewRepository(database)
+	reviewService := service.NewReviewService(reviewRepo, bookRepo, redisCache, activityService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+
+	readingRepo := repository.NewReadingRepository(database)
+	readingService := service.NewReadingService(readingRepo, bookRepo)
+	readingHandler := handlers.NewReadingHandler(readingService)
+
+	// Импорт библиотеки переносит полки и оценки, поэтому собирается после чтения и рецензий
+	libraryImportRepo := repository.NewLibraryImportRepository(database)
+	libraryImportService := service.NewLibraryImportService(libraryImportRepo, bookRepo, favService, readingService, reviewService)
+	libraryImportHandler := handlers.NewLibraryImportHandler(libraryImportService)
+
+	goalRepo := repository.NewGoalRepository(database)
+	goalService := service.NewGoalService(goalRepo, readingRepo, bookRepo)
+	goalHandler := handlers.NewGoalHandler(goalService)
+
+	collectionRepo := repository.NewCollectionRepository(database)
+	collectionService := service.NewCollectionService(collectionRepo, bookRepo, authRepo, activityService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+
+	noteRepo := repository.NewNoteRepository(database)
+	noteService := service.NewNoteService(noteRepo, bookRepo)
+	noteHandler := handlers.NewNoteHandler(noteService)
+
+	recommendationRepo := repository.NewRecommendationRepository(database)
+	recommendationService := service.NewRecommendationService(recommendationRepo, favRepo, bookRepo, redisCache)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+
+	opdsHandler := handlers.NewOPDSHandler(bookService, favService, authService, fileService)
+
+	// Фоновые задачи
+	jobs := scheduler.New()
+	jobs.Every("recommendations", envDuration("RECOMMENDATIONS_INTERVAL", time.Hour), recommendationService.RebuildSimilarities)
+	jobs.Every("similar-index", envDuration("SIMILAR_INDEX_INTERVAL", 6*time.Hour), similarService.RebuildIndex)
+	jobs.Every("popularity-flush", envDuration("STATS_FLUSH_INTERVAL", time.Minute), popularityService.Flush)
+	jobs.Every("holds", envDuration("HOLD_CHECK_INTERVAL", 5*time.Minute), holdService.ProcessHolds)
+	jobs.Every("overdue", envDuration("OVERDUE_CHECK_INTERVAL", 24*time.Hour), fineService.ProcessOverdue)
+	jobs.Every("stock", envDuration("STOCK_CHECK_INTERVAL", time.Minute), stockService.ProcessStock)
+	jobs.Every("stale-imports", envDuration("IMPORT_CHECK_INTERVAL", time.Minute), importService.FailStaleJobs)
+	jobs.Start(context.Background())
+	defer jobs.Stop()
+
 	r := chi.NewRouter()
 	r.Use(chimiddleware.Logger)
 	r.Use(chimiddleware.Recoverer)
@@ -74,6 +285,47 @@ func main() {
 		r.Get("/books/{id}", bookHandler.GetBookByIDHandler)
 
 		r.Get("/books/genres", bookHandler.GetAllGenresHandler)
+		r.Get("/books/trending", popularityHandler.GetTrendingHandler)
+		r.Get("/books/popular", popularityHandler.GetPopularHandler)
+		r.Get("/books/{id}/similar", similarHandler.GetSimilarBooksHandler)
+		r.Get("/books/{id}/reviews", reviewHandler.GetBookReviewsHandler)
+		r.Get("/books/{id}/notes", noteHandler.GetBookNotesHandler)
+		r.Get("/books/{id}/copies", lendingHandler.GetCopiesHandler)
+		r.Get("/books/{id}/prices", pricingHandler.GetBookPricesHandler)
+		r.Get("/books/{id}/files", fileHandler.GetFilesHandler)
+		r.Get("/books/{id}/cover", fileHandler.GetCoverHandler)
+		r.Get("/files/{id}", fileHandler.DownloadFileHandler)
+
+		r.Get("/exchange-rates", pricingHandler.GetExchangeRatesHandler)
+
+		r.Post("/payments/webhook", paymentHandler.PaymentWebhookHandler)
+
+		// Сервер синхронизации KOReader: авторизация заголовками x-auth-user и x-auth-key
+		r.Post("/kosync/users/create", koSyncHandler.KOSyncCreateUserHandler)
+		r.Get("/kosync/users/auth", koSyncHandler.KOSyncAuthHandler)
+		r.Put("/kosync/syncs/progress", koSyncHandler.KOSyncUpdateProgressHandler)
+		r.Get("/kosync/syncs/progress/{document}", koSyncHandler.KOSyncGetProgressHandler)
+	})
+
+	// Публичные роуты, которым пользователь нужен, только если он передал токен
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.OptionalJWTAuthMiddleware)
+
+		r.Get("/collections", collectionHandler.ListPublicCollectionsHandler)
+		r.Get("/collections/{id}", collectionHandler.GetCollectionHandler)
+		r.Get("/collections/shared/{token}", collectionHandler.GetSharedCollectionHandler)
+
+		// Каталог OPDS: избранное и скачивание требуют Bearer или Basic
+		r.Get("/opds/opensearch.xml", opdsHandler.OpenSearchHandler)
+		r.Get("/opds/books/{id}/download/{format}", opdsHandler.DownloadHandler)
+		for _, prefix := range []string{"/opds", "/opds/v2"} {
+			r.Get(prefix, opdsHandler.RootHandler)
+			r.Get(prefix+"/new", opdsHandler.NewestHandler)
+			r.Get(prefix+"/genres", opdsHandler.GenresHandler)
+			r.Get(prefix+"/genres/{genre}", opdsHandler.GenreBooksHandler)
+			r.Get(prefix+"/favourites", opdsHandler.FavouritesHandler)
+			r.Get(prefix+"/search", opdsHandler.SearchHandler)
+		}
 	})
 
 	// Защищенные роуты (для всех авторизованных)
@@ -86,6 +338,101 @@ func main() {
 		r.Get("/favourites", favHandler.GetFavourites)
 		r.Post("/favourites/{bookID}", favHandler.AddFavouriteHandler)
 		r.Delete("/favourites/{bookID}", favHandler.RemoveFavourite)
+		r.Get("/users/me/recommendations", recommendationHandler.GetRecommendationsHandler)
+
+		r.Get("/users/me/follows", followHandler.GetFollowsHandler)
+		r.Post("/users/me/follows", followHandler.CreateFollowHandler)
+		r.Delete("/users/me/follows/{id}", followHandler.UnfollowHandler)
+
+		r.Get("/users/me/notifications", notificationHandler.GetNotificationsHandler)
+		r.Post("/users/me/notifications/read-all", notificationHandler.MarkAllNotificationsReadHandler)
+		r.Post("/users/me/notifications/{id}/read", notificationHandler.MarkNotificationReadHandler)
+		r.Get("/users/me/notification-settings", notificationHandler.GetNotificationSettingsHandler)
+		r.Put("/users/me/notification-settings", notificationHandler.UpdateNotificationSettingsHandler)
+		r.Get("/users/me/feed", activityHandler.GetFeedHandler)
+		r.Get("/users/me/privacy", activityHandler.GetPrivacyHandler)
+		r.Put("/users/me/privacy", activityHandler.UpdatePrivacyHandler)
+
+		r.Post("/users/me/imports/goodreads", libraryImportHandler.ImportLibraryHandler)
+		r.Get("/users/me/imports/{id}", libraryImportHandler.GetLibraryImportHandler)
+		r.Post("/users/me/imports/{id}/rows/{row}/resolve", libraryImportHandler.ResolveLibraryRowHandler)
+
+		r.Get("/books/{id}/reviews/me", reviewHandler.GetMyReviewHandler)
+		r.Put("/books/{id}/reviews/me", reviewHandler.UpsertReviewHandler)
+		r.Delete("/books/{id}/reviews/me", reviewHandler.DeleteReviewHandler)
+		r.Post("/reviews/{id}/helpful", reviewHandler.VoteHelpfulHandler)
+		r.Delete("/reviews/{id}/helpful", reviewHandler.UnvoteHelpfulHandler)
+
+		r.Get("/users/me/shelves/{shelf}", readingHandler.GetShelfHandler)
+		r.Get("/users/me/reading/{bookID}", readingHandler.GetReadingStatusHandler)
+		r.Put("/users/me/reading/{bookID}", readingHandler.SetReadingStatusHandler)
+		r.Patch("/users/me/reading/{bookID}/progress", readingHandler.UpdateProgressHandler)
+		r.Delete("/users/me/reading/{bookID}", readingHandler.RemoveReadingStatusHandler)
+		r.Get("/users/me/reading-stats", readingHandler.GetReadingStatsHandler)
+		r.Get("/users/me/progress/{bookID}", progressHandler.GetProgressHandler)
+		r.Put("/users/me/progress/{bookID}", progressHandler.SaveProgressHandler)
+		r.Get("/users/me/progress/{bookID}/history", progressHandler.GetProgressHistoryHandler)
+		r.Get("/users/me/goals", goalHandler.GetGoalsHandler)
+		r.Post("/users/me/goals", goalHandler.CreateGoalHandler)
+		r.Get("/users/me/goals/{id}", goalHandler.GetGoalHandler)
+		r.Put("/users/me/goals/{id}", goalHandler.UpdateGoalHandler)
+		r.Delete("/users/me/goals/{id}", goalHandler.DeleteGoalHandler)
+		r.Get("/users/me/year-in-review", goalHandler.GetYearInReviewHandler)
+
+		r.Get("/users/me/collections", collectionHandler.ListMyCollectionsHandler)
+		r.Post("/collections", collectionHandler.CreateCollectionHandler)
+		r.Put("/collections/{id}", collectionHandler.UpdateCollectionHandler)
+		r.Delete("/collections/{id}", collectionHandler.DeleteCollectionHandler)
+		r.Post("/collections/{id}/share-token", collectionHandler.RotateShareTokenHandler)
+		r.Post("/collections/{id}/books", collectionHandler.AddCollectionBookHandler)
+		r.Put("/collections/{id}/books/order", collectionHandler.ReorderCollectionHandler)
+		r.Delete("/collections/{id}/books/{bookID}", collectionHandler.RemoveCollectionBookHandler)
+		r.Post("/collections/{id}/collaborators", collectionHandler.AddCollaboratorHandler)
+		r.Delete("/collections/{id}/collaborators/{userID}", collectionHandler.RemoveCollaboratorHandler)
+
+		r.Post("/books/{id}/notes", noteHandler.CreateNoteHandler)
+		r.Get("/users/me/notes", noteHandler.GetMyNotesHandler)
+		r.Get("/users/me/notes/export/{bookID}", noteHandler.ExportNotesHandler)
+		r.Put("/notes/{id}", noteHandler.UpdateNoteHandler)
+		r.Delete("/notes/{id}", noteHandler.DeleteNoteHandler)
+
+		r.Post("/loans", lendingHandler.CheckoutHandler)
+		r.Get("/users/me/loans", lendingHandler.GetMyLoansHandler)
+		r.Post("/loans/{id}/return", lendingHandler.ReturnHandler)
+		r.Post("/loans/{id}/renew", lendingHandler.RenewHandler)
+
+		r.Post("/books/{id}/holds", holdHandler.PlaceHoldHandler)
+		r.Get("/users/me/holds", holdHandler.GetMyHoldsHandler)
+		r.Delete("/holds/{id}", holdHandler.CancelHoldHandler)
+		r.Get("/users/me/fines", fineHandler.GetMyFinesHandler)
+
+		r.Get("/users/me/cart", cartHandler.GetCartHandler)
+		r.Delete("/users/me/cart", cartHandler.ClearCartHandler)
+		r.Put("/users/me/cart/{bookID}", cartHandler.SetCartItemHandler)
+		r.Delete("/users/me/cart/{bookID}", cartHandler.RemoveCartItemHandler)
+		r.Post("/orders", orderHandler.CheckoutOrderHandler)
+		r.Get("/users/me/orders", orderHandler.GetMyOrdersHandler)
+		r.Get("/orders/{id}", orderHandler.GetOrderHandler)
+		r.Post("/orders/{id}/cancel", orderHandler.CancelOrderHandler)
+		r.Post("/orders/{id}/pay", paymentHandler.PayOrderHandler)
+		r.Post("/books/{id}/files/{format}/link", fileHandler.CreateDownloadLinkHandler)
+		if fakePayments != nil {
+			r.Post("/payments/fake/confirm", paymentHandler.FakeConfirmHandler)
+		}
+	})
+
+	// Роуты модераторов (модераторы и админы)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTAuthMiddleware, middleware.ModeratorMiddleware)
+
+		r.Get("/moderation/reviews", reviewHandler.GetModerationQueueHandler)
+		r.Put("/moderation/reviews/{id}", reviewHandler.ModerateReviewHandler)
+
+		// Модераторы работают библиотекарями: ведут экземпляры и видят все выдачи
+		r.Post("/books/{id}/copies", lendingHandler.AddCopyHandler)
+		r.Put("/copies/{id}", lendingHandler.UpdateCopyHandler)
+		r.Delete("/copies/{id}", lendingHandler.DeleteCopyHandler)
+		r.Get("/loans", lendingHandler.GetLoansHandler)
 	})
 
 	// Админские роуты (только для админов)
@@ -99,6 +446,37 @@ func main() {
 		r.Post("/books", bookHandler.CreateBookHandler)
 		r.Put("/books/{id}", bookHandler.UpdateBookHandler)
 		r.Delete("/books/{id}", bookHandler.DeleteBookHandler)
+		r.Put("/books/{id}/prices/{currency}", pricingHandler.SetBookPriceHandler)
+		r.Delete("/books/{id}/prices/{currency}", pricingHandler.DeleteBookPriceHandler)
+		r.Post("/books/{id}/files", fileHandler.UploadFileHandler)
+		r.Delete("/books/{id}/files/{format}", fileHandler.DeleteFileHandler)
+
+		r.Put("/admin/exchange-rates", pricingHandler.UploadExchangeRatesHandler)
+
+		r.Get("/admin/discounts", discountHandler.GetDiscountsHandler)
+		r.Post("/admin/discounts", discountHandler.CreateDiscountHandler)
+		r.Get("/admin/discounts/{id}", discountHandler.GetDiscountHandler)
+		r.Put("/admin/discounts/{id}", discountHandler.UpdateDiscountHandler)
+		r.Delete("/admin/discounts/{id}", discountHandler.DeleteDiscountHandler)
+
+		r.Post("/admin/imports", importHandler.StartImportHandler)
+		r.Get("/admin/imports/{id}", importHandler.GetImportJobHandler)
+
+		r.Get("/admin/exports/books", exportHandler.ExportBooksHandler)
+
+		r.Get("/admin/fines", fineHandler.GetBalancesHandler)
+		r.Get("/admin/fines/{userID}", fineHandler.GetUserFinesHandler)
+		r.Post("/admin/fines/{userID}/entries", fineHandler.AddFineEntryHandler)
+
+		r.Get("/admin/orders", orderHandler.GetOrdersHandler)
+		r.Put("/admin/orders/{id}/status", orderHandler.UpdateOrderStatusHandler)
+		r.Post("/admin/orders/{id}/refund", paymentHandler.RefundOrderHandler)
+
+		r.Get("/admin/stock", stockHandler.GetStockListHandler)
+		r.Get("/admin/stock/{bookID}", stockHandler.GetStockHandler)
+		r.Put("/admin/stock/{bookID}", stockHandler.UpdateStockSettingsHandler)
+		r.Get("/admin/stock/{bookID}/movements", stockHandler.GetStockMovementsHandler)
+		r.Post("/admin/stock/{bookID}/movements", stockHandler.AddStockMovementHandler)
 	})
 
 	// Swagger документация
@@ -120,3 +498,19 @@ func main() {
 		log.Fatalf("Could not start listening: %s", err.Error())
 	}
 }
+
+// envDuration читает интервал вида "30m"; пустое или некорректное значение заменяется на def
+func envDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
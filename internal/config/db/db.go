@@ -3,6 +3,8 @@ package db
 
 import (
 	"bookshelf/internal/models"
+	"bookshelf/pkg/money"
+	"fmt"
 	"log"
 	"os"
 
@@ -15,16 +17,146 @@ var (
 	err error
 )
 
+// InitDB подключается к БД и приводит схему к моделям. Для чтения без миграций - Connect
 func InitDB() (*gorm.DB, error) {
-	dsn := os.Getenv("DSN")
-
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err = Connect()
 	if err != nil {
 		log.Fatalf("Could not connect to bd: %s", err.Error())
 	}
-	if err = db.AutoMigrate(&models.Book{}, &models.User{}); err != nil {
+	migrate(db)
+	return db, nil
+}
+
+// Connect открывает соединение по DSN без миграций: так работают команды, которые только читают,
+// например выгрузка каталога из реплики
+func Connect() (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(os.Getenv("DSN")), &gorm.Config{})
+}
+
+func migrate(db *gorm.DB) {
+	if err := migrateMoney(db, BaseCurrency()); err != nil {
+		log.Fatalf("Could not migrate prices: %s", err.Error())
+	}
+	if err := migrateCopyBarcode(db); err != nil {
+		log.Fatalf("Could not migrate copy barcodes: %s", err.Error())
+	}
+	if err := db.AutoMigrate(
+		&models.Book{}, &models.User{},
+		&models.ImportJob{}, &models.LibraryImport{},
+		&models.Review{}, &models.ReviewVote{},
+		&models.ReadingStatus{}, &models.ReadThrough{}, &models.ReadingGoal{},
+		&models.Collection{}, &models.CollectionItem{}, &models.CollectionCollaborator{},
+		&models.Note{},
+		&models.BookSimilarity{}, &models.BookStatsDaily{},
+		&models.Follow{}, &models.Notification{}, &models.NotificationSettings{},
+		&models.Copy{}, &models.Loan{}, &models.Hold{}, &models.FineEntry{},
+		&models.ActivityEvent{}, &models.FeedItem{}, &models.PrivacySettings{},
+		&models.CartItem{}, &models.Order{}, &models.OrderItem{}, &models.Payment{}, &models.PaymentEvent{},
+		&models.BookPrice{}, &models.ExchangeRate{}, &models.Discount{},
+		&models.StockLevel{}, &models.StockReservation{}, &models.StockMovement{}, &models.BookFile{},
+		&models.ReadingProgress{}, &models.ReadingProgressEntry{},
+	); err != nil {
 		log.Fatalf("Could not migrate: %s", err.Error())
 	}
 
-	return db, nil
+	// AutoMigrate не умеет индексы по выражению
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_notes_search ON notes USING GIN (" + models.NoteSearchVector + ")").Error; err != nil {
+		log.Fatalf("Could not create search index: %s", err.Error())
+	}
+
+	if err := migrateFavourites(db); err != nil {
+		log.Fatalf("Could not migrate favourites: %s", err.Error())
+	}
+}
+
+// BaseCurrency - валюта каталога из BASE_CURRENCY, по умолчанию USD
+func BaseCurrency() string {
+	code, err := money.NormalizeCurrency(os.Getenv("BASE_CURRENCY"))
+	if err != nil {
+		if os.Getenv("BASE_CURRENCY") != "" {
+			log.Fatalf("Invalid BASE_CURRENCY: %q", os.Getenv("BASE_CURRENCY"))
+		}
+		return "USD"
+	}
+	return code
+}
+
+// migrateFavourites переносит избранное из favourite_books, куда оно раньше писалось, хотя модель
+// объявляет user_favourites. Старая таблица переименовывается в той же транзакции, поэтому перенос
+// выполняется один раз и удалённое после него избранное не возвращается при следующем запуске
+func migrateFavourites(db *gorm.DB) error {
+	if !db.Migrator().HasTable("favourite_books") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO user_favourites (user_id, book_id)
+			SELECT user_id, book_id FROM favourite_books
+			ON CONFLICT DO NOTHING
+		`).Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE favourite_books RENAME TO favourite_books_migrated").Error
+	})
+}
+
+// migrateCopyBarcode удаляет прежний уникальный индекс штрихкода, который учитывал и мягко удалённые
+// экземпляры. Новый индекс с тем же именем, но только по неудалённым строкам, создаст AutoMigrate:
+// сам он существующий индекс не пересоздаёт
+func migrateCopyBarcode(db *gorm.DB) error {
+	var stale int64
+	if err := db.Raw(`SELECT COUNT(*) FROM pg_indexes
+		WHERE schemaname = CURRENT_SCHEMA() AND indexname = 'idx_copies_barcode' AND indexdef NOT LIKE '% WHERE %'`).
+		Scan(&stale).Error; err != nil {
+		return err
+	}
+	if stale == 0 {
+		return nil
+	}
+	return db.Exec("DROP INDEX idx_copies_barcode").Error
+}
+
+// migrateMoney переводит цены из float в минимальные единицы базовой валюты и добавляет колонку валюты
+// к существующим строкам. Выполняется до AutoMigrate: он сменил бы тип простым приведением и потерял копейки
+func migrateMoney(db *gorm.DB, base string) error {
+	scale := 1
+	for i := 0; i < money.Digits(base); i++ {
+		scale *= 10
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, c := range []struct{ table, column string }{
+			{"books", "price"}, {"notifications", "old_price"}, {"notifications", "new_price"},
+		} {
+			var dataType string
+			if err := tx.Raw(`SELECT data_type FROM information_schema.columns
+				WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?`, c.table, c.column).
+				Scan(&dataType).Error; err != nil {
+				return err
+			}
+			if dataType != "numeric" && dataType != "double precision" {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * %d)",
+				c.table, c.column, c.column, scale)).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"books", "orders", "fine_entries"} {
+			if !tx.Migrator().HasTable(table) || tx.Migrator().HasColumn(table, "currency") {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN currency varchar(3) NOT NULL DEFAULT '%s'", table, base)).Error; err != nil {
+				return err
+			}
+		}
+		if !tx.Migrator().HasTable("notifications") || tx.Migrator().HasColumn("notifications", "currency") {
+			return nil
+		}
+		if err := tx.Exec("ALTER TABLE notifications ADD COLUMN currency varchar(3)").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE notifications SET currency = ? WHERE old_price IS NOT NULL", base).Error
+	})
 }
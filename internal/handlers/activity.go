@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type ActivityHandler struct {
+	activityService service.ActivityService
+}
+
+func NewActivityHandler(activityService service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+func toFeedEventResponse(entry service.FeedEntry) FeedEventResponse {
+	e := entry.Event
+	response := FeedEventResponse{
+		ID:           e.ID,
+		Type:         e.Type,
+		CollectionID: e.CollectionID,
+		ReviewID:     e.ReviewID,
+		Summary:      e.Summary,
+		CreatedAt:    e.CreatedAt,
+	}
+	if e.ActorID != nil {
+		response.Actor = &FeedActorResponse{ID: *e.ActorID, Username: e.ActorName}
+	}
+	if entry.Book != nil {
+		book := toBookBriefResponse(*entry.Book)
+		response.Book = &book
+	}
+	return response
+}
+
+func toPrivacySettingsResponse(s models.PrivacySettings) PrivacySettingsResponse {
+	return PrivacySettingsResponse{
+		ShareFavourites:  s.ShareFavourites,
+		ShareReviews:     s.ShareReviews,
+		ShareCollections: s.ShareCollections,
+	}
+}
+
+// GetFeedHandler godoc
+// @Summary Лента активности
+// @Description Новые книги авторов и жанров из подписок, рецензии, публичные подборки и избранное пользователей, на которых вы подписаны. Новые события первыми
+// @Tags Activity
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Событий на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedFeedResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/feed [get]
+func (h *ActivityHandler) GetFeedHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	entries, total, err := h.activityService.GetFeed(userID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get feed"})
+		return
+	}
+
+	response := PaginatedFeedResponse{
+		Data: make([]FeedEventResponse, 0, len(entries)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, entry := range entries {
+		response.Data = append(response.Data, toFeedEventResponse(entry))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// GetPrivacyHandler godoc
+// @Summary Настройки приватности
+// @Description Какие действия видят подписчики. По умолчанию рецензии и подборки видны, избранное скрыто
+// @Tags Activity
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} PrivacySettingsResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/privacy [get]
+func (h *ActivityHandler) GetPrivacyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	settings, err := h.activityService.GetPrivacy(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPrivacySettingsResponse(settings))
+}
+
+// UpdatePrivacyHandler godoc
+// @Summary Изменение настроек приватности
+// @Description Изменения действуют и на уже разосланные события: скрытые действия пропадают из лент подписчиков
+// @Tags Activity
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.PrivacyRequest true "Настройки"
+// @Success 200 {object} PrivacySettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/privacy [put]
+func (h *ActivityHandler) UpdatePrivacyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.PrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	settings, err := h.activityService.UpdatePrivacy(userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPrivacySettingsResponse(settings))
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockActivityService struct {
+	mock.Mock
+}
+
+func (m *MockActivityService) BookChanged(event service.BookEvent) {
+	m.Called(event)
+}
+
+func (m *MockActivityService) ReviewPublished(review models.Review) {
+	m.Called(review)
+}
+
+func (m *MockActivityService) CollectionUpdated(c models.Collection, actorID, bookID uint) {
+	m.Called(c, actorID, bookID)
+}
+
+func (m *MockActivityService) FavouriteAdded(userID, bookID uint) {
+	m.Called(userID, bookID)
+}
+
+func (m *MockActivityService) GetFeed(userID uint, page, limit int) ([]service.FeedEntry, int64, error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).([]service.FeedEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockActivityService) GetPrivacy(userID uint) (models.PrivacySettings, error) {
+	args := m.Called(userID)
+	return args.Get(0).(models.PrivacySettings), args.Error(1)
+}
+
+func (m *MockActivityService) UpdatePrivacy(userID uint, req service.PrivacyRequest) (models.PrivacySettings, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(models.PrivacySettings), args.Error(1)
+}
+
+func TestActivityHandler_GetFeedHandler_Success(t *testing.T) {
+	mockService := new(MockActivityService)
+	handler := NewActivityHandler(mockService)
+
+	// Настройка мока: рецензия подписки и новая книга без автора действия
+	actorID, bookID, reviewID := uint(2), uint(7), uint(5)
+	mockService.On("GetFeed", uint(1), 1, 20).Return([]service.FeedEntry{
+		{
+			Event: models.ActivityEvent{
+				ID:        11,
+				Type:      models.ActivityReviewPublished,
+				ActorID:   &actorID,
+				ActorName: "john_doe",
+				BookID:    &bookID,
+				ReviewID:  &reviewID,
+				Summary:   "john_doe написал(а) рецензию на книгу «Dune»",
+			},
+			Book: &service.BookBrief{ID: 7, Title: "Dune"},
+		},
+		{
+			Event: models.ActivityEvent{ID: 10, Type: models.ActivityBookAdded, Summary: "Новая книга: «Solaris», Stanisław Lem"},
+		},
+	}, int64(2), nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/feed", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetFeedHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedFeedResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "john_doe", response.Data[0].Actor.Username)
+	assert.Equal(t, "Dune", response.Data[0].Book.Title)
+	assert.Nil(t, response.Data[1].Actor)
+	assert.Nil(t, response.Data[1].Book)
+	assert.Equal(t, int64(2), response.Meta.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestActivityHandler_GetPrivacyHandler_Defaults(t *testing.T) {
+	mockService := new(MockActivityService)
+	handler := NewActivityHandler(mockService)
+
+	mockService.On("GetPrivacy", uint(1)).Return(models.DefaultPrivacySettings(1), nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/privacy", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetPrivacyHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PrivacySettingsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.False(t, response.ShareFavourites)
+	assert.True(t, response.ShareReviews)
+	assert.True(t, response.ShareCollections)
+	mockService.AssertExpectations(t)
+}
+
+func TestActivityHandler_UpdatePrivacyHandler_InvalidBody(t *testing.T) {
+	mockService := new(MockActivityService)
+	handler := NewActivityHandler(mockService)
+
+	req, _ := http.NewRequest("PUT", "/users/me/privacy", strings.NewReader(`{"share_favourites":`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.UpdatePrivacyHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "UpdatePrivacy", mock.Anything, mock.Anything)
+}
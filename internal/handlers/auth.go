@@ -28,14 +28,22 @@ type errorMapping struct {
 
 var errorMappings = []errorMapping{
 	{"access denied", http.StatusForbidden},
+	{"payment provider", http.StatusBadGateway},
 	{"invalid", http.StatusBadRequest},
 	{"required", http.StatusBadRequest},
 	{"not found", http.StatusNotFound},
 	{"already exists", http.StatusConflict},
+	{"unavailable", http.StatusConflict},
+	{"limit reached", http.StatusConflict},
 	{"credentials", http.StatusUnauthorized},
 }
 
 func (h *AuthHandler) handleServiceError(w http.ResponseWriter, err error) {
+	writeServiceError(w, err)
+}
+
+// writeServiceError подбирает HTTP-статус по тексту ошибки сервиса
+func writeServiceError(w http.ResponseWriter, err error) {
 	status := http.StatusInternalServerError
 	msg := err.Error()
 
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"bookshelf/internal/models"
 	"bookshelf/internal/service"
+	"bookshelf/pkg/marc"
+	"bookshelf/pkg/money"
 	"bookshelf/pkg/utils"
 	"encoding/json"
 	"errors"
@@ -12,18 +15,62 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	marcContentType    = "application/marc"
+	marcXMLContentType = "application/marcxml+xml"
+)
+
 type BookHandler struct {
-	bookService service.BookService
+	bookService       service.BookService
+	popularityService service.PopularityService
+	lendingService    service.LendingService
+	pricingService    service.PricingService
 }
 
-func NewBookHandler(bookService service.BookService) *BookHandler {
-	return &BookHandler{bookService: bookService}
+func NewBookHandler(bookService service.BookService, popularityService service.PopularityService, lendingService service.LendingService, pricingService service.PricingService) *BookHandler {
+	return &BookHandler{bookService: bookService, popularityService: popularityService, lendingService: lendingService, pricingService: pricingService}
 }
 
-func checkBook(book service.BookRequest) error {
-	if book.Author == "" || book.Title == "" || book.Genre == "" || book.Description == "" || book.Price < 0 {
+func toBookResponse(book models.Book) BookResponse {
+	response := BookResponse{
+		ID:             book.ID,
+		Title:          book.Title,
+		Author:         book.Author,
+		Genre:          book.Genre,
+		Description:    book.Description,
+		Price:          money.New(book.Price, book.Currency),
+		EffectivePrice: money.New(book.Price, book.Currency),
+		SaleEndsAt:     book.SaleEndsAt,
+		ISBN:           book.ISBN,
+		Publisher:      book.Publisher,
+		Subjects:       book.Subjects,
+		Pages:          book.Pages,
+		AverageRating:  book.AverageRating(),
+		RatingCount:    book.RatingCount,
+		ReviewCount:    book.ReviewCount,
+	}
+	if book.SalePrice != nil {
+		response.EffectivePrice.Amount = *book.SalePrice
+	}
+	return response
+}
+
+// checkBook: цена не отрицательная и без лишних знаков для своей валюты (по умолчанию - базовой)
+func checkBook(book service.BookRequest, baseCurrency string) error {
+	if book.Author == "" || book.Title == "" || book.Genre == "" || book.Description == "" || book.Pages < 0 {
 		return errors.New("invalid book data")
 	}
+	currency := book.Currency
+	if currency == "" {
+		currency = baseCurrency
+	}
+	price := string(book.Price)
+	if price == "" {
+		price = "0"
+	}
+	if m, err := money.ParseIn(price, currency); err != nil || m.Amount < 0 {
+		return errors.New("invalid book price or currency")
+	}
 	return nil
 }
 
@@ -47,7 +94,7 @@ func (h *BookHandler) CreateBookHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := checkBook(req); err != nil {
+	if err := checkBook(req, h.pricingService.BaseCurrency()); err != nil {
 		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{err.Error()})
 		return
 	}
@@ -58,25 +105,23 @@ func (h *BookHandler) CreateBookHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	utils.JSONResponse(w, http.StatusCreated, BookResponse{
-		ID:          book.ID,
-		Title:       book.Title,
-		Author:      book.Author,
-		Genre:       book.Genre,
-		Description: book.Description,
-		Price:       book.Price,
-	})
+	utils.JSONResponse(w, http.StatusCreated, toBookResponse(book))
 }
 
 // GetBookByIDHandler godoc
 // @Summary Получение книги по ID
-// @Description Получение информации о книге по её идентификатору
+// @Description Получение информации о книге по её идентификатору, в JSON - с числом свободных экземпляров. По заголовку Accept отдаёт JSON, MARCXML (application/marcxml+xml) или бинарный MARC21 (application/marc)
+// @Description currency - валюта цены: из прайс-листа книги или пересчёт по курсу
 // @Tags Books
 // @Produce json
+// @Produce application/marcxml+xml
+// @Produce application/marc
 // @Param id path string true "ID книги"
+// @Param currency query string false "Код валюты ISO 4217, например EUR"
 // @Success 200 {object} BookResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 406 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBookByIDHandler(w http.ResponseWriter, r *http.Request) {
@@ -86,20 +131,54 @@ func (h *BookHandler) GetBookByIDHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	w.Header().Add("Vary", "Accept")
+	format := negotiate(r.Header.Get("Accept"), "application/json", marcXMLContentType, marcContentType)
+	if format == "" {
+		utils.JSONResponse(w, http.StatusNotAcceptable, ErrorResponse{"Supported formats: application/json, application/marcxml+xml, application/marc"})
+		return
+	}
+
 	book, err := h.bookService.GetBookByID(id)
 	if err != nil {
 		utils.JSONResponse(w, http.StatusNotFound, ErrorResponse{"Book not found"})
 		return
 	}
+	h.popularityService.RecordView(book.ID, viewerKey(r), r.UserAgent())
 
-	utils.JSONResponse(w, http.StatusOK, BookResponse{
-		ID:          book.ID,
-		Title:       book.Title,
-		Author:      book.Author,
-		Genre:       book.Genre,
-		Description: book.Description,
-		Price:       book.Price,
-	})
+	switch format {
+	case marcXMLContentType:
+		w.Header().Set("Content-Type", marcXMLContentType+"; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = marc.WriteRecordXML(w, service.BookToMARC(book))
+		return
+	case marcContentType:
+		data, err := marc.Encode(service.BookToMARC(book))
+		if err != nil {
+			utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to encode MARC record"})
+			return
+		}
+		w.Header().Set("Content-Type", marcContentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+		return
+	}
+
+	// Наличие и цена в другой валюте не кэшируются вместе с книгой: они меняются независимо от неё
+	response := toBookResponse(book)
+	prices, err := h.pricingService.PriceBooks([]models.Book{book}, r.URL.Query().Get("currency"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response.Price, response.EffectivePrice = prices[0].List, prices[0].Effective
+	availability, err := h.lendingService.GetAvailability(book.ID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get book availability"})
+		return
+	}
+	copies := toAvailabilityResponse(availability)
+	response.Copies = &copies
+	utils.JSONResponse(w, http.StatusOK, response)
 }
 
 // GetAllBooksHandler godoc
@@ -108,9 +187,11 @@ func (h *BookHandler) GetBookByIDHandler(w http.ResponseWriter, r *http.Request)
 // @Tags Books
 // @Produce json
 // @Param genre query string false "Фильтр по жанру"
+// @Param currency query string false "Валюта цен, код ISO 4217"
 // @Param page query int false "Номер страницы (по умолчанию 1)" default(1)
 // @Param limit query int false "Количество книг на странице (по умолчанию 10, максимум 100)" default(10)
 // @Success 200 {object} PaginatedBooksResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /books [get]
 func (h *BookHandler) GetAllBooksHandler(w http.ResponseWriter, r *http.Request) {
@@ -131,16 +212,15 @@ func (h *BookHandler) GetAllBooksHandler(w http.ResponseWriter, r *http.Request)
 		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Couldn't find books"})
 		return
 	}
+	books, err = h.pricingService.PriceBriefs(books, r.URL.Query().Get("currency"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
 
 	var bookResponses []BookBriefResponse
 	for _, book := range books {
-		bookResponses = append(bookResponses, BookBriefResponse{
-			ID:     book.ID,
-			Title:  book.Title,
-			Author: book.Author,
-			Genre:  book.Genre,
-			Price:  book.Price,
-		})
+		bookResponses = append(bookResponses, toBookBriefResponse(book))
 	}
 
 	response := PaginatedBooksResponse{
@@ -201,7 +281,7 @@ func (h *BookHandler) UpdateBookHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := checkBook(req); err != nil {
+	if err := checkBook(req, h.pricingService.BaseCurrency()); err != nil {
 		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{err.Error()})
 		return
 	}
@@ -218,14 +298,7 @@ func (h *BookHandler) UpdateBookHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	utils.JSONResponse(w, http.StatusOK, BookResponse{
-		ID:          book.ID,
-		Title:       book.Title,
-		Author:      book.Author,
-		Genre:       book.Genre,
-		Description: book.Description,
-		Price:       book.Price,
-	})
+	utils.JSONResponse(w, http.StatusOK, toBookResponse(book))
 }
 
 // DeleteBookHandler godoc
@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
 	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
 	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -40,6 +43,16 @@ func (m *MockBookService) GetAllGenres() ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockBookService) GetNewestBooks(page, limit int) ([]service.BookBrief, int64, error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]service.BookBrief), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookService) SearchBooks(query string, page, limit int) ([]service.BookBrief, int64, error) {
+	args := m.Called(query, page, limit)
+	return args.Get(0).([]service.BookBrief), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockBookService) UpdateBook(id string, update service.BookRequest) (models.Book, error) {
 	args := m.Called(id, update)
 	return args.Get(0).(models.Book), args.Error(1)
@@ -52,7 +65,9 @@ func (m *MockBookService) DeleteBook(id string) error {
 
 func TestBookHandler_CreateBookHandler_Success(t *testing.T) {
 	mockService := new(MockBookService)
-	handler := NewBookHandler(mockService)
+	mockPricing := new(MockPricingService)
+	mockPricing.On("BaseCurrency").Return("USD")
+	handler := NewBookHandler(mockService, new(MockPopularityService), new(MockLendingService), mockPricing)
 
 	// Настройка мока
 	bookReq := service.BookRequest{
@@ -60,7 +75,7 @@ func TestBookHandler_CreateBookHandler_Success(t *testing.T) {
 		Author:      "Author",
 		Genre:       "Fiction",
 		Description: "Description",
-		Price:       19.99,
+		Price:       "19.99",
 	}
 	createdBook := models.Book{
 		Model:       gorm.Model{ID: 1},
@@ -68,7 +83,8 @@ func TestBookHandler_CreateBookHandler_Success(t *testing.T) {
 		Author:      "Author",
 		Genre:       "Fiction",
 		Description: "Description",
-		Price:       19.99,
+		Price:       1999,
+		Currency:    "USD",
 	}
 	mockService.On("CreateBook", bookReq).Return(createdBook, nil)
 
@@ -89,7 +105,8 @@ func TestBookHandler_CreateBookHandler_Success(t *testing.T) {
 		"author":"Author",
 		"genre":"Fiction",
 		"description":"Description",
-		"price":19.99
+		"price":{"amount":"19.99","currency":"USD"},
+		"effective_price":{"amount":"19.99","currency":"USD"}
 	}`
 	assert.JSONEq(t, expected, rr.Body.String())
 	mockService.AssertExpectations(t)
@@ -97,7 +114,12 @@ func TestBookHandler_CreateBookHandler_Success(t *testing.T) {
 
 func TestBookHandler_GetBookByIDHandler_Success(t *testing.T) {
 	mockService := new(MockBookService)
-	handler := NewBookHandler(mockService)
+	mockPopularity := new(MockPopularityService)
+	mockPopularity.On("RecordView", uint(1), mock.Anything, mock.Anything).Return()
+	mockLending := new(MockLendingService)
+	mockLending.On("GetAvailability", uint(1)).Return(repository.Availability{Total: 3, Available: 1}, nil)
+	mockPricing := new(MockPricingService)
+	handler := NewBookHandler(mockService, mockPopularity, mockLending, mockPricing)
 
 	// Настройка мока
 	book := models.Book{
@@ -106,12 +128,16 @@ func TestBookHandler_GetBookByIDHandler_Success(t *testing.T) {
 		Author:      "Author",
 		Genre:       "Fiction",
 		Description: "Description",
-		Price:       19.99,
+		Price:       1999,
+		Currency:    "USD",
 	}
 	mockService.On("GetBookByID", "1").Return(book, nil)
+	mockPricing.On("PriceBooks", []models.Book{book}, "EUR").Return([]service.PriceQuote{
+		{List: money.New(1842, "EUR"), Effective: money.New(1474, "EUR")},
+	}, nil)
 
 	// Создание запроса
-	req, _ := http.NewRequest("GET", "/books/1", nil)
+	req, _ := http.NewRequest("GET", "/books/1?currency=EUR", nil)
 
 	// Добавление параметра в роут
 	rctx := chi.NewRouteContext()
@@ -130,15 +156,21 @@ func TestBookHandler_GetBookByIDHandler_Success(t *testing.T) {
 		"author":"Author",
 		"genre":"Fiction",
 		"description":"Description",
-		"price":19.99
+		"price":{"amount":"18.42","currency":"EUR"},
+		"effective_price":{"amount":"14.74","currency":"EUR"},
+		"copies":{"total":3,"available":1}
 	}`
 	assert.JSONEq(t, expected, rr.Body.String())
 	mockService.AssertExpectations(t)
+	mockPopularity.AssertExpectations(t)
+	mockLending.AssertExpectations(t)
+	mockPricing.AssertExpectations(t)
 }
 
 func TestBookHandler_GetAllBooksHandler_Success(t *testing.T) {
 	mockService := new(MockBookService)
-	handler := NewBookHandler(mockService)
+	mockPricing := new(MockPricingService)
+	handler := NewBookHandler(mockService, new(MockPopularityService), new(MockLendingService), mockPricing)
 
 	// Настройка мока
 	briefs := []service.BookBrief{
@@ -147,17 +179,18 @@ func TestBookHandler_GetAllBooksHandler_Success(t *testing.T) {
 			Title:  "Book 1",
 			Author: "Author 1",
 			Genre:  "Fiction",
-			Price:  19.99,
+			Price:  money.New(1999, "USD"),
 		},
 		{
 			ID:     2,
 			Title:  "Book 2",
 			Author: "Author 2",
 			Genre:  "Non-Fiction",
-			Price:  24.99,
+			Price:  money.New(2499, "USD"),
 		},
 	}
 	mockService.On("GetAllBooks", "", 1, 10).Return(briefs, int64(2), nil)
+	mockPricing.On("PriceBriefs", briefs, "").Return(briefs, nil)
 
 	// Создание запроса
 	req, _ := http.NewRequest("GET", "/books", nil)
@@ -170,8 +203,8 @@ func TestBookHandler_GetAllBooksHandler_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	expected := `{
 		"data": [
-			{"id":1, "title":"Book 1", "author":"Author 1", "genre":"Fiction", "price":19.99},
-			{"id":2, "title":"Book 2", "author":"Author 2", "genre":"Non-Fiction", "price":24.99}
+			{"id":1, "title":"Book 1", "author":"Author 1", "genre":"Fiction", "price":{"amount":"19.99","currency":"USD"}},
+			{"id":2, "title":"Book 2", "author":"Author 2", "genre":"Non-Fiction", "price":{"amount":"24.99","currency":"USD"}}
 		],
 		"meta": {
 			"total":2,
@@ -183,3 +216,80 @@ func TestBookHandler_GetAllBooksHandler_Success(t *testing.T) {
 	assert.JSONEq(t, expected, rr.Body.String())
 	mockService.AssertExpectations(t)
 }
+
+func TestBookHandler_GetAllBooksHandler_EffectivePrice(t *testing.T) {
+	mockService := new(MockBookService)
+	mockPricing := new(MockPricingService)
+	handler := NewBookHandler(mockService, new(MockPopularityService), new(MockLendingService), mockPricing)
+
+	// Настройка мока: книга на распродаже
+	saleEnds := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	effective := money.New(1499, "USD")
+	briefs := []service.BookBrief{{
+		ID:             1,
+		Title:          "Book 1",
+		Author:         "Author 1",
+		Genre:          "Fiction",
+		Price:          money.New(1999, "USD"),
+		EffectivePrice: &effective,
+		SaleEndsAt:     &saleEnds,
+	}}
+	mockService.On("GetAllBooks", "", 1, 10).Return(briefs, int64(1), nil)
+	mockPricing.On("PriceBriefs", briefs, "").Return(briefs, nil)
+
+	req, _ := http.NewRequest("GET", "/books", nil)
+	rr := httptest.NewRecorder()
+	handler.GetAllBooksHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, map[string]interface{}{"amount": "14.99", "currency": "USD"}, response.Data[0]["effective_price"])
+		assert.Equal(t, "2026-12-31T00:00:00Z", response.Data[0]["sale_ends_at"])
+	}
+	mockService.AssertExpectations(t)
+	mockPricing.AssertExpectations(t)
+}
+
+func TestBookHandler_GetBookByIDHandler_MARCXML(t *testing.T) {
+	mockService := new(MockBookService)
+	mockPopularity := new(MockPopularityService)
+	mockPopularity.On("RecordView", uint(1), mock.Anything, mock.Anything).Return()
+	handler := NewBookHandler(mockService, mockPopularity, new(MockLendingService), new(MockPricingService))
+
+	// Настройка мока
+	book := models.Book{
+		Model:       gorm.Model{ID: 1},
+		Title:       "Test Book",
+		Author:      "John Smith",
+		Genre:       "Fiction",
+		Description: "Description",
+		ISBN:        "9780134190440",
+	}
+	mockService.On("GetBookByID", "1").Return(book, nil)
+
+	// Создание запроса
+	req, _ := http.NewRequest("GET", "/books/1", nil)
+	req.Header.Set("Accept", "application/marcxml+xml")
+
+	// Добавление параметра в роут
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.GetBookByIDHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "application/marcxml+xml")
+	assert.Contains(t, rr.Body.String(), `<datafield tag="245" ind1="1" ind2="0">`)
+	assert.Contains(t, rr.Body.String(), `<subfield code="a">Smith, John</subfield>`)
+	assert.Contains(t, rr.Body.String(), `<subfield code="a">9780134190440</subfield>`)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type CartHandler struct {
+	cartService service.CartService
+}
+
+func NewCartHandler(cartService service.CartService) *CartHandler {
+	return &CartHandler{cartService: cartService}
+}
+
+func toCartResponse(cart service.Cart) CartResponse {
+	response := CartResponse{
+		Items: make([]CartLineResponse, 0, len(cart.Lines)),
+		Count: cart.Count,
+		Total: money.New(cart.Total, cart.Currency),
+	}
+	for _, line := range cart.Lines {
+		response.Items = append(response.Items, CartLineResponse{
+			Book:      toBookBriefResponse(line.Book),
+			Quantity:  line.Quantity,
+			UnitPrice: money.New(line.UnitPrice, cart.Currency),
+			LineTotal: money.New(line.LineTotal, cart.Currency),
+		})
+	}
+	return response
+}
+
+// GetCartHandler godoc
+// @Summary Корзина
+// @Description Книги в корзине по текущим ценам. Суммы считаются точно, в минимальных единицах валюты
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param currency query string false "Валюта корзины, код ISO 4217 (по умолчанию базовая)"
+// @Success 200 {object} CartResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/cart [get]
+func (h *CartHandler) GetCartHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	cart, err := h.cartService.GetCart(userID, r.URL.Query().Get("currency"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCartResponse(cart))
+}
+
+// SetCartItemHandler godoc
+// @Summary Положить книгу в корзину
+// @Description Задаёт количество экземпляров книги; 0 убирает её из корзины
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param currency query string false "Валюта корзины в ответе, код ISO 4217"
+// @Param input body service.CartItemRequest true "Количество"
+// @Success 200 {object} CartResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/me/cart/{bookID} [put]
+func (h *CartHandler) SetCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.CartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	cart, err := h.cartService.SetItem(userID, bookID, r.URL.Query().Get("currency"), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCartResponse(cart))
+}
+
+// RemoveCartItemHandler godoc
+// @Summary Убрать книгу из корзины
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Param bookID path int true "ID книги"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/cart/{bookID} [delete]
+func (h *CartHandler) RemoveCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.cartService.RemoveItem(userID, bookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ClearCartHandler godoc
+// @Summary Очистить корзину
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/cart [delete]
+func (h *CartHandler) ClearCartHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.cartService.Clear(userID); err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to clear cart"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
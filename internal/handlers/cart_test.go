@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) GetCart(userID uint, currency string) (service.Cart, error) {
+	args := m.Called(userID, currency)
+	return args.Get(0).(service.Cart), args.Error(1)
+}
+
+func (m *MockCartService) SetItem(userID, bookID uint, currency string, req service.CartItemRequest) (service.Cart, error) {
+	args := m.Called(userID, bookID, currency, req)
+	return args.Get(0).(service.Cart), args.Error(1)
+}
+
+func (m *MockCartService) RemoveItem(userID, bookID uint) error {
+	args := m.Called(userID, bookID)
+	return args.Error(0)
+}
+
+func (m *MockCartService) Clear(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func TestCartHandler_SetCartItemHandler_Success(t *testing.T) {
+	mockService := new(MockCartService)
+	handler := NewCartHandler(mockService)
+
+	// Настройка мока: 0.10 + 2 * 0.10 без ошибок округления float64
+	mockService.On("SetItem", uint(1), uint(2), "", service.CartItemRequest{Quantity: 2}).Return(service.Cart{
+		Lines: []service.CartLine{
+			{Book: service.BookBrief{ID: 1, Title: "Leaflet"}, Quantity: 1, UnitPrice: 10, LineTotal: 10},
+			{Book: service.BookBrief{ID: 2, Title: "Pamphlet"}, Quantity: 2, UnitPrice: 10, LineTotal: 20},
+		},
+		Count:    3,
+		Total:    30,
+		Currency: "USD",
+	}, nil)
+
+	req, _ := http.NewRequest("PUT", "/users/me/cart/2", strings.NewReader(`{"quantity":2}`))
+	req = withRouteAndUser(req, "bookID", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.SetCartItemHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response CartResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Items, 2)
+	assert.Equal(t, money.New(30, "USD"), response.Total)
+	assert.Contains(t, rr.Body.String(), `"total":{"amount":"0.30","currency":"USD"}`)
+	assert.Contains(t, rr.Body.String(), `"line_total":{"amount":"0.20","currency":"USD"}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_SetCartItemHandler_InvalidQuantity(t *testing.T) {
+	mockService := new(MockCartService)
+	handler := NewCartHandler(mockService)
+
+	mockService.On("SetItem", uint(1), uint(2), "", service.CartItemRequest{Quantity: 500}).
+		Return(service.Cart{}, errors.New("invalid quantity, must be between 0 and 99"))
+
+	req, _ := http.NewRequest("PUT", "/users/me/cart/2", strings.NewReader(`{"quantity":500}`))
+	req = withRouteAndUser(req, "bookID", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.SetCartItemHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,491 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CollectionHandler struct {
+	collectionService service.CollectionService
+}
+
+func NewCollectionHandler(collectionService service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// toCollectionResponse показывает ссылку только тем, кто может ею делиться
+func toCollectionResponse(c models.Collection, viewerID uint) CollectionResponse {
+	response := CollectionResponse{
+		ID:          c.ID,
+		OwnerID:     c.OwnerID,
+		Name:        c.Name,
+		Description: c.Description,
+		Visibility:  c.Visibility,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+	if c.OwnerID == viewerID && c.Visibility == models.CollectionUnlisted && c.ShareToken != nil {
+		response.ShareURL = "/collections/shared/" + *c.ShareToken
+	}
+	return response
+}
+
+func toCollectionDetailResponse(detail service.CollectionDetail, viewerID uint, page, limit int) CollectionDetailResponse {
+	response := CollectionDetailResponse{
+		CollectionResponse: toCollectionResponse(detail.Collection, viewerID),
+		CanEdit:            detail.CanEdit,
+		Collaborators:      detail.Collaborators,
+	}
+	response.Books.Data = make([]BookBriefResponse, 0, len(detail.Books))
+	for _, book := range detail.Books {
+		response.Books.Data = append(response.Books.Data, toBookBriefResponse(book))
+	}
+	response.Books.Meta = newPaginationMeta(detail.Total, page, limit)
+	return response
+}
+
+func toPaginatedCollections(collections []models.Collection, viewerID uint, total int64, page, limit int) PaginatedCollectionsResponse {
+	response := PaginatedCollectionsResponse{
+		Data: make([]CollectionResponse, 0, len(collections)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, c := range collections {
+		response.Data = append(response.Data, toCollectionResponse(c, viewerID))
+	}
+	return response
+}
+
+// CreateCollectionHandler godoc
+// @Summary Создание коллекции
+// @Description Именованный упорядоченный список книг. visibility: private (по умолчанию), unlisted (доступ по ссылке), public
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.CollectionRequest true "Коллекция"
+// @Success 201 {object} CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /collections [post]
+func (h *CollectionHandler) CreateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	c, err := h.collectionService.CreateCollection(userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toCollectionResponse(c, userID))
+}
+
+// ListPublicCollectionsHandler godoc
+// @Summary Публичные коллекции
+// @Description Каталог публичных коллекций, недавно изменённые первыми
+// @Tags Collections
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Коллекций на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedCollectionsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /collections [get]
+func (h *CollectionHandler) ListPublicCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := currentUserID(r)
+	page, limit := parsePagination(r, 10)
+
+	collections, total, err := h.collectionService.ListPublic(page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get collections"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedCollections(collections, viewerID, total, page, limit))
+}
+
+// ListMyCollectionsHandler godoc
+// @Summary Мои коллекции
+// @Description Коллекции, которыми пользователь владеет или которые редактирует как соавтор
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Коллекций на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedCollectionsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/collections [get]
+func (h *CollectionHandler) ListMyCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	page, limit := parsePagination(r, 10)
+
+	collections, total, err := h.collectionService.ListUserCollections(userID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get collections"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedCollections(collections, userID, total, page, limit))
+}
+
+// GetCollectionHandler godoc
+// @Summary Коллекция с книгами
+// @Description Публичная коллекция доступна всем, приватная и unlisted - владельцу и соавторам (unlisted также по ссылке)
+// @Tags Collections
+// @Produce json
+// @Param id path int true "ID коллекции"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} CollectionDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) GetCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	viewerID, _ := currentUserID(r)
+	page, limit := parsePagination(r, 10)
+
+	detail, err := h.collectionService.GetCollection(viewerID, id, page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCollectionDetailResponse(detail, viewerID, page, limit))
+}
+
+// GetSharedCollectionHandler godoc
+// @Summary Коллекция по ссылке
+// @Description Открывает unlisted или public коллекцию по токену из share_url
+// @Tags Collections
+// @Produce json
+// @Param token path string true "Токен ссылки"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} CollectionDetailResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/shared/{token} [get]
+func (h *CollectionHandler) GetSharedCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 10)
+
+	detail, err := h.collectionService.GetSharedCollection(chi.URLParam(r, "token"), page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCollectionDetailResponse(detail, 0, page, limit))
+}
+
+// UpdateCollectionHandler godoc
+// @Summary Изменение коллекции
+// @Description Название, описание и видимость. Только владелец
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID коллекции"
+// @Param input body service.CollectionRequest true "Коллекция"
+// @Success 200 {object} CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id} [put]
+func (h *CollectionHandler) UpdateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	c, err := h.collectionService.UpdateCollection(userID, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCollectionResponse(c, userID))
+}
+
+// DeleteCollectionHandler godoc
+// @Summary Удаление коллекции
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Param id path int true "ID коллекции"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateShareTokenHandler godoc
+// @Summary Новая ссылка на коллекцию
+// @Description Выдаёт новый токен unlisted-коллекции, старая ссылка перестаёт работать
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID коллекции"
+// @Success 200 {object} CollectionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id}/share-token [post]
+func (h *CollectionHandler) RotateShareTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	c, err := h.collectionService.RotateShareToken(userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCollectionResponse(c, userID))
+}
+
+// AddCollectionBookHandler godoc
+// @Summary Добавить книгу в коллекцию
+// @Description Книга встаёт в конец списка. Владелец или соавтор
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Param id path int true "ID коллекции"
+// @Param input body CollectionBookRequest true "Книга"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /collections/{id}/books [post]
+func (h *CollectionHandler) AddCollectionBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req CollectionBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.BookID == 0 {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	if err := h.collectionService.AddBook(userID, id, req.BookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveCollectionBookHandler godoc
+// @Summary Убрать книгу из коллекции
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Param id path int true "ID коллекции"
+// @Param bookID path int true "ID книги"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id}/books/{bookID} [delete]
+func (h *CollectionHandler) RemoveCollectionBookHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.collectionService.RemoveBook(userID, id, bookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderCollectionHandler godoc
+// @Summary Порядок книг в коллекции
+// @Description Принимает все книги коллекции в новом порядке
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Param id path int true "ID коллекции"
+// @Param input body ReorderCollectionRequest true "ID книг по порядку"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id}/books/order [put]
+func (h *CollectionHandler) ReorderCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req ReorderCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	if err := h.collectionService.ReorderBooks(userID, id, req.BookIDs); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddCollaboratorHandler godoc
+// @Summary Добавить соавтора
+// @Description Соавтор может добавлять, убирать и переставлять книги. Только владелец
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Param id path int true "ID коллекции"
+// @Param input body CollaboratorRequest true "Пользователь"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id}/collaborators [post]
+func (h *CollectionHandler) AddCollaboratorHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req CollaboratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == 0 {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	if err := h.collectionService.AddCollaborator(userID, id, req.UserID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveCollaboratorHandler godoc
+// @Summary Убрать соавтора
+// @Description Владелец убирает соавтора, соавтор может выйти сам
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Param id path int true "ID коллекции"
+// @Param userID path int true "ID соавтора"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /collections/{id}/collaborators/{userID} [delete]
+func (h *CollectionHandler) RemoveCollaboratorHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid collection ID"})
+		return
+	}
+	collaboratorID, ok := pathID(r, "userID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid user ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.collectionService.RemoveCollaborator(userID, id, collaboratorID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
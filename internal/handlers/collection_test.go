@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) CreateCollection(ownerID uint, req service.CollectionRequest) (models.Collection, error) {
+	args := m.Called(ownerID, req)
+	return args.Get(0).(models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) UpdateCollection(userID, id uint, req service.CollectionRequest) (models.Collection, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) DeleteCollection(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) GetCollection(viewerID, id uint, page, limit int) (service.CollectionDetail, error) {
+	args := m.Called(viewerID, id, page, limit)
+	return args.Get(0).(service.CollectionDetail), args.Error(1)
+}
+
+func (m *MockCollectionService) GetSharedCollection(token string, page, limit int) (service.CollectionDetail, error) {
+	args := m.Called(token, page, limit)
+	return args.Get(0).(service.CollectionDetail), args.Error(1)
+}
+
+func (m *MockCollectionService) ListPublic(page, limit int) ([]models.Collection, int64, error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]models.Collection), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCollectionService) ListUserCollections(userID uint, page, limit int) ([]models.Collection, int64, error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).([]models.Collection), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCollectionService) RotateShareToken(userID, id uint) (models.Collection, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(models.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) AddBook(userID, id, bookID uint) error {
+	args := m.Called(userID, id, bookID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) RemoveBook(userID, id, bookID uint) error {
+	args := m.Called(userID, id, bookID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) ReorderBooks(userID, id uint, bookIDs []uint) error {
+	args := m.Called(userID, id, bookIDs)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) AddCollaborator(ownerID, id, collaboratorID uint) error {
+	args := m.Called(ownerID, id, collaboratorID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) RemoveCollaborator(ownerID, id, collaboratorID uint) error {
+	args := m.Called(ownerID, id, collaboratorID)
+	return args.Error(0)
+}
+
+func TestCollectionHandler_CreateCollectionHandler_Unlisted(t *testing.T) {
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+
+	// Настройка мока
+	token := "abc123"
+	req := service.CollectionRequest{Name: "Go onboarding", Visibility: models.CollectionUnlisted}
+	mockService.On("CreateCollection", uint(1), req).Return(models.Collection{
+		Model:      gorm.Model{ID: 4},
+		OwnerID:    1,
+		Name:       "Go onboarding",
+		Visibility: models.CollectionUnlisted,
+		ShareToken: &token,
+	}, nil)
+
+	httpReq, _ := http.NewRequest("POST", "/collections", strings.NewReader(`{"name":"Go onboarding","visibility":"unlisted"}`))
+	httpReq = withRouteAndUser(httpReq, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateCollectionHandler(rr, httpReq)
+
+	// Проверки: владелец видит ссылку
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response CollectionResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "/collections/shared/abc123", response.ShareURL)
+	mockService.AssertExpectations(t)
+}
+
+func TestCollectionHandler_GetCollectionHandler_HidesShareURL(t *testing.T) {
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+
+	token := "abc123"
+	detail := service.CollectionDetail{
+		Collection: models.Collection{Model: gorm.Model{ID: 4}, OwnerID: 1, Visibility: models.CollectionUnlisted, ShareToken: &token},
+		Books:      []service.BookBrief{{ID: 9, Title: "Go Programming"}},
+		Total:      1,
+		CanEdit:    true,
+	}
+	mockService.On("GetCollection", uint(2), uint(4), 1, 10).Return(detail, nil)
+
+	// Соавтор видит книги, но не ссылку владельца
+	req, _ := http.NewRequest("GET", "/collections/4", nil)
+	req = withRouteAndUser(req, "id", "4", &utils.Claims{UserID: "2"})
+
+	rr := httptest.NewRecorder()
+	handler.GetCollectionHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response CollectionDetailResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Empty(t, response.ShareURL)
+	assert.True(t, response.CanEdit)
+	assert.Len(t, response.Books.Data, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestCollectionHandler_AddCollectionBookHandler_Duplicate(t *testing.T) {
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+
+	mockService.On("AddBook", uint(1), uint(4), uint(9)).Return(errors.New("book already exists in collection"))
+
+	req, _ := http.NewRequest("POST", "/collections/4/books", strings.NewReader(`{"book_id":9}`))
+	req = withRouteAndUser(req, "id", "4", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.AddCollectionBookHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
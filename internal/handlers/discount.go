@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type DiscountHandler struct {
+	discountService service.DiscountService
+}
+
+func NewDiscountHandler(discountService service.DiscountService) *DiscountHandler {
+	return &DiscountHandler{discountService: discountService}
+}
+
+func toDiscountResponse(discount models.Discount) DiscountResponse {
+	response := DiscountResponse{
+		ID:         discount.ID,
+		Name:       discount.Name,
+		Target:     discount.Target,
+		Value:      discount.Value,
+		Kind:       discount.Kind,
+		Percent:    discount.Percent,
+		StartsAt:   discount.StartsAt,
+		EndsAt:     discount.EndsAt,
+		UsageLimit: discount.UsageLimit,
+		UsedCount:  discount.UsedCount,
+		Active:     discount.ActiveAt(time.Now()),
+	}
+	if discount.Kind == models.DiscountFixed {
+		amount := money.New(discount.Amount, discount.Currency)
+		response.Amount = &amount
+	}
+	if discount.Code != nil {
+		response.Code = *discount.Code
+	}
+	return response
+}
+
+// GetDiscountsHandler godoc
+// @Summary Скидки
+// @Description Распродажи и купоны, начинающиеся позже первыми
+// @Tags Discounts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Скидок на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedDiscountsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/discounts [get]
+func (h *DiscountHandler) GetDiscountsHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+	discounts, total, err := h.discountService.ListDiscounts(page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get discounts"})
+		return
+	}
+
+	response := PaginatedDiscountsResponse{
+		Data: make([]DiscountResponse, 0, len(discounts)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, discount := range discounts {
+		response.Data = append(response.Data, toDiscountResponse(discount))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// CreateDiscountHandler godoc
+// @Summary Создать скидку
+// @Description Скидка без code - распродажа: её цена сразу видна в каталоге с starts_at до ends_at.
+// @Description Скидка с code - купон, применяется при оформлении заказа; usage_limit ограничивает число заказов
+// @Tags Discounts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.DiscountRequest true "Скидка"
+// @Success 201 {object} DiscountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/discounts [post]
+func (h *DiscountHandler) CreateDiscountHandler(w http.ResponseWriter, r *http.Request) {
+	var req service.DiscountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	discount, err := h.discountService.CreateDiscount(req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toDiscountResponse(discount))
+}
+
+// GetDiscountHandler godoc
+// @Summary Скидка
+// @Tags Discounts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID скидки"
+// @Success 200 {object} DiscountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/discounts/{id} [get]
+func (h *DiscountHandler) GetDiscountHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid discount ID"})
+		return
+	}
+
+	discount, err := h.discountService.GetDiscount(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toDiscountResponse(discount))
+}
+
+// UpdateDiscountHandler godoc
+// @Summary Изменить скидку
+// @Description Заменяет условия скидки целиком; счётчик использований купона сохраняется
+// @Tags Discounts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID скидки"
+// @Param input body service.DiscountRequest true "Скидка"
+// @Success 200 {object} DiscountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/discounts/{id} [put]
+func (h *DiscountHandler) UpdateDiscountHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid discount ID"})
+		return
+	}
+
+	var req service.DiscountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	discount, err := h.discountService.UpdateDiscount(id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toDiscountResponse(discount))
+}
+
+// DeleteDiscountHandler godoc
+// @Summary Удалить скидку
+// @Description Уже оформленные заказы сохраняют цены со скидкой
+// @Tags Discounts
+// @Security ApiKeyAuth
+// @Param id path int true "ID скидки"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/discounts/{id} [delete]
+func (h *DiscountHandler) DeleteDiscountHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid discount ID"})
+		return
+	}
+
+	if err := h.discountService.DeleteDiscount(id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
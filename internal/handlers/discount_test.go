@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDiscountService struct {
+	mock.Mock
+}
+
+func (m *MockDiscountService) CreateDiscount(req service.DiscountRequest) (models.Discount, error) {
+	args := m.Called(req)
+	return args.Get(0).(models.Discount), args.Error(1)
+}
+
+func (m *MockDiscountService) GetDiscount(id uint) (models.Discount, error) {
+	args := m.Called(id)
+	return args.Get(0).(models.Discount), args.Error(1)
+}
+
+func (m *MockDiscountService) UpdateDiscount(id uint, req service.DiscountRequest) (models.Discount, error) {
+	args := m.Called(id, req)
+	return args.Get(0).(models.Discount), args.Error(1)
+}
+
+func (m *MockDiscountService) DeleteDiscount(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDiscountService) ListDiscounts(page, limit int) ([]models.Discount, int64, error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]models.Discount), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDiscountService) ApplySales(books []models.Book) (time.Time, error) {
+	args := m.Called(books)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockDiscountService) Coupon(code string) (models.Discount, error) {
+	args := m.Called(code)
+	return args.Get(0).(models.Discount), args.Error(1)
+}
+
+func TestDiscountHandler_CreateDiscountHandler_Success(t *testing.T) {
+	mockService := new(MockDiscountService)
+	handler := NewDiscountHandler(mockService)
+
+	// Настройка мока
+	startsAt := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)
+	req := service.DiscountRequest{
+		Name: "Неделя Go", Target: "genre", Value: "Programming", Kind: "percent", Percent: 20,
+		StartsAt: &startsAt, EndsAt: &endsAt,
+	}
+	mockService.On("CreateDiscount", req).Return(models.Discount{
+		ID: 1, Name: "Неделя Go", Target: "genre", Value: "Programming", Kind: "percent", Percent: 20,
+		StartsAt: startsAt, EndsAt: &endsAt,
+	}, nil)
+
+	body := `{"name":"Неделя Go","target":"genre","value":"Programming","kind":"percent","percent":20,
+		"starts_at":"2026-11-02T00:00:00Z","ends_at":"2026-11-09T00:00:00Z"}`
+	httpReq, _ := http.NewRequest("POST", "/admin/discounts", strings.NewReader(body))
+	httpReq = withRouteAndUser(httpReq, "", "", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateDiscountHandler(rr, httpReq)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"percent":20`)
+	assert.Contains(t, rr.Body.String(), `"ends_at":"2026-11-09T00:00:00Z"`)
+	assert.NotContains(t, rr.Body.String(), `"code"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestDiscountHandler_CreateDiscountHandler_DuplicateCode(t *testing.T) {
+	mockService := new(MockDiscountService)
+	handler := NewDiscountHandler(mockService)
+
+	req := service.DiscountRequest{
+		Name: "Купон", Target: "author", Value: "Rob Pike", Kind: "fixed", Amount: "5.00", Code: "GOPHER20", UsageLimit: 100,
+	}
+	mockService.On("CreateDiscount", req).Return(models.Discount{}, errors.New("coupon code already exists"))
+
+	body := `{"name":"Купон","target":"author","value":"Rob Pike","kind":"fixed","amount":"5.00","code":"GOPHER20","usage_limit":100}`
+	httpReq, _ := http.NewRequest("POST", "/admin/discounts", strings.NewReader(body))
+	httpReq = withRouteAndUser(httpReq, "", "", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateDiscountHandler(rr, httpReq)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDiscountHandler_GetDiscountHandler_FixedCoupon(t *testing.T) {
+	mockService := new(MockDiscountService)
+	handler := NewDiscountHandler(mockService)
+
+	code := "GOPHER20"
+	mockService.On("GetDiscount", uint(2)).Return(models.Discount{
+		ID: 2, Name: "Купон", Target: "book", Value: "1", Kind: "fixed", Amount: 500, Currency: "USD",
+		StartsAt: time.Now().Add(-time.Hour), Code: &code, UsageLimit: 100, UsedCount: 12,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/admin/discounts/2", nil)
+	req = withRouteAndUser(req, "id", "2", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.GetDiscountHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"amount":{"amount":"5.00","currency":"USD"}`)
+	assert.Contains(t, rr.Body.String(), `"code":"GOPHER20"`)
+	assert.Contains(t, rr.Body.String(), `"active":true`)
+	mockService.AssertExpectations(t)
+}
@@ -1,5 +1,11 @@
 package handlers
 
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/money"
+	"time"
+)
+
 type UserResponse struct {
 	ID       uint   `json:"id" example:"1"`
 	Username string `json:"username" example:"john_doe"`
@@ -7,20 +13,43 @@ type UserResponse struct {
 }
 
 type BookResponse struct {
-	ID          uint    `json:"id" example:"1"`
-	Title       string  `json:"title" example:"The Go Programming Language"`
-	Author      string  `json:"author" example:"Alan A. A. Donovan"`
-	Genre       string  `json:"genre" example:"Programming"`
-	Description string  `json:"description" example:"Definitive guide to Go programming"`
-	Price       float64 `json:"price" example:"49.99"`
+	ID          uint        `json:"id" example:"1"`
+	Title       string      `json:"title" example:"The Go Programming Language"`
+	Author      string      `json:"author" example:"Alan A. A. Donovan"`
+	Genre       string      `json:"genre" example:"Programming"`
+	Description string      `json:"description" example:"Definitive guide to Go programming"`
+	Price       money.Money `json:"price"`
+	// EffectivePrice - цена с учётом распродаж, SaleEndsAt - когда закончится распродажа
+	EffectivePrice money.Money `json:"effective_price"`
+	SaleEndsAt     *time.Time  `json:"sale_ends_at,omitempty"`
+	ISBN           string      `json:"isbn,omitempty" example:"9780134190440"`
+	Publisher      string      `json:"publisher,omitempty" example:"Addison-Wesley"`
+	Subjects       []string    `json:"subjects,omitempty" example:"Go (Computer program language)"`
+	Pages          int         `json:"pages,omitempty" example:"380"`
+	// Рейтинг считается по одобренным рецензиям
+	AverageRating float64 `json:"average_rating,omitempty" example:"4.2"`
+	RatingCount   int     `json:"rating_count,omitempty" example:"10"`
+	ReviewCount   int     `json:"review_count,omitempty" example:"7"`
+	// Copies - физические экземпляры для выдачи, только в карточке книги
+	Copies *AvailabilityResponse `json:"copies,omitempty"`
 }
 
 type BookBriefResponse struct {
-	ID     uint    `json:"id" example:"1"`
-	Title  string  `json:"title" example:"The Go Programming Language"`
-	Author string  `json:"author" example:"Alan A. A. Donovan"`
-	Genre  string  `json:"genre" example:"Programming"`
-	Price  float64 `json:"price" example:"49.99"`
+	ID     uint        `json:"id" example:"1"`
+	Title  string      `json:"title" example:"The Go Programming Language"`
+	Author string      `json:"author" example:"Alan A. A. Donovan"`
+	Genre  string      `json:"genre" example:"Programming"`
+	Price  money.Money `json:"price"`
+	// EffectivePrice есть только в списках каталога
+	EffectivePrice *money.Money `json:"effective_price,omitempty"`
+	SaleEndsAt     *time.Time   `json:"sale_ends_at,omitempty"`
+}
+
+type PaginationMeta struct {
+	Total      int64 `json:"total" example:"100"`
+	Page       int   `json:"page" example:"1"`
+	Limit      int   `json:"limit" example:"10"`
+	TotalPages int   `json:"totalPages" example:"10"`
 }
 
 type PaginatedBooksResponse struct {
@@ -54,3 +83,590 @@ type ErrorResponse struct {
 type UpdateRoleRequest struct {
 	NewRole string `json:"new_role" example:"admin"`
 }
+
+type ImportJobResponse struct {
+	ID            uint                    `json:"id" example:"1"`
+	Status        string                  `json:"status" example:"running"`
+	Format        string                  `json:"format" example:"csv"`
+	DryRun        bool                    `json:"dry_run" example:"false"`
+	TotalRows     int                     `json:"total_rows" example:"1200"`
+	ValidRows     int                     `json:"valid_rows" example:"1200"`
+	ProcessedRows int                     `json:"processed_rows" example:"500"`
+	Errors        []models.ImportRowError `json:"errors"`
+	Message       string                  `json:"message" example:""`
+	CreatedAt     time.Time               `json:"created_at"`
+	FinishedAt    *time.Time              `json:"finished_at"`
+}
+
+type LibraryImportResponse struct {
+	ID      uint   `json:"id" example:"1"`
+	Source  string `json:"source" example:"goodreads"`
+	Summary struct {
+		Matched   int `json:"matched" example:"120"`
+		Ambiguous int `json:"ambiguous" example:"4"`
+		Unmatched int `json:"unmatched" example:"17"`
+		Skipped   int `json:"skipped" example:"0"`
+		Failed    int `json:"failed" example:"0"`
+	} `json:"summary"`
+	Rows      []models.LibraryImportRow `json:"rows"`
+	CreatedAt time.Time                 `json:"created_at"`
+}
+
+type ResolveLibraryRowRequest struct {
+	BookID *uint `json:"book_id" example:"12"`
+}
+
+type ReviewResponse struct {
+	ID             uint       `json:"id" example:"1"`
+	BookID         uint       `json:"book_id" example:"1"`
+	UserID         uint       `json:"user_id" example:"1"`
+	Rating         int        `json:"rating" example:"5"`
+	Text           string     `json:"text" example:"Лучшая книга по Go"`
+	Status         string     `json:"status" example:"approved"`
+	HelpfulCount   int        `json:"helpful_count" example:"3"`
+	ModerationNote string     `json:"moderation_note,omitempty" example:""`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type PaginatedReviewsResponse struct {
+	Data []ReviewResponse `json:"data"`
+	Meta PaginationMeta   `json:"meta"`
+}
+
+type ReadingStatusResponse struct {
+	BookID      uint       `json:"book_id" example:"1"`
+	Shelf       string     `json:"shelf" example:"reading"`
+	CurrentPage int        `json:"current_page" example:"120"`
+	Percent     int        `json:"percent" example:"32"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	ReadCount   int        `json:"read_count" example:"1"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ShelfItemResponse struct {
+	Book   BookBriefResponse     `json:"book"`
+	Status ReadingStatusResponse `json:"status"`
+}
+
+type PaginatedShelfResponse struct {
+	Data []ShelfItemResponse `json:"data"`
+	Meta PaginationMeta      `json:"meta"`
+}
+
+type CollectionResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	OwnerID     uint      `json:"owner_id" example:"1"`
+	Name        string    `json:"name" example:"Go для новичков"`
+	Description string    `json:"description" example:"Что читать в первый месяц"`
+	Visibility  string    `json:"visibility" example:"unlisted"`
+	ShareURL    string    `json:"share_url,omitempty" example:"/collections/shared/4f1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CollectionDetailResponse struct {
+	CollectionResponse
+	CanEdit       bool                   `json:"can_edit" example:"true"`
+	Collaborators []uint                 `json:"collaborators,omitempty"`
+	Books         PaginatedBooksResponse `json:"books"`
+}
+
+type PaginatedCollectionsResponse struct {
+	Data []CollectionResponse `json:"data"`
+	Meta PaginationMeta       `json:"meta"`
+}
+
+type CollectionBookRequest struct {
+	BookID uint `json:"book_id" example:"12"`
+}
+
+type ReorderCollectionRequest struct {
+	BookIDs []uint `json:"book_ids"`
+}
+
+type CollaboratorRequest struct {
+	UserID uint `json:"user_id" example:"7"`
+}
+
+type NoteResponse struct {
+	ID         uint      `json:"id" example:"1"`
+	BookID     uint      `json:"book_id" example:"1"`
+	UserID     uint      `json:"user_id" example:"1"`
+	Kind       string    `json:"kind" example:"highlight"`
+	Quote      string    `json:"quote,omitempty" example:"Don't communicate by sharing memory"`
+	Text       string    `json:"text,omitempty" example:"Главная идея главы про каналы"`
+	Page       *int      `json:"page,omitempty" example:"231"`
+	Location   string    `json:"location,omitempty" example:"epubcfi(/6/14!/4/2/1:0)"`
+	Tags       []string  `json:"tags"`
+	Visibility string    `json:"visibility" example:"private"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type PaginatedNotesResponse struct {
+	Data []NoteResponse `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+type RecommendationResponse struct {
+	Book        BookBriefResponse `json:"book"`
+	Score       float64           `json:"score" example:"0.42"`
+	Reason      string            `json:"reason" example:"co_favourite"`
+	BecauseOf   BookBriefResponse `json:"because_of"`
+	Explanation string            `json:"explanation" example:"Читатели, которые добавили в избранное «Go in Action», добавляли и эту книгу"`
+}
+
+type RecommendationsResponse struct {
+	Data []RecommendationResponse `json:"data"`
+}
+
+type SimilarBookResponse struct {
+	Book  BookBriefResponse `json:"book"`
+	Score float64           `json:"score" example:"0.37"`
+}
+
+type SimilarBooksResponse struct {
+	Data []SimilarBookResponse `json:"data"`
+}
+
+type PopularBookResponse struct {
+	Book       BookBriefResponse `json:"book"`
+	Views      int64             `json:"views" example:"340"`
+	Favourites int64             `json:"favourites" example:"12"`
+	Score      float64           `json:"score" example:"187.5"`
+}
+
+type PaginatedPopularBooksResponse struct {
+	Data []PopularBookResponse `json:"data"`
+	Meta PaginationMeta        `json:"meta"`
+}
+
+type NotificationResponse struct {
+	ID        uint         `json:"id" example:"1"`
+	Type      string       `json:"type" example:"price_drop"`
+	BookID    uint         `json:"book_id" example:"1"`
+	Title     string       `json:"title" example:"Цена снижена: «Dune»"`
+	Body      string       `json:"body" example:"Цена книги «Dune» из вашего избранного снизилась с 49.99 до 39.99"`
+	OldPrice  *money.Money `json:"old_price,omitempty"`
+	NewPrice  *money.Money `json:"new_price,omitempty"`
+	Read      bool         `json:"read" example:"false"`
+	ReadAt    *time.Time   `json:"read_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type PaginatedNotificationsResponse struct {
+	Data   []NotificationResponse `json:"data"`
+	Meta   PaginationMeta         `json:"meta"`
+	Unread int64                  `json:"unread" example:"3"`
+}
+
+type NotificationSettingsResponse struct {
+	PriceDrops  bool   `json:"price_drops" example:"true"`
+	NewArrivals bool   `json:"new_arrivals" example:"true"`
+	Email       string `json:"email,omitempty" example:"reader@example.com"`
+	WebhookURL  string `json:"webhook_url,omitempty" example:"https://example.com/hooks/bookshelf"`
+}
+
+type FollowResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	Type      string    `json:"type" example:"author"`
+	Target    string    `json:"target" example:"Ursula K. Le Guin"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FollowsResponse struct {
+	Data []FollowResponse `json:"data"`
+}
+
+type FeedActorResponse struct {
+	ID       uint   `json:"id" example:"2"`
+	Username string `json:"username" example:"john_doe"`
+}
+
+type FeedEventResponse struct {
+	ID           uint               `json:"id" example:"1"`
+	Type         string             `json:"type" example:"review_published"`
+	Actor        *FeedActorResponse `json:"actor,omitempty"`
+	Book         *BookBriefResponse `json:"book,omitempty"`
+	CollectionID *uint              `json:"collection_id,omitempty" example:"3"`
+	ReviewID     *uint              `json:"review_id,omitempty" example:"5"`
+	Summary      string             `json:"summary" example:"john_doe написал(а) рецензию на книгу «Go in Action»"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+type PaginatedFeedResponse struct {
+	Data []FeedEventResponse `json:"data"`
+	Meta PaginationMeta      `json:"meta"`
+}
+
+type PrivacySettingsResponse struct {
+	ShareFavourites  bool `json:"share_favourites" example:"false"`
+	ShareReviews     bool `json:"share_reviews" example:"true"`
+	ShareCollections bool `json:"share_collections" example:"true"`
+}
+
+type GoalResponse struct {
+	ID        uint   `json:"id" example:"1"`
+	Title     string `json:"title" example:"30 книг за 2026"`
+	Metric    string `json:"metric" example:"books"`
+	Target    int    `json:"target" example:"30"`
+	StartDate string `json:"start_date" example:"2026-01-01"`
+	EndDate   string `json:"end_date" example:"2026-12-31"`
+	Current   int    `json:"current" example:"12"`
+	Expected  int    `json:"expected" example:"23"`
+	Percent   int    `json:"percent" example:"40"`
+	Completed bool   `json:"completed" example:"false"`
+	OnTrack   bool   `json:"on_track" example:"false"`
+	DaysLeft  int    `json:"days_left" example:"75"`
+}
+
+type GoalsResponse struct {
+	Data []GoalResponse `json:"data"`
+}
+
+type GenreCountResponse struct {
+	Genre string `json:"genre" example:"Fantasy"`
+	Books int    `json:"books" example:"7"`
+}
+
+type LongestBookResponse struct {
+	Book  BookBriefResponse `json:"book"`
+	Pages int               `json:"pages" example:"896"`
+}
+
+type YearInReviewResponse struct {
+	Year          int                  `json:"year" example:"2026"`
+	BooksRead     int                  `json:"books_read" example:"24"`
+	PagesRead     int                  `json:"pages_read" example:"8120"`
+	TopGenres     []GenreCountResponse `json:"top_genres"`
+	LongestBook   *LongestBookResponse `json:"longest_book,omitempty"`
+	AverageRating *float64             `json:"average_rating,omitempty" example:"4.25"`
+	RatedBooks    int                  `json:"rated_books" example:"16"`
+	Goals         []GoalResponse       `json:"goals"`
+}
+
+type AvailabilityResponse struct {
+	Total     int64 `json:"total" example:"3"`
+	Available int64 `json:"available" example:"1"`
+}
+
+type CopyResponse struct {
+	ID        uint   `json:"id" example:"4"`
+	BookID    uint   `json:"book_id" example:"1"`
+	Barcode   string `json:"barcode" example:"LIB-000123"`
+	Location  string `json:"location,omitempty" example:"Шкаф 2, полка 3"`
+	Condition string `json:"condition" example:"good"`
+	Status    string `json:"status" example:"available"`
+}
+
+type CopiesResponse struct {
+	Data         []CopyResponse       `json:"data"`
+	Availability AvailabilityResponse `json:"availability"`
+}
+
+type LoanResponse struct {
+	ID           uint              `json:"id" example:"1"`
+	Book         BookBriefResponse `json:"book"`
+	CopyID       uint              `json:"copy_id" example:"4"`
+	Barcode      string            `json:"barcode" example:"LIB-000123"`
+	UserID       uint              `json:"user_id" example:"2"`
+	CheckedOutAt time.Time         `json:"checked_out_at"`
+	DueAt        time.Time         `json:"due_at"`
+	ReturnedAt   *time.Time        `json:"returned_at,omitempty"`
+	Renewals     int               `json:"renewals" example:"0"`
+	Overdue      bool              `json:"overdue" example:"false"`
+}
+
+type PaginatedLoansResponse struct {
+	Data []LoanResponse `json:"data"`
+	Meta PaginationMeta `json:"meta"`
+}
+
+type HoldResponse struct {
+	ID     uint              `json:"id" example:"1"`
+	Book   BookBriefResponse `json:"book"`
+	Status string            `json:"status" example:"waiting"`
+	// Position - место в очереди, пока бронь ждёт экземпляр
+	Position int `json:"position,omitempty" example:"2"`
+	// Barcode - отложенный экземпляр, его нужно забрать до expires_at
+	Barcode   string     `json:"barcode,omitempty" example:"LIB-000123"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type HoldsResponse struct {
+	Data []HoldResponse `json:"data"`
+}
+
+type FineEntryResponse struct {
+	ID   uint   `json:"id" example:"1"`
+	Type string `json:"type" example:"charge"`
+	// Amount: начисление положительное, оплата и списание отрицательные
+	Amount    money.Money `json:"amount"`
+	LoanID    *uint       `json:"loan_id,omitempty" example:"7"`
+	Note      string      `json:"note,omitempty" example:"Просрочка «Dune»: дни 1-3"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type FineAccountResponse struct {
+	// Balance - непогашенный долг
+	Balance money.Money         `json:"balance"`
+	Data    []FineEntryResponse `json:"data"`
+	Meta    PaginationMeta      `json:"meta"`
+}
+
+type FineBalanceResponse struct {
+	UserID   uint        `json:"user_id" example:"2"`
+	Username string      `json:"username" example:"john_doe"`
+	Balance  money.Money `json:"balance"`
+}
+
+type PaginatedFineBalancesResponse struct {
+	Data []FineBalanceResponse `json:"data"`
+	Meta PaginationMeta        `json:"meta"`
+}
+
+type CartLineResponse struct {
+	Book      BookBriefResponse `json:"book"`
+	Quantity  int               `json:"quantity" example:"2"`
+	UnitPrice money.Money       `json:"unit_price"`
+	LineTotal money.Money       `json:"line_total"`
+}
+
+type CartResponse struct {
+	Items []CartLineResponse `json:"items"`
+	// Count - число экземпляров в корзине
+	Count int         `json:"count" example:"2"`
+	Total money.Money `json:"total"`
+}
+
+type OrderItemResponse struct {
+	BookID    uint        `json:"book_id" example:"1"`
+	Title     string      `json:"title" example:"Dune"`
+	Author    string      `json:"author" example:"Frank Herbert"`
+	UnitPrice money.Money `json:"unit_price"`
+	Quantity  int         `json:"quantity" example:"2"`
+	LineTotal money.Money `json:"line_total"`
+}
+
+type OrderResponse struct {
+	ID         uint                `json:"id" example:"1"`
+	UserID     uint                `json:"user_id" example:"1"`
+	Status     string              `json:"status" example:"pending"`
+	Items      []OrderItemResponse `json:"items"`
+	Total      money.Money         `json:"total"`
+	CouponCode string              `json:"coupon_code,omitempty" example:"GOPHER20"`
+	// ReservedUntil - до какого момента товар отложен под неоплаченный заказ
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	FulfilledAt   *time.Time `json:"fulfilled_at,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+}
+
+type PaginatedOrdersResponse struct {
+	Data []OrderResponse `json:"data"`
+	Meta PaginationMeta  `json:"meta"`
+}
+
+type PaymentResponse struct {
+	ID       uint   `json:"id" example:"1"`
+	OrderID  uint   `json:"order_id" example:"1"`
+	Provider string `json:"provider" example:"stripe"`
+	IntentID string `json:"intent_id" example:"pi_3PqK2eLk"`
+	// ClientSecret передаётся в платёжную форму провайдера (Stripe.js или /payments/fake/confirm)
+	ClientSecret  string      `json:"client_secret,omitempty" example:"pi_3PqK2eLk_secret_9fQ"`
+	Amount        money.Money `json:"amount"`
+	Status        string      `json:"status" example:"pending"`
+	FailureReason string      `json:"failure_reason,omitempty" example:"Your card was declined."`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type FakePaymentConfirmRequest struct {
+	ClientSecret string `json:"client_secret" example:"fake_pi_1_secret_3b1f"`
+	// Succeed: true - оплата проходит, false - банк отказывает
+	Succeed bool `json:"succeed" example:"true"`
+}
+
+type ExchangeRateResponse struct {
+	Currency string `json:"currency" example:"EUR"`
+	// Rate - сколько единиц валюты за одну единицу базовой
+	Rate      string    `json:"rate" example:"0.9215"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExchangeRatesResponse struct {
+	Base  string                 `json:"base" example:"USD"`
+	Rates []ExchangeRateResponse `json:"rates"`
+}
+
+type BookPricesResponse struct {
+	BookID uint `json:"book_id" example:"1"`
+	// Base - цена книги в её собственной валюте
+	Base money.Money `json:"base"`
+	// Prices - цены из прайс-листа; в остальных валютах цена пересчитывается по курсу
+	Prices []money.Money `json:"prices"`
+}
+
+type DiscountResponse struct {
+	ID         uint         `json:"id" example:"1"`
+	Name       string       `json:"name" example:"Неделя Go"`
+	Target     string       `json:"target" example:"genre"`
+	Value      string       `json:"value" example:"Programming"`
+	Kind       string       `json:"kind" example:"percent"`
+	Percent    int          `json:"percent,omitempty" example:"20"`
+	Amount     *money.Money `json:"amount,omitempty"`
+	StartsAt   time.Time    `json:"starts_at"`
+	EndsAt     *time.Time   `json:"ends_at,omitempty"`
+	Code       string       `json:"code,omitempty" example:"GOPHER20"`
+	UsageLimit int          `json:"usage_limit" example:"100"`
+	UsedCount  int          `json:"used_count" example:"12"`
+	// Active - действует ли скидка сейчас
+	Active bool `json:"active" example:"true"`
+}
+
+type PaginatedDiscountsResponse struct {
+	Data []DiscountResponse `json:"data"`
+	Meta PaginationMeta     `json:"meta"`
+}
+
+type StockResponse struct {
+	BookID            uint   `json:"book_id" example:"1"`
+	Title             string `json:"title" example:"Dune"`
+	OnHand            int    `json:"on_hand" example:"12"`
+	Reserved          int    `json:"reserved" example:"2"`
+	Available         int    `json:"available" example:"10"`
+	LowStockThreshold int    `json:"low_stock_threshold" example:"3"`
+	// Low - доступный остаток не выше порога
+	Low       bool      `json:"low" example:"false"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PaginatedStockResponse struct {
+	Data []StockResponse `json:"data"`
+	Meta PaginationMeta  `json:"meta"`
+}
+
+type StockMovementResponse struct {
+	ID     uint `json:"id" example:"1"`
+	BookID uint `json:"book_id" example:"1"`
+	// Delta - изменение остатка, OnHand - остаток после движения
+	Delta     int       `json:"delta" example:"-2"`
+	OnHand    int       `json:"on_hand" example:"10"`
+	Reason    string    `json:"reason" example:"sale"`
+	Note      string    `json:"note,omitempty" example:"Поставка от издательства"`
+	OrderID   *uint     `json:"order_id,omitempty" example:"3"`
+	CreatedBy *uint     `json:"created_by,omitempty" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PaginatedStockMovementsResponse struct {
+	Data []StockMovementResponse `json:"data"`
+	Meta PaginationMeta          `json:"meta"`
+}
+
+type FileResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	BookID      uint      `json:"book_id" example:"1"`
+	Format      string    `json:"format" example:"epub"`
+	Filename    string    `json:"filename" example:"master-i-margarita.epub"`
+	ContentType string    `json:"content_type" example:"application/epub+zip"`
+	Size        int64     `json:"size" example:"524288"`
+	SHA256      string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EPUBMetadataResponse - метаданные, прочитанные из OPF
+type EPUBMetadataResponse struct {
+	Title       string   `json:"title" example:"Мастер и Маргарита"`
+	Authors     []string `json:"authors" example:"Михаил Булгаков"`
+	ISBN        string   `json:"isbn,omitempty" example:"978-5-17-090630-7"`
+	Publisher   string   `json:"publisher,omitempty" example:"АСТ"`
+	Language    string   `json:"language,omitempty" example:"ru"`
+	Description string   `json:"description,omitempty"`
+	Subjects    []string `json:"subjects,omitempty" example:"Роман"`
+}
+
+type FileUploadResponse struct {
+	File     FileResponse          `json:"file"`
+	Cover    *FileResponse         `json:"cover,omitempty"`
+	Metadata *EPUBMetadataResponse `json:"metadata,omitempty"`
+	// Prefilled - пустые поля книги, заполненные из файла
+	Prefilled []string `json:"prefilled" example:"publisher"`
+	// Mismatches - расхождения файла с карточкой книги, карточка не меняется
+	Mismatches []string `json:"mismatches"`
+}
+
+type DownloadLinkResponse struct {
+	URL       string    `json:"url" example:"/files/1?expires=1767225600&signature=Zm9v"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ReadingProgressResponse struct {
+	BookID uint `json:"book_id" example:"1"`
+	// Locator - EPUB CFI, XPointer или страница KOReader, в зависимости от locator_type
+	Locator     string    `json:"locator" example:"epubcfi(/6/14!/4/2/10/2:352)"`
+	LocatorType string    `json:"locator_type" example:"cfi"`
+	Percent     float64   `json:"percent" example:"42.5"`
+	Device      string    `json:"device" example:"Kobo Libra 2"`
+	DeviceID    string    `json:"device_id" example:"kobo-5f2c"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SaveProgressResponse struct {
+	ReadingProgressResponse
+	// Applied - false, если на другом устройстве книгу читали позже: тогда возвращается та позиция
+	Applied bool `json:"applied" example:"true"`
+}
+
+type ReadingProgressEntryResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	Locator     string    `json:"locator" example:"epubcfi(/6/14!/4/2/10/2:352)"`
+	LocatorType string    `json:"locator_type" example:"cfi"`
+	Percent     float64   `json:"percent" example:"42.5"`
+	Device      string    `json:"device" example:"Kobo Libra 2"`
+	DeviceID    string    `json:"device_id" example:"kobo-5f2c"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Applied     bool      `json:"applied" example:"true"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PaginatedProgressHistoryResponse struct {
+	Data []ReadingProgressEntryResponse `json:"data"`
+	Meta PaginationMeta                 `json:"meta"`
+}
+
+// KOSyncErrorResponse - ошибка в формате сервера синхронизации KOReader
+type KOSyncErrorResponse struct {
+	Code    int    `json:"code" example:"2001"`
+	Message string `json:"message" example:"Unauthorized"`
+}
+
+type KOSyncUserRequest struct {
+	Username string `json:"username" example:"john_doe"`
+	Password string `json:"password" example:"5f4dcc3b5aa765d61d8327deb882cf99"`
+}
+
+type KOSyncAuthResponse struct {
+	Authorized string `json:"authorized" example:"OK"`
+}
+
+type KOSyncUpdateResponse struct {
+	Document  string `json:"document" example:"0b229176d4e8db7f6d2b5a4952368d7a"`
+	Timestamp int64  `json:"timestamp" example:"1767225600"`
+}
+
+type KOSyncProgressResponse struct {
+	Document   string  `json:"document" example:"0b229176d4e8db7f6d2b5a4952368d7a"`
+	Progress   string  `json:"progress,omitempty" example:"/body/DocFragment[20]/body/p[22]/img.0"`
+	Percentage float64 `json:"percentage" example:"0.425"`
+	Device     string  `json:"device" example:"Kobo Libra 2"`
+	DeviceID   string  `json:"device_id" example:"0FE37C4C7A5A4B1DB4B45F1A7C8E4D2A"`
+	Timestamp  int64   `json:"timestamp" example:"1767225600"`
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:     "text/csv; charset=utf-8",
+	service.ExportFormatNDJSON:  "application/x-ndjson",
+	service.ExportFormatMARC:    "application/marc",
+	service.ExportFormatMARCXML: "application/marcxml+xml",
+}
+
+var exportExtensions = map[string]string{
+	service.ExportFormatCSV:     "csv",
+	service.ExportFormatNDJSON:  "ndjson",
+	service.ExportFormatMARC:    "mrc",
+	service.ExportFormatMARCXML: "xml",
+}
+
+// ExportBooksHandler godoc
+// @Summary Выгрузка каталога
+// @Description Потоковая выгрузка всех книг в CSV, NDJSON или MARC21 без ограничения limit. Поддерживает фильтр по жанру и gzip
+// @Tags Exports
+// @Security ApiKeyAuth
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/marc
+// @Produce application/marcxml+xml
+// @Param format query string false "Формат выгрузки (csv, ndjson, marc, marcxml), по умолчанию csv"
+// @Param genre query string false "Фильтр по жанру"
+// @Param gzip query bool false "Отдать файл .gz"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/exports/books [get]
+func (h *ExportHandler) ExportBooksHandler(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = service.ExportFormatCSV
+	}
+	if !service.IsExportFormat(format) {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid format, must be 'csv', 'ndjson', 'marc' or 'marcxml'"})
+		return
+	}
+	genre := r.URL.Query().Get("genre")
+	asFile, _ := strconv.ParseBool(r.URL.Query().Get("gzip"))
+
+	filename := fmt.Sprintf("books-%s.%s", time.Now().Format("20060102"), exportExtensions[format])
+	var out io.Writer = w
+
+	switch {
+	case asFile:
+		filename += ".gz"
+		w.Header().Set("Content-Type", "application/gzip")
+	case strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"):
+		w.Header().Set("Content-Type", exportContentTypes[format])
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+	default:
+		w.Header().Set("Content-Type", exportContentTypes[format])
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if w.Header().Get("Content-Encoding") == "gzip" || asFile {
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	}
+
+	w.WriteHeader(http.StatusOK)
+	// Заголовки уже отправлены, поэтому ошибку посреди потока можно только залогировать
+	if count, err := h.exportService.ExportBooks(out, format, genre); err != nil {
+		log.Printf("export of books failed after %d rows: %s", count, err.Error())
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) ExportBooks(w io.Writer, format, genre string) (int, error) {
+	args := m.Called(w, format, genre)
+	if fn, ok := args.Get(0).(func(io.Writer) int); ok {
+		return fn(w), args.Error(1)
+	}
+	return args.Int(0), args.Error(1)
+}
+
+func TestExportHandler_ExportBooksHandler_CSV(t *testing.T) {
+	mockService := new(MockExportService)
+	handler := NewExportHandler(mockService)
+
+	// Настройка мока
+	write := func(w io.Writer) int {
+		io.WriteString(w, "id,title\n1,Book\n")
+		return 1
+	}
+	mockService.On("ExportBooks", mock.Anything, "csv", "Fiction").Return(write, nil)
+
+	// Создание запроса
+	req, _ := http.NewRequest("GET", "/admin/exports/books?format=csv&genre=Fiction", nil)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.ExportBooksHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), ".csv")
+	assert.Equal(t, "id,title\n1,Book\n", rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestExportHandler_ExportBooksHandler_Gzip(t *testing.T) {
+	mockService := new(MockExportService)
+	handler := NewExportHandler(mockService)
+
+	// Настройка мока
+	write := func(w io.Writer) int {
+		io.WriteString(w, "{\"id\":1}\n")
+		return 1
+	}
+	mockService.On("ExportBooks", mock.Anything, "ndjson", "").Return(write, nil)
+
+	// Создание запроса
+	req, _ := http.NewRequest("GET", "/admin/exports/books?format=ndjson&gzip=true", nil)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.ExportBooksHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/gzip", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), ".ndjson.gz")
+
+	gz, err := gzip.NewReader(rr.Body)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(gz)
+	assert.Equal(t, "{\"id\":1}\n", string(body))
+	mockService.AssertExpectations(t)
+}
+
+func TestExportHandler_ExportBooksHandler_InvalidFormat(t *testing.T) {
+	mockService := new(MockExportService)
+	handler := NewExportHandler(mockService)
+
+	// Создание запроса
+	req, _ := http.NewRequest("GET", "/admin/exports/books?format=xlsx", nil)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.ExportBooksHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "ExportBooks", mock.Anything, mock.Anything, mock.Anything)
+}
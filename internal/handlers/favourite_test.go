@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
 	"bookshelf/pkg/utils"
 	"context"
 	"net/http"
@@ -75,7 +76,7 @@ func TestFavouriteHandler_GetFavourites_Success(t *testing.T) {
 			Title:  "Fav Book 1",
 			Author: "Author 1",
 			Genre:  "Fiction",
-			Price:  19.99,
+			Price:  money.New(1999, "USD"),
 		},
 	}
 	mockService.On("GetFavourites", uint(1), 1, 10).Return(briefs, int64(1), nil)
@@ -98,7 +99,7 @@ func TestFavouriteHandler_GetFavourites_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	expected := `{
 		"data": [
-			{"id":1, "title":"Fav Book 1", "author":"Author 1", "genre":"Fiction", "price":19.99}
+			{"id":1, "title":"Fav Book 1", "author":"Author 1", "genre":"Fiction", "price":{"amount":"19.99","currency":"USD"}}
 		],
 		"meta": {
 			"total":1,
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/epub"
+	"bookshelf/pkg/utils"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxFileSize = 64 << 20
+
+type FileHandler struct {
+	fileService service.FileService
+}
+
+func NewFileHandler(fileService service.FileService) *FileHandler {
+	return &FileHandler{fileService: fileService}
+}
+
+func toFileResponse(file models.BookFile) FileResponse {
+	return FileResponse{
+		ID:          file.ID,
+		BookID:      file.BookID,
+		Format:      file.Format,
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		SHA256:      file.SHA256,
+		UpdatedAt:   file.UpdatedAt,
+	}
+}
+
+func toEPUBMetadataResponse(meta epub.Metadata) *EPUBMetadataResponse {
+	authors := meta.Authors
+	if authors == nil {
+		authors = []string{}
+	}
+	return &EPUBMetadataResponse{
+		Title:       meta.Title,
+		Authors:     authors,
+		ISBN:        meta.ISBN,
+		Publisher:   meta.Publisher,
+		Language:    meta.Language,
+		Description: meta.Description,
+		Subjects:    meta.Subjects,
+	}
+}
+
+// UploadFileHandler godoc
+// @Summary Загрузка файла книги
+// @Description Принимает EPUB, PDF или изображение обложки, формат определяется по содержимому. Файл того же формата заменяется,
+// @Description выданные на него ссылки перестают действовать. Из EPUB читаются метаданные и обложка: пустые поля книги
+// @Description (isbn, publisher, subjects) заполняются, расхождения с заполненными возвращаются в mismatches
+// @Tags Files
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param file formData file true "EPUB, PDF, JPEG, PNG, GIF или WebP, до 64 МБ"
+// @Success 201 {object} FileUploadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/files [post]
+func (h *FileHandler) UploadFileHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	data, filename, err := readUpload(w, r, maxFileSize)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid upload: " + err.Error()})
+		return
+	}
+
+	upload, err := h.fileService.Upload(r.Context(), bookID, filename, data)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := FileUploadResponse{
+		File:       toFileResponse(upload.File),
+		Prefilled:  upload.Prefilled,
+		Mismatches: upload.Mismatches,
+	}
+	if upload.Cover != nil {
+		cover := toFileResponse(*upload.Cover)
+		response.Cover = &cover
+	}
+	if upload.Metadata != nil {
+		response.Metadata = toEPUBMetadataResponse(*upload.Metadata)
+	}
+	if response.Prefilled == nil {
+		response.Prefilled = []string{}
+	}
+	if response.Mismatches == nil {
+		response.Mismatches = []string{}
+	}
+	utils.JSONResponse(w, http.StatusCreated, response)
+}
+
+// GetFilesHandler godoc
+// @Summary Файлы книги
+// @Description Загруженные форматы книги. Скачивание - по подписанной ссылке
+// @Tags Files
+// @Produce json
+// @Param id path int true "ID книги"
+// @Success 200 {array} FileResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/files [get]
+func (h *FileHandler) GetFilesHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	files, err := h.fileService.ListFiles(bookID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get files"})
+		return
+	}
+
+	response := make([]FileResponse, 0, len(files))
+	for _, file := range files {
+		response = append(response, toFileResponse(file))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// DeleteFileHandler godoc
+// @Summary Удаление файла книги
+// @Tags Files
+// @Security ApiKeyAuth
+// @Param id path int true "ID книги"
+// @Param format path string true "Формат (epub, pdf, cover)"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/files/{format} [delete]
+func (h *FileHandler) DeleteFileHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	if err := h.fileService.DeleteFile(r.Context(), bookID, chi.URLParam(r, "format")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateDownloadLinkHandler godoc
+// @Summary Ссылка на скачивание
+// @Description Короткоживущая подписанная ссылка на файл книги. Доступна купившим книгу и администраторам
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param format path string true "Формат (epub, pdf)"
+// @Success 200 {object} DownloadLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/files/{format}/link [post]
+func (h *FileHandler) CreateDownloadLinkHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	url, expires, err := h.fileService.DownloadURL(userID, currentRole(r), bookID, chi.URLParam(r, "format"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, DownloadLinkResponse{URL: url, ExpiresAt: expires})
+}
+
+// DownloadFileHandler godoc
+// @Summary Скачивание файла
+// @Description Отдаёт файл по подписанной ссылке. Поддерживает Range-запросы для докачки
+// @Tags Files
+// @Produce application/epub+zip,application/pdf
+// @Param id path int true "ID файла"
+// @Param expires query int true "Срок действия ссылки, unix-время"
+// @Param signature query string true "Подпись"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /files/{id} [get]
+func (h *FileHandler) DownloadFileHandler(w http.ResponseWriter, r *http.Request) {
+	fileID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid file ID"})
+		return
+	}
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid expires"})
+		return
+	}
+
+	file, object, err := h.fileService.OpenSigned(r.Context(), fileID, expires, r.URL.Query().Get("signature"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer object.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename}))
+	w.Header().Set("ETag", strconv.Quote(file.SHA256))
+	w.Header().Set("Cache-Control", "private, no-transform")
+	http.ServeContent(w, r, file.Filename, object.ModTime, object)
+}
+
+// GetCoverHandler godoc
+// @Summary Обложка книги
+// @Description Загруженная или извлечённая из EPUB обложка. Ответ кэшируется, проверка по ETag
+// @Tags Files
+// @Produce image/jpeg,image/png,image/gif,image/webp
+// @Param id path int true "ID книги"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/cover [get]
+func (h *FileHandler) GetCoverHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	file, object, err := h.fileService.OpenCover(r.Context(), bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer object.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", strconv.Quote(file.SHA256))
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeContent(w, r, "", object.ModTime, object)
+}
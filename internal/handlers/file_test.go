@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/storage"
+	"bookshelf/pkg/utils"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFileService struct {
+	mock.Mock
+}
+
+func (m *MockFileService) Upload(ctx context.Context, bookID uint, filename string, data []byte) (service.FileUpload, error) {
+	args := m.Called(ctx, bookID, filename, data)
+	return args.Get(0).(service.FileUpload), args.Error(1)
+}
+
+func (m *MockFileService) ListFiles(bookID uint) ([]models.BookFile, error) {
+	args := m.Called(bookID)
+	return args.Get(0).([]models.BookFile), args.Error(1)
+}
+
+func (m *MockFileService) FilesByBooks(bookIDs []uint) (map[uint][]models.BookFile, error) {
+	args := m.Called(bookIDs)
+	return args.Get(0).(map[uint][]models.BookFile), args.Error(1)
+}
+
+func (m *MockFileService) DeleteFile(ctx context.Context, bookID uint, format string) error {
+	args := m.Called(ctx, bookID, format)
+	return args.Error(0)
+}
+
+func (m *MockFileService) DownloadURL(userID uint, role string, bookID uint, format string) (string, time.Time, error) {
+	args := m.Called(userID, role, bookID, format)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockFileService) OpenSigned(ctx context.Context, fileID uint, expires int64, signature string) (models.BookFile, *storage.Object, error) {
+	args := m.Called(ctx, fileID, expires, signature)
+	object, _ := args.Get(1).(*storage.Object)
+	return args.Get(0).(models.BookFile), object, args.Error(2)
+}
+
+func (m *MockFileService) OpenCover(ctx context.Context, bookID uint) (models.BookFile, *storage.Object, error) {
+	args := m.Called(ctx, bookID)
+	object, _ := args.Get(1).(*storage.Object)
+	return args.Get(0).(models.BookFile), object, args.Error(2)
+}
+
+// storedObject кладёт содержимое в локальное хранилище во временном каталоге и открывает его
+func storedObject(t *testing.T, content string) *storage.Object {
+	store, err := storage.NewLocal(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, store.Put(context.Background(), "books/1/file", strings.NewReader(content)))
+	object, err := store.Open(context.Background(), "books/1/file")
+	assert.NoError(t, err)
+	return object
+}
+
+func TestFileHandler_CreateDownloadLinkHandler_NotPurchased(t *testing.T) {
+	mockService := new(MockFileService)
+	handler := NewFileHandler(mockService)
+
+	// Настройка мока
+	mockService.On("DownloadURL", uint(2), "user", uint(1), "epub").
+		Return("", time.Time{}, errors.New("access denied: buy the book to download it"))
+
+	req, _ := http.NewRequest("POST", "/books/1/files/epub/link", nil)
+	req = withRouteAndUser(req, "id", "1", &utils.Claims{UserID: "2", Role: "user"})
+	chi.RouteContext(req.Context()).URLParams.Add("format", "epub")
+
+	rr := httptest.NewRecorder()
+	handler.CreateDownloadLinkHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "buy the book")
+	mockService.AssertExpectations(t)
+}
+
+func TestFileHandler_DownloadFileHandler_Range(t *testing.T) {
+	mockService := new(MockFileService)
+	handler := NewFileHandler(mockService)
+
+	// Настройка мока
+	file := models.BookFile{ID: 7, Filename: "Мастер и Маргарита.epub", ContentType: "application/epub+zip", SHA256: "abc"}
+	mockService.On("OpenSigned", mock.Anything, uint(7), int64(1767225600), "sig").
+		Return(file, storedObject(t, "0123456789"), nil)
+
+	req, _ := http.NewRequest("GET", "/files/7?expires=1767225600&signature=sig", nil)
+	req.Header.Set("Range", "bytes=2-5")
+	req = withRouteAndUser(req, "id", "7", nil)
+
+	rr := httptest.NewRecorder()
+	handler.DownloadFileHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "2345", rr.Body.String())
+	assert.Equal(t, "bytes 2-5/10", rr.Header().Get("Content-Range"))
+	assert.Equal(t, "application/epub+zip", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment; filename*=utf-8''")
+	mockService.AssertExpectations(t)
+}
+
+func TestFileHandler_GetCoverHandler_NotModified(t *testing.T) {
+	mockService := new(MockFileService)
+	handler := NewFileHandler(mockService)
+
+	// Настройка мока
+	file := models.BookFile{ID: 8, BookID: 1, Format: models.FileCover, ContentType: "image/png", SHA256: "abc"}
+	mockService.On("OpenCover", mock.Anything, uint(1)).Return(file, storedObject(t, "png"), nil)
+
+	req, _ := http.NewRequest("GET", "/books/1/cover", nil)
+	req.Header.Set("If-None-Match", `"abc"`)
+	req = withRouteAndUser(req, "id", "1", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetCoverHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type FineHandler struct {
+	fineService service.FineService
+}
+
+func NewFineHandler(fineService service.FineService) *FineHandler {
+	return &FineHandler{fineService: fineService}
+}
+
+func toFineEntryResponse(entry models.FineEntry) FineEntryResponse {
+	return FineEntryResponse{
+		ID:        entry.ID,
+		Type:      entry.Type,
+		Amount:    money.New(entry.Amount, entry.Currency),
+		LoanID:    entry.LoanID,
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func (h *FineHandler) writeAccount(w http.ResponseWriter, r *http.Request, userID uint) {
+	page, limit := parsePagination(r, 20)
+	balance, entries, total, err := h.fineService.GetAccount(userID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get fines"})
+		return
+	}
+
+	response := FineAccountResponse{
+		Balance: money.New(balance, h.fineService.Currency()),
+		Data:    make([]FineEntryResponse, 0, len(entries)),
+		Meta:    newPaginationMeta(total, page, limit),
+	}
+	for _, entry := range entries {
+		response.Data = append(response.Data, toFineEntryResponse(entry))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// GetMyFinesHandler godoc
+// @Summary Мои штрафы
+// @Description Долг и журнал штрафов: начисления за просрочку, оплаты и списания, новые первыми
+// @Tags Fines
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Записей на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} FineAccountResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/fines [get]
+func (h *FineHandler) GetMyFinesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	h.writeAccount(w, r, userID)
+}
+
+// GetBalancesHandler godoc
+// @Summary Должники
+// @Description Пользователи с непогашенными штрафами, крупный долг первым
+// @Tags Fines
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Пользователей на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedFineBalancesResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/fines [get]
+func (h *FineHandler) GetBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+	balances, total, err := h.fineService.ListBalances(page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get balances"})
+		return
+	}
+
+	response := PaginatedFineBalancesResponse{
+		Data: make([]FineBalanceResponse, 0, len(balances)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, b := range balances {
+		response.Data = append(response.Data, FineBalanceResponse{UserID: b.UserID, Username: b.Username, Balance: money.New(b.Balance, h.fineService.Currency())})
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// GetUserFinesHandler godoc
+// @Summary Штрафы пользователя
+// @Tags Fines
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userID path int true "ID пользователя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Записей на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} FineAccountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/fines/{userID} [get]
+func (h *FineHandler) GetUserFinesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(r, "userID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid user ID"})
+		return
+	}
+	h.writeAccount(w, r, userID)
+}
+
+// AddFineEntryHandler godoc
+// @Summary Принять оплату или списать штраф
+// @Description Журнал только дополняется: оплата (payment) и списание (waiver) уменьшают долг и не могут его превысить. Сумма - в валюте штрафов (базовой валюте каталога)
+// @Tags Fines
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param userID path int true "ID пользователя"
+// @Param input body service.FineAdjustmentRequest true "Оплата или списание"
+// @Success 201 {object} FineEntryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/fines/{userID}/entries [post]
+func (h *FineHandler) AddFineEntryHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pathID(r, "userID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid user ID"})
+		return
+	}
+	adminID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.FineAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	entry, err := h.fineService.AddEntry(adminID, userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toFineEntryResponse(entry))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFineService struct {
+	mock.Mock
+}
+
+func (m *MockFineService) ProcessOverdue(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockFineService) Currency() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockFineService) GetAccount(userID uint, page, limit int) (money.Amount, []models.FineEntry, int64, error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).(money.Amount), args.Get(1).([]models.FineEntry), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockFineService) ListBalances(page, limit int) ([]repository.FineBalance, int64, error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]repository.FineBalance), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockFineService) AddEntry(adminID, userID uint, req service.FineAdjustmentRequest) (models.FineEntry, error) {
+	args := m.Called(adminID, userID, req)
+	return args.Get(0).(models.FineEntry), args.Error(1)
+}
+
+func TestFineHandler_GetMyFinesHandler_Success(t *testing.T) {
+	mockService := new(MockFineService)
+	handler := NewFineHandler(mockService)
+
+	// Настройка мока
+	loanID := uint(7)
+	mockService.On("Currency").Return("EUR")
+	mockService.On("GetAccount", uint(2), 1, 20).Return(money.Amount(100), []models.FineEntry{
+		{ID: 2, UserID: 2, Type: models.FinePayment, Amount: -50, Currency: "EUR"},
+		{ID: 1, UserID: 2, Type: models.FineCharge, Amount: 150, Currency: "EUR", LoanID: &loanID, Note: "Просрочка «Dune»: дни 1-3"},
+	}, int64(2), nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/fines", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.GetMyFinesHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response FineAccountResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, money.New(100, "EUR"), response.Balance)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, money.New(-50, "EUR"), response.Data[0].Amount)
+	assert.Contains(t, rr.Body.String(), `"balance":{"amount":"1.00","currency":"EUR"}`)
+	assert.Equal(t, uint(7), *response.Data[1].LoanID)
+	assert.Equal(t, int64(2), response.Meta.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestFineHandler_AddFineEntryHandler_ExceedsBalance(t *testing.T) {
+	mockService := new(MockFineService)
+	handler := NewFineHandler(mockService)
+
+	reqBody := service.FineAdjustmentRequest{Type: models.FinePayment, Amount: "5.00"}
+	mockService.On("AddEntry", uint(1), uint(2), reqBody).
+		Return(models.FineEntry{}, errors.New("invalid amount, exceeds outstanding balance"))
+
+	req, _ := http.NewRequest("POST", "/admin/fines/2/entries", strings.NewReader(`{"type":"payment","amount":"5.00"}`))
+	req = withRouteAndUser(req, "userID", "2", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.AddFineEntryHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestFineHandler_GetBalancesHandler_Success(t *testing.T) {
+	mockService := new(MockFineService)
+	handler := NewFineHandler(mockService)
+
+	mockService.On("Currency").Return("USD")
+	mockService.On("ListBalances", 1, 20).Return([]repository.FineBalance{
+		{UserID: 2, Username: "john_doe", Balance: 1250},
+	}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/admin/fines", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.GetBalancesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedFineBalancesResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "john_doe", response.Data[0].Username)
+	assert.Equal(t, money.New(1250, "USD"), response.Data[0].Balance)
+	mockService.AssertExpectations(t)
+}
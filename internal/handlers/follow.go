@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+func toFollowResponse(f models.Follow) FollowResponse {
+	return FollowResponse{
+		ID:        f.ID,
+		Type:      f.TargetType,
+		Target:    f.Target,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+// GetFollowsHandler godoc
+// @Summary Подписки
+// @Tags Follows
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type query string false "Тип подписки" Enums(author, genre, user)
+// @Success 200 {object} FollowsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/follows [get]
+func (h *FollowHandler) GetFollowsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	follows, err := h.followService.ListFollows(userID, r.URL.Query().Get("type"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := FollowsResponse{Data: make([]FollowResponse, 0, len(follows))}
+	for _, f := range follows {
+		response.Data = append(response.Data, toFollowResponse(f))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// CreateFollowHandler godoc
+// @Summary Подписаться
+// @Description Подписка на автора или жанр: о новых книгах придёт уведомление, они же попадут в ленту. Имя сравнивается без учёта регистра и пунктуации. На пользователя подписываются по имени - его рецензии, подборки и избранное появятся в ленте
+// @Tags Follows
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.FollowRequest true "Подписка"
+// @Success 201 {object} FollowResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/me/follows [post]
+func (h *FollowHandler) CreateFollowHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.FollowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	follow, err := h.followService.Follow(userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toFollowResponse(follow))
+}
+
+// UnfollowHandler godoc
+// @Summary Отписаться
+// @Tags Follows
+// @Security ApiKeyAuth
+// @Param id path int true "ID подписки"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/follows/{id} [delete]
+func (h *FollowHandler) UnfollowHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid follow ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.followService.Unfollow(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFollowService struct {
+	mock.Mock
+}
+
+func (m *MockFollowService) Follow(userID uint, req service.FollowRequest) (models.Follow, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(models.Follow), args.Error(1)
+}
+
+func (m *MockFollowService) Unfollow(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockFollowService) ListFollows(userID uint, targetType string) ([]models.Follow, error) {
+	args := m.Called(userID, targetType)
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func TestFollowHandler_CreateFollowHandler_Success(t *testing.T) {
+	mockService := new(MockFollowService)
+	handler := NewFollowHandler(mockService)
+
+	// Настройка мока
+	reqBody := service.FollowRequest{Type: models.FollowAuthor, Target: "Ursula K. Le Guin"}
+	mockService.On("Follow", uint(1), reqBody).Return(models.Follow{
+		ID:         3,
+		UserID:     1,
+		TargetType: models.FollowAuthor,
+		Target:     "Ursula K. Le Guin",
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/users/me/follows", strings.NewReader(`{"type":"author","target":"Ursula K. Le Guin"}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateFollowHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response FollowResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, uint(3), response.ID)
+	assert.Equal(t, models.FollowAuthor, response.Type)
+	mockService.AssertExpectations(t)
+}
+
+func TestFollowHandler_CreateFollowHandler_Duplicate(t *testing.T) {
+	mockService := new(MockFollowService)
+	handler := NewFollowHandler(mockService)
+
+	reqBody := service.FollowRequest{Type: models.FollowGenre, Target: "Fantasy"}
+	mockService.On("Follow", uint(1), reqBody).Return(models.Follow{}, errors.New("follow already exists"))
+
+	req, _ := http.NewRequest("POST", "/users/me/follows", strings.NewReader(`{"type":"genre","target":"Fantasy"}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateFollowHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type GoalHandler struct {
+	goalService service.GoalService
+}
+
+func NewGoalHandler(goalService service.GoalService) *GoalHandler {
+	return &GoalHandler{goalService: goalService}
+}
+
+func toGoalResponse(p service.GoalProgress) GoalResponse {
+	return GoalResponse{
+		ID:        p.Goal.ID,
+		Title:     p.Goal.Title,
+		Metric:    p.Goal.Metric,
+		Target:    p.Goal.Target,
+		StartDate: p.Goal.StartDate.Format("2006-01-02"),
+		EndDate:   p.Goal.EndDate.Format("2006-01-02"),
+		Current:   p.Current,
+		Expected:  p.Expected,
+		Percent:   p.Percent,
+		Completed: p.Completed,
+		OnTrack:   p.OnTrack,
+		DaysLeft:  p.DaysLeft,
+	}
+}
+
+func toGoalResponses(goals []service.GoalProgress) []GoalResponse {
+	response := make([]GoalResponse, 0, len(goals))
+	for _, p := range goals {
+		response = append(response, toGoalResponse(p))
+	}
+	return response
+}
+
+// GetGoalsHandler godoc
+// @Summary Цели чтения
+// @Description Все цели пользователя с прогрессом, последние по периоду первыми
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} GoalsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/goals [get]
+func (h *GoalHandler) GetGoalsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	goals, err := h.goalService.ListGoals(userID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get goals"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, GoalsResponse{Data: toGoalResponses(goals)})
+}
+
+// CreateGoalHandler godoc
+// @Summary Поставить цель
+// @Description Цель по числу книг или страниц на год (year) или на период (start_date и end_date включительно). Прогресс считается по прочтениям, завершённым в периоде
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.GoalRequest true "Цель"
+// @Success 201 {object} GoalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/goals [post]
+func (h *GoalHandler) CreateGoalHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.GoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	goal, err := h.goalService.CreateGoal(userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toGoalResponse(goal))
+}
+
+// GetGoalHandler godoc
+// @Summary Прогресс цели
+// @Description expected - сколько нужно было прочитать к сегодняшнему дню, чтобы успеть при равномерном темпе
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID цели"
+// @Success 200 {object} GoalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/goals/{id} [get]
+func (h *GoalHandler) GetGoalHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid goal ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	goal, err := h.goalService.GetGoal(userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toGoalResponse(goal))
+}
+
+// UpdateGoalHandler godoc
+// @Summary Изменить цель
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID цели"
+// @Param input body service.GoalRequest true "Цель"
+// @Success 200 {object} GoalResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/goals/{id} [put]
+func (h *GoalHandler) UpdateGoalHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid goal ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.GoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	goal, err := h.goalService.UpdateGoal(userID, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toGoalResponse(goal))
+}
+
+// DeleteGoalHandler godoc
+// @Summary Удалить цель
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Param id path int true "ID цели"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/goals/{id} [delete]
+func (h *GoalHandler) DeleteGoalHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid goal ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.goalService.DeleteGoal(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetYearInReviewHandler godoc
+// @Summary Итоги года
+// @Description Прочитанные книги и страницы, любимые жанры, самая длинная книга, средняя оценка прочитанного и цели, пересекающиеся с годом
+// @Tags Goals
+// @Security ApiKeyAuth
+// @Produce json
+// @Param year query int false "Год (по умолчанию текущий)"
+// @Success 200 {object} YearInReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/year-in-review [get]
+func (h *GoalHandler) GetYearInReviewHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	year := time.Now().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid year"})
+			return
+		}
+		year = parsed
+	}
+
+	review, err := h.goalService.GetYearInReview(userID, year)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get year in review"})
+		return
+	}
+
+	response := YearInReviewResponse{
+		Year:          review.Year,
+		BooksRead:     review.BooksRead,
+		PagesRead:     review.PagesRead,
+		TopGenres:     make([]GenreCountResponse, 0, len(review.TopGenres)),
+		AverageRating: review.AverageRating,
+		RatedBooks:    review.RatedBooks,
+		Goals:         toGoalResponses(review.Goals),
+	}
+	for _, g := range review.TopGenres {
+		response.TopGenres = append(response.TopGenres, GenreCountResponse{Genre: g.Genre, Books: g.Books})
+	}
+	if review.LongestBook != nil {
+		response.LongestBook = &LongestBookResponse{
+			Book:  toBookBriefResponse(*review.LongestBook),
+			Pages: review.LongestBookPages,
+		}
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGoalService struct {
+	mock.Mock
+}
+
+func (m *MockGoalService) CreateGoal(userID uint, req service.GoalRequest) (service.GoalProgress, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(service.GoalProgress), args.Error(1)
+}
+
+func (m *MockGoalService) UpdateGoal(userID, id uint, req service.GoalRequest) (service.GoalProgress, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(service.GoalProgress), args.Error(1)
+}
+
+func (m *MockGoalService) DeleteGoal(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockGoalService) GetGoal(userID, id uint) (service.GoalProgress, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(service.GoalProgress), args.Error(1)
+}
+
+func (m *MockGoalService) ListGoals(userID uint) ([]service.GoalProgress, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]service.GoalProgress), args.Error(1)
+}
+
+func (m *MockGoalService) GetYearInReview(userID uint, year int) (service.YearInReview, error) {
+	args := m.Called(userID, year)
+	return args.Get(0).(service.YearInReview), args.Error(1)
+}
+
+func TestGoalHandler_CreateGoalHandler_Success(t *testing.T) {
+	mockService := new(MockGoalService)
+	handler := NewGoalHandler(mockService)
+
+	// Настройка мока
+	reqBody := service.GoalRequest{Metric: models.GoalBooks, Target: 30, Year: 2026}
+	mockService.On("CreateGoal", uint(1), reqBody).Return(service.GoalProgress{
+		Goal: models.ReadingGoal{
+			ID:        4,
+			UserID:    1,
+			Title:     "Цель на 2026 год",
+			Metric:    models.GoalBooks,
+			Target:    30,
+			StartDate: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		Current:  12,
+		Expected: 23,
+		Percent:  40,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/users/me/goals", strings.NewReader(`{"metric":"books","target":30,"year":2026}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateGoalHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response GoalResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, uint(4), response.ID)
+	assert.Equal(t, "2026-01-01", response.StartDate)
+	assert.Equal(t, "2026-12-31", response.EndDate)
+	assert.Equal(t, 12, response.Current)
+	assert.False(t, response.OnTrack)
+	mockService.AssertExpectations(t)
+}
+
+func TestGoalHandler_CreateGoalHandler_InvalidMetric(t *testing.T) {
+	mockService := new(MockGoalService)
+	handler := NewGoalHandler(mockService)
+
+	reqBody := service.GoalRequest{Metric: "hours", Target: 100, Year: 2026}
+	mockService.On("CreateGoal", uint(1), reqBody).
+		Return(service.GoalProgress{}, errors.New("invalid metric, must be 'books' or 'pages'"))
+
+	req, _ := http.NewRequest("POST", "/users/me/goals", strings.NewReader(`{"metric":"hours","target":100,"year":2026}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateGoalHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGoalHandler_GetYearInReviewHandler_Success(t *testing.T) {
+	mockService := new(MockGoalService)
+	handler := NewGoalHandler(mockService)
+
+	rating := 4.25
+	mockService.On("GetYearInReview", uint(1), 2025).Return(service.YearInReview{
+		Year:             2025,
+		BooksRead:        24,
+		PagesRead:        8120,
+		TopGenres:        []repository.GenreCount{{Genre: "Fantasy", Books: 7}},
+		LongestBook:      &service.BookBrief{ID: 9, Title: "Dune"},
+		LongestBookPages: 896,
+		AverageRating:    &rating,
+		RatedBooks:       16,
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/year-in-review?year=2025", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetYearInReviewHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response YearInReviewResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "Fantasy", response.TopGenres[0].Genre)
+	assert.Equal(t, "Dune", response.LongestBook.Book.Title)
+	assert.Equal(t, 896, response.LongestBook.Pages)
+	assert.Equal(t, 4.25, *response.AverageRating)
+	assert.NotNil(t, response.Goals)
+	mockService.AssertExpectations(t)
+}
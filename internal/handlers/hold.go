@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"net/http"
+)
+
+type HoldHandler struct {
+	holdService service.HoldService
+}
+
+func NewHoldHandler(holdService service.HoldService) *HoldHandler {
+	return &HoldHandler{holdService: holdService}
+}
+
+func toHoldResponse(entry service.HoldEntry) HoldResponse {
+	hold := entry.Hold
+	response := HoldResponse{
+		ID:        hold.ID,
+		Book:      toLendingBookResponse(hold.BookID, hold.Book),
+		Status:    hold.Status,
+		Position:  entry.Position,
+		ReadyAt:   hold.ReadyAt,
+		ExpiresAt: hold.ExpiresAt,
+		CreatedAt: hold.CreatedAt,
+	}
+	if hold.Copy != nil {
+		response.Barcode = hold.Copy.Barcode
+	}
+	return response
+}
+
+// PlaceHoldHandler godoc
+// @Summary Встать в очередь на книгу
+// @Description Доступно, когда все экземпляры книги на руках. Вернувшийся экземпляр откладывается первому в очереди на время HOLD_PICKUP_WINDOW, затем переходит следующему
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID книги"
+// @Success 201 {object} HoldResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /books/{id}/holds [post]
+func (h *HoldHandler) PlaceHoldHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	entry, err := h.holdService.PlaceHold(userID, currentRole(r), bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toHoldResponse(entry))
+}
+
+// CancelHoldHandler godoc
+// @Summary Отменить бронь
+// @Description Отложенный по брони экземпляр переходит следующему в очереди
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Param id path int true "ID брони"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /holds/{id} [delete]
+func (h *HoldHandler) CancelHoldHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid hold ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.holdService.CancelHold(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMyHoldsHandler godoc
+// @Summary Мои брони
+// @Description Активные брони: waiting - с местом в очереди, ready - экземпляр отложен до expires_at
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} HoldsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/holds [get]
+func (h *HoldHandler) GetMyHoldsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	entries, err := h.holdService.ListUserHolds(userID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get holds"})
+		return
+	}
+	response := HoldsResponse{Data: make([]HoldResponse, 0, len(entries))}
+	for _, entry := range entries {
+		response.Data = append(response.Data, toHoldResponse(entry))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockHoldService struct {
+	mock.Mock
+}
+
+func (m *MockHoldService) PlaceHold(userID uint, role string, bookID uint) (service.HoldEntry, error) {
+	args := m.Called(userID, role, bookID)
+	return args.Get(0).(service.HoldEntry), args.Error(1)
+}
+
+func (m *MockHoldService) CancelHold(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockHoldService) ListUserHolds(userID uint) ([]service.HoldEntry, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]service.HoldEntry), args.Error(1)
+}
+
+func (m *MockHoldService) ProcessHolds(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestHoldHandler_PlaceHoldHandler_Success(t *testing.T) {
+	mockService := new(MockHoldService)
+	handler := NewHoldHandler(mockService)
+
+	// Настройка мока
+	mockService.On("PlaceHold", uint(2), "user", uint(1)).Return(service.HoldEntry{
+		Hold: models.Hold{
+			ID:     5,
+			BookID: 1,
+			Book:   models.Book{Model: gorm.Model{ID: 1}, Title: "Dune"},
+			UserID: 2,
+			Status: models.HoldWaiting,
+		},
+		Position: 3,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/books/1/holds", nil)
+	req = withRouteAndUser(req, "id", "1", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.PlaceHoldHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response HoldResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, uint(5), response.ID)
+	assert.Equal(t, models.HoldWaiting, response.Status)
+	assert.Equal(t, 3, response.Position)
+	assert.Equal(t, "Dune", response.Book.Title)
+	assert.Nil(t, response.ExpiresAt)
+	mockService.AssertExpectations(t)
+}
+
+func TestHoldHandler_PlaceHoldHandler_CopiesAvailable(t *testing.T) {
+	mockService := new(MockHoldService)
+	handler := NewHoldHandler(mockService)
+
+	mockService.On("PlaceHold", uint(2), "user", uint(1)).
+		Return(service.HoldEntry{}, errors.New("hold unavailable: free copies are on the shelf, borrow one instead"))
+
+	req, _ := http.NewRequest("POST", "/books/1/holds", nil)
+	req = withRouteAndUser(req, "id", "1", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.PlaceHoldHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHoldHandler_GetMyHoldsHandler_ReadyForPickup(t *testing.T) {
+	mockService := new(MockHoldService)
+	handler := NewHoldHandler(mockService)
+
+	copyID := uint(4)
+	readyAt := time.Now()
+	expiresAt := readyAt.Add(72 * time.Hour)
+	mockService.On("ListUserHolds", uint(2)).Return([]service.HoldEntry{{
+		Hold: models.Hold{
+			ID:        5,
+			BookID:    1,
+			UserID:    2,
+			Status:    models.HoldReady,
+			CopyID:    &copyID,
+			Copy:      &models.Copy{Model: gorm.Model{ID: 4}, Barcode: "LIB-000123"},
+			ReadyAt:   &readyAt,
+			ExpiresAt: &expiresAt,
+		},
+	}}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/holds", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.GetMyHoldsHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response HoldsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "LIB-000123", response.Data[0].Barcode)
+	assert.Equal(t, 0, response.Data[0].Position)
+	assert.NotNil(t, response.Data[0].ExpiresAt)
+	mockService.AssertExpectations(t)
+}
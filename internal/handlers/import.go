@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxImportSize = 32 << 20
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// readUpload читает файл из multipart-поля "file" либо тело запроса целиком
+func readUpload(w http.ResponseWriter, r *http.Request, maxSize int64) ([]byte, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("file is required")
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		return data, header.Filename, err
+	}
+
+	data, err := io.ReadAll(r.Body)
+	return data, "", err
+}
+
+func detectImportFormat(r *http.Request, filename string) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return service.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return service.ImportFormatNDJSON
+	case ".mrc", ".marc":
+		return service.ImportFormatMARC
+	case ".xml":
+		return service.ImportFormatMARCXML
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return service.ImportFormatCSV
+	case strings.HasPrefix(contentType, "application/x-ndjson"):
+		return service.ImportFormatNDJSON
+	case strings.HasPrefix(contentType, "application/marc"):
+		return service.ImportFormatMARC
+	case strings.HasPrefix(contentType, "application/marcxml+xml"):
+		return service.ImportFormatMARCXML
+	}
+	return ""
+}
+
+// parseColumnMapping разбирает параметр вида "title:Name,author:Writer"
+func parseColumnMapping(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	columns := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		field, column, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(field) == "" || strings.TrimSpace(column) == "" {
+			return nil, errors.New("invalid columns parameter")
+		}
+		columns[strings.ToLower(strings.TrimSpace(field))] = strings.TrimSpace(column)
+	}
+	return columns, nil
+}
+
+func toImportJobResponse(job models.ImportJob) ImportJobResponse {
+	errs := job.Errors
+	if errs == nil {
+		errs = []models.ImportRowError{}
+	}
+	return ImportJobResponse{
+		ID:            job.ID,
+		Status:        job.Status,
+		Format:        job.Format,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ValidRows:     job.ValidRows,
+		ProcessedRows: job.ProcessedRows,
+		Errors:        errs,
+		Message:       job.Message,
+		CreatedAt:     job.CreatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
+
+// StartImportHandler godoc
+// @Summary Массовый импорт книг
+// @Description Загрузка каталога из CSV, NDJSON или MARC21 (бинарный ISO 2709 и MARCXML). Импорт выполняется фоновой задачей, статус доступен по ID задачи. В режиме dry_run строки только проверяются
+// @Tags Imports
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV, NDJSON, MARC или MARCXML файл"
+// @Param format query string false "Формат файла (csv, ndjson, marc, marcxml), по умолчанию определяется по расширению"
+// @Param dry_run query bool false "Только проверить строки, ничего не сохранять"
+// @Param columns query string false "Соответствие полей колонкам CSV, например title:Name,author:Writer"
+// @Success 202 {object} ImportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/imports [post]
+func (h *ImportHandler) StartImportHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("user").(*utils.Claims)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	userID, _ := strconv.Atoi(claims.UserID)
+
+	data, filename, err := readUpload(w, r, maxImportSize)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid upload: " + err.Error()})
+		return
+	}
+
+	columns, err := parseColumnMapping(r.URL.Query().Get("columns"))
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{err.Error()})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	opts := service.ImportOptions{
+		Format:  detectImportFormat(r, filename),
+		DryRun:  dryRun,
+		Columns: columns,
+	}
+
+	job, err := h.importService.StartImport(uint(userID), opts, data)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "required") {
+			status = http.StatusBadRequest
+		}
+		utils.JSONResponse(w, status, ErrorResponse{err.Error()})
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusAccepted, toImportJobResponse(job))
+}
+
+// GetImportJobHandler godoc
+// @Summary Статус импорта
+// @Description Прогресс фоновой задачи импорта и ошибки по строкам
+// @Tags Imports
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID задачи импорта"
+// @Success 200 {object} ImportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/imports/{id} [get]
+func (h *ImportHandler) GetImportJobHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid import ID"})
+		return
+	}
+
+	job, err := h.importService.GetImportJob(id)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusNotFound, ErrorResponse{"Import not found"})
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, toImportJobResponse(job))
+}
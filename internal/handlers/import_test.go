@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) StartImport(userID uint, opts service.ImportOptions, data []byte) (models.ImportJob, error) {
+	args := m.Called(userID, opts, data)
+	return args.Get(0).(models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportService) GetImportJob(id string) (models.ImportJob, error) {
+	args := m.Called(id)
+	return args.Get(0).(models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportService) FailStaleJobs(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestImportHandler_StartImportHandler_Success(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+
+	// Настройка мока
+	data := []byte("title,author,genre,description,price\nBook,Author,Fiction,Desc,9.99\n")
+	opts := service.ImportOptions{Format: "csv", DryRun: true}
+	job := models.ImportJob{
+		Model:  gorm.Model{ID: 7},
+		Format: "csv",
+		DryRun: true,
+		Status: models.ImportStatusPending,
+	}
+	mockService.On("StartImport", uint(1), opts, data).Return(job, nil)
+
+	// Создание multipart-запроса
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "books.csv")
+	part.Write(data)
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "/admin/imports?dry_run=true", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := context.WithValue(req.Context(), "user", &utils.Claims{UserID: "1"})
+	req = req.WithContext(ctx)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.StartImportHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":7`)
+	assert.Contains(t, rr.Body.String(), `"status":"pending"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestImportHandler_StartImportHandler_InvalidFormat(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+
+	// Настройка мока
+	data := []byte("<books/>")
+	opts := service.ImportOptions{Format: "xml"}
+	mockService.On("StartImport", uint(1), opts, data).
+		Return(models.ImportJob{}, errors.New("invalid import format, must be 'csv' or 'ndjson'"))
+
+	// Создание запроса
+	req, _ := http.NewRequest("POST", "/admin/imports?format=xml", bytes.NewReader(data))
+	ctx := context.WithValue(req.Context(), "user", &utils.Claims{UserID: "1"})
+	req = req.WithContext(ctx)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.StartImportHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid import format")
+	mockService.AssertExpectations(t)
+}
+
+func TestImportHandler_GetImportJobHandler_NotFound(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+
+	// Настройка мока
+	mockService.On("GetImportJob", "42").Return(models.ImportJob{}, gorm.ErrRecordNotFound)
+
+	// Создание запроса
+	req, _ := http.NewRequest("GET", "/admin/imports/42", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "42")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.GetImportJobHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
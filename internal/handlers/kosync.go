@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/kosync"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// KOSyncHandler - сервер синхронизации KOReader (kosync): в настройках KOReader
+// указывается адрес <сервер>/kosync и логин с паролем аккаунта Bookshelf
+type KOSyncHandler struct {
+	progressService service.ProgressService
+}
+
+func NewKOSyncHandler(progressService service.ProgressService) *KOSyncHandler {
+	return &KOSyncHandler{progressService: progressService}
+}
+
+// authenticate проверяет заголовки x-auth-user и x-auth-key и сам отвечает 401
+func (h *KOSyncHandler) authenticate(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	userID, err := h.progressService.AuthenticateSync(r.Header.Get("x-auth-user"), r.Header.Get("x-auth-key"))
+	if err != nil {
+		if strings.Contains(err.Error(), "credentials") {
+			utils.JSONResponse(w, http.StatusUnauthorized, KOSyncErrorResponse{kosync.CodeUnauthorized, "Unauthorized"})
+		} else {
+			utils.JSONResponse(w, http.StatusInternalServerError, KOSyncErrorResponse{kosync.CodeUnknown, "Unknown server error"})
+		}
+		return 0, false
+	}
+	return userID, true
+}
+
+func writeKOSyncError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "document is required"):
+		utils.JSONResponse(w, http.StatusForbidden, KOSyncErrorResponse{kosync.CodeDocumentMissing, "Field 'document' not provided."})
+	case strings.Contains(msg, "invalid") || strings.Contains(msg, "required"):
+		utils.JSONResponse(w, http.StatusForbidden, KOSyncErrorResponse{kosync.CodeInvalidRequest, "Invalid request: " + msg})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, KOSyncErrorResponse{kosync.CodeUnknown, "Unknown server error"})
+	}
+}
+
+// KOSyncCreateUserHandler godoc
+// @Summary Регистрация KOReader
+// @Description Регистрация из KOReader отключена: аккаунт создаётся в Bookshelf, в KOReader нужно войти с теми же логином и паролем
+// @Tags KOReader
+// @Accept json
+// @Produce json
+// @Param input body KOSyncUserRequest true "Логин и MD5 пароля"
+// @Failure 403 {object} KOSyncErrorResponse
+// @Router /kosync/users/create [post]
+func (h *KOSyncHandler) KOSyncCreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	utils.JSONResponse(w, http.StatusForbidden, KOSyncErrorResponse{
+		kosync.CodeInvalidRequest,
+		"Registration is disabled: sign up in Bookshelf, then log in with the same username and password",
+	})
+}
+
+// KOSyncAuthHandler godoc
+// @Summary Проверка входа KOReader
+// @Description Логин - имя пользователя Bookshelf, ключ - MD5 пароля, как его отправляет KOReader
+// @Tags KOReader
+// @Produce json
+// @Param x-auth-user header string true "Имя пользователя"
+// @Param x-auth-key header string true "MD5 пароля"
+// @Success 200 {object} KOSyncAuthResponse
+// @Failure 401 {object} KOSyncErrorResponse
+// @Router /kosync/users/auth [get]
+func (h *KOSyncHandler) KOSyncAuthHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authenticate(w, r); !ok {
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, KOSyncAuthResponse{Authorized: "OK"})
+}
+
+// KOSyncUpdateProgressHandler godoc
+// @Summary Позиция из KOReader
+// @Description Документ KOReader (MD5 по фрагментам файла) сопоставляется с файлом книги каталога, тогда позиция общая
+// @Description с /users/me/progress/{bookID}; позиции в других документах хранятся отдельно. Время позиции - время запроса
+// @Tags KOReader
+// @Accept json
+// @Produce json
+// @Param x-auth-user header string true "Имя пользователя"
+// @Param x-auth-key header string true "MD5 пароля"
+// @Param input body service.SyncProgressRequest true "Позиция"
+// @Success 200 {object} KOSyncUpdateResponse
+// @Failure 401 {object} KOSyncErrorResponse
+// @Failure 403 {object} KOSyncErrorResponse
+// @Router /kosync/syncs/progress [put]
+func (h *KOSyncHandler) KOSyncUpdateProgressHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req service.SyncProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusForbidden, KOSyncErrorResponse{kosync.CodeInvalidRequest, "Invalid request"})
+		return
+	}
+
+	progress, err := h.progressService.SaveSyncProgress(userID, req)
+	if err != nil {
+		writeKOSyncError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, KOSyncUpdateResponse{Document: req.Document, Timestamp: progress.RecordedAt.Unix()})
+}
+
+// KOSyncGetProgressHandler godoc
+// @Summary Позиция для KOReader
+// @Description Последняя позиция в документе. Позиция из веб-читалки (EPUB CFI) не переводится в XPointer: KOReader
+// @Description получает только процент. Если позиции нет, возвращается пустой объект
+// @Tags KOReader
+// @Produce json
+// @Param x-auth-user header string true "Имя пользователя"
+// @Param x-auth-key header string true "MD5 пароля"
+// @Param document path string true "Документ KOReader"
+// @Success 200 {object} KOSyncProgressResponse
+// @Failure 401 {object} KOSyncErrorResponse
+// @Failure 403 {object} KOSyncErrorResponse
+// @Router /kosync/syncs/progress/{document} [get]
+func (h *KOSyncHandler) KOSyncGetProgressHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	document := chi.URLParam(r, "document")
+	progress, err := h.progressService.GetSyncProgress(userID, document)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			utils.JSONResponse(w, http.StatusOK, struct{}{})
+			return
+		}
+		writeKOSyncError(w, err)
+		return
+	}
+
+	response := KOSyncProgressResponse{
+		Document:   document,
+		Percentage: progress.Percent / 100,
+		Device:     progress.Device,
+		DeviceID:   progress.DeviceID,
+		Timestamp:  progress.RecordedAt.Unix(),
+	}
+	if progress.LocatorType == models.LocatorXPointer || progress.LocatorType == models.LocatorPage {
+		response.Progress = progress.Locator
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
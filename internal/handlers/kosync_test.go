@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKOSyncHandler_KOSyncAuthHandler_Unauthorized(t *testing.T) {
+	mockService := new(MockProgressService)
+	handler := NewKOSyncHandler(mockService)
+
+	// Настройка мока
+	mockService.On("AuthenticateSync", "john_doe", "bad").Return(uint(0), errors.New("invalid credentials"))
+
+	req, _ := http.NewRequest("GET", "/kosync/users/auth", nil)
+	req.Header.Set("x-auth-user", "john_doe")
+	req.Header.Set("x-auth-key", "bad")
+
+	rr := httptest.NewRecorder()
+	handler.KOSyncAuthHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"code":2001,"message":"Unauthorized"}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestKOSyncHandler_KOSyncGetProgressHandler_FromWebReader(t *testing.T) {
+	mockService := new(MockProgressService)
+	handler := NewKOSyncHandler(mockService)
+
+	// Настройка мока: последняя позиция сохранена веб-читалкой, CFI в KOReader не передаётся
+	document := "0b229176d4e8db7f6d2b5a4952368d7a"
+	mockService.On("AuthenticateSync", "john_doe", "key").Return(uint(2), nil)
+	mockService.On("GetSyncProgress", uint(2), document).Return(models.ReadingProgress{
+		Locator:     "epubcfi(/6/14!/4/2/10/2:352)",
+		LocatorType: models.LocatorCFI,
+		Percent:     42.5,
+		Device:      "Firefox",
+		DeviceID:    "web-1",
+		RecordedAt:  time.Unix(1767225600, 0),
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/kosync/syncs/progress/"+document, nil)
+	req.Header.Set("x-auth-user", "john_doe")
+	req.Header.Set("x-auth-key", "key")
+	req = withRouteAndUser(req, "document", document, nil)
+
+	rr := httptest.NewRecorder()
+	handler.KOSyncGetProgressHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"document":"`+document+`","percentage":0.425,"device":"Firefox","device_id":"web-1","timestamp":1767225600}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestKOSyncHandler_KOSyncGetProgressHandler_NoProgress(t *testing.T) {
+	mockService := new(MockProgressService)
+	handler := NewKOSyncHandler(mockService)
+
+	// Настройка мока
+	mockService.On("AuthenticateSync", "john_doe", "key").Return(uint(2), nil)
+	mockService.On("GetSyncProgress", uint(2), "abc").Return(models.ReadingProgress{}, errors.New("progress not found"))
+
+	req, _ := http.NewRequest("GET", "/kosync/syncs/progress/abc", nil)
+	req.Header.Set("x-auth-user", "john_doe")
+	req.Header.Set("x-auth-key", "key")
+	req = withRouteAndUser(req, "document", "abc", nil)
+
+	rr := httptest.NewRecorder()
+	handler.KOSyncGetProgressHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
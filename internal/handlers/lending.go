@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type LendingHandler struct {
+	lendingService service.LendingService
+}
+
+func NewLendingHandler(lendingService service.LendingService) *LendingHandler {
+	return &LendingHandler{lendingService: lendingService}
+}
+
+func toAvailabilityResponse(a repository.Availability) AvailabilityResponse {
+	return AvailabilityResponse{Total: a.Total, Available: a.Available}
+}
+
+func toCopyResponse(c models.Copy) CopyResponse {
+	return CopyResponse{
+		ID:        c.ID,
+		BookID:    c.BookID,
+		Barcode:   c.Barcode,
+		Location:  c.Location,
+		Condition: c.Condition,
+		Status:    c.Status,
+	}
+}
+
+// toLendingBookResponse - краткая карточка книги из выдачи или брони; книга может быть уже удалена из каталога
+func toLendingBookResponse(id uint, book models.Book) BookBriefResponse {
+	return toBookBriefResponse(service.BookBrief{
+		ID:     id,
+		Title:  book.Title,
+		Author: book.Author,
+		Genre:  book.Genre,
+		Price:  money.New(book.Price, book.Currency),
+	})
+}
+
+func toLoanResponse(loan models.Loan) LoanResponse {
+	return LoanResponse{
+		ID:           loan.ID,
+		Book:         toLendingBookResponse(loan.BookID, loan.Book),
+		CopyID:       loan.CopyID,
+		Barcode:      loan.Copy.Barcode,
+		UserID:       loan.UserID,
+		CheckedOutAt: loan.CheckedOutAt,
+		DueAt:        loan.DueAt,
+		ReturnedAt:   loan.ReturnedAt,
+		Renewals:     loan.Renewals,
+		Overdue:      loan.ReturnedAt == nil && time.Now().After(loan.DueAt),
+	}
+}
+
+func toPaginatedLoansResponse(loans []models.Loan, total int64, page, limit int) PaginatedLoansResponse {
+	response := PaginatedLoansResponse{
+		Data: make([]LoanResponse, 0, len(loans)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, loan := range loans {
+		response.Data = append(response.Data, toLoanResponse(loan))
+	}
+	return response
+}
+
+// GetCopiesHandler godoc
+// @Summary Экземпляры книги
+// @Description Физические экземпляры с местом хранения, состоянием и статусом
+// @Tags Lending
+// @Produce json
+// @Param id path int true "ID книги"
+// @Success 200 {object} CopiesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/copies [get]
+func (h *LendingHandler) GetCopiesHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	copies, err := h.lendingService.ListCopies(bookID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get copies"})
+		return
+	}
+	availability, err := h.lendingService.GetAvailability(bookID)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get copies"})
+		return
+	}
+
+	response := CopiesResponse{
+		Data:         make([]CopyResponse, 0, len(copies)),
+		Availability: toAvailabilityResponse(availability),
+	}
+	for _, c := range copies {
+		response.Data = append(response.Data, toCopyResponse(c))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// AddCopyHandler godoc
+// @Summary Добавить экземпляр
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param input body service.CopyRequest true "Экземпляр"
+// @Success 201 {object} CopyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /books/{id}/copies [post]
+func (h *LendingHandler) AddCopyHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	var req service.CopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	c, err := h.lendingService.AddCopy(bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toCopyResponse(c))
+}
+
+// UpdateCopyHandler godoc
+// @Summary Изменить экземпляр
+// @Description Штрихкод, место, состояние и статус (available, maintenance, lost). Статус выданного экземпляра меняется только возвратом
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID экземпляра"
+// @Param input body service.CopyRequest true "Экземпляр"
+// @Success 200 {object} CopyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /copies/{id} [put]
+func (h *LendingHandler) UpdateCopyHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid copy ID"})
+		return
+	}
+
+	var req service.CopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	c, err := h.lendingService.UpdateCopy(id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toCopyResponse(c))
+}
+
+// DeleteCopyHandler godoc
+// @Summary Списать экземпляр
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Param id path int true "ID экземпляра"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /copies/{id} [delete]
+func (h *LendingHandler) DeleteCopyHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid copy ID"})
+		return
+	}
+
+	if err := h.lendingService.DeleteCopy(id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckoutHandler godoc
+// @Summary Взять книгу
+// @Description Выдаёт любой свободный экземпляр книги (book_id) или конкретный экземпляр (barcode). Число одновременных выдач ограничено ролью
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.CheckoutRequest true "Книга или экземпляр"
+// @Success 201 {object} LoanResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /loans [post]
+func (h *LendingHandler) CheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	loan, err := h.lendingService.Checkout(userID, currentRole(r), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toLoanResponse(loan))
+}
+
+// ReturnHandler godoc
+// @Summary Вернуть книгу
+// @Description Вернуть может сам читатель или библиотекарь. Экземпляр в состоянии damaged уходит на обслуживание
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID выдачи"
+// @Param input body service.ReturnRequest false "Состояние экземпляра"
+// @Success 200 {object} LoanResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /loans/{id}/return [post]
+func (h *LendingHandler) ReturnHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid loan ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	// Тело необязательно
+	var req service.ReturnRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+			return
+		}
+	}
+
+	loan, err := h.lendingService.Return(userID, currentRole(r), id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toLoanResponse(loan))
+}
+
+// RenewHandler godoc
+// @Summary Продлить выдачу
+// @Description Продлевает срок ещё на один период от текущей даты возврата. Просроченную выдачу продлить нельзя, число продлений ограничено
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID выдачи"
+// @Success 200 {object} LoanResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /loans/{id}/renew [post]
+func (h *LendingHandler) RenewHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid loan ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	loan, err := h.lendingService.Renew(userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toLoanResponse(loan))
+}
+
+// GetMyLoansHandler godoc
+// @Summary Мои выдачи
+// @Description По умолчанию вся история, новые первыми; active=true - только книги на руках, ближайший срок первым
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Produce json
+// @Param active query bool false "Только невозвращённые"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Выдач на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedLoansResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/loans [get]
+func (h *LendingHandler) GetMyLoansHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	loans, total, err := h.lendingService.ListUserLoans(userID, r.URL.Query().Get("active") == "true", page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get loans"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedLoansResponse(loans, total, page, limit))
+}
+
+// GetLoansHandler godoc
+// @Summary Книги на руках
+// @Description Все невозвращённые выдачи, ближайший срок первым; overdue=true - только просроченные
+// @Tags Lending
+// @Security ApiKeyAuth
+// @Produce json
+// @Param overdue query bool false "Только просроченные"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Выдач на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedLoansResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /loans [get]
+func (h *LendingHandler) GetLoansHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+	loans, total, err := h.lendingService.ListLoans(r.URL.Query().Get("overdue") == "true", page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get loans"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedLoansResponse(loans, total, page, limit))
+}
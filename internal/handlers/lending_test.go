@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockLendingService struct {
+	mock.Mock
+}
+
+func (m *MockLendingService) AddCopy(bookID uint, req service.CopyRequest) (models.Copy, error) {
+	args := m.Called(bookID, req)
+	return args.Get(0).(models.Copy), args.Error(1)
+}
+
+func (m *MockLendingService) UpdateCopy(id uint, req service.CopyRequest) (models.Copy, error) {
+	args := m.Called(id, req)
+	return args.Get(0).(models.Copy), args.Error(1)
+}
+
+func (m *MockLendingService) DeleteCopy(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockLendingService) ListCopies(bookID uint) ([]models.Copy, error) {
+	args := m.Called(bookID)
+	return args.Get(0).([]models.Copy), args.Error(1)
+}
+
+func (m *MockLendingService) GetAvailability(bookID uint) (repository.Availability, error) {
+	args := m.Called(bookID)
+	return args.Get(0).(repository.Availability), args.Error(1)
+}
+
+func (m *MockLendingService) Checkout(userID uint, role string, req service.CheckoutRequest) (models.Loan, error) {
+	args := m.Called(userID, role, req)
+	return args.Get(0).(models.Loan), args.Error(1)
+}
+
+func (m *MockLendingService) Return(userID uint, role string, loanID uint, req service.ReturnRequest) (models.Loan, error) {
+	args := m.Called(userID, role, loanID, req)
+	return args.Get(0).(models.Loan), args.Error(1)
+}
+
+func (m *MockLendingService) Renew(userID, loanID uint) (models.Loan, error) {
+	args := m.Called(userID, loanID)
+	return args.Get(0).(models.Loan), args.Error(1)
+}
+
+func (m *MockLendingService) ListUserLoans(userID uint, activeOnly bool, page, limit int) ([]models.Loan, int64, error) {
+	args := m.Called(userID, activeOnly, page, limit)
+	return args.Get(0).([]models.Loan), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockLendingService) ListLoans(overdue bool, page, limit int) ([]models.Loan, int64, error) {
+	args := m.Called(overdue, page, limit)
+	return args.Get(0).([]models.Loan), args.Get(1).(int64), args.Error(2)
+}
+
+func TestLendingHandler_CheckoutHandler_Success(t *testing.T) {
+	mockService := new(MockLendingService)
+	handler := NewLendingHandler(mockService)
+
+	// Настройка мока
+	due := time.Now().Add(14 * 24 * time.Hour)
+	mockService.On("Checkout", uint(2), "user", service.CheckoutRequest{BookID: 1}).Return(models.Loan{
+		ID:           7,
+		CopyID:       4,
+		Copy:         models.Copy{Model: gorm.Model{ID: 4}, Barcode: "LIB-000123"},
+		BookID:       1,
+		Book:         models.Book{Model: gorm.Model{ID: 1}, Title: "Dune"},
+		UserID:       2,
+		CheckedOutAt: time.Now(),
+		DueAt:        due,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/loans", strings.NewReader(`{"book_id":1}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.CheckoutHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response LoanResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, uint(7), response.ID)
+	assert.Equal(t, "LIB-000123", response.Barcode)
+	assert.Equal(t, "Dune", response.Book.Title)
+	assert.False(t, response.Overdue)
+	assert.Nil(t, response.ReturnedAt)
+	mockService.AssertExpectations(t)
+}
+
+func TestLendingHandler_CheckoutHandler_NoCopyAvailable(t *testing.T) {
+	mockService := new(MockLendingService)
+	handler := NewLendingHandler(mockService)
+
+	mockService.On("Checkout", uint(2), "user", service.CheckoutRequest{BookID: 1}).
+		Return(models.Loan{}, errors.New("copy unavailable: no free copies, try again later"))
+
+	req, _ := http.NewRequest("POST", "/loans", strings.NewReader(`{"book_id":1}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.CheckoutHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLendingHandler_RenewHandler_LimitReached(t *testing.T) {
+	mockService := new(MockLendingService)
+	handler := NewLendingHandler(mockService)
+
+	mockService.On("Renew", uint(2), uint(7)).
+		Return(models.Loan{}, errors.New("renewal limit reached: at most 2 renewals"))
+
+	req, _ := http.NewRequest("POST", "/loans/7/renew", nil)
+	req = withRouteAndUser(req, "id", "7", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.RenewHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
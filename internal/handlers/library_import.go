@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxLibraryImportSize = 16 << 20
+
+type LibraryImportHandler struct {
+	libraryService service.LibraryImportService
+}
+
+func NewLibraryImportHandler(libraryService service.LibraryImportService) *LibraryImportHandler {
+	return &LibraryImportHandler{libraryService: libraryService}
+}
+
+func toLibraryImportResponse(imp models.LibraryImport) LibraryImportResponse {
+	resp := LibraryImportResponse{
+		ID:        imp.ID,
+		Source:    imp.Source,
+		Rows:      imp.Rows,
+		CreatedAt: imp.CreatedAt,
+	}
+	if resp.Rows == nil {
+		resp.Rows = []models.LibraryImportRow{}
+	}
+
+	for _, row := range imp.Rows {
+		switch row.Status {
+		case models.LibraryRowMatched, models.LibraryRowResolved:
+			resp.Summary.Matched++
+		case models.LibraryRowAmbiguous:
+			resp.Summary.Ambiguous++
+		case models.LibraryRowUnmatched:
+			resp.Summary.Unmatched++
+		case models.LibraryRowSkipped:
+			resp.Summary.Skipped++
+		case models.LibraryRowFailed:
+			resp.Summary.Failed++
+		}
+	}
+	return resp
+}
+
+// ImportLibraryHandler godoc
+// @Summary Импорт библиотеки из Goodreads или StoryGraph
+// @Description Загрузка CSV-выгрузки Goodreads/StoryGraph. Книги сопоставляются с каталогом по ISBN, затем по названию и автору, найденные добавляются в избранное.
+// @Description Полка (read, currently-reading, to-read) переносится в статус чтения, оценка - в рецензию без текста, если у книги их ещё нет;
+// @Description прочитанная книга без даты прочтения на полку не ставится. Возвращает отчёт по строкам: applied и skipped с причинами
+// @Tags Favourites
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV-выгрузка Goodreads или StoryGraph"
+// @Success 201 {object} LibraryImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/imports/goodreads [post]
+func (h *LibraryImportHandler) ImportLibraryHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("user").(*utils.Claims)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	userID, _ := strconv.Atoi(claims.UserID)
+
+	data, _, err := readUpload(w, r, maxLibraryImportSize)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid upload: " + err.Error()})
+		return
+	}
+
+	imp, err := h.libraryService.ImportLibrary(uint(userID), data)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusCreated, toLibraryImportResponse(imp))
+}
+
+// GetLibraryImportHandler godoc
+// @Summary Отчёт об импорте библиотеки
+// @Description Отчёт по строкам импорта: найденные, неоднозначные и не найденные книги
+// @Tags Favourites
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID импорта"
+// @Success 200 {object} LibraryImportResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/imports/{id} [get]
+func (h *LibraryImportHandler) GetLibraryImportHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("user").(*utils.Claims)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	userID, _ := strconv.Atoi(claims.UserID)
+
+	imp, err := h.libraryService.GetImport(uint(userID), chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, toLibraryImportResponse(imp))
+}
+
+// ResolveLibraryRowHandler godoc
+// @Summary Ручное сопоставление строки импорта
+// @Description Привязывает неоднозначную, ненайденную или не перенесённую из-за ошибки строку к книге и переносит её, как при импорте. Пустой book_id пропускает строку
+// @Tags Favourites
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID импорта"
+// @Param row path int true "Номер строки"
+// @Param input body ResolveLibraryRowRequest true "Выбранная книга"
+// @Success 200 {object} LibraryImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/imports/{id}/rows/{row}/resolve [post]
+func (h *LibraryImportHandler) ResolveLibraryRowHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("user").(*utils.Claims)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+	userID, _ := strconv.Atoi(claims.UserID)
+
+	row, err := strconv.Atoi(chi.URLParam(r, "row"))
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid row number"})
+		return
+	}
+
+	var input ResolveLibraryRowRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	imp, err := h.libraryService.ResolveRow(uint(userID), chi.URLParam(r, "id"), row, input.BookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, toLibraryImportResponse(imp))
+}
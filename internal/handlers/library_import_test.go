@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/utils"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockLibraryImportService struct {
+	mock.Mock
+}
+
+func (m *MockLibraryImportService) ImportLibrary(userID uint, data []byte) (models.LibraryImport, error) {
+	args := m.Called(userID, data)
+	return args.Get(0).(models.LibraryImport), args.Error(1)
+}
+
+func (m *MockLibraryImportService) GetImport(userID uint, id string) (models.LibraryImport, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(models.LibraryImport), args.Error(1)
+}
+
+func (m *MockLibraryImportService) ResolveRow(userID uint, importID string, row int, bookID *uint) (models.LibraryImport, error) {
+	args := m.Called(userID, importID, row, bookID)
+	return args.Get(0).(models.LibraryImport), args.Error(1)
+}
+
+func TestLibraryImportHandler_ImportLibraryHandler_Success(t *testing.T) {
+	mockService := new(MockLibraryImportService)
+	handler := NewLibraryImportHandler(mockService)
+
+	// Настройка мока
+	data := []byte("Title,Author,ISBN13,Exclusive Shelf\nDune,Frank Herbert,9780441172719,read\n")
+	bookID := uint(3)
+	imp := models.LibraryImport{
+		Model:  gorm.Model{ID: 1},
+		Source: "goodreads",
+		Rows: []models.LibraryImportRow{
+			{Row: 2, Title: "Dune", Status: models.LibraryRowMatched, MatchedBy: "isbn", BookID: &bookID},
+			{Row: 3, Title: "Unknown", Status: models.LibraryRowUnmatched},
+		},
+	}
+	mockService.On("ImportLibrary", uint(1), data).Return(imp, nil)
+
+	// Создание запроса
+	req, _ := http.NewRequest("POST", "/users/me/imports/goodreads", bytes.NewReader(data))
+	ctx := context.WithValue(req.Context(), "user", &utils.Claims{UserID: "1"})
+	req = req.WithContext(ctx)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.ImportLibraryHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"summary":{"matched":1,"ambiguous":0,"unmatched":1,"skipped":0,"failed":0}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestLibraryImportHandler_ResolveLibraryRowHandler_InvalidRow(t *testing.T) {
+	mockService := new(MockLibraryImportService)
+	handler := NewLibraryImportHandler(mockService)
+
+	// Настройка мока
+	bookID := uint(5)
+	mockService.On("ResolveRow", uint(1), "1", 2, &bookID).
+		Return(models.LibraryImport{}, errors.New("invalid row: only ambiguous or unmatched rows can be resolved"))
+
+	// Создание запроса
+	req, _ := http.NewRequest("POST", "/users/me/imports/1/rows/2/resolve", bytes.NewReader([]byte(`{"book_id":5}`)))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	rctx.URLParams.Add("row", "2")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	ctx := context.WithValue(req.Context(), "user", &utils.Claims{UserID: "1"})
+	req = req.WithContext(ctx)
+
+	// Вызов хендлера
+	rr := httptest.NewRecorder()
+	handler.ResolveLibraryRowHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// negotiate выбирает из offers тип с наибольшим q в заголовке Accept.
+// Пустой Accept и */* дают первый из offers, при отсутствии совпадений возвращается ""
+func negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	type mediaRange struct {
+		value string
+		q     float64
+		order int
+	}
+	var ranges []mediaRange
+	for i, part := range strings.Split(accept, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		mr := mediaRange{value: strings.ToLower(strings.TrimSpace(value)), q: 1, order: i}
+		for _, param := range strings.Split(params, ";") {
+			if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok && k == "q" {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					mr.q = q
+				}
+			}
+		}
+		ranges = append(ranges, mr)
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	for _, mr := range ranges {
+		if mr.q <= 0 {
+			continue
+		}
+		for _, offer := range offers {
+			if mr.value == offer || mr.value == "*/*" ||
+				(strings.HasSuffix(mr.value, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mr.value, "*"))) {
+				return offer
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type NoteHandler struct {
+	noteService service.NoteService
+}
+
+func NewNoteHandler(noteService service.NoteService) *NoteHandler {
+	return &NoteHandler{noteService: noteService}
+}
+
+func toNoteResponse(note models.Note) NoteResponse {
+	tags := note.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return NoteResponse{
+		ID:         note.ID,
+		BookID:     note.BookID,
+		UserID:     note.UserID,
+		Kind:       note.Kind,
+		Quote:      note.Quote,
+		Text:       note.Text,
+		Page:       note.Page,
+		Location:   note.Location,
+		Tags:       tags,
+		Visibility: note.Visibility,
+		CreatedAt:  note.CreatedAt,
+		UpdatedAt:  note.UpdatedAt,
+	}
+}
+
+func toPaginatedNotes(notes []models.Note, total int64, page, limit int) PaginatedNotesResponse {
+	response := PaginatedNotesResponse{
+		Data: make([]NoteResponse, 0, len(notes)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, note := range notes {
+		response.Data = append(response.Data, toNoteResponse(note))
+	}
+	return response
+}
+
+// CreateNoteHandler godoc
+// @Summary Заметка к книге
+// @Description kind: note (по умолчанию), highlight или quote (для них обязателен quote). Страница, место в книге и теги необязательны
+// @Tags Notes
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param input body service.NoteRequest true "Заметка"
+// @Success 201 {object} NoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/notes [post]
+func (h *NoteHandler) CreateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	note, err := h.noteService.CreateNote(userID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toNoteResponse(note))
+}
+
+// GetBookNotesHandler godoc
+// @Summary Публичные заметки к книге
+// @Tags Notes
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Заметок на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedNotesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/notes [get]
+func (h *NoteHandler) GetBookNotesHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	page, limit := parsePagination(r, 10)
+	notes, total, err := h.noteService.ListPublicBookNotes(bookID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get notes"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedNotes(notes, total, page, limit))
+}
+
+// GetMyNotesHandler godoc
+// @Summary Свои заметки и поиск по ним
+// @Description q - полнотекстовый поиск по цитатам, тексту и тегам (поддерживает "фразы", OR и -исключения), результаты по релевантности
+// @Tags Notes
+// @Security ApiKeyAuth
+// @Produce json
+// @Param q query string false "Поисковый запрос"
+// @Param book_id query int false "Только заметки к книге"
+// @Param tag query string false "Только с тегом"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Заметок на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedNotesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/notes [get]
+func (h *NoteHandler) GetMyNotesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var bookID uint
+	if raw := r.URL.Query().Get("book_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 1 {
+			utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+			return
+		}
+		bookID = uint(id)
+	}
+
+	page, limit := parsePagination(r, 10)
+	query := r.URL.Query()
+	notes, total, err := h.noteService.ListUserNotes(userID, bookID, query.Get("tag"), query.Get("q"), page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get notes"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedNotes(notes, total, page, limit))
+}
+
+// UpdateNoteHandler godoc
+// @Summary Изменение заметки
+// @Tags Notes
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID заметки"
+// @Param input body service.NoteRequest true "Заметка"
+// @Success 200 {object} NoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /notes/{id} [put]
+func (h *NoteHandler) UpdateNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid note ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	note, err := h.noteService.UpdateNote(userID, id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toNoteResponse(note))
+}
+
+// DeleteNoteHandler godoc
+// @Summary Удаление заметки
+// @Tags Notes
+// @Security ApiKeyAuth
+// @Param id path int true "ID заметки"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /notes/{id} [delete]
+func (h *NoteHandler) DeleteNoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid note ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.noteService.DeleteNote(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportNotesHandler godoc
+// @Summary Экспорт заметок в Markdown
+// @Description Все свои заметки к книге одним файлом, по порядку страниц
+// @Tags Notes
+// @Security ApiKeyAuth
+// @Produce text/markdown
+// @Param bookID path int true "ID книги"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/notes/export/{bookID} [get]
+func (h *NoteHandler) ExportNotesHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	markdown, err := h.noteService.ExportMarkdown(userID, bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="notes-%d.md"`, bookID))
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, markdown)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNoteService struct {
+	mock.Mock
+}
+
+func (m *MockNoteService) CreateNote(userID, bookID uint, req service.NoteRequest) (models.Note, error) {
+	args := m.Called(userID, bookID, req)
+	return args.Get(0).(models.Note), args.Error(1)
+}
+
+func (m *MockNoteService) UpdateNote(userID, id uint, req service.NoteRequest) (models.Note, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(models.Note), args.Error(1)
+}
+
+func (m *MockNoteService) DeleteNote(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNoteService) ListUserNotes(userID, bookID uint, tag, query string, page, limit int) ([]models.Note, int64, error) {
+	args := m.Called(userID, bookID, tag, query, page, limit)
+	return args.Get(0).([]models.Note), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNoteService) ListPublicBookNotes(bookID uint, page, limit int) ([]models.Note, int64, error) {
+	args := m.Called(bookID, page, limit)
+	return args.Get(0).([]models.Note), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNoteService) ExportMarkdown(userID, bookID uint) (string, error) {
+	args := m.Called(userID, bookID)
+	return args.String(0), args.Error(1)
+}
+
+func TestNoteHandler_CreateNoteHandler_Highlight(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+
+	// Настройка мока
+	page := 42
+	reqBody := service.NoteRequest{Quote: "Clear is better than clever", Page: &page, Tags: []string{"style"}}
+	mockService.On("CreateNote", uint(1), uint(2), reqBody).Return(models.Note{
+		UserID:     1,
+		BookID:     2,
+		Kind:       models.NoteKindHighlight,
+		Quote:      reqBody.Quote,
+		Page:       &page,
+		Tags:       []string{"style"},
+		Visibility: models.NoteVisibilityPrivate,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/books/2/notes", strings.NewReader(`{"quote":"Clear is better than clever","page":42,"tags":["style"]}`))
+	req = withRouteAndUser(req, "id", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CreateNoteHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response NoteResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.NoteKindHighlight, response.Kind)
+	assert.Equal(t, 42, *response.Page)
+	mockService.AssertExpectations(t)
+}
+
+func TestNoteHandler_GetMyNotesHandler_Search(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+
+	mockService.On("ListUserNotes", uint(1), uint(3), "go", "каналы", 1, 10).
+		Return([]models.Note{{BookID: 3, Kind: models.NoteKindNote, Text: "про каналы"}}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/notes?book_id=3&tag=go&q=%D0%BA%D0%B0%D0%BD%D0%B0%D0%BB%D1%8B", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetMyNotesHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedNotesResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, []string{}, response.Data[0].Tags)
+
+	// Некорректный book_id
+	req, _ = http.NewRequest("GET", "/users/me/notes?book_id=abc", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+	rr = httptest.NewRecorder()
+	handler.GetMyNotesHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestNoteHandler_ExportNotesHandler(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+
+	mockService.On("ExportMarkdown", uint(1), uint(2)).Return("# Go\n\n### Заметка · стр. 10\n\nтекст\n", nil)
+	mockService.On("ExportMarkdown", uint(1), uint(9)).Return("", errors.New("book not found"))
+
+	req, _ := http.NewRequest("GET", "/users/me/notes/export/2", nil)
+	req = withRouteAndUser(req, "bookID", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.ExportNotesHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "notes-2.md")
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "# Go"))
+
+	// Несуществующая книга
+	req, _ = http.NewRequest("GET", "/users/me/notes/export/9", nil)
+	req = withRouteAndUser(req, "bookID", "9", &utils.Claims{UserID: "1"})
+	rr = httptest.NewRecorder()
+	handler.ExportNotesHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
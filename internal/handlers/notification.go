@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+func toNotificationResponse(n models.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		BookID:    n.BookID,
+		Title:     n.Title,
+		Body:      n.Body,
+		OldPrice:  notificationPrice(n.OldPrice, n.Currency),
+		NewPrice:  notificationPrice(n.NewPrice, n.Currency),
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func notificationPrice(amount *money.Amount, currency string) *money.Money {
+	if amount == nil {
+		return nil
+	}
+	price := money.New(*amount, currency)
+	return &price
+}
+
+func toNotificationSettingsResponse(s models.NotificationSettings) NotificationSettingsResponse {
+	return NotificationSettingsResponse{
+		PriceDrops:  s.PriceDrops,
+		NewArrivals: s.NewArrivals,
+		Email:       s.Email,
+		WebhookURL:  s.WebhookURL,
+	}
+}
+
+// GetNotificationsHandler godoc
+// @Summary Уведомления
+// @Description Снижение цены на книги из избранного и новые книги авторов и жанров из подписок, новые первыми
+// @Tags Notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Уведомлений на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedNotificationsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/notifications [get]
+func (h *NotificationHandler) GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	items, total, unread, err := h.notificationService.ListNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get notifications"})
+		return
+	}
+
+	response := PaginatedNotificationsResponse{
+		Data:   make([]NotificationResponse, 0, len(items)),
+		Meta:   newPaginationMeta(total, page, limit),
+		Unread: unread,
+	}
+	for _, item := range items {
+		response.Data = append(response.Data, toNotificationResponse(item))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// MarkNotificationReadHandler godoc
+// @Summary Отметить уведомление прочитанным
+// @Tags Notifications
+// @Security ApiKeyAuth
+// @Param id path int true "ID уведомления"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid notification ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsReadHandler godoc
+// @Summary Отметить все уведомления прочитанными
+// @Tags Notifications
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.notificationService.MarkAllRead(userID); err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to update notifications"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetNotificationSettingsHandler godoc
+// @Summary Настройки уведомлений
+// @Tags Notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/notification-settings [get]
+func (h *NotificationHandler) GetNotificationSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	settings, err := h.notificationService.GetSettings(userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toNotificationSettingsResponse(settings))
+}
+
+// UpdateNotificationSettingsHandler godoc
+// @Summary Изменение настроек уведомлений
+// @Description Какие события присылать и куда доставлять их помимо приложения. Пустой email или webhook_url выключает канал.
+// @Description webhook_url должен вести на публичный адрес: loopback, частные и link-local сети отклоняются
+// @Tags Notifications
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.NotificationSettingsRequest true "Настройки"
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/notification-settings [put]
+func (h *NotificationHandler) UpdateNotificationSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.NotificationSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	settings, err := h.notificationService.UpdateSettings(userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toNotificationSettingsResponse(settings))
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) BookChanged(event service.BookEvent) {
+	m.Called(event)
+}
+
+func (m *MockNotificationService) Notify(items []models.Notification) error {
+	args := m.Called(items)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) ListNotifications(userID uint, unreadOnly bool, page, limit int) ([]models.Notification, int64, int64, error) {
+	args := m.Called(userID, unreadOnly, page, limit)
+	return args.Get(0).([]models.Notification), args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockNotificationService) MarkRead(userID, id uint) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) MarkAllRead(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) GetSettings(userID uint) (models.NotificationSettings, error) {
+	args := m.Called(userID)
+	return args.Get(0).(models.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdateSettings(userID uint, req service.NotificationSettingsRequest) (models.NotificationSettings, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(models.NotificationSettings), args.Error(1)
+}
+
+func TestNotificationHandler_GetNotificationsHandler_Unread(t *testing.T) {
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	// Настройка мока
+	oldPrice, newPrice := money.Amount(4999), money.Amount(3999)
+	mockService.On("ListNotifications", uint(1), true, 1, 20).Return([]models.Notification{
+		{
+			ID:        5,
+			Type:      models.NotificationPriceDrop,
+			BookID:    2,
+			Title:     "Цена снижена: «Dune»",
+			OldPrice:  &oldPrice,
+			NewPrice:  &newPrice,
+			Currency:  "USD",
+			CreatedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}, int64(1), int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/notifications?unread=true", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetNotificationsHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedNotificationsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.False(t, response.Data[0].Read)
+	assert.Equal(t, money.New(3999, "USD"), *response.Data[0].NewPrice)
+	assert.Equal(t, int64(1), response.Unread)
+	mockService.AssertExpectations(t)
+}
+
+func TestNotificationHandler_MarkNotificationReadHandler_NotFound(t *testing.T) {
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	mockService.On("MarkRead", uint(1), uint(9)).Return(errors.New("notification not found"))
+
+	req, _ := http.NewRequest("POST", "/users/me/notifications/9/read", nil)
+	req = withRouteAndUser(req, "id", "9", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.MarkNotificationReadHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestNotificationHandler_UpdateNotificationSettingsHandler_InvalidEmail(t *testing.T) {
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	reqBody := service.NotificationSettingsRequest{PriceDrops: true, Email: "not-an-email"}
+	mockService.On("UpdateSettings", uint(1), reqBody).Return(models.NotificationSettings{}, errors.New("invalid email"))
+
+	req, _ := http.NewRequest("PUT", "/users/me/notification-settings", strings.NewReader(`{"price_drops":true,"email":"not-an-email"}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.UpdateNotificationSettingsHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
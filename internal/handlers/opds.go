@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/opds"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	opdsPrefix      = "/opds"
+	opdsV2Prefix    = "/opds/v2"
+	opdsPageSize    = 20
+	opdsTitle       = "BookShelf"
+	opdsAuthRealm   = `Basic realm="BookShelf OPDS"`
+	schemaOrgBook   = "http://schema.org/Book"
+	atomContentType = "application/atom+xml"
+)
+
+type OPDSHandler struct {
+	bookService service.BookService
+	favService  service.FavouriteService
+	authService service.AuthService
+	fileService service.FileService
+}
+
+func NewOPDSHandler(bookService service.BookService, favService service.FavouriteService, authService service.AuthService, fileService service.FileService) *OPDSHandler {
+	return &OPDSHandler{bookService: bookService, favService: favService, authService: authService, fileService: fileService}
+}
+
+// opdsPage - лента в общем виде, из неё собираются и Atom (OPDS 1.2), и JSON (OPDS 2.0).
+// Навигационная лента заполняет navigation, лента книг - books и пагинацию
+type opdsPage struct {
+	path       string
+	title      string
+	navigation []opdsNavItem
+	books      []service.BookBrief
+	files      map[uint][]models.BookFile
+	query      url.Values
+	page       int
+	limit      int
+	total      int64
+}
+
+type opdsNavItem struct {
+	title       string
+	path        string
+	description string
+	rel         string
+	acquisition bool
+}
+
+// RootHandler godoc
+// @Summary Корневой каталог OPDS
+// @Description Навигационная лента: новинки, жанры, избранное. /opds отдаёт Atom (OPDS 1.2) или JSON при Accept: application/opds+json, /opds/v2 - всегда OPDS 2.0
+// @Tags OPDS
+// @Produce xml
+// @Produce json
+// @Success 200
+// @Router /opds [get]
+// @Router /opds/v2 [get]
+func (h *OPDSHandler) RootHandler(w http.ResponseWriter, r *http.Request) {
+	h.render(w, r, opdsPage{
+		path:  "",
+		title: opdsTitle,
+		navigation: []opdsNavItem{
+			{title: "Новинки", path: "/new", description: "Недавно добавленные книги", rel: opds.RelNew, acquisition: true},
+			{title: "Жанры", path: "/genres", description: "Книги по жанрам", rel: opds.RelSubsection},
+			{title: "Избранное", path: "/favourites", description: "Ваши избранные книги (нужна авторизация)", rel: opds.RelShelf, acquisition: true},
+		},
+	})
+}
+
+// NewestHandler godoc
+// @Summary Новинки в OPDS
+// @Description Лента книг, отсортированных по дате добавления
+// @Tags OPDS
+// @Produce xml
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200
+// @Failure 500 {object} ErrorResponse
+// @Router /opds/new [get]
+// @Router /opds/v2/new [get]
+func (h *OPDSHandler) NewestHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, opdsPageSize)
+	books, total, err := h.bookService.GetNewestBooks(page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Couldn't find books"})
+		return
+	}
+
+	h.render(w, r, opdsPage{path: "/new", title: "Новинки", books: books, page: page, limit: limit, total: total})
+}
+
+// GenresHandler godoc
+// @Summary Жанры в OPDS
+// @Description Навигационная лента со списком жанров
+// @Tags OPDS
+// @Produce xml
+// @Produce json
+// @Success 200
+// @Failure 500 {object} ErrorResponse
+// @Router /opds/genres [get]
+// @Router /opds/v2/genres [get]
+func (h *OPDSHandler) GenresHandler(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.bookService.GetAllGenres()
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Couldn't get genres"})
+		return
+	}
+
+	navigation := make([]opdsNavItem, 0, len(genres))
+	for _, genre := range genres {
+		navigation = append(navigation, opdsNavItem{
+			title:       genre,
+			path:        "/genres/" + url.PathEscape(genre),
+			rel:         opds.RelSubsection,
+			acquisition: true,
+		})
+	}
+	h.render(w, r, opdsPage{path: "/genres", title: "Жанры", navigation: navigation})
+}
+
+// GenreBooksHandler godoc
+// @Summary Книги жанра в OPDS
+// @Description Лента книг выбранного жанра
+// @Tags OPDS
+// @Produce xml
+// @Produce json
+// @Param genre path string true "Жанр"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200
+// @Failure 500 {object} ErrorResponse
+// @Router /opds/genres/{genre} [get]
+// @Router /opds/v2/genres/{genre} [get]
+func (h *OPDSHandler) GenreBooksHandler(w http.ResponseWriter, r *http.Request) {
+	// chi уже раскодировал путь, повторный PathUnescape испортил бы жанры с "%"
+	genre := chi.URLParam(r, "genre")
+	page, limit := parsePagination(r, opdsPageSize)
+	books, total, err := h.bookService.GetAllBooks(genre, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Couldn't find books"})
+		return
+	}
+
+	h.render(w, r, opdsPage{
+		path:  "/genres/" + url.PathEscape(genre),
+		title: genre,
+		books: books,
+		page:  page,
+		limit: limit,
+		total: total,
+	})
+}
+
+// FavouritesHandler godoc
+// @Summary Избранное в OPDS
+// @Description Лента избранных книг. Принимает Bearer-токен или HTTP Basic (логин и пароль), так как читалки обычно умеют только Basic
+// @Tags OPDS
+// @Security ApiKeyAuth
+// @Produce xml
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /opds/favourites [get]
+// @Router /opds/v2/favourites [get]
+func (h *OPDSHandler) FavouritesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := h.currentUser(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", opdsAuthRealm)
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"Authentication required"})
+		return
+	}
+
+	page, limit := parsePagination(r, opdsPageSize)
+	books, total, err := h.favService.GetFavourites(userID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get favourites"})
+		return
+	}
+
+	h.render(w, r, opdsPage{path: "/favourites", title: "Избранное", books: books, page: page, limit: limit, total: total})
+}
+
+// SearchHandler godoc
+// @Summary Поиск в OPDS
+// @Description Поиск книг по названию или автору. OPDS 1.2 передаёт запрос в q (шаблон из OpenSearch), OPDS 2.0 - в query
+// @Tags OPDS
+// @Produce xml
+// @Produce json
+// @Param q query string false "Строка поиска"
+// @Param query query string false "Строка поиска (OPDS 2.0)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /opds/search [get]
+// @Router /opds/v2/search [get]
+func (h *OPDSHandler) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		query = strings.TrimSpace(r.URL.Query().Get("query"))
+	}
+	if query == "" {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Search query is required"})
+		return
+	}
+
+	page, limit := parsePagination(r, opdsPageSize)
+	books, total, err := h.bookService.SearchBooks(query, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Couldn't find books"})
+		return
+	}
+
+	h.render(w, r, opdsPage{
+		path:  "/search",
+		title: fmt.Sprintf("Поиск: %s", query),
+		books: books,
+		query: url.Values{"q": {query}},
+		page:  page,
+		limit: limit,
+		total: total,
+	})
+}
+
+// OpenSearchHandler godoc
+// @Summary Описание OpenSearch
+// @Description OpenSearch description, на который ссылаются ленты OPDS 1.2
+// @Tags OPDS
+// @Produce xml
+// @Success 200
+// @Router /opds/opensearch.xml [get]
+func (h *OPDSHandler) OpenSearchHandler(w http.ResponseWriter, r *http.Request) {
+	description := opds.OpenSearchDescription{
+		Xmlns:          opds.OpenSearchNamespace,
+		ShortName:      opdsTitle,
+		Description:    "Поиск книг по названию или автору",
+		InputEncoding:  "UTF-8",
+		OutputEncoding: "UTF-8",
+		URLs: []opds.OpenSearchURL{
+			{Type: opds.AcquisitionType, Template: opdsPrefix + "/search?q={searchTerms}&page={startPage?}"},
+		},
+	}
+	writeXML(w, opds.OpenSearchType, description)
+}
+
+// DownloadHandler godoc
+// @Summary Скачивание книги из OPDS
+// @Description Ссылка acquisition из лент: перенаправляет на подписанную ссылку файла. Доступна купившим книгу и администраторам,
+// @Description принимает Bearer-токен или HTTP Basic
+// @Tags OPDS
+// @Security ApiKeyAuth
+// @Param id path int true "ID книги"
+// @Param format path string true "Формат (epub, pdf)"
+// @Success 302
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /opds/books/{id}/download/{format} [get]
+func (h *OPDSHandler) DownloadHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, role, ok := h.currentUser(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", opdsAuthRealm)
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"Authentication required"})
+		return
+	}
+
+	link, _, err := h.fileService.DownloadURL(userID, role, bookID, chi.URLParam(r, "format"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	http.Redirect(w, r, link, http.StatusFound)
+}
+
+// currentUser берёт пользователя из Bearer-токена или, для читалок, из HTTP Basic
+func (h *OPDSHandler) currentUser(r *http.Request) (uint, string, bool) {
+	if userID, ok := currentUserID(r); ok {
+		return userID, currentRole(r), true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return 0, "", false
+	}
+	user, err := h.authService.LoginUser(username, password)
+	if err != nil {
+		return 0, "", false
+	}
+	return user.ID, user.Role, true
+}
+
+// wantsOPDSv2: /opds/v2 всегда JSON, остальные пути - по заголовку Accept, по умолчанию Atom
+func wantsOPDSv2(r *http.Request) bool {
+	if r.URL.Path == opdsV2Prefix || strings.HasPrefix(r.URL.Path, opdsV2Prefix+"/") {
+		return true
+	}
+	chosen := negotiate(r.Header.Get("Accept"), atomContentType, opds.JSONType, "application/json")
+	return chosen == opds.JSONType || chosen == "application/json"
+}
+
+func (h *OPDSHandler) render(w http.ResponseWriter, r *http.Request, p opdsPage) {
+	if len(p.books) > 0 {
+		ids := make([]uint, 0, len(p.books))
+		for _, book := range p.books {
+			ids = append(ids, book.ID)
+		}
+		// Без файлов лента всё равно полезна: книги останутся со ссылками на покупку
+		files, err := h.fileService.FilesByBooks(ids)
+		if err != nil {
+			log.Printf("opds: load files for %s: %s", p.path, err.Error())
+		}
+		p.files = files
+	}
+
+	w.Header().Set("Vary", "Accept")
+	if wantsOPDSv2(r) {
+		w.Header().Set("Content-Type", opds.JSONType)
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(p.feedV2()); err != nil {
+			log.Printf("opds: write feed %s: %s", p.path, err.Error())
+		}
+		return
+	}
+
+	contentType := opds.AcquisitionType
+	if p.navigation != nil {
+		contentType = opds.NavigationType
+	}
+	writeXML(w, contentType, p.feedV1())
+}
+
+func writeXML(w http.ResponseWriter, contentType string, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("opds: write xml: %s", err.Error())
+	}
+}
+
+// href собирает ссылку на страницу ленты с сохранением поискового запроса
+func (p opdsPage) href(prefix string, page int) string {
+	query := url.Values{}
+	for k, v := range p.query {
+		query[k] = v
+	}
+	if page > 1 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if p.limit > 0 && p.limit != opdsPageSize {
+		query.Set("limit", strconv.Itoa(p.limit))
+	}
+	if len(query) == 0 {
+		return prefix + p.path
+	}
+	return prefix + p.path + "?" + query.Encode()
+}
+
+// bookLinks - ссылки книги: карточка, обложка и acquisition на каждый загруженный файл.
+// Книга без файлов получает ссылку на покупку
+func (p opdsPage) bookLinks(book service.BookBrief, selfRel string) []opds.Link {
+	bookURL := fmt.Sprintf("/books/%d", book.ID)
+	links := []opds.Link{
+		{Rel: selfRel, Href: bookURL, Type: "application/json"},
+		{Rel: opds.RelAlternate, Href: bookURL, Type: marcXMLContentType},
+	}
+
+	acquisition := false
+	for _, file := range p.files[book.ID] {
+		switch file.Format {
+		case models.FileCover:
+			links = append(links, opds.Link{Rel: opds.RelImage, Href: bookURL + "/cover", Type: file.ContentType})
+		default:
+			acquisition = true
+			links = append(links, opds.Link{
+				Rel:  opds.RelAcquisition,
+				Href: fmt.Sprintf("%s/books/%d/download/%s", opdsPrefix, book.ID, file.Format),
+				Type: file.ContentType,
+			})
+		}
+	}
+	if !acquisition {
+		links = append(links, opds.Link{Rel: opds.RelBuy, Href: bookURL, Type: "application/json"})
+	}
+	return links
+}
+
+// pageLinks - ссылки first/previous/next/last для лент с книгами
+func (p opdsPage) pageLinks(prefix, linkType string) []opds.Link {
+	if p.limit == 0 || p.total <= int64(p.limit) {
+		return nil
+	}
+	last := int((p.total + int64(p.limit) - 1) / int64(p.limit))
+
+	links := []opds.Link{{Rel: opds.RelFirst, Href: p.href(prefix, 1), Type: linkType}}
+	if p.page > 1 {
+		links = append(links, opds.Link{Rel: opds.RelPrevious, Href: p.href(prefix, p.page-1), Type: linkType})
+	}
+	if p.page < last {
+		links = append(links, opds.Link{Rel: opds.RelNext, Href: p.href(prefix, p.page+1), Type: linkType})
+	}
+	return append(links, opds.Link{Rel: opds.RelLast, Href: p.href(prefix, last), Type: linkType})
+}
+
+func (p opdsPage) feedV1() opds.Feed {
+	updated := time.Now().UTC().Format(time.RFC3339)
+	selfType := opds.AcquisitionType
+	if p.navigation != nil {
+		selfType = opds.NavigationType
+	}
+
+	feed := opds.NewFeed("urn:bookshelf:opds"+p.path, p.title, updated)
+	feed.Author = &opds.Author{Name: opdsTitle}
+	feed.Links = []opds.Link{
+		{Rel: opds.RelSelf, Href: p.href(opdsPrefix, p.page), Type: selfType},
+		{Rel: opds.RelStart, Href: opdsPrefix, Type: opds.NavigationType},
+		{Rel: opds.RelSearch, Href: opdsPrefix + "/opensearch.xml", Type: opds.OpenSearchType},
+	}
+	feed.Links = append(feed.Links, p.pageLinks(opdsPrefix, opds.AcquisitionType)...)
+
+	for _, item := range p.navigation {
+		linkType := opds.NavigationType
+		if item.acquisition {
+			linkType = opds.AcquisitionType
+		}
+		entry := opds.Entry{
+			ID:      "urn:bookshelf:opds" + item.path,
+			Title:   item.title,
+			Updated: updated,
+			Links:   []opds.Link{{Rel: item.rel, Href: opdsPrefix + item.path, Type: linkType}},
+		}
+		if item.description != "" {
+			entry.Content = &opds.Content{Type: "text", Value: item.description}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if p.navigation == nil {
+		feed.TotalResults = p.total
+		feed.ItemsPerPage = p.limit
+		feed.StartIndex = (p.page-1)*p.limit + 1
+	}
+	for _, book := range p.books {
+		feed.Entries = append(feed.Entries, opds.Entry{
+			ID:         fmt.Sprintf("urn:bookshelf:book:%d", book.ID),
+			Title:      book.Title,
+			Updated:    updated,
+			Authors:    []opds.Author{{Name: book.Author}},
+			Categories: []opds.Category{{Term: book.Genre, Label: book.Genre}},
+			Content:    &opds.Content{Type: "text", Value: fmt.Sprintf("%s. Цена: %s", book.Genre, book.Price)},
+			Links:      p.bookLinks(book, opds.RelAlternate),
+		})
+	}
+	return feed
+}
+
+func (p opdsPage) feedV2() opds.FeedV2 {
+	feed := opds.FeedV2{
+		Metadata: opds.Metadata{Title: p.title},
+		Links: []opds.Link{
+			{Rel: opds.RelSelf, Href: p.href(opdsV2Prefix, p.page), Type: opds.JSONType},
+			{Rel: opds.RelStart, Href: opdsV2Prefix, Type: opds.JSONType},
+			{Rel: opds.RelSearch, Href: opdsV2Prefix + "/search{?query}", Type: opds.JSONType, Templated: true},
+		},
+	}
+	feed.Links = append(feed.Links, p.pageLinks(opdsV2Prefix, opds.JSONType)...)
+
+	for _, item := range p.navigation {
+		feed.Navigation = append(feed.Navigation, opds.Link{
+			Rel:   item.rel,
+			Href:  opdsV2Prefix + item.path,
+			Type:  opds.JSONType,
+			Title: item.title,
+		})
+	}
+
+	if p.navigation != nil {
+		return feed
+	}
+	feed.Metadata.NumberOfItems = p.total
+	feed.Metadata.ItemsPerPage = p.limit
+	feed.Metadata.CurrentPage = p.page
+	feed.Publications = make([]opds.Publication, 0, len(p.books))
+	for _, book := range p.books {
+		feed.Publications = append(feed.Publications, opds.Publication{
+			Metadata: opds.PublicationMetadata{
+				Type:       schemaOrgBook,
+				Identifier: fmt.Sprintf("urn:bookshelf:book:%d", book.ID),
+				Title:      book.Title,
+				Author:     []opds.Contributor{{Name: book.Author}},
+				Subject:    []opds.Subject{{Name: book.Genre}},
+			},
+			Links: p.bookLinks(book, opds.RelSelf),
+		})
+	}
+	return feed
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/opds"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOPDSHandler_NewestHandler_Atom(t *testing.T) {
+	mockBooks := new(MockBookService)
+	mockFiles := new(MockFileService)
+	handler := NewOPDSHandler(mockBooks, new(MockFavouriteService), new(MockAuthService), mockFiles)
+
+	// Настройка мока: 25 книг, на странице 20 - должна появиться ссылка next
+	briefs := []service.BookBrief{
+		{ID: 7, Title: "Go Programming", Author: "Alan Donovan", Genre: "Programming", Price: money.New(3999, "USD")},
+		{ID: 8, Title: "Paper Only", Author: "Anon", Genre: "Programming", Price: money.New(999, "USD")},
+	}
+	mockBooks.On("GetNewestBooks", 1, 20).Return(briefs, int64(25), nil)
+	mockFiles.On("FilesByBooks", []uint{7, 8}).Return(map[uint][]models.BookFile{
+		7: {{BookID: 7, Format: models.FileEPUB, ContentType: "application/epub+zip"}},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/opds/new", nil)
+	rr := httptest.NewRecorder()
+	handler.NewestHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, opds.AcquisitionType, rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "<id>urn:bookshelf:book:7</id>")
+	assert.Contains(t, rr.Body.String(), `rel="next" href="/opds/new?page=2"`)
+	assert.Contains(t, rr.Body.String(), `href="/opds/opensearch.xml"`)
+	assert.Contains(t, rr.Body.String(), `rel="http://opds-spec.org/acquisition" href="/opds/books/7/download/epub" type="application/epub+zip"`)
+	assert.Contains(t, rr.Body.String(), `rel="http://opds-spec.org/acquisition/buy" href="/books/8"`)
+	mockBooks.AssertExpectations(t)
+	mockFiles.AssertExpectations(t)
+}
+
+func TestOPDSHandler_GenresHandler_JSON(t *testing.T) {
+	mockBooks := new(MockBookService)
+	handler := NewOPDSHandler(mockBooks, new(MockFavouriteService), new(MockAuthService), new(MockFileService))
+
+	mockBooks.On("GetAllGenres").Return([]string{"Science Fiction"}, nil)
+
+	req, _ := http.NewRequest("GET", "/opds/v2/genres", nil)
+	rr := httptest.NewRecorder()
+	handler.GenresHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, opds.JSONType, rr.Header().Get("Content-Type"))
+
+	var feed opds.FeedV2
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &feed))
+	assert.Len(t, feed.Navigation, 1)
+	assert.Equal(t, "/opds/v2/genres/Science%20Fiction", feed.Navigation[0].Href)
+	mockBooks.AssertExpectations(t)
+}
+
+func TestOPDSHandler_FavouritesHandler_BasicAuth(t *testing.T) {
+	mockFav := new(MockFavouriteService)
+	mockAuth := new(MockAuthService)
+	mockFiles := new(MockFileService)
+	handler := NewOPDSHandler(new(MockBookService), mockFav, mockAuth, mockFiles)
+
+	// Без учётных данных читалка должна получить запрос Basic-авторизации
+	req, _ := http.NewRequest("GET", "/opds/favourites", nil)
+	rr := httptest.NewRecorder()
+	handler.FavouritesHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "Basic")
+
+	// С логином и паролем
+	mockAuth.On("LoginUser", "reader", "secret").Return(models.User{Model: gorm.Model{ID: 3}}, nil)
+	mockAuth.On("LoginUser", "reader", "wrong").Return(models.User{}, errors.New("invalid credentials"))
+	mockFav.On("GetFavourites", uint(3), 1, 20).Return([]service.BookBrief{{ID: 1, Title: "Fav Book"}}, int64(1), nil)
+	mockFiles.On("FilesByBooks", []uint{1}).Return(map[uint][]models.BookFile{}, nil)
+
+	req, _ = http.NewRequest("GET", "/opds/favourites", nil)
+	req.SetBasicAuth("reader", "secret")
+	rr = httptest.NewRecorder()
+	handler.FavouritesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Fav Book")
+
+	req, _ = http.NewRequest("GET", "/opds/favourites", nil)
+	req.SetBasicAuth("reader", "wrong")
+	rr = httptest.NewRecorder()
+	handler.FavouritesHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockFav.AssertExpectations(t)
+	mockAuth.AssertExpectations(t)
+}
+
+func TestOPDSHandler_DownloadHandler(t *testing.T) {
+	mockAuth := new(MockAuthService)
+	mockFiles := new(MockFileService)
+	handler := NewOPDSHandler(new(MockBookService), new(MockFavouriteService), mockAuth, mockFiles)
+
+	// Настройка мока
+	mockAuth.On("LoginUser", "reader", "secret").Return(models.User{Model: gorm.Model{ID: 3}, Role: "user"}, nil)
+	mockFiles.On("DownloadURL", uint(3), "user", uint(7), "epub").
+		Return("/files/12?expires=1700000000&signature=abc", time.Unix(1700000000, 0), nil)
+
+	req, _ := http.NewRequest("GET", "/opds/books/7/download/epub", nil)
+	req.SetBasicAuth("reader", "secret")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "7")
+	rctx.URLParams.Add("format", "epub")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	handler.DownloadHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/files/12?expires=1700000000&signature=abc", rr.Header().Get("Location"))
+	mockAuth.AssertExpectations(t)
+	mockFiles.AssertExpectations(t)
+}
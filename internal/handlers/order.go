@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type OrderHandler struct {
+	orderService service.OrderService
+}
+
+func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+func toOrderResponse(order models.Order) OrderResponse {
+	response := OrderResponse{
+		ID:            order.ID,
+		UserID:        order.UserID,
+		Status:        order.Status,
+		Items:         make([]OrderItemResponse, 0, len(order.Items)),
+		Total:         money.New(order.Total, order.Currency),
+		CouponCode:    order.CouponCode,
+		ReservedUntil: order.ReservedUntil,
+		CreatedAt:     order.CreatedAt,
+		PaidAt:        order.PaidAt,
+		FulfilledAt:   order.FulfilledAt,
+		CancelledAt:   order.CancelledAt,
+		RefundedAt:    order.RefundedAt,
+	}
+	for _, item := range order.Items {
+		response.Items = append(response.Items, OrderItemResponse{
+			BookID:    item.BookID,
+			Title:     item.Title,
+			Author:    item.Author,
+			UnitPrice: money.New(item.UnitPrice, order.Currency),
+			Quantity:  item.Quantity,
+			LineTotal: money.New(item.LineTotal, order.Currency),
+		})
+	}
+	return response
+}
+
+func toPaginatedOrdersResponse(orders []models.Order, total int64, page, limit int) PaginatedOrdersResponse {
+	response := PaginatedOrdersResponse{
+		Data: make([]OrderResponse, 0, len(orders)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, order := range orders {
+		response.Data = append(response.Data, toOrderResponse(order))
+	}
+	return response
+}
+
+// CheckoutOrderHandler godoc
+// @Summary Оформить заказ
+// @Description Создаёт заказ в статусе pending из всей корзины. Цены копируются в заказ и больше не меняются, корзина очищается
+// @Description Заказ выставляется в валюте currency и оплачивается в ней же. Цены учитывают действующие распродажи
+// @Description Купон снижает цену книг, на которые действует, если это дешевле распродажи; скидки не суммируются
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param currency query string false "Валюта заказа, код ISO 4217 (по умолчанию базовая)"
+// @Param input body service.OrderCheckoutRequest false "Код купона"
+// @Success 201 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders [post]
+func (h *OrderHandler) CheckoutOrderHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	// Тело необязательно
+	var req service.OrderCheckoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+			return
+		}
+	}
+
+	order, err := h.orderService.Checkout(userID, r.URL.Query().Get("currency"), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toOrderResponse(order))
+}
+
+// GetMyOrdersHandler godoc
+// @Summary История заказов
+// @Description Заказы пользователя, новые первыми
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Заказов на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedOrdersResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/orders [get]
+func (h *OrderHandler) GetMyOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	orders, total, err := h.orderService.ListUserOrders(userID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get orders"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedOrdersResponse(orders, total, page, limit))
+}
+
+// GetOrderHandler godoc
+// @Summary Заказ
+// @Description Покупатель видит свои заказы, администратор - любые
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /orders/{id} [get]
+func (h *OrderHandler) GetOrderHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid order ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	order, err := h.orderService.GetOrder(userID, currentRole(r), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toOrderResponse(order))
+}
+
+// CancelOrderHandler godoc
+// @Summary Отменить заказ
+// @Description Покупатель может отменить только неоплаченный заказ
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid order ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	order, err := h.orderService.Cancel(userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toOrderResponse(order))
+}
+
+// GetOrdersHandler godoc
+// @Summary Все заказы
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query string false "Статус: pending, paid, fulfilled, cancelled, refunded"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Заказов на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedOrdersResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/orders [get]
+func (h *OrderHandler) GetOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+	orders, total, err := h.orderService.ListOrders(r.URL.Query().Get("status"), page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedOrdersResponse(orders, total, page, limit))
+}
+
+// UpdateOrderStatusHandler godoc
+// @Summary Отметить заказ выполненным
+// @Description Доступен только переход paid -> fulfilled. Оплата проходит через платёжный шлюз, возврат и отмена оплаченного заказа - через POST /admin/orders/{id}/refund
+// @Tags Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Param input body service.OrderStatusRequest true "Новый статус"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/orders/{id}/status [put]
+func (h *OrderHandler) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid order ID"})
+		return
+	}
+
+	var req service.OrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	order, err := h.orderService.UpdateStatus(id, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toOrderResponse(order))
+}
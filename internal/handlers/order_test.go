@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrderService struct {
+	mock.Mock
+}
+
+func (m *MockOrderService) Checkout(userID uint, currency string, req service.OrderCheckoutRequest) (models.Order, error) {
+	args := m.Called(userID, currency, req)
+	return args.Get(0).(models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) GetOrder(userID uint, role string, id uint) (models.Order, error) {
+	args := m.Called(userID, role, id)
+	return args.Get(0).(models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) ListUserOrders(userID uint, page, limit int) ([]models.Order, int64, error) {
+	args := m.Called(userID, page, limit)
+	return args.Get(0).([]models.Order), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOrderService) ListOrders(status string, page, limit int) ([]models.Order, int64, error) {
+	args := m.Called(status, page, limit)
+	return args.Get(0).([]models.Order), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOrderService) Cancel(userID, id uint) (models.Order, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) UpdateStatus(id uint, req service.OrderStatusRequest) (models.Order, error) {
+	args := m.Called(id, req)
+	return args.Get(0).(models.Order), args.Error(1)
+}
+
+func TestOrderHandler_CheckoutOrderHandler_Success(t *testing.T) {
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+
+	// Настройка мока
+	mockService.On("Checkout", uint(1), "EUR", service.OrderCheckoutRequest{}).Return(models.Order{
+		ID:       3,
+		UserID:   1,
+		Status:   models.OrderPending,
+		Total:    3998,
+		Currency: "EUR",
+		Items: []models.OrderItem{
+			{BookID: 1, Title: "Dune", Author: "Frank Herbert", UnitPrice: 1999, Quantity: 2, LineTotal: 3998},
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/orders?currency=EUR", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CheckoutOrderHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response OrderResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.OrderPending, response.Status)
+	assert.Equal(t, "Dune", response.Items[0].Title)
+	assert.Contains(t, rr.Body.String(), `"total":{"amount":"39.98","currency":"EUR"}`)
+	assert.Contains(t, rr.Body.String(), `"unit_price":{"amount":"19.99","currency":"EUR"}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_CheckoutOrderHandler_EmptyCart(t *testing.T) {
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+
+	mockService.On("Checkout", uint(1), "", service.OrderCheckoutRequest{}).Return(models.Order{}, errors.New("invalid cart: it is empty"))
+
+	req, _ := http.NewRequest("POST", "/orders", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CheckoutOrderHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_CheckoutOrderHandler_CouponExhausted(t *testing.T) {
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+
+	mockService.On("Checkout", uint(1), "", service.OrderCheckoutRequest{Coupon: "GOPHER20"}).
+		Return(models.Order{}, errors.New("coupon unavailable: usage limit reached"))
+
+	req, _ := http.NewRequest("POST", "/orders", strings.NewReader(`{"coupon":"GOPHER20"}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.CheckoutOrderHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_GetOrderHandler_OtherUsersOrder(t *testing.T) {
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+
+	mockService.On("GetOrder", uint(2), "user", uint(3)).Return(models.Order{}, errors.New("order not found"))
+
+	req, _ := http.NewRequest("GET", "/orders/3", nil)
+	req = withRouteAndUser(req, "id", "3", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.GetOrderHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_UpdateOrderStatusHandler_GatewayStatusesRejected(t *testing.T) {
+	// Настоящий сервис: запрос отклоняется до обращения к репозиторию
+	handler := NewOrderHandler(service.NewOrderService(nil, nil, nil, nil, 0))
+
+	for _, status := range []string{models.OrderPaid, models.OrderRefunded, models.OrderCancelled} {
+		t.Run(status, func(t *testing.T) {
+			req, _ := http.NewRequest("PUT", "/admin/orders/3/status", strings.NewReader(`{"status":"`+status+`"}`))
+			req = withRouteAndUser(req, "id", "3", &utils.Claims{UserID: "1", Role: "admin"})
+
+			rr := httptest.NewRecorder()
+			handler.UpdateOrderStatusHandler(rr, req)
+
+			// Проверки
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// parsePagination читает page и limit; limit вне 1..100 заменяется на defaultLimit
+func parsePagination(r *http.Request, defaultLimit int) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+func newPaginationMeta(total int64, page, limit int) PaginationMeta {
+	return PaginationMeta{
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/payment"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// maxWebhookBody - события провайдеров занимают единицы килобайт
+const maxWebhookBody = 1 << 20
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+	fake           *payment.FakeProvider
+}
+
+// NewPaymentHandler: fake задаётся, только когда включён фейковый провайдер, и нужен для /payments/fake/confirm
+func NewPaymentHandler(paymentService service.PaymentService, fake *payment.FakeProvider) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService, fake: fake}
+}
+
+func toPaymentResponse(p models.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:            p.ID,
+		OrderID:       p.OrderID,
+		Provider:      p.Provider,
+		IntentID:      p.IntentID,
+		Amount:        money.New(p.Amount, p.Currency),
+		Status:        p.Status,
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+// PayOrderHandler godoc
+// @Summary Оплатить заказ
+// @Description Создаёт платёж у провайдера и возвращает client_secret для платёжной формы. Пока попытка не отклонена, повторный запрос возвращает её же. Заказ станет paid, когда провайдер сообщит о списании
+// @Tags Payments
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 200 {object} PaymentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /orders/{id}/pay [post]
+func (h *PaymentHandler) PayOrderHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid order ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	p, err := h.paymentService.Pay(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := toPaymentResponse(p)
+	if p.Status == models.PaymentPending {
+		response.ClientSecret = p.ClientSecret
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// PaymentWebhookHandler godoc
+// @Summary Вебхук платёжного провайдера
+// @Description Принимает подписанные события провайдера (Stripe-Signature или X-Fake-Payment-Signature). Повторная доставка события безопасна
+// @Tags Payments
+// @Accept json
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) PaymentWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(r.Context(), payload, r.Header); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefundOrderHandler godoc
+// @Summary Вернуть деньги за заказ
+// @Description Запускает возврат у провайдера. Платёж переходит в refund_pending, заказ станет refunded, когда провайдер подтвердит возврат
+// @Tags Payments
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID заказа"
+// @Success 202 {object} PaymentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /admin/orders/{id}/refund [post]
+func (h *PaymentHandler) RefundOrderHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid order ID"})
+		return
+	}
+
+	p, err := h.paymentService.Refund(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusAccepted, toPaymentResponse(p))
+}
+
+// FakeConfirmHandler godoc
+// @Summary Подтвердить оплату у фейкового провайдера
+// @Description Только для разработки (PAYMENT_PROVIDER=fake): заменяет платёжную форму. Провайдер отправит вебхук об авторизации или отказе
+// @Tags Payments
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body FakePaymentConfirmRequest true "client_secret из ответа /orders/{id}/pay"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /payments/fake/confirm [post]
+func (h *PaymentHandler) FakeConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	if h.fake == nil {
+		utils.JSONResponse(w, http.StatusNotFound, ErrorResponse{"Fake payment provider is disabled"})
+		return
+	}
+
+	var req FakePaymentConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClientSecret == "" {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	intent, err := h.fake.Confirm(req.ClientSecret, req.Succeed)
+	switch {
+	case errors.Is(err, payment.ErrIntentNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, ErrorResponse{"Payment not found"})
+	case errors.Is(err, payment.ErrInvalidState):
+		utils.JSONResponse(w, http.StatusConflict, ErrorResponse{"Payment is already confirmed"})
+	case err != nil:
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to confirm payment"})
+	default:
+		utils.JSONResponse(w, http.StatusOK, map[string]string{"intent_id": intent.ID, "status": intent.Status})
+	}
+}
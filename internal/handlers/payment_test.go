@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/payment"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) Pay(ctx context.Context, userID, orderID uint) (models.Payment, error) {
+	args := m.Called(userID, orderID)
+	return args.Get(0).(models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	args := m.Called(string(payload))
+	return args.Error(0)
+}
+
+func (m *MockPaymentService) Refund(ctx context.Context, orderID uint) (models.Payment, error) {
+	args := m.Called(orderID)
+	return args.Get(0).(models.Payment), args.Error(1)
+}
+
+func TestPaymentHandler_PayOrderHandler_Success(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService, nil)
+
+	// Настройка мока
+	mockService.On("Pay", uint(1), uint(3)).Return(models.Payment{
+		ID:           7,
+		OrderID:      3,
+		Provider:     "fake",
+		IntentID:     "fake_pi_1",
+		ClientSecret: "fake_pi_1_secret_abc",
+		Amount:       3998,
+		Currency:     "USD",
+		Status:       models.PaymentPending,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/orders/3/pay", nil)
+	req = withRouteAndUser(req, "id", "3", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.PayOrderHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaymentResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "fake_pi_1_secret_abc", response.ClientSecret)
+	assert.Equal(t, models.PaymentPending, response.Status)
+	assert.Contains(t, rr.Body.String(), `"amount":"39.98"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_PaymentWebhookHandler_InvalidSignature(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService, nil)
+
+	body := `{"id":"evt_1","type":"payment.succeeded"}`
+	mockService.On("HandleWebhook", body).Return(errors.New("invalid webhook signature"))
+
+	req, _ := http.NewRequest("POST", "/payments/webhook", strings.NewReader(body))
+
+	rr := httptest.NewRecorder()
+	handler.PaymentWebhookHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_FakeConfirmHandler_SendsSignedWebhook(t *testing.T) {
+	fake := payment.NewFakeProvider("test-secret")
+	handler := NewPaymentHandler(new(MockPaymentService), fake)
+
+	intent, err := fake.CreateIntent(context.Background(), payment.IntentParams{Amount: 3998, Currency: "usd", OrderID: 3})
+	assert.NoError(t, err)
+
+	var events []payment.Event
+	fake.SetWebhook(func(payload []byte, header http.Header) {
+		event, err := fake.VerifyWebhook(payload, header)
+		assert.NoError(t, err)
+		events = append(events, event)
+	})
+
+	req, _ := http.NewRequest("POST", "/payments/fake/confirm", strings.NewReader(`{"client_secret":"`+intent.ClientSecret+`","succeed":true}`))
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.FakeConfirmHandler(rr, req)
+	fake.Wait()
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, events, 1)
+	assert.Equal(t, payment.EventAuthorized, events[0].Type)
+	assert.Equal(t, intent.ID, events[0].IntentID)
+
+	// Подтвердить второй раз нельзя
+	req, _ = http.NewRequest("POST", "/payments/fake/confirm", strings.NewReader(`{"client_secret":"`+intent.ClientSecret+`","succeed":true}`))
+	rr = httptest.NewRecorder()
+	handler.FakeConfirmHandler(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PopularityHandler struct {
+	popularityService service.PopularityService
+}
+
+func NewPopularityHandler(popularityService service.PopularityService) *PopularityHandler {
+	return &PopularityHandler{popularityService: popularityService}
+}
+
+// viewerKey различает посетителей для дедупликации просмотров: пользователя - по токену,
+// анонима - по IP и User-Agent. Маршрут книги публичный, поэтому токен разбирается здесь же.
+// IP берётся из соединения: X-Forwarded-For подделывается клиентом и позволил бы накручивать просмотры
+func viewerKey(r *http.Request) string {
+	if userID, ok := currentUserID(r); ok {
+		return "u" + strconv.FormatUint(uint64(userID), 10)
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if claims, err := utils.ParseToken(token); err == nil {
+			return "u" + claims.UserID
+		}
+	}
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	sum := sha256.Sum256([]byte(ip + "|" + r.UserAgent()))
+	return "a" + hex.EncodeToString(sum[:8])
+}
+
+func toPaginatedPopularBooks(books []service.PopularBook, total int64, page, limit int) PaginatedPopularBooksResponse {
+	response := PaginatedPopularBooksResponse{
+		Data: make([]PopularBookResponse, 0, len(books)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, book := range books {
+		response.Data = append(response.Data, PopularBookResponse{
+			Book:       toBookBriefResponse(book.Book),
+			Views:      book.Views,
+			Favourites: book.Favourites,
+			Score:      book.Score,
+		})
+	}
+	return response
+}
+
+// GetTrendingHandler godoc
+// @Summary Книги в тренде
+// @Description Просмотры и добавления в избранное за последние 7 дней; вклад каждого дня убывает вдвое за 2 дня
+// @Tags Books
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedPopularBooksResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/trending [get]
+func (h *PopularityHandler) GetTrendingHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 10)
+	books, total, err := h.popularityService.GetTrending(page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get trending books"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedPopularBooks(books, total, page, limit))
+}
+
+// GetPopularHandler godoc
+// @Summary Популярные книги
+// @Description Сумма просмотров и добавлений в избранное (с весом 5) за период
+// @Tags Books
+// @Produce json
+// @Param period query string false "Период" Enums(week, month, all) default(week)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedPopularBooksResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/popular [get]
+func (h *PopularityHandler) GetPopularHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 10)
+	books, total, err := h.popularityService.GetPopular(r.URL.Query().Get("period"), page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedPopularBooks(books, total, page, limit))
+}
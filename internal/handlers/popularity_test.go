@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPopularityService struct {
+	mock.Mock
+}
+
+func (m *MockPopularityService) RecordView(bookID uint, viewer, userAgent string) {
+	m.Called(bookID, viewer, userAgent)
+}
+
+func (m *MockPopularityService) FavouriteAdded(userID, bookID uint) {
+	m.Called(userID, bookID)
+}
+
+func (m *MockPopularityService) Flush(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockPopularityService) GetTrending(page, limit int) ([]service.PopularBook, int64, error) {
+	args := m.Called(page, limit)
+	return args.Get(0).([]service.PopularBook), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPopularityService) GetPopular(period string, page, limit int) ([]service.PopularBook, int64, error) {
+	args := m.Called(period, page, limit)
+	return args.Get(0).([]service.PopularBook), args.Get(1).(int64), args.Error(2)
+}
+
+func TestPopularityHandler_GetTrendingHandler_Success(t *testing.T) {
+	mockService := new(MockPopularityService)
+	handler := NewPopularityHandler(mockService)
+
+	// Настройка мока
+	mockService.On("GetTrending", 1, 5).Return([]service.PopularBook{
+		{Book: service.BookBrief{ID: 2, Title: "Concurrency in Go"}, Views: 120, Favourites: 6, Score: 97.5},
+	}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/books/trending?limit=5", nil)
+	rr := httptest.NewRecorder()
+	handler.GetTrendingHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedPopularBooksResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, int64(120), response.Data[0].Views)
+	assert.Equal(t, int64(1), response.Meta.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestPopularityHandler_GetPopularHandler_InvalidPeriod(t *testing.T) {
+	mockService := new(MockPopularityService)
+	handler := NewPopularityHandler(mockService)
+
+	mockService.On("GetPopular", "year", 1, 10).
+		Return([]service.PopularBook(nil), int64(0), errors.New("invalid period, must be 'week', 'month' or 'all'"))
+
+	req, _ := http.NewRequest("GET", "/books/popular?period=year", nil)
+	rr := httptest.NewRecorder()
+	handler.GetPopularHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestViewerKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/books/1", nil)
+	req.RemoteAddr = "10.0.0.1:52344"
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	first := viewerKey(req)
+
+	// Другой порт того же посетителя - тот же ключ
+	req.RemoteAddr = "10.0.0.1:60000"
+	assert.Equal(t, first, viewerKey(req))
+
+	// Подставленный X-Forwarded-For не делает посетителя новым
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	assert.Equal(t, first, viewerKey(req))
+
+	// Другой браузер - другой посетитель
+	req.Header.Set("User-Agent", "Safari")
+	assert.NotEqual(t, first, viewerKey(req))
+
+	// С невалидным токеном посетитель остаётся анонимом
+	req.Header.Set("Authorization", "Bearer invalid")
+	assert.True(t, strings.HasPrefix(viewerKey(req), "a"))
+
+	// Пользователь узнаётся по claims независимо от IP
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "7"})
+	assert.Equal(t, "u7", viewerKey(req))
+}
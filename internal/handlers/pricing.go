@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PricingHandler struct {
+	pricingService service.PricingService
+}
+
+func NewPricingHandler(pricingService service.PricingService) *PricingHandler {
+	return &PricingHandler{pricingService: pricingService}
+}
+
+func (h *PricingHandler) toExchangeRatesResponse(rates []models.ExchangeRate) ExchangeRatesResponse {
+	response := ExchangeRatesResponse{
+		Base:  h.pricingService.BaseCurrency(),
+		Rates: make([]ExchangeRateResponse, 0, len(rates)),
+	}
+	for _, rate := range rates {
+		response.Rates = append(response.Rates, ExchangeRateResponse{
+			Currency:  rate.Currency,
+			Rate:      rate.Rate,
+			UpdatedAt: rate.UpdatedAt,
+		})
+	}
+	return response
+}
+
+// GetExchangeRatesHandler godoc
+// @Summary Курсы валют
+// @Description Курсы к базовой валюте каталога: сколько единиц валюты за одну единицу базовой
+// @Tags Pricing
+// @Produce json
+// @Success 200 {object} ExchangeRatesResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /exchange-rates [get]
+func (h *PricingHandler) GetExchangeRatesHandler(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.pricingService.GetRates()
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get exchange rates"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, h.toExchangeRatesResponse(rates))
+}
+
+// UploadExchangeRatesHandler godoc
+// @Summary Загрузить курсы валют
+// @Description Заменяет таблицу курсов целиком. Валюты, которых нет в запросе, перестают пересчитываться
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body service.ExchangeRatesRequest true "Курсы к базовой валюте"
+// @Success 200 {object} ExchangeRatesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/exchange-rates [put]
+func (h *PricingHandler) UploadExchangeRatesHandler(w http.ResponseWriter, r *http.Request) {
+	var req service.ExchangeRatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	rates, err := h.pricingService.UploadRates(req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, h.toExchangeRatesResponse(rates))
+}
+
+// GetBookPricesHandler godoc
+// @Summary Прайс-лист книги
+// @Description Цена книги в её валюте и цены, заданные вручную в других валютах
+// @Tags Pricing
+// @Produce json
+// @Param id path int true "ID книги"
+// @Success 200 {object} BookPricesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/prices [get]
+func (h *PricingHandler) GetBookPricesHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	book, prices, err := h.pricingService.ListBookPrices(bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	response := BookPricesResponse{
+		BookID: book.ID,
+		Base:   money.New(book.Price, book.Currency),
+		Prices: make([]money.Money, 0, len(prices)),
+	}
+	for _, price := range prices {
+		response.Prices = append(response.Prices, money.New(price.Amount, price.Currency))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// SetBookPriceHandler godoc
+// @Summary Задать цену книги в валюте
+// @Description Цена из прайс-листа важнее пересчёта по курсу
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param currency path string true "Код валюты ISO 4217"
+// @Param input body service.BookPriceRequest true "Цена"
+// @Success 200 {object} money.Money
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/prices/{currency} [put]
+func (h *PricingHandler) SetBookPriceHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	var req service.BookPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	price, err := h.pricingService.SetBookPrice(bookID, chi.URLParam(r, "currency"), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, money.New(price.Amount, price.Currency))
+}
+
+// DeleteBookPriceHandler godoc
+// @Summary Удалить цену книги в валюте
+// @Description После удаления цена в этой валюте снова пересчитывается по курсу
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Param id path int true "ID книги"
+// @Param currency path string true "Код валюты ISO 4217"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/prices/{currency} [delete]
+func (h *PricingHandler) DeleteBookPriceHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	if err := h.pricingService.DeleteBookPrice(bookID, chi.URLParam(r, "currency")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPricingService struct {
+	mock.Mock
+}
+
+func (m *MockPricingService) BaseCurrency() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockPricingService) PriceBooks(books []models.Book, currency string) ([]service.PriceQuote, error) {
+	args := m.Called(books, currency)
+	return args.Get(0).([]service.PriceQuote), args.Error(1)
+}
+
+func (m *MockPricingService) PriceBriefs(briefs []service.BookBrief, currency string) ([]service.BookBrief, error) {
+	args := m.Called(briefs, currency)
+	return args.Get(0).([]service.BookBrief), args.Error(1)
+}
+
+func (m *MockPricingService) GetRates() ([]models.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockPricingService) UploadRates(req service.ExchangeRatesRequest) ([]models.ExchangeRate, error) {
+	args := m.Called(req)
+	return args.Get(0).([]models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockPricingService) ListBookPrices(bookID uint) (models.Book, []models.BookPrice, error) {
+	args := m.Called(bookID)
+	return args.Get(0).(models.Book), args.Get(1).([]models.BookPrice), args.Error(2)
+}
+
+func (m *MockPricingService) SetBookPrice(bookID uint, currency string, req service.BookPriceRequest) (models.BookPrice, error) {
+	args := m.Called(bookID, currency, req)
+	return args.Get(0).(models.BookPrice), args.Error(1)
+}
+
+func (m *MockPricingService) DeleteBookPrice(bookID uint, currency string) error {
+	args := m.Called(bookID, currency)
+	return args.Error(0)
+}
+
+func TestPricingHandler_GetExchangeRatesHandler_Success(t *testing.T) {
+	mockService := new(MockPricingService)
+	handler := NewPricingHandler(mockService)
+
+	// Настройка мока
+	updated := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	mockService.On("BaseCurrency").Return("USD")
+	mockService.On("GetRates").Return([]models.ExchangeRate{
+		{Currency: "EUR", Rate: "0.9215", UpdatedAt: updated},
+		{Currency: "JPY", Rate: "151.37", UpdatedAt: updated},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/exchange-rates", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetExchangeRatesHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expected := `{
+		"base":"USD",
+		"rates":[
+			{"currency":"EUR","rate":"0.9215","updated_at":"2026-10-01T09:00:00Z"},
+			{"currency":"JPY","rate":"151.37","updated_at":"2026-10-01T09:00:00Z"}
+		]
+	}`
+	assert.JSONEq(t, expected, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestPricingHandler_SetBookPriceHandler_Success(t *testing.T) {
+	mockService := new(MockPricingService)
+	handler := NewPricingHandler(mockService)
+
+	mockService.On("SetBookPrice", uint(1), "jpy", service.BookPriceRequest{Amount: "7500"}).
+		Return(models.BookPrice{BookID: 1, Currency: "JPY", Amount: 7500}, nil)
+
+	req, _ := http.NewRequest("PUT", "/books/1/prices/jpy", strings.NewReader(`{"amount":7500}`))
+	req = withRouteAndUser(req, "id", "1", &utils.Claims{UserID: "1", Role: "admin"})
+	chi.RouteContext(req.Context()).URLParams.Add("currency", "jpy")
+
+	rr := httptest.NewRecorder()
+	handler.SetBookPriceHandler(rr, req)
+
+	// У иены нет дробной части
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"amount":"7500","currency":"JPY"}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestPricingHandler_SetBookPriceHandler_InvalidCurrency(t *testing.T) {
+	mockService := new(MockPricingService)
+	handler := NewPricingHandler(mockService)
+
+	mockService.On("SetBookPrice", uint(1), "XYZ", service.BookPriceRequest{Amount: "10.00"}).
+		Return(models.BookPrice{}, errors.New(`invalid currency "XYZ"`))
+
+	req, _ := http.NewRequest("PUT", "/books/1/prices/XYZ", strings.NewReader(`{"amount":"10.00"}`))
+	req = withRouteAndUser(req, "id", "1", &utils.Claims{UserID: "1", Role: "admin"})
+	chi.RouteContext(req.Context()).URLParams.Add("currency", "XYZ")
+
+	rr := httptest.NewRecorder()
+	handler.SetBookPriceHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type ProgressHandler struct {
+	progressService service.ProgressService
+}
+
+func NewProgressHandler(progressService service.ProgressService) *ProgressHandler {
+	return &ProgressHandler{progressService: progressService}
+}
+
+func toReadingProgressResponse(progress models.ReadingProgress) ReadingProgressResponse {
+	response := ReadingProgressResponse{
+		Locator:     progress.Locator,
+		LocatorType: progress.LocatorType,
+		Percent:     progress.Percent,
+		Device:      progress.Device,
+		DeviceID:    progress.DeviceID,
+		RecordedAt:  progress.RecordedAt,
+		UpdatedAt:   progress.UpdatedAt,
+	}
+	if progress.BookID != nil {
+		response.BookID = *progress.BookID
+	}
+	return response
+}
+
+// SaveProgressHandler godoc
+// @Summary Сохранить позицию чтения
+// @Description Позиция в книге с устройства: EPUB CFI, процент или оба. Из позиций с разных устройств текущей становится
+// @Description прочитанная позже (по recorded_at, по умолчанию - время запроса); остальные остаются в истории. Если книгу
+// @Description позже читали на другом устройстве, applied = false и возвращается та позиция
+// @Tags Progress
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param input body service.ReadingPositionRequest true "Позиция"
+// @Success 200 {object} SaveProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/progress/{bookID} [put]
+func (h *ProgressHandler) SaveProgressHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.ReadingPositionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	progress, applied, err := h.progressService.SaveProgress(userID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, SaveProgressResponse{
+		ReadingProgressResponse: toReadingProgressResponse(progress),
+		Applied:                 applied,
+	})
+}
+
+// GetProgressHandler godoc
+// @Summary Позиция чтения
+// @Description Последняя позиция в книге со всех устройств, включая KOReader
+// @Tags Progress
+// @Security ApiKeyAuth
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Success 200 {object} ReadingProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/progress/{bookID} [get]
+func (h *ProgressHandler) GetProgressHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	progress, err := h.progressService.GetProgress(userID, bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReadingProgressResponse(progress))
+}
+
+// GetProgressHistoryHandler godoc
+// @Summary История позиций чтения
+// @Description Все сохранённые позиции в книге, новые первыми; applied = false у позиций, проигравших более поздней
+// @Tags Progress
+// @Security ApiKeyAuth
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Записей на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedProgressHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/progress/{bookID}/history [get]
+func (h *ProgressHandler) GetProgressHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	entries, total, err := h.progressService.ListHistory(userID, bookID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get progress history"})
+		return
+	}
+
+	response := PaginatedProgressHistoryResponse{
+		Data: make([]ReadingProgressEntryResponse, 0, len(entries)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, entry := range entries {
+		response.Data = append(response.Data, ReadingProgressEntryResponse{
+			ID:          entry.ID,
+			Locator:     entry.Locator,
+			LocatorType: entry.LocatorType,
+			Percent:     entry.Percent,
+			Device:      entry.Device,
+			DeviceID:    entry.DeviceID,
+			RecordedAt:  entry.RecordedAt,
+			Applied:     entry.Applied,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
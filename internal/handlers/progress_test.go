@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProgressService struct {
+	mock.Mock
+}
+
+func (m *MockProgressService) SaveProgress(userID, bookID uint, req service.ReadingPositionRequest) (models.ReadingProgress, bool, error) {
+	args := m.Called(userID, bookID, req)
+	return args.Get(0).(models.ReadingProgress), args.Bool(1), args.Error(2)
+}
+
+func (m *MockProgressService) GetProgress(userID, bookID uint) (models.ReadingProgress, error) {
+	args := m.Called(userID, bookID)
+	return args.Get(0).(models.ReadingProgress), args.Error(1)
+}
+
+func (m *MockProgressService) ListHistory(userID, bookID uint, page, limit int) ([]models.ReadingProgressEntry, int64, error) {
+	args := m.Called(userID, bookID, page, limit)
+	return args.Get(0).([]models.ReadingProgressEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProgressService) AuthenticateSync(username, key string) (uint, error) {
+	args := m.Called(username, key)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockProgressService) SaveSyncProgress(userID uint, req service.SyncProgressRequest) (models.ReadingProgress, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(models.ReadingProgress), args.Error(1)
+}
+
+func (m *MockProgressService) GetSyncProgress(userID uint, document string) (models.ReadingProgress, error) {
+	args := m.Called(userID, document)
+	return args.Get(0).(models.ReadingProgress), args.Error(1)
+}
+
+func TestProgressHandler_SaveProgressHandler_OlderPositionLoses(t *testing.T) {
+	mockService := new(MockProgressService)
+	handler := NewProgressHandler(mockService)
+
+	// Настройка мока: на телефоне книгу читали позже, позиция с ридера только попадает в историю
+	recorded := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	percent := 30.0
+	reqBody := service.ReadingPositionRequest{
+		CFI:        "epubcfi(/6/10!/4/2:10)",
+		Percent:    &percent,
+		DeviceID:   "kobo-5f2c",
+		RecordedAt: &recorded,
+	}
+	bookID := uint(1)
+	mockService.On("SaveProgress", uint(2), uint(1), reqBody).Return(models.ReadingProgress{
+		BookID:      &bookID,
+		Locator:     "epubcfi(/6/14!/4/2/10/2:352)",
+		LocatorType: models.LocatorCFI,
+		Percent:     42.5,
+		DeviceID:    "phone-77",
+		RecordedAt:  recorded.Add(time.Hour),
+	}, false, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("PUT", "/users/me/progress/1", bytes.NewBuffer(body))
+	req = withRouteAndUser(req, "bookID", "1", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.SaveProgressHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response SaveProgressResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.False(t, response.Applied)
+	assert.Equal(t, "phone-77", response.DeviceID)
+	assert.Equal(t, 42.5, response.Percent)
+	mockService.AssertExpectations(t)
+}
+
+func TestProgressHandler_GetProgressHandler_NotFound(t *testing.T) {
+	mockService := new(MockProgressService)
+	handler := NewProgressHandler(mockService)
+
+	// Настройка мока
+	mockService.On("GetProgress", uint(2), uint(5)).Return(models.ReadingProgress{}, errors.New("progress not found"))
+
+	req, _ := http.NewRequest("GET", "/users/me/progress/5", nil)
+	req = withRouteAndUser(req, "bookID", "5", &utils.Claims{UserID: "2", Role: "user"})
+
+	rr := httptest.NewRecorder()
+	handler.GetProgressHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReadingHandler struct {
+	readingService service.ReadingService
+}
+
+func NewReadingHandler(readingService service.ReadingService) *ReadingHandler {
+	return &ReadingHandler{readingService: readingService}
+}
+
+func toReadingStatusResponse(status models.ReadingStatus) ReadingStatusResponse {
+	return ReadingStatusResponse{
+		BookID:      status.BookID,
+		Shelf:       status.Shelf,
+		CurrentPage: status.CurrentPage,
+		Percent:     status.Percent,
+		StartedAt:   status.StartedAt,
+		FinishedAt:  status.FinishedAt,
+		ReadCount:   status.ReadCount,
+		UpdatedAt:   status.UpdatedAt,
+	}
+}
+
+func toBookBriefResponse(book service.BookBrief) BookBriefResponse {
+	return BookBriefResponse{
+		ID:             book.ID,
+		Title:          book.Title,
+		Author:         book.Author,
+		Genre:          book.Genre,
+		Price:          book.Price,
+		EffectivePrice: book.EffectivePrice,
+		SaleEndsAt:     book.SaleEndsAt,
+	}
+}
+
+// GetShelfHandler godoc
+// @Summary Полка чтения
+// @Description Книги на полке want_to_read, reading или read с пагинацией, недавно изменённые первыми
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Produce json
+// @Param shelf path string true "Полка" Enums(want_to_read, reading, read)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedShelfResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/shelves/{shelf} [get]
+func (h *ReadingHandler) GetShelfHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	page, limit := parsePagination(r, 10)
+	items, total, err := h.readingService.GetShelf(userID, chi.URLParam(r, "shelf"), page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := PaginatedShelfResponse{
+		Data: make([]ShelfItemResponse, 0, len(items)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, item := range items {
+		response.Data = append(response.Data, ShelfItemResponse{
+			Book:   toBookBriefResponse(item.Book),
+			Status: toReadingStatusResponse(item.Status),
+		})
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// GetReadingStatusHandler godoc
+// @Summary Статус чтения книги
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Success 200 {object} ReadingStatusResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/reading/{bookID} [get]
+func (h *ReadingHandler) GetReadingStatusHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	status, err := h.readingService.GetStatus(userID, bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReadingStatusResponse(status))
+}
+
+// SetReadingStatusHandler godoc
+// @Summary Переставить книгу на полку
+// @Description reading начинает новое прочтение (так учитывается перечитывание), read завершает его. Даты необязательны, по умолчанию - текущее время
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param input body service.ReadingStatusRequest true "Полка и даты"
+// @Success 200 {object} ReadingStatusResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/reading/{bookID} [put]
+func (h *ReadingHandler) SetReadingStatusHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.ReadingStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	status, err := h.readingService.SetStatus(userID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReadingStatusResponse(status))
+}
+
+// UpdateProgressHandler godoc
+// @Summary Прогресс чтения
+// @Description Страница или процент. Книга автоматически переносится на полку reading
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param input body service.ProgressRequest true "Страница или процент"
+// @Success 200 {object} ReadingStatusResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/reading/{bookID}/progress [patch]
+func (h *ReadingHandler) UpdateProgressHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.ProgressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	status, err := h.readingService.UpdateProgress(userID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReadingStatusResponse(status))
+}
+
+// RemoveReadingStatusHandler godoc
+// @Summary Снять книгу с полок
+// @Description Завершённые прочтения остаются в статистике
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Param bookID path int true "ID книги"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/reading/{bookID} [delete]
+func (h *ReadingHandler) RemoveReadingStatusHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.readingService.RemoveStatus(userID, bookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReadingStatsHandler godoc
+// @Summary Статистика чтения за год
+// @Description Число прочитанных книг и страниц за год с разбивкой по месяцам
+// @Tags Reading
+// @Security ApiKeyAuth
+// @Produce json
+// @Param year query int false "Год (по умолчанию текущий)"
+// @Success 200 {object} service.ReadingStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/reading-stats [get]
+func (h *ReadingHandler) GetReadingStatsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	year := time.Now().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid year"})
+			return
+		}
+		year = parsed
+	}
+
+	stats, err := h.readingService.GetYearStats(userID, year)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get reading stats"})
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, stats)
+}
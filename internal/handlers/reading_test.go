@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReadingService struct {
+	mock.Mock
+}
+
+func (m *MockReadingService) GetStatus(userID, bookID uint) (models.ReadingStatus, error) {
+	args := m.Called(userID, bookID)
+	return args.Get(0).(models.ReadingStatus), args.Error(1)
+}
+
+func (m *MockReadingService) SetStatus(userID, bookID uint, req service.ReadingStatusRequest) (models.ReadingStatus, error) {
+	args := m.Called(userID, bookID, req)
+	return args.Get(0).(models.ReadingStatus), args.Error(1)
+}
+
+func (m *MockReadingService) UpdateProgress(userID, bookID uint, req service.ProgressRequest) (models.ReadingStatus, error) {
+	args := m.Called(userID, bookID, req)
+	return args.Get(0).(models.ReadingStatus), args.Error(1)
+}
+
+func (m *MockReadingService) RemoveStatus(userID, bookID uint) error {
+	args := m.Called(userID, bookID)
+	return args.Error(0)
+}
+
+func (m *MockReadingService) GetShelf(userID uint, shelf string, page, limit int) ([]service.ShelfItem, int64, error) {
+	args := m.Called(userID, shelf, page, limit)
+	return args.Get(0).([]service.ShelfItem), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReadingService) GetYearStats(userID uint, year int) (service.ReadingStats, error) {
+	args := m.Called(userID, year)
+	return args.Get(0).(service.ReadingStats), args.Error(1)
+}
+
+func TestReadingHandler_SetReadingStatusHandler_Read(t *testing.T) {
+	mockService := new(MockReadingService)
+	handler := NewReadingHandler(mockService)
+
+	// Настройка мока
+	finished := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	req := service.ReadingStatusRequest{Shelf: models.ShelfRead, FinishedAt: &finished}
+	mockService.On("SetStatus", uint(1), uint(2), req).Return(models.ReadingStatus{
+		BookID:      2,
+		Shelf:       models.ShelfRead,
+		Percent:     100,
+		CurrentPage: 380,
+		FinishedAt:  &finished,
+		ReadCount:   2,
+	}, nil)
+
+	httpReq, _ := http.NewRequest("PUT", "/users/me/reading/2", strings.NewReader(`{"shelf":"read","finished_at":"2026-03-14T00:00:00Z"}`))
+	httpReq = withRouteAndUser(httpReq, "bookID", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.SetReadingStatusHandler(rr, httpReq)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response ReadingStatusResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.ShelfRead, response.Shelf)
+	assert.Equal(t, 2, response.ReadCount)
+	mockService.AssertExpectations(t)
+}
+
+func TestReadingHandler_GetShelfHandler_InvalidShelf(t *testing.T) {
+	mockService := new(MockReadingService)
+	handler := NewReadingHandler(mockService)
+
+	mockService.On("GetShelf", uint(1), "unknown", 1, 10).
+		Return([]service.ShelfItem(nil), int64(0), errors.New("invalid shelf, must be 'want_to_read', 'reading' or 'read'"))
+
+	req, _ := http.NewRequest("GET", "/users/me/shelves/unknown", nil)
+	req = withRouteAndUser(req, "shelf", "unknown", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetShelfHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReadingHandler_GetReadingStatsHandler_Year(t *testing.T) {
+	mockService := new(MockReadingService)
+	handler := NewReadingHandler(mockService)
+
+	stats := service.ReadingStats{Year: 2025, BooksRead: 12, PagesRead: 4100}
+	mockService.On("GetYearStats", uint(1), 2025).Return(stats, nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/reading-stats?year=2025", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetReadingStatsHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"pages_read":4100`)
+
+	// Некорректный год
+	req, _ = http.NewRequest("GET", "/users/me/reading-stats?year=abc", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+	rr = httptest.NewRecorder()
+	handler.GetReadingStatsHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
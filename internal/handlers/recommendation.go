@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"net/http"
+)
+
+type RecommendationHandler struct {
+	recommendationService service.RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: recommendationService}
+}
+
+// GetRecommendationsHandler godoc
+// @Summary Персональные рекомендации
+// @Description Книги, похожие на избранное: по совместному добавлению в избранное другими читателями, автору, жанру и описанию.
+// @Description Книги из избранного не попадают в выдачу. reason: co_favourite, same_author, similar_description, same_genre
+// @Tags Recommendations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Количество (по умолчанию 10, максимум 50)" default(10)
+// @Success 200 {object} RecommendationsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/recommendations [get]
+func (h *RecommendationHandler) GetRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	_, limit := parsePagination(r, 10)
+	recommendations, err := h.recommendationService.GetRecommendations(userID, min(limit, 50))
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get recommendations"})
+		return
+	}
+
+	response := RecommendationsResponse{Data: make([]RecommendationResponse, 0, len(recommendations))}
+	for _, rec := range recommendations {
+		response.Data = append(response.Data, RecommendationResponse{
+			Book:        toBookBriefResponse(rec.Book),
+			Score:       rec.Score,
+			Reason:      rec.Reason,
+			BecauseOf:   toBookBriefResponse(rec.BecauseOf),
+			Explanation: rec.Explanation,
+		})
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
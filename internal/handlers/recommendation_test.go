@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecommendationService struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationService) RebuildSimilarities(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockRecommendationService) GetRecommendations(userID uint, limit int) ([]service.Recommendation, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]service.Recommendation), args.Error(1)
+}
+
+func TestRecommendationHandler_GetRecommendationsHandler_Success(t *testing.T) {
+	mockService := new(MockRecommendationService)
+	handler := NewRecommendationHandler(mockService)
+
+	// Настройка мока
+	mockService.On("GetRecommendations", uint(1), 5).Return([]service.Recommendation{
+		{
+			Book:        service.BookBrief{ID: 3, Title: "Concurrency in Go"},
+			Score:       0.42,
+			Reason:      service.ReasonCoFavourite,
+			BecauseOf:   service.BookBrief{ID: 1, Title: "The Go Programming Language"},
+			Explanation: "Читатели, которые добавили в избранное «The Go Programming Language», добавляли и эту книгу",
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/recommendations?limit=5", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetRecommendationsHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response RecommendationsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, uint(3), response.Data[0].Book.ID)
+	assert.Equal(t, uint(1), response.Data[0].BecauseOf.ID)
+	assert.Equal(t, service.ReasonCoFavourite, response.Data[0].Reason)
+	assert.NotEmpty(t, response.Data[0].Explanation)
+	mockService.AssertExpectations(t)
+}
+
+func TestRecommendationHandler_GetRecommendationsHandler_LimitCapped(t *testing.T) {
+	mockService := new(MockRecommendationService)
+	handler := NewRecommendationHandler(mockService)
+
+	mockService.On("GetRecommendations", uint(1), 50).Return([]service.Recommendation{}, nil)
+
+	req, _ := http.NewRequest("GET", "/users/me/recommendations?limit=80", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.GetRecommendationsHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data":[]}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestRecommendationHandler_GetRecommendationsHandler_Unauthorized(t *testing.T) {
+	mockService := new(MockRecommendationService)
+	handler := NewRecommendationHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/users/me/recommendations", nil)
+	rr := httptest.NewRecorder()
+	handler.GetRecommendationsHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "GetRecommendations", mock.Anything, mock.Anything)
+}
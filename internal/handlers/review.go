@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+func toReviewResponse(review models.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:             review.ID,
+		BookID:         review.BookID,
+		UserID:         review.UserID,
+		Rating:         review.Rating,
+		Text:           review.Text,
+		Status:         review.Status,
+		HelpfulCount:   review.HelpfulCount,
+		ModerationNote: review.ModerationNote,
+		ModeratedAt:    review.ModeratedAt,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+}
+
+func toPaginatedReviews(reviews []models.Review, total int64, page, limit int) PaginatedReviewsResponse {
+	response := PaginatedReviewsResponse{
+		Data: make([]ReviewResponse, 0, len(reviews)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, review := range reviews {
+		response.Data = append(response.Data, toReviewResponse(review))
+	}
+	return response
+}
+
+// pathID читает положительный числовой параметр пути
+func pathID(r *http.Request, name string) (uint, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || id < 1 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// currentUserID достаёт пользователя, положенного JWTAuthMiddleware
+func currentUserID(r *http.Request) (uint, bool) {
+	claims, ok := r.Context().Value("user").(*utils.Claims)
+	if !ok {
+		return 0, false
+	}
+	userID, _ := strconv.Atoi(claims.UserID)
+	return uint(userID), true
+}
+
+// currentRole - роль из токена, пустая строка для анонимного запроса
+func currentRole(r *http.Request) string {
+	if claims, ok := r.Context().Value("user").(*utils.Claims); ok {
+		return claims.Role
+	}
+	return ""
+}
+
+// GetBookReviewsHandler godoc
+// @Summary Рецензии на книгу
+// @Description Одобренные рецензии с пагинацией. sort: helpful (по умолчанию), newest, rating
+// @Tags Reviews
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param sort query string false "Сортировка" Enums(helpful, newest, rating)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Рецензий на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedReviewsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/reviews [get]
+func (h *ReviewHandler) GetBookReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	page, limit := parsePagination(r, 10)
+	reviews, total, err := h.reviewService.GetBookReviews(bookID, r.URL.Query().Get("sort"), page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get reviews"})
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, toPaginatedReviews(reviews, total, page, limit))
+}
+
+// GetMyReviewHandler godoc
+// @Summary Своя рецензия на книгу
+// @Description Рецензия текущего пользователя в любом статусе модерации
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID книги"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/reviews/me [get]
+func (h *ReviewHandler) GetMyReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	review, err := h.reviewService.GetUserReview(userID, bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReviewResponse(review))
+}
+
+// UpsertReviewHandler godoc
+// @Summary Оценка и рецензия
+// @Description Создаёт или обновляет рецензию текущего пользователя. Оценка без текста публикуется сразу, текст уходит на модерацию
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param input body service.ReviewRequest true "Оценка 1-5 и текст"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/reviews/me [put]
+func (h *ReviewHandler) UpsertReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	review, err := h.reviewService.UpsertReview(userID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReviewResponse(review))
+}
+
+// DeleteReviewHandler godoc
+// @Summary Удаление своей рецензии
+// @Description Удаляет рецензию и оценку текущего пользователя
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Param id path int true "ID книги"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /books/{id}/reviews/me [delete]
+func (h *ReviewHandler) DeleteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(userID, bookID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VoteHelpfulHandler godoc
+// @Summary Отметить рецензию полезной
+// @Description Повторная отметка ничего не меняет. За свою рецензию голосовать нельзя
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Param id path int true "ID рецензии"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /reviews/{id}/helpful [post]
+func (h *ReviewHandler) VoteHelpfulHandler(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid review ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.reviewService.VoteHelpful(userID, reviewID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnvoteHelpfulHandler godoc
+// @Summary Снять отметку "полезно"
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Param id path int true "ID рецензии"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /reviews/{id}/helpful [delete]
+func (h *ReviewHandler) UnvoteHelpfulHandler(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid review ID"})
+		return
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	if err := h.reviewService.UnvoteHelpful(userID, reviewID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetModerationQueueHandler godoc
+// @Summary Очередь модерации рецензий
+// @Description Рецензии в заданном статусе (по умолчанию pending), старые первыми
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query string false "Статус" Enums(pending, approved, rejected)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Рецензий на странице (по умолчанию 10, максимум 100)" default(10)
+// @Success 200 {object} PaginatedReviewsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /moderation/reviews [get]
+func (h *ReviewHandler) GetModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 10)
+	reviews, total, err := h.reviewService.GetModerationQueue(r.URL.Query().Get("status"), page, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toPaginatedReviews(reviews, total, page, limit))
+}
+
+// ModerateReviewHandler godoc
+// @Summary Модерация рецензии
+// @Description Одобряет или отклоняет рецензию. Рейтинг книги пересчитывается инкрементально. Если рецензию изменили
+// @Description после того, как её прочитали (или после updated_at из запроса), решение не применяется и возвращается 409
+// @Tags Reviews
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID рецензии"
+// @Param input body service.ModerationRequest true "Решение модератора"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /moderation/reviews/{id} [put]
+func (h *ReviewHandler) ModerateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid review ID"})
+		return
+	}
+	moderatorID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.ModerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(moderatorID, reviewID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toReviewResponse(review))
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockReviewService struct {
+	mock.Mock
+}
+
+func (m *MockReviewService) UpsertReview(userID, bookID uint, req service.ReviewRequest) (models.Review, error) {
+	args := m.Called(userID, bookID, req)
+	return args.Get(0).(models.Review), args.Error(1)
+}
+
+func (m *MockReviewService) GetUserReview(userID, bookID uint) (models.Review, error) {
+	args := m.Called(userID, bookID)
+	return args.Get(0).(models.Review), args.Error(1)
+}
+
+func (m *MockReviewService) DeleteReview(userID, bookID uint) error {
+	args := m.Called(userID, bookID)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) GetBookReviews(bookID uint, sort string, page, limit int) ([]models.Review, int64, error) {
+	args := m.Called(bookID, sort, page, limit)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewService) VoteHelpful(userID, reviewID uint) error {
+	args := m.Called(userID, reviewID)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) UnvoteHelpful(userID, reviewID uint) error {
+	args := m.Called(userID, reviewID)
+	return args.Error(0)
+}
+
+func (m *MockReviewService) GetModerationQueue(status string, page, limit int) ([]models.Review, int64, error) {
+	args := m.Called(status, page, limit)
+	return args.Get(0).([]models.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewService) ModerateReview(moderatorID, reviewID uint, req service.ModerationRequest) (models.Review, error) {
+	args := m.Called(moderatorID, reviewID, req)
+	return args.Get(0).(models.Review), args.Error(1)
+}
+
+// withRouteAndUser добавляет параметр пути и claims в контекст запроса
+func withRouteAndUser(req *http.Request, param, value string, claims *utils.Claims) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(param, value)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if claims != nil {
+		ctx = context.WithValue(ctx, "user", claims)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestReviewHandler_UpsertReviewHandler_Success(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	// Настройка мока
+	reqBody := service.ReviewRequest{Rating: 5, Text: "Отличная книга"}
+	mockService.On("UpsertReview", uint(1), uint(2), reqBody).Return(models.Review{
+		Model:  gorm.Model{ID: 10},
+		UserID: 1,
+		BookID: 2,
+		Rating: 5,
+		Text:   "Отличная книга",
+		Status: models.ReviewStatusPending,
+	}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest("PUT", "/books/2/reviews/me", strings.NewReader(string(body)))
+	req = withRouteAndUser(req, "id", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.UpsertReviewHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response ReviewResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, uint(10), response.ID)
+	assert.Equal(t, models.ReviewStatusPending, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestReviewHandler_UpsertReviewHandler_InvalidRating(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	reqBody := service.ReviewRequest{Rating: 7}
+	mockService.On("UpsertReview", uint(1), uint(2), reqBody).
+		Return(models.Review{}, errors.New("invalid rating, must be between 1 and 5"))
+
+	req, _ := http.NewRequest("PUT", "/books/2/reviews/me", strings.NewReader(`{"rating":7}`))
+	req = withRouteAndUser(req, "id", "2", &utils.Claims{UserID: "1"})
+
+	rr := httptest.NewRecorder()
+	handler.UpsertReviewHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid rating")
+	mockService.AssertExpectations(t)
+}
+
+func TestReviewHandler_GetBookReviewsHandler_SortAndMeta(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	reviews := []models.Review{{Model: gorm.Model{ID: 3}, BookID: 2, Rating: 4, HelpfulCount: 8, Status: models.ReviewStatusApproved}}
+	mockService.On("GetBookReviews", uint(2), "helpful", 2, 5).Return(reviews, int64(6), nil)
+
+	req, _ := http.NewRequest("GET", "/books/2/reviews?sort=helpful&page=2&limit=5", nil)
+	req = withRouteAndUser(req, "id", "2", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetBookReviewsHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedReviewsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, 8, response.Data[0].HelpfulCount)
+	assert.Equal(t, 2, response.Meta.TotalPages)
+	mockService.AssertExpectations(t)
+}
+
+func TestReviewHandler_ModerateReviewHandler_Success(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	reqBody := service.ModerationRequest{Status: models.ReviewStatusRejected, Note: "spam"}
+	mockService.On("ModerateReview", uint(5), uint(3), reqBody).Return(models.Review{
+		Model:          gorm.Model{ID: 3},
+		Status:         models.ReviewStatusRejected,
+		ModerationNote: "spam",
+	}, nil)
+
+	req, _ := http.NewRequest("PUT", "/moderation/reviews/3", strings.NewReader(`{"status":"rejected","note":"spam"}`))
+	req = withRouteAndUser(req, "id", "3", &utils.Claims{UserID: "5", Role: "moderator"})
+
+	rr := httptest.NewRecorder()
+	handler.ModerateReviewHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"rejected"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestReviewHandler_ModerateReviewHandler_ChangedConcurrently(t *testing.T) {
+	mockService := new(MockReviewService)
+	handler := NewReviewHandler(mockService)
+
+	// Модератор видел версию до правки автора
+	seen := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	reqBody := service.ModerationRequest{Status: models.ReviewStatusApproved, UpdatedAt: &seen}
+	mockService.On("ModerateReview", uint(5), uint(3), reqBody).
+		Return(models.Review{}, errors.New("moderation unavailable: review changed concurrently, reload it and moderate again"))
+
+	req, _ := http.NewRequest("PUT", "/moderation/reviews/3", strings.NewReader(`{"status":"approved","updated_at":"2026-03-01T10:00:00Z"}`))
+	req = withRouteAndUser(req, "id", "3", &utils.Claims{UserID: "5", Role: "moderator"})
+
+	rr := httptest.NewRecorder()
+	handler.ModerateReviewHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"net/http"
+)
+
+type SimilarHandler struct {
+	similarService service.SimilarService
+}
+
+func NewSimilarHandler(similarService service.SimilarService) *SimilarHandler {
+	return &SimilarHandler{similarService: similarService}
+}
+
+// GetSimilarBooksHandler godoc
+// @Summary Похожие книги
+// @Description Книги, близкие по названию, жанру и описанию (косинус TF-IDF), самые похожие первыми
+// @Tags Books
+// @Produce json
+// @Param id path int true "ID книги"
+// @Param limit query int false "Количество (по умолчанию 10, максимум 50)" default(10)
+// @Success 200 {object} SimilarBooksResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /books/{id}/similar [get]
+func (h *SimilarHandler) GetSimilarBooksHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "id")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	_, limit := parsePagination(r, 10)
+	similar, err := h.similarService.GetSimilarBooks(bookID, min(limit, 50))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := SimilarBooksResponse{Data: make([]SimilarBookResponse, 0, len(similar))}
+	for _, item := range similar {
+		response.Data = append(response.Data, SimilarBookResponse{
+			Book:  toBookBriefResponse(item.Book),
+			Score: item.Score,
+		})
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
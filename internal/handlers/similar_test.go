@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"bookshelf/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSimilarService struct {
+	mock.Mock
+}
+
+func (m *MockSimilarService) BookChanged(event service.BookEvent) {
+	m.Called(event)
+}
+
+func (m *MockSimilarService) GetSimilarBooks(bookID uint, limit int) ([]service.SimilarBook, error) {
+	args := m.Called(bookID, limit)
+	return args.Get(0).([]service.SimilarBook), args.Error(1)
+}
+
+func (m *MockSimilarService) RebuildIndex(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestSimilarHandler_GetSimilarBooksHandler_Success(t *testing.T) {
+	mockService := new(MockSimilarService)
+	handler := NewSimilarHandler(mockService)
+
+	// Настройка мока
+	mockService.On("GetSimilarBooks", uint(1), 3).Return([]service.SimilarBook{
+		{Book: service.BookBrief{ID: 4, Title: "Concurrency in Go"}, Score: 0.61},
+		{Book: service.BookBrief{ID: 7, Title: "Go in Action"}, Score: 0.44},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/books/1/similar?limit=3", nil)
+	req = withRouteAndUser(req, "id", "1", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetSimilarBooksHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response SimilarBooksResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, uint(4), response.Data[0].Book.ID)
+	assert.Equal(t, 0.61, response.Data[0].Score)
+	mockService.AssertExpectations(t)
+}
+
+func TestSimilarHandler_GetSimilarBooksHandler_NotFound(t *testing.T) {
+	mockService := new(MockSimilarService)
+	handler := NewSimilarHandler(mockService)
+
+	mockService.On("GetSimilarBooks", uint(99), 10).Return([]service.SimilarBook(nil), errors.New("book not found"))
+
+	req, _ := http.NewRequest("GET", "/books/99/similar", nil)
+	req = withRouteAndUser(req, "id", "99", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetSimilarBooksHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type StockHandler struct {
+	stockService service.StockService
+}
+
+func NewStockHandler(stockService service.StockService) *StockHandler {
+	return &StockHandler{stockService: stockService}
+}
+
+func toStockResponse(level models.StockLevel) StockResponse {
+	return StockResponse{
+		BookID:            level.BookID,
+		Title:             level.Book.Title,
+		OnHand:            level.OnHand,
+		Reserved:          level.Reserved,
+		Available:         level.Available(),
+		LowStockThreshold: level.LowStockThreshold,
+		Low:               level.Available() <= level.LowStockThreshold,
+		UpdatedAt:         level.UpdatedAt,
+	}
+}
+
+func toStockMovementResponse(movement models.StockMovement) StockMovementResponse {
+	return StockMovementResponse{
+		ID:        movement.ID,
+		BookID:    movement.BookID,
+		Delta:     movement.Delta,
+		OnHand:    movement.OnHand,
+		Reason:    movement.Reason,
+		Note:      movement.Note,
+		OrderID:   movement.OrderID,
+		CreatedBy: movement.CreatedBy,
+		CreatedAt: movement.CreatedAt,
+	}
+}
+
+// GetStockListHandler godoc
+// @Summary Остатки на складе
+// @Description Книги с учётом остатков. Доступно к продаже on_hand - reserved: reserved отложено под неоплаченные заказы
+// @Tags Stock
+// @Security ApiKeyAuth
+// @Produce json
+// @Param low query bool false "Только с остатком не выше порога, меньшие первыми"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Книг на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedStockResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/stock [get]
+func (h *StockHandler) GetStockListHandler(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+	levels, total, err := h.stockService.ListStock(r.URL.Query().Get("low") == "true", page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get stock"})
+		return
+	}
+
+	response := PaginatedStockResponse{
+		Data: make([]StockResponse, 0, len(levels)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, level := range levels {
+		response.Data = append(response.Data, toStockResponse(level))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// GetStockHandler godoc
+// @Summary Остаток книги
+// @Tags Stock
+// @Security ApiKeyAuth
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Success 200 {object} StockResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/stock/{bookID} [get]
+func (h *StockHandler) GetStockHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	level, err := h.stockService.GetStock(bookID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toStockResponse(level))
+}
+
+// UpdateStockSettingsHandler godoc
+// @Summary Порог малого остатка
+// @Description Когда доступный остаток опускается до порога, администраторы получают уведомление. Включает учёт книги
+// @Tags Stock
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param input body service.StockSettingsRequest true "Порог"
+// @Success 200 {object} StockResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/stock/{bookID} [put]
+func (h *StockHandler) UpdateStockSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	var req service.StockSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	level, err := h.stockService.SetThreshold(bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusOK, toStockResponse(level))
+}
+
+// GetStockMovementsHandler godoc
+// @Summary Журнал движения товара
+// @Description Поступления, списания и продажи книги, новые первыми
+// @Tags Stock
+// @Security ApiKeyAuth
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Записей на странице (по умолчанию 20, максимум 100)" default(20)
+// @Success 200 {object} PaginatedStockMovementsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/stock/{bookID}/movements [get]
+func (h *StockHandler) GetStockMovementsHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	movements, total, err := h.stockService.ListMovements(bookID, page, limit)
+	if err != nil {
+		utils.JSONResponse(w, http.StatusInternalServerError, ErrorResponse{"Failed to get stock movements"})
+		return
+	}
+
+	response := PaginatedStockMovementsResponse{
+		Data: make([]StockMovementResponse, 0, len(movements)),
+		Meta: newPaginationMeta(total, page, limit),
+	}
+	for _, movement := range movements {
+		response.Data = append(response.Data, toStockMovementResponse(movement))
+	}
+	utils.JSONResponse(w, http.StatusOK, response)
+}
+
+// AddStockMovementHandler godoc
+// @Summary Поступление или списание товара
+// @Description Журнал только дополняется: ошибка исправляется движением correction. Причины: restock и return
+// @Description увеличивают остаток, damaged и lost уменьшают. Отложенное под заказы списать нельзя
+// @Tags Stock
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param bookID path int true "ID книги"
+// @Param input body service.StockAdjustmentRequest true "Движение"
+// @Success 201 {object} StockMovementResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/stock/{bookID}/movements [post]
+func (h *StockHandler) AddStockMovementHandler(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := pathID(r, "bookID")
+	if !ok {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid book ID"})
+		return
+	}
+	adminID, ok := currentUserID(r)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, ErrorResponse{"User information not found"})
+		return
+	}
+
+	var req service.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.JSONResponse(w, http.StatusBadRequest, ErrorResponse{"Invalid request body"})
+		return
+	}
+
+	movement, err := h.stockService.Adjust(adminID, bookID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	utils.JSONResponse(w, http.StatusCreated, toStockMovementResponse(movement))
+}
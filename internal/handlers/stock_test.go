@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/service"
+	"bookshelf/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStockService struct {
+	mock.Mock
+}
+
+func (m *MockStockService) GetStock(bookID uint) (models.StockLevel, error) {
+	args := m.Called(bookID)
+	return args.Get(0).(models.StockLevel), args.Error(1)
+}
+
+func (m *MockStockService) ListStock(lowOnly bool, page, limit int) ([]models.StockLevel, int64, error) {
+	args := m.Called(lowOnly, page, limit)
+	return args.Get(0).([]models.StockLevel), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStockService) SetThreshold(bookID uint, req service.StockSettingsRequest) (models.StockLevel, error) {
+	args := m.Called(bookID, req)
+	return args.Get(0).(models.StockLevel), args.Error(1)
+}
+
+func (m *MockStockService) Adjust(adminID, bookID uint, req service.StockAdjustmentRequest) (models.StockMovement, error) {
+	args := m.Called(adminID, bookID, req)
+	return args.Get(0).(models.StockMovement), args.Error(1)
+}
+
+func (m *MockStockService) ListMovements(bookID uint, page, limit int) ([]models.StockMovement, int64, error) {
+	args := m.Called(bookID, page, limit)
+	return args.Get(0).([]models.StockMovement), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStockService) ProcessStock(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestStockHandler_GetStockListHandler_LowOnly(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+
+	// Настройка мока
+	mockService.On("ListStock", true, 1, 20).Return([]models.StockLevel{
+		{BookID: 1, Book: models.Book{Title: "Dune"}, OnHand: 4, Reserved: 2, LowStockThreshold: 3},
+	}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/admin/stock?low=true", nil)
+	req = withRouteAndUser(req, "", "", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.GetStockListHandler(rr, req)
+
+	// Проверки
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response PaginatedStockResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data[0].Available)
+	assert.True(t, response.Data[0].Low)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockHandler_AddStockMovementHandler_Success(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+
+	adminID := uint(1)
+	mockService.On("Adjust", uint(1), uint(5), service.StockAdjustmentRequest{Delta: 20, Reason: "restock", Note: "Поставка"}).
+		Return(models.StockMovement{ID: 7, BookID: 5, Delta: 20, OnHand: 24, Reason: "restock", Note: "Поставка", CreatedBy: &adminID}, nil)
+
+	req, _ := http.NewRequest("POST", "/admin/stock/5/movements", strings.NewReader(`{"delta":20,"reason":"restock","note":"Поставка"}`))
+	req = withRouteAndUser(req, "bookID", "5", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.AddStockMovementHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"on_hand":24`)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockHandler_AddStockMovementHandler_BelowReserved(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+
+	mockService.On("Adjust", uint(1), uint(5), service.StockAdjustmentRequest{Delta: -5, Reason: "lost"}).
+		Return(models.StockMovement{}, errors.New("invalid delta, stock would drop below units reserved for pending orders"))
+
+	req, _ := http.NewRequest("POST", "/admin/stock/5/movements", strings.NewReader(`{"delta":-5,"reason":"lost"}`))
+	req = withRouteAndUser(req, "bookID", "5", &utils.Claims{UserID: "1", Role: "admin"})
+
+	rr := httptest.NewRecorder()
+	handler.AddStockMovementHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -20,3 +20,20 @@ func AdminOnlyMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// ModeratorMiddleware пускает модераторов и админов
+func ModeratorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value("user").(*utils.Claims)
+		if !ok {
+			http.Error(w, "User information not found", http.StatusUnauthorized)
+			return
+		}
+
+		if claims.Role != "moderator" && claims.Role != "admin" {
+			http.Error(w, "Moderator privileges required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
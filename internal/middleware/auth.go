@@ -30,3 +30,24 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OptionalJWTAuthMiddleware кладёт пользователя в контекст, если передан Bearer-токен,
+// и пропускает запрос без него. Прочие схемы (например, Basic у читалок) не трогает
+func OptionalJWTAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenHeader := r.Header.Get("Authorization")
+		tokenString := strings.TrimPrefix(tokenHeader, "Bearer ")
+		if tokenHeader == "" || tokenString == tokenHeader {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := utils.ParseToken(tokenString)
+		if err != nil {
+			http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "user", claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -114,3 +114,38 @@ func TestAdminOnlyMiddleware_NonAdminUser(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Admin privileges required")
 }
+
+func TestModeratorMiddleware_Roles(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := ModeratorMiddleware(nextHandler)
+
+	// Модератор и админ проходят, обычный пользователь - нет
+	for role, status := range map[string]int{"moderator": http.StatusOK, "admin": http.StatusOK, "user": http.StatusForbidden} {
+		req, _ := http.NewRequest("GET", "/moderation/reviews", nil)
+		req = req.WithContext(context.WithValue(req.Context(), "user", &utils.Claims{Role: role}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, status, rr.Code, role)
+	}
+}
+
+func TestOptionalJWTAuthMiddleware_Anonymous(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/opds", nil)
+	req.SetBasicAuth("reader", "secret")
+
+	// Basic-авторизация не мешает, пользователь в контекст не попадает
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Value("user").(*utils.Claims)
+		assert.False(t, ok)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	OptionalJWTAuthMiddleware(nextHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	ActivityBookAdded         = "book_added"
+	ActivityReviewPublished   = "review_published"
+	ActivityCollectionUpdated = "collection_updated"
+	ActivityFavouriteAdded    = "favourite_added"
+)
+
+// ActivityEvent - запись журнала активности. Событие пишется один раз и раскладывается
+// по лентам подписчиков через FeedItem. У новой книги нет автора действия
+type ActivityEvent struct {
+	ID           uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Type         string    `json:"type" gorm:"not null;index:idx_activity_actor_type" example:"review_published"`
+	ActorID      *uint     `json:"actor_id,omitempty" gorm:"index:idx_activity_actor_type" example:"2"`
+	ActorName    string    `json:"actor_name,omitempty" example:"john_doe"`
+	BookID       *uint     `json:"book_id,omitempty" example:"1"`
+	CollectionID *uint     `json:"collection_id,omitempty" example:"3"`
+	ReviewID     *uint     `json:"review_id,omitempty" example:"5"`
+	Summary      string    `json:"summary" gorm:"not null" example:"john_doe оценил(а) книгу «Go in Action» на 5"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+}
+
+// FeedItem - событие в ленте конкретного пользователя
+type FeedItem struct {
+	UserID    uint      `gorm:"primaryKey"`
+	EventID   uint      `gorm:"primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// PrivacySettings решают, какие действия пользователя видят подписчики. Избранное скрыто,
+// пока пользователь явно не разрешит его показывать
+type PrivacySettings struct {
+	UserID           uint      `json:"-" gorm:"primaryKey"`
+	ShareFavourites  bool      `json:"share_favourites" gorm:"not null" example:"false"`
+	ShareReviews     bool      `json:"share_reviews" gorm:"not null" example:"true"`
+	ShareCollections bool      `json:"share_collections" gorm:"not null" example:"true"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func DefaultPrivacySettings(userID uint) PrivacySettings {
+	return PrivacySettings{UserID: userID, ShareReviews: true, ShareCollections: true}
+}
@@ -1,13 +1,39 @@
 // Package models
 package models
 
-import "gorm.io/gorm"
+import (
+	"bookshelf/pkg/money"
+	"math"
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Book - книга каталога. Price хранится в минимальных единицах валюты Currency (код ISO 4217)
 type Book struct {
 	gorm.Model  `swaggerignore:"true"`
-	Title       string  `json:"title" gorm:"not null" example:"The Go Programming Language"`
-	Author      string  `json:"author" gorm:"not null" example:"Alan A. A. Donovan"`
-	Genre       string  `json:"genre" gorm:"not null" example:"Programming"`
-	Description string  `json:"description" gorm:"not null" example:"Definitive guide to Go programming"`
-	Price       float64 `json:"price" gorm:"not null" example:"49.99"`
+	Title       string       `json:"title" gorm:"not null" example:"The Go Programming Language"`
+	Author      string       `json:"author" gorm:"not null" example:"Alan A. A. Donovan"`
+	Genre       string       `json:"genre" gorm:"not null" example:"Programming"`
+	Description string       `json:"description" gorm:"not null" example:"Definitive guide to Go programming"`
+	Price       money.Amount `json:"price" gorm:"not null" swaggertype:"string" example:"49.99"`
+	Currency    string       `json:"currency" gorm:"not null;size:3" example:"USD"`
+	ISBN        string       `json:"isbn" gorm:"index" example:"9780134190440"`
+	Publisher   string       `json:"publisher" example:"Addison-Wesley"`
+	Subjects    []string     `json:"subjects" gorm:"serializer:json" example:"Go (Computer program language)"`
+	Pages       int          `json:"pages" example:"380"`
+	// SalePrice и SaleEndsAt - цена по действующей распродаже; не хранятся, их считает сервис книг
+	SalePrice  *money.Amount `json:"sale_price,omitempty" gorm:"-" swaggertype:"string" example:"39.99"`
+	SaleEndsAt *time.Time    `json:"sale_ends_at,omitempty" gorm:"-"`
+	// Счётчики рецензий обновляются инкрементально вместе с рецензией
+	RatingSum   int `json:"rating_sum" gorm:"not null;default:0" example:"42"`
+	RatingCount int `json:"rating_count" gorm:"not null;default:0" example:"10"`
+	ReviewCount int `json:"review_count" gorm:"not null;default:0" example:"7"`
+}
+
+func (b Book) AverageRating() float64 {
+	if b.RatingCount == 0 {
+		return 0
+	}
+	return math.Round(float64(b.RatingSum)/float64(b.RatingCount)*100) / 100
 }
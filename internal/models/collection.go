@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Видимость коллекции: private - только владелец и соавторы, unlisted - плюс все, у кого есть ссылка,
+// public - видна всем и попадает в общий каталог
+const (
+	CollectionPrivate  = "private"
+	CollectionUnlisted = "unlisted"
+	CollectionPublic   = "public"
+)
+
+type Collection struct {
+	gorm.Model  `swaggerignore:"true"`
+	OwnerID     uint    `json:"owner_id" gorm:"not null;index" example:"1"`
+	Name        string  `json:"name" gorm:"not null" example:"Go для новичков"`
+	Description string  `json:"description" example:"Что читать в первый месяц"`
+	Visibility  string  `json:"visibility" gorm:"not null;default:private;index" example:"public"`
+	ShareToken  *string `json:"-" gorm:"uniqueIndex"`
+}
+
+// CollectionItem - книга в коллекции, порядок задаётся Position
+type CollectionItem struct {
+	CollectionID uint `gorm:"primaryKey"`
+	BookID       uint `gorm:"primaryKey"`
+	Book         Book `gorm:"foreignKey:BookID"`
+	Position     int  `gorm:"not null"`
+	AddedBy      uint
+	CreatedAt    time.Time
+}
+
+// CollectionCollaborator может добавлять, убирать и переставлять книги
+type CollectionCollaborator struct {
+	CollectionID uint `gorm:"primaryKey"`
+	UserID       uint `gorm:"primaryKey;index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+// На что действует скидка
+const (
+	DiscountTargetGenre  = "genre"
+	DiscountTargetAuthor = "author"
+	DiscountTargetBook   = "book"
+)
+
+// Как считается скидка
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Discount - скидка на жанр, автора или книгу, действует с StartsAt до EndsAt (не включая).
+// Скидка без кода - распродажа, её цена видна в каталоге; скидка с кодом - купон, он применяется
+// только при оформлении заказа. Скидки не суммируются: книга продаётся по самой низкой цене
+type Discount struct {
+	ID     uint   `json:"id" gorm:"primaryKey" example:"1"`
+	Name   string `json:"name" gorm:"not null" example:"Неделя Go"`
+	Target string `json:"target" gorm:"not null" example:"genre"`
+	// Value - жанр, автор или ID книги; жанр и автор сравниваются без учёта регистра
+	Value string `json:"value" gorm:"not null" example:"Programming"`
+	Kind  string `json:"kind" gorm:"not null" example:"percent"`
+	// Percent - размер скидки kind=percent, от 1 до 100
+	Percent int `json:"percent,omitempty" example:"20"`
+	// Amount - размер скидки kind=fixed в валюте Currency; действует только на книги с ценой в этой валюте
+	Amount   money.Amount `json:"amount,omitempty" swaggertype:"string" example:"5.00"`
+	Currency string       `json:"currency,omitempty" gorm:"size:3" example:"USD"`
+	StartsAt time.Time    `json:"starts_at" gorm:"not null;index"`
+	EndsAt   *time.Time   `json:"ends_at" gorm:"index"`
+	// Code - код купона в верхнем регистре, nil у распродажи
+	Code *string `json:"code,omitempty" gorm:"uniqueIndex" example:"GOPHER20"`
+	// UsageLimit - сколько заказов можно оформить с купоном, 0 - без ограничения
+	UsageLimit int       `json:"usage_limit" gorm:"not null;default:0" example:"100"`
+	UsedCount  int       `json:"used_count" gorm:"not null;default:0" example:"12"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ActiveAt - действует ли скидка в момент t
+func (d Discount) ActiveAt(t time.Time) bool {
+	return !t.Before(d.StartsAt) && (d.EndsAt == nil || t.Before(*d.EndsAt))
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Форматы файлов книги
+const (
+	FileEPUB  = "epub"
+	FilePDF   = "pdf"
+	FileCover = "cover"
+)
+
+// BookFile - файл книги в хранилище: электронная книга или обложка, не больше одного на формат
+type BookFile struct {
+	ID          uint   `json:"id" gorm:"primaryKey" example:"1"`
+	BookID      uint   `json:"book_id" gorm:"not null;uniqueIndex:idx_book_files_book_format" example:"1"`
+	Format      string `json:"format" gorm:"not null;uniqueIndex:idx_book_files_book_format" example:"epub"`
+	Filename    string `json:"filename" example:"gopl.epub"`
+	ContentType string `json:"content_type" gorm:"not null" example:"application/epub+zip"`
+	Size        int64  `json:"size" gorm:"not null" example:"2483011"`
+	SHA256      string `json:"sha256" gorm:"not null;size:64"`
+	// KOReaderDigest - идентификатор документа KOReader, по нему синхронизация находит книгу
+	KOReaderDigest string `json:"-" gorm:"column:koreader_digest;size:32;index"`
+	// StorageKey - ключ в хранилище; содержит хэш, поэтому новая версия файла не перезаписывает старую
+	StorageKey string    `json:"-" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+// Типы записей журнала штрафов
+const (
+	FineCharge  = "charge"
+	FinePayment = "payment"
+	FineWaiver  = "waiver"
+)
+
+// FineEntry - запись журнала штрафов. Журнал только дополняется: ошибочное начисление не правится,
+// а гасится списанием (waiver). Amount в минимальных единицах валюты Currency (базовой валюты каталога):
+// начисление положительное, оплата и списание отрицательные, поэтому долг - сумма по пользователю
+type FineEntry struct {
+	ID       uint         `json:"id" gorm:"primaryKey" example:"1"`
+	UserID   uint         `json:"user_id" gorm:"not null;index" example:"2"`
+	Type     string       `json:"type" gorm:"not null" example:"charge"`
+	Amount   money.Amount `json:"amount" gorm:"not null" swaggertype:"string" example:"1.50"`
+	Currency string       `json:"currency" gorm:"not null;size:3" example:"USD"`
+	// LoanID - выдача, за просрочку которой начислен штраф
+	LoanID *uint  `json:"loan_id" gorm:"index" example:"7"`
+	Note   string `json:"note" example:"Просрочка 3 дн."`
+	// CreatedBy - администратор, принявший оплату или списавший долг; пусто у автоматических начислений
+	CreatedBy *uint     `json:"created_by" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	FollowAuthor = "author"
+	FollowGenre  = "genre"
+	FollowUser   = "user"
+)
+
+// Follow - подписка пользователя на автора, жанр или другого пользователя. TargetKey - нормализованное
+// имя для сопоставления с книгами, у подписки на пользователя - его ID
+type Follow struct {
+	ID         uint      `json:"id" gorm:"primaryKey" example:"1"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_follows_user_target" example:"1"`
+	TargetType string    `json:"type" gorm:"not null;uniqueIndex:idx_follows_user_target;index:idx_follows_target" example:"author"`
+	TargetKey  string    `json:"-" gorm:"not null;uniqueIndex:idx_follows_user_target;index:idx_follows_target"`
+	Target     string    `json:"target" gorm:"not null" example:"Ursula K. Le Guin"`
+	CreatedAt  time.Time `json:"created_at"`
+}
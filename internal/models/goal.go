@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Метрики цели чтения
+const (
+	GoalBooks = "books"
+	GoalPages = "pages"
+)
+
+// ReadingGoal - цель на период: прочитать Target книг или страниц с StartDate по EndDate включительно.
+// Прогресс не хранится, а считается по завершённым прочтениям
+type ReadingGoal struct {
+	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
+	UserID    uint      `json:"user_id" gorm:"not null;index" example:"1"`
+	Title     string    `json:"title" gorm:"not null" example:"30 книг за 2026"`
+	Metric    string    `json:"metric" gorm:"not null" example:"books"`
+	Target    int       `json:"target" gorm:"not null" example:"30"`
+	StartDate time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate   time.Time `json:"end_date" gorm:"type:date;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Статусы брони
+const (
+	HoldWaiting   = "waiting"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+	HoldExpired   = "expired"
+)
+
+// Hold - место в очереди на книгу. Очередь FIFO по ID: вернувшийся экземпляр откладывается
+// первому ожидающему (ready) до ExpiresAt, потом переходит следующему
+type Hold struct {
+	ID     uint   `json:"id" gorm:"primaryKey" example:"1"`
+	BookID uint   `json:"book_id" gorm:"not null;index:idx_holds_book_status;uniqueIndex:idx_holds_active_user_book,where:status = 'waiting' OR status = 'ready'" example:"1"`
+	Book   Book   `json:"-" gorm:"foreignKey:BookID"`
+	UserID uint   `json:"user_id" gorm:"not null;index;uniqueIndex:idx_holds_active_user_book" example:"2"`
+	Status string `json:"status" gorm:"not null;index:idx_holds_book_status" example:"waiting"`
+	// CopyID - отложенный экземпляр, заполняется при переходе в ready
+	CopyID    *uint      `json:"copy_id" example:"4"`
+	Copy      *Copy      `json:"-" gorm:"foreignKey:CopyID"`
+	ReadyAt   *time.Time `json:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+type ImportRowError struct {
+	Row     int    `json:"row" example:"3"`
+	Field   string `json:"field,omitempty" example:"price"`
+	Message string `json:"message" example:"price must be a non-negative number"`
+}
+
+type ImportJob struct {
+	gorm.Model    `swaggerignore:"true"`
+	UserID        uint             `json:"user_id" gorm:"not null;index"`
+	Format        string           `json:"format" gorm:"not null"`
+	DryRun        bool             `json:"dry_run"`
+	Status        string           `json:"status" gorm:"not null;default:pending"`
+	TotalRows     int              `json:"total_rows"`
+	ValidRows     int              `json:"valid_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Errors        []ImportRowError `json:"errors" gorm:"serializer:json"`
+	Message       string           `json:"message"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+	// HeartbeatAt продлевает экземпляр сервера, который выполняет задачу. Файл задачи есть только в его
+	// памяти, поэтому задачу с давним heartbeat уже никто не закончит
+	HeartbeatAt *time.Time `json:"-" gorm:"index"`
+}
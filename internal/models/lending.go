@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Статусы экземпляра
+const (
+	CopyAvailable   = "available"
+	CopyOnLoan      = "on_loan"
+	CopyOnHold      = "on_hold"
+	CopyMaintenance = "maintenance"
+	CopyLost        = "lost"
+)
+
+// Состояние экземпляра
+const (
+	ConditionNew     = "new"
+	ConditionGood    = "good"
+	ConditionFair    = "fair"
+	ConditionPoor    = "poor"
+	ConditionDamaged = "damaged"
+)
+
+// Copy - физический экземпляр книги. Book остаётся карточкой издания, выдаются экземпляры.
+// Штрихкод уникален только среди неудалённых экземпляров, чтобы его можно было выдать заново
+type Copy struct {
+	gorm.Model `swaggerignore:"true"`
+	BookID     uint   `json:"book_id" gorm:"not null;index:idx_copies_book_status" example:"1"`
+	Barcode    string `json:"barcode" gorm:"not null;uniqueIndex:idx_copies_barcode,where:deleted_at IS NULL" example:"LIB-000123"`
+	Location   string `json:"location" example:"Шкаф 2, полка 3"`
+	Condition  string `json:"condition" gorm:"not null" example:"good"`
+	Status     string `json:"status" gorm:"not null;index:idx_copies_book_status" example:"available"`
+}
+
+// Loan - выдача экземпляра. Пока ReturnedAt пустой, выдача активна; частичный уникальный индекс
+// не даёт выдать один экземпляр дважды
+type Loan struct {
+	ID           uint       `json:"id" gorm:"primaryKey" example:"1"`
+	CopyID       uint       `json:"copy_id" gorm:"not null;uniqueIndex:idx_loans_active_copy,where:returned_at IS NULL" example:"4"`
+	Copy         Copy       `json:"-" gorm:"foreignKey:CopyID"`
+	BookID       uint       `json:"book_id" gorm:"not null;index" example:"1"`
+	Book         Book       `json:"-" gorm:"foreignKey:BookID"`
+	UserID       uint       `json:"user_id" gorm:"not null;index:idx_loans_user_returned" example:"2"`
+	CheckedOutAt time.Time  `json:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at" gorm:"not null;index"`
+	ReturnedAt   *time.Time `json:"returned_at" gorm:"index:idx_loans_user_returned"`
+	Renewals     int        `json:"renewals" gorm:"not null;default:0" example:"0"`
+	// FinedDays - сколько дней просрочки уже начислено в журнал штрафов
+	FinedDays int `json:"-" gorm:"not null;default:0"`
+	// DueReminderAt - когда отправлено напоминание о скором сроке; продление сбрасывает его
+	DueReminderAt *time.Time `json:"-"`
+	// OverdueReminderAt - когда отправлено последнее напоминание о просрочке
+	OverdueReminderAt *time.Time `json:"-"`
+}
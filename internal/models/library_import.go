@@ -0,0 +1,46 @@
+package models
+
+import "gorm.io/gorm"
+
+const (
+	LibraryRowMatched   = "matched"
+	LibraryRowAmbiguous = "ambiguous"
+	LibraryRowUnmatched = "unmatched"
+	LibraryRowResolved  = "resolved"
+	LibraryRowSkipped   = "skipped"
+	// LibraryRowFailed - книгу нашли, но перенести строку не удалось; её можно перенести заново через resolve
+	LibraryRowFailed = "failed"
+)
+
+type LibraryImportCandidate struct {
+	BookID uint    `json:"book_id" example:"12"`
+	Title  string  `json:"title" example:"The Go Programming Language"`
+	Author string  `json:"author" example:"Alan A. A. Donovan"`
+	Score  float64 `json:"score" example:"0.82"`
+}
+
+// LibraryImportRow хранит исходные данные строки. У найденной книги полка переносится в статус чтения,
+// оценка - в рецензию без текста; Applied и Skipped показывают, что перенесено, а что нет и почему
+type LibraryImportRow struct {
+	Row        int                      `json:"row" example:"2"`
+	Title      string                   `json:"title" example:"The Go Programming Language"`
+	Author     string                   `json:"author" example:"Alan A. A. Donovan"`
+	ISBN       string                   `json:"isbn,omitempty" example:"9780134190440"`
+	Shelf      string                   `json:"shelf,omitempty" example:"read"`
+	Rating     int                      `json:"rating,omitempty" example:"5"`
+	DateRead   string                   `json:"date_read,omitempty" example:"2024/03/15"`
+	Status     string                   `json:"status" example:"matched"`
+	MatchedBy  string                   `json:"matched_by,omitempty" example:"isbn"`
+	BookID     *uint                    `json:"book_id,omitempty" example:"12"`
+	Candidates []LibraryImportCandidate `json:"candidates,omitempty"`
+	Applied    []string                 `json:"applied,omitempty" example:"favourite,shelf,rating"`
+	Skipped    []string                 `json:"skipped,omitempty" example:"rating: book already has your review"`
+	Error      string                   `json:"error,omitempty" example:"failed to add favourite: connection refused"`
+}
+
+type LibraryImport struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint               `json:"user_id" gorm:"not null;index"`
+	Source     string             `json:"source" gorm:"not null"`
+	Rows       []LibraryImportRow `json:"rows" gorm:"serializer:json"`
+}
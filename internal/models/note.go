@@ -0,0 +1,34 @@
+package models
+
+import "gorm.io/gorm"
+
+// Виды заметок
+const (
+	NoteKindNote      = "note"
+	NoteKindHighlight = "highlight"
+	NoteKindQuote     = "quote"
+)
+
+const (
+	NoteVisibilityPrivate = "private"
+	NoteVisibilityPublic  = "public"
+)
+
+// NoteSearchVector - документ полнотекстового поиска. Тем же выражением построен GIN-индекс,
+// поэтому запросы должны использовать его без изменений. Конфигурация simple: заметки бывают на разных языках
+const NoteSearchVector = "to_tsvector('simple', coalesce(quote, '') || ' ' || coalesce(text, '') || ' ' || coalesce(tags::text, ''))"
+
+// Note - заметка, выделение или цитата к книге. Quote - текст из книги, Text - мысли пользователя
+type Note struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint     `json:"user_id" gorm:"not null;index:idx_notes_user_book" example:"1"`
+	BookID     uint     `json:"book_id" gorm:"not null;index:idx_notes_user_book;index" example:"1"`
+	Book       Book     `json:"-" gorm:"foreignKey:BookID"`
+	Kind       string   `json:"kind" gorm:"not null" example:"highlight"`
+	Quote      string   `json:"quote" example:"Don't communicate by sharing memory"`
+	Text       string   `json:"text" example:"Главная идея главы про каналы"`
+	Page       *int     `json:"page" example:"231"`
+	Location   string   `json:"location" example:"epubcfi(/6/14!/4/2/1:0)"`
+	Tags       []string `json:"tags" gorm:"type:jsonb;serializer:json" example:"concurrency"`
+	Visibility string   `json:"visibility" gorm:"not null;default:private" example:"private"`
+}
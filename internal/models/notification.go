@@ -0,0 +1,43 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+const (
+	NotificationPriceDrop     = "price_drop"
+	NotificationAuthorArrival = "new_book_author"
+	NotificationGenreArrival  = "new_book_genre"
+	NotificationLoanDue       = "loan_due"
+	NotificationLoanOverdue   = "loan_overdue"
+	NotificationLowStock      = "low_stock"
+)
+
+type Notification struct {
+	ID        uint          `json:"id" gorm:"primaryKey" example:"1"`
+	UserID    uint          `json:"user_id" gorm:"not null;index:idx_notifications_user_read" example:"1"`
+	Type      string        `json:"type" gorm:"not null" example:"price_drop"`
+	BookID    uint          `json:"book_id" gorm:"index" example:"1"`
+	Title     string        `json:"title" gorm:"not null" example:"Цена снижена: «Dune»"`
+	Body      string        `json:"body" example:"Цена снизилась с 49.99 до 39.99"`
+	OldPrice  *money.Amount `json:"old_price,omitempty" swaggertype:"string" example:"49.99"`
+	NewPrice  *money.Amount `json:"new_price,omitempty" swaggertype:"string" example:"39.99"`
+	Currency  string        `json:"currency,omitempty" gorm:"size:3" example:"USD"`
+	ReadAt    *time.Time    `json:"read_at" gorm:"index:idx_notifications_user_read"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// NotificationSettings: без записи действуют значения по умолчанию - все события, только в приложении
+type NotificationSettings struct {
+	UserID      uint      `json:"-" gorm:"primaryKey"`
+	PriceDrops  bool      `json:"price_drops" gorm:"not null" example:"true"`
+	NewArrivals bool      `json:"new_arrivals" gorm:"not null" example:"true"`
+	Email       string    `json:"email" example:"reader@example.com"`
+	WebhookURL  string    `json:"webhook_url" example:"https://example.com/hooks/bookshelf"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func DefaultNotificationSettings(userID uint) NotificationSettings {
+	return NotificationSettings{UserID: userID, PriceDrops: true, NewArrivals: true}
+}
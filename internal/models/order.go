@@ -0,0 +1,59 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+// Статусы заказа: pending -> paid -> fulfilled; отменить можно неоплаченный или оплаченный заказ,
+// вернуть деньги - за оплаченный или выполненный
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderFulfilled = "fulfilled"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+// CartItem - книга в корзине. Цена не хранится: корзина всегда показывает текущую цену книги
+type CartItem struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey" example:"1"`
+	BookID    uint      `json:"book_id" gorm:"primaryKey" example:"1"`
+	Book      Book      `json:"-" gorm:"foreignKey:BookID"`
+	Quantity  int       `json:"quantity" gorm:"not null" example:"2"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Order - заказ. Цены и названия книг копируются в позиции при оформлении и дальше не меняются.
+// Все суммы заказа - в минимальных единицах его валюты Currency
+type Order struct {
+	ID       uint         `json:"id" gorm:"primaryKey" example:"1"`
+	UserID   uint         `json:"user_id" gorm:"not null;index" example:"1"`
+	Status   string       `json:"status" gorm:"not null;index" example:"pending"`
+	Total    money.Amount `json:"total" gorm:"not null" swaggertype:"string" example:"39.98"`
+	Currency string       `json:"currency" gorm:"not null;size:3" example:"USD"`
+	// CouponID и CouponCode - купон, с которым оформлен заказ
+	CouponID   *uint  `json:"coupon_id,omitempty" gorm:"index" example:"2"`
+	CouponCode string `json:"coupon_code,omitempty" example:"GOPHER20"`
+	// ReservedUntil - до какого момента для неоплаченного заказа отложен товар; потом заказ отменяется
+	ReservedUntil *time.Time  `json:"reserved_until,omitempty" gorm:"index"`
+	Items         []OrderItem `json:"items"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	PaidAt        *time.Time  `json:"paid_at"`
+	FulfilledAt   *time.Time  `json:"fulfilled_at"`
+	CancelledAt   *time.Time  `json:"cancelled_at"`
+	RefundedAt    *time.Time  `json:"refunded_at"`
+}
+
+type OrderItem struct {
+	ID        uint         `json:"id" gorm:"primaryKey" example:"1"`
+	OrderID   uint         `json:"order_id" gorm:"not null;index" example:"1"`
+	BookID    uint         `json:"book_id" gorm:"not null;index" example:"1"`
+	Title     string       `json:"title" gorm:"not null" example:"Dune"`
+	Author    string       `json:"author" example:"Frank Herbert"`
+	UnitPrice money.Amount `json:"unit_price" gorm:"not null" swaggertype:"string" example:"19.99"`
+	Quantity  int          `json:"quantity" gorm:"not null" example:"2"`
+	LineTotal money.Amount `json:"line_total" gorm:"not null" swaggertype:"string" example:"39.98"`
+}
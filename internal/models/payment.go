@@ -0,0 +1,45 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+// Статусы платежа: pending (ждёт подтверждения покупателем) -> authorized -> succeeded -> refund_pending -> refunded;
+// из pending и authorized платёж может стать failed
+const (
+	PaymentPending       = "pending"
+	PaymentAuthorized    = "authorized"
+	PaymentSucceeded     = "succeeded"
+	PaymentFailed        = "failed"
+	PaymentRefundPending = "refund_pending"
+	PaymentRefunded      = "refunded"
+)
+
+// Payment - попытка оплаты заказа у платёжного провайдера. У заказа может быть несколько попыток,
+// но активная (не failed) - только одна
+type Payment struct {
+	ID       uint   `json:"id" gorm:"primaryKey" example:"1"`
+	OrderID  uint   `json:"order_id" gorm:"not null;index" example:"1"`
+	Provider string `json:"provider" gorm:"not null;uniqueIndex:idx_payments_provider_intent" example:"stripe"`
+	IntentID string `json:"intent_id" gorm:"not null;uniqueIndex:idx_payments_provider_intent" example:"pi_3PqK2eLk"`
+	// ClientSecret нужен покупателю для подтверждения оплаты на стороне провайдера
+	ClientSecret  string       `json:"-"`
+	Amount        money.Amount `json:"amount" gorm:"not null" swaggertype:"string" example:"39.98"`
+	Currency      string       `json:"currency" gorm:"not null;size:3" example:"USD"`
+	Status        string       `json:"status" gorm:"not null" example:"pending"`
+	FailureReason string       `json:"failure_reason" example:"Your card was declined."`
+	// RefundAttempts - сколько раз начинали возврат; номер попытки входит в ключ идемпотентности возврата
+	RefundAttempts int       `json:"-" gorm:"not null;default:0"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PaymentEvent - обработанное событие вебхука. Провайдеры доставляют события «хотя бы один раз»,
+// повтор с тем же ID пропускается
+type PaymentEvent struct {
+	Provider  string `gorm:"primaryKey"`
+	EventID   string `gorm:"primaryKey"`
+	Type      string `gorm:"not null"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// BookStatsDaily - просмотры и добавления в избранное книги за день (UTC).
+// Счётчики копятся в Redis и периодически добавляются сюда пачкой
+type BookStatsDaily struct {
+	BookID     uint      `json:"book_id" gorm:"primaryKey" example:"1"`
+	Day        time.Time `json:"day" gorm:"primaryKey;type:date;index"`
+	Views      int64     `json:"views" gorm:"not null;default:0" example:"120"`
+	Favourites int64     `json:"favourites" gorm:"not null;default:0" example:"4"`
+}
+
+func (BookStatsDaily) TableName() string {
+	return "book_stats_daily"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"bookshelf/pkg/money"
+	"time"
+)
+
+// BookPrice - цена книги в конкретной валюте из прайс-листа. Если записи нет, цена пересчитывается
+// из основной цены книги по курсу
+type BookPrice struct {
+	BookID    uint         `json:"book_id" gorm:"primaryKey" example:"1"`
+	Currency  string       `json:"currency" gorm:"primaryKey;size:3" example:"EUR"`
+	Amount    money.Amount `json:"amount" gorm:"not null" swaggertype:"string" example:"45.00"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// ExchangeRate - сколько единиц Currency стоит одна единица базовой валюты каталога.
+// Таблица загружается администратором целиком
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"primaryKey;size:3" example:"EUR"`
+	Rate      string    `json:"rate" gorm:"type:numeric(20,10);not null" example:"0.9215"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Форматы позиции чтения
+const (
+	// LocatorCFI - EPUB CFI веб-читалки и приложений
+	LocatorCFI = "cfi"
+	// LocatorXPointer - позиция KOReader в EPUB и других reflowable-документах
+	LocatorXPointer = "xpointer"
+	// LocatorPage - номер страницы KOReader в PDF
+	LocatorPage = "page"
+)
+
+// ReadingProgress - последняя позиция чтения пользователя в документе. Из нескольких устройств
+// побеждает запись с более поздним временем чтения (last-writer-wins)
+type ReadingProgress struct {
+	ID     uint `json:"id" gorm:"primaryKey" example:"1"`
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex:idx_reading_progress_user_document" example:"1"`
+	// Document - "book:<id>" для книги каталога или идентификатор документа KOReader для прочих файлов
+	Document    string  `json:"document" gorm:"not null;size:80;uniqueIndex:idx_reading_progress_user_document" example:"book:1"`
+	BookID      *uint   `json:"book_id" gorm:"index" example:"1"`
+	Locator     string  `json:"locator" example:"epubcfi(/6/14!/4/2/10/2:352)"`
+	LocatorType string  `json:"locator_type" example:"cfi"`
+	Percent     float64 `json:"percent" example:"42.5"`
+	Device      string  `json:"device" example:"Kobo Libra 2"`
+	DeviceID    string  `json:"device_id" gorm:"not null" example:"kobo-5f2c"`
+	// RecordedAt - когда позиция достигнута на устройстве
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ReadingProgressEntry - запись истории позиций, в том числе проигравших более поздней
+type ReadingProgressEntry struct {
+	ID          uint      `json:"id" gorm:"primaryKey" example:"1"`
+	UserID      uint      `json:"user_id" gorm:"not null;index:idx_progress_entries_user_document" example:"1"`
+	Document    string    `json:"document" gorm:"not null;size:80;index:idx_progress_entries_user_document" example:"book:1"`
+	BookID      *uint     `json:"book_id" example:"1"`
+	Locator     string    `json:"locator" example:"epubcfi(/6/14!/4/2/10/2:352)"`
+	LocatorType string    `json:"locator_type" example:"cfi"`
+	Percent     float64   `json:"percent" example:"42.5"`
+	Device      string    `json:"device" example:"Kobo Libra 2"`
+	DeviceID    string    `json:"device_id" example:"kobo-5f2c"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	// Applied - стала ли запись текущей позицией
+	Applied   bool      `json:"applied" example:"true"`
+	CreatedAt time.Time `json:"created_at"`
+}
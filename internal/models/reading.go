@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Полки чтения
+const (
+	ShelfWantToRead = "want_to_read"
+	ShelfReading    = "reading"
+	ShelfRead       = "read"
+)
+
+// ReadingStatus - текущее положение книги у пользователя: полка, прогресс и даты последнего прочтения
+type ReadingStatus struct {
+	gorm.Model  `swaggerignore:"true"`
+	UserID      uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_reading_user_book" example:"1"`
+	BookID      uint       `json:"book_id" gorm:"not null;uniqueIndex:idx_reading_user_book" example:"1"`
+	Book        Book       `json:"-" gorm:"foreignKey:BookID"`
+	Shelf       string     `json:"shelf" gorm:"not null;index" example:"reading"`
+	CurrentPage int        `json:"current_page" example:"120"`
+	Percent     int        `json:"percent" example:"32"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	ReadCount   int        `json:"read_count" gorm:"not null;default:0" example:"1"`
+}
+
+// ReadThrough - одно прочтение книги. Повторное чтение создаёт новую запись,
+// по завершённым прочтениям считается статистика
+type ReadThrough struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint       `json:"user_id" gorm:"not null;index:idx_read_throughs_user_finished" example:"1"`
+	BookID     uint       `json:"book_id" gorm:"not null;index" example:"1"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at" gorm:"index:idx_read_throughs_user_finished"`
+	// Объём книги на момент завершения, чтобы статистика не менялась при правке книги
+	Pages int `json:"pages" example:"380"`
+}
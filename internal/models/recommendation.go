@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// BookSimilarity - предрассчитанная похожесть пары книг. Таблица целиком пересобирается фоновой задачей,
+// для каждой книги хранятся только ближайшие соседи
+type BookSimilarity struct {
+	BookID        uint    `json:"book_id" gorm:"primaryKey" example:"1"`
+	SimilarBookID uint    `json:"similar_book_id" gorm:"primaryKey" example:"2"`
+	Score         float64 `json:"score" gorm:"not null" example:"0.42"`
+	// Сколько пользователей добавили в избранное обе книги
+	CoFavourites     int       `json:"co_favourites" example:"3"`
+	SameAuthor       bool      `json:"same_author"`
+	SameGenre        bool      `json:"same_genre"`
+	DescriptionScore float64   `json:"description_score" example:"0.18"`
+	ComputedAt       time.Time `json:"computed_at"`
+}
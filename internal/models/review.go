@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review - оценка 1-5 и необязательный текст. У пользователя одна рецензия на книгу.
+// В средний рейтинг книги попадают только одобренные рецензии
+type Review struct {
+	gorm.Model     `swaggerignore:"true"`
+	UserID         uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_reviews_user_book" example:"1"`
+	BookID         uint       `json:"book_id" gorm:"not null;uniqueIndex:idx_reviews_user_book;index" example:"1"`
+	Rating         int        `json:"rating" gorm:"not null" example:"5"`
+	Text           string     `json:"text" example:"Лучшая книга по Go"`
+	Status         string     `json:"status" gorm:"not null;index" example:"approved"`
+	HelpfulCount   int        `json:"helpful_count" gorm:"not null;default:0" example:"3"`
+	ModeratorID    *uint      `json:"moderator_id,omitempty"`
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+}
+
+// RatingContribution - вклад рецензии в счётчики книги: сумма оценок, число оценок, число текстовых рецензий
+func (r Review) RatingContribution() (sum, ratings, reviews int) {
+	if r.ID == 0 || r.Status != ReviewStatusApproved {
+		return 0, 0, 0
+	}
+	if r.Text != "" {
+		reviews = 1
+	}
+	return r.Rating, 1, reviews
+}
+
+// ReviewVote - отметка "полезно", одна от пользователя на рецензию
+type ReviewVote struct {
+	UserID    uint `gorm:"primaryKey"`
+	ReviewID  uint `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Причины движения товара. Ручные: restock, return, damaged, lost, correction; остальные пишутся заказами
+const (
+	StockRestock        = "restock"
+	StockReturn         = "return"
+	StockDamaged        = "damaged"
+	StockLost           = "lost"
+	StockCorrection     = "correction"
+	StockSale           = "sale"
+	StockOrderCancelled = "order_cancelled"
+	StockOrderRefunded  = "order_refunded"
+)
+
+// StockLevel - остаток книги на складе. Учёт включается первой записью: книги без неё продаются без ограничений.
+// Reserved - экземпляры в неоплаченных заказах; продать можно только OnHand - Reserved
+type StockLevel struct {
+	BookID   uint `json:"book_id" gorm:"primaryKey" example:"1"`
+	Book     Book `json:"-" gorm:"foreignKey:BookID"`
+	OnHand   int  `json:"on_hand" gorm:"not null;default:0" example:"12"`
+	Reserved int  `json:"reserved" gorm:"not null;default:0" example:"2"`
+	// LowStockThreshold - при доступном остатке не больше порога администраторы получают уведомление
+	LowStockThreshold int `json:"low_stock_threshold" gorm:"not null;default:0" example:"3"`
+	// LowStockAlertedAt - когда отправлено уведомление; сбрасывается, когда остаток снова выше порога
+	LowStockAlertedAt *time.Time `json:"low_stock_alerted_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (l StockLevel) Available() int {
+	return l.OnHand - l.Reserved
+}
+
+// StockReservation - экземпляры книги, отложенные под неоплаченный заказ до Order.ReservedUntil
+type StockReservation struct {
+	OrderID  uint `json:"order_id" gorm:"primaryKey" example:"1"`
+	BookID   uint `json:"book_id" gorm:"primaryKey;index" example:"1"`
+	Quantity int  `json:"quantity" gorm:"not null" example:"2"`
+}
+
+// StockMovement - запись журнала движения товара. Журнал только дополняется, OnHand - остаток после движения
+type StockMovement struct {
+	ID     uint   `json:"id" gorm:"primaryKey" example:"1"`
+	BookID uint   `json:"book_id" gorm:"not null;index" example:"1"`
+	Delta  int    `json:"delta" gorm:"not null" example:"-2"`
+	OnHand int    `json:"on_hand" gorm:"not null" example:"10"`
+	Reason string `json:"reason" gorm:"not null" example:"sale"`
+	Note   string `json:"note" example:"Поставка от издательства"`
+	// OrderID - заказ, которым вызвано движение
+	OrderID *uint `json:"order_id" gorm:"index" example:"3"`
+	// CreatedBy - администратор, пусто у движений по заказам
+	CreatedBy *uint     `json:"created_by" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+}
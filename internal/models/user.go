@@ -3,9 +3,11 @@ package models
 import "gorm.io/gorm"
 
 type User struct {
-	gorm.Model     `swaggerignore:"true"`
-	Username       string `json:"username" gorm:"unique;not null" example:"john_doe"`
-	PasswordHash   string `json:"-" gorm:"not null"`
+	gorm.Model   `swaggerignore:"true"`
+	Username     string `json:"username" gorm:"unique;not null" example:"john_doe"`
+	PasswordHash string `json:"-" gorm:"not null"`
+	// SyncKeyHash - bcrypt от ключа синхронизации KOReader (MD5 пароля), задаётся при регистрации и входе
+	SyncKeyHash    string `json:"-"`
 	Role           string `json:"role" gorm:"default:user" example:"user"`
 	FavouriteBooks []Book `gorm:"many2many:user_favourites;"`
 }
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FeedTarget - подписка, через которую событие попадает в ленту
+type FeedTarget struct {
+	Type string
+	Key  string
+}
+
+type ActivityRepository interface {
+	// Publish сохраняет событие и раскладывает его по лентам подписчиков targets, кроме самого автора
+	Publish(event *models.ActivityEvent, targets []FeedTarget) error
+	// HasRecent проверяет, было ли то же действие после since. Незаполненные ID события не сравниваются
+	HasRecent(event models.ActivityEvent, since time.Time) (bool, error)
+	GetFeed(userID uint, page, limit int) ([]models.ActivityEvent, int64, error)
+	GetPrivacy(userID uint) (models.PrivacySettings, error)
+	SavePrivacy(settings *models.PrivacySettings) error
+}
+
+type activityRepo struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepo{db: db}
+}
+
+func (r *activityRepo) Publish(event *models.ActivityEvent, targets []FeedTarget) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+
+		conds := make([]string, len(targets))
+		args := []interface{}{event.ID, event.CreatedAt}
+		for i, t := range targets {
+			conds[i] = "(target_type = ? AND target_key = ?)"
+			args = append(args, t.Type, t.Key)
+		}
+		query := `
+			INSERT INTO feed_items (user_id, event_id, created_at)
+			SELECT DISTINCT user_id, CAST(? AS bigint), CAST(? AS timestamptz)
+			FROM follows
+			WHERE (` + strings.Join(conds, " OR ") + `)`
+		if event.ActorID != nil {
+			query += " AND user_id <> ?"
+			args = append(args, *event.ActorID)
+		}
+		return tx.Exec(query+" ON CONFLICT DO NOTHING", args...).Error
+	})
+}
+
+func (r *activityRepo) HasRecent(event models.ActivityEvent, since time.Time) (bool, error) {
+	db := r.db.Model(&models.ActivityEvent{}).
+		Where("type = ? AND created_at > ?", event.Type, since)
+	db = whereID(db, "actor_id", event.ActorID)
+	db = whereID(db, "book_id", event.BookID)
+	db = whereID(db, "collection_id", event.CollectionID)
+
+	var ids []uint
+	err := db.Limit(1).Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
+
+func whereID(db *gorm.DB, column string, value *uint) *gorm.DB {
+	if value == nil {
+		return db
+	}
+	return db.Where(column+" = ?", *value)
+}
+
+// GetFeed применяет настройки приватности на чтении: если автор скрыл действия уже после
+// раскладки, они пропадают из лент. Отклонённые рецензии и закрытые подборки тоже не показываются
+func (r *activityRepo) GetFeed(userID uint, page, limit int) ([]models.ActivityEvent, int64, error) {
+	var events []models.ActivityEvent
+	var total int64
+
+	db := r.db.Table("feed_items AS f").
+		Joins("JOIN activity_events e ON e.id = f.event_id").
+		Joins("LEFT JOIN privacy_settings p ON p.user_id = e.actor_id").
+		Where("f.user_id = ?", userID).
+		Where("e.type <> ? OR COALESCE(p.share_favourites, false)", models.ActivityFavouriteAdded).
+		Where("e.type <> ? OR COALESCE(p.share_reviews, true)", models.ActivityReviewPublished).
+		Where("e.type <> ? OR COALESCE(p.share_collections, true)", models.ActivityCollectionUpdated).
+		Where(`e.review_id IS NULL OR EXISTS (
+			SELECT 1 FROM reviews r
+			WHERE r.id = e.review_id AND r.status = ? AND r.deleted_at IS NULL
+		)`, models.ReviewStatusApproved).
+		Where(`e.collection_id IS NULL OR EXISTS (
+			SELECT 1 FROM collections c
+			WHERE c.id = e.collection_id AND c.visibility = ? AND c.deleted_at IS NULL
+		)`, models.CollectionPublic)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Select("e.*").
+		Order("f.event_id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&events).Error
+	return events, total, err
+}
+
+func (r *activityRepo) GetPrivacy(userID uint) (models.PrivacySettings, error) {
+	var settings models.PrivacySettings
+	err := r.db.First(&settings, "user_id = ?", userID).Error
+	return settings, err
+}
+
+func (r *activityRepo) SavePrivacy(settings *models.PrivacySettings) error {
+	return r.db.Save(settings).Error
+}
@@ -2,15 +2,22 @@ package repository
 
 import (
 	models "bookshelf/internal/models"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
 type BookRepository interface {
-	CreateBook(book models.Book) error
+	CreateBook(book *models.Book) error
+	CreateBooks(books []models.Book, batchSize int, onBatch func(inserted int)) error
 	GetAllBooks(genre string, page, limit int) ([]models.Book, int64, error)
 	GetBookByID(id string) (models.Book, error)
+	StreamBooks(genre string, fn func(book models.Book) error) error
 	GetAllGenres() ([]string, error)
+	FindBooksByISBN(isbns []string) ([]models.Book, error)
+	GetBooksByIDs(ids []uint) ([]models.Book, error)
+	SearchBooks(query string, page, limit int) ([]models.Book, int64, error)
+	GetNewestBooks(page, limit int) ([]models.Book, int64, error)
 	UpdateBook(book models.Book) error
 	DeleteBook(id string) error
 }
@@ -23,8 +30,30 @@ func NewBookRepository(db *gorm.DB) BookRepository {
 	return &bookRepo{db: db}
 }
 
-func (r *bookRepo) CreateBook(book models.Book) error {
-	return r.db.Create(&book).Error
+// withLiveBook оставляет строки table, книга которых не удалена из каталога. Preload("Book") такие
+// книги не находит, и без фильтра строка превратилась бы в пустую книгу, но осталась бы в total
+func withLiveBook(db *gorm.DB, table string) *gorm.DB {
+	return db.Where("EXISTS (SELECT 1 FROM books WHERE books.id = " + table + ".book_id AND books.deleted_at IS NULL)")
+}
+
+func (r *bookRepo) CreateBook(book *models.Book) error {
+	return r.db.Create(book).Error
+}
+
+// CreateBooks вставляет книги пачками в одной транзакции: либо все, либо ничего
+func (r *bookRepo) CreateBooks(books []models.Book, batchSize int, onBatch func(inserted int)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(books); start += batchSize {
+			end := min(start+batchSize, len(books))
+			if err := tx.Create(books[start:end]).Error; err != nil {
+				return err
+			}
+			if onBatch != nil {
+				onBatch(end)
+			}
+		}
+		return nil
+	})
 }
 
 func (r *bookRepo) GetAllBooks(genre string, page, limit int) ([]models.Book, int64, error) {
@@ -46,6 +75,31 @@ func (r *bookRepo) GetAllBooks(genre string, page, limit int) ([]models.Book, in
 	return books, total, err
 }
 
+// StreamBooks проходит по книгам курсором, не загружая всю выборку в память
+func (r *bookRepo) StreamBooks(genre string, fn func(book models.Book) error) error {
+	db := r.db.Model(&models.Book{})
+	if genre != "" {
+		db = db.Where("genre = ?", genre)
+	}
+
+	rows, err := db.Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.Book
+		if err := r.db.ScanRows(rows, &book); err != nil {
+			return err
+		}
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *bookRepo) GetBookByID(id string) (models.Book, error) {
 	var book models.Book
 	err := r.db.First(&book, "id = ?", id).Error
@@ -58,8 +112,58 @@ func (r *bookRepo) GetAllGenres() ([]string, error) {
 	return genres, err
 }
 
+func (r *bookRepo) FindBooksByISBN(isbns []string) ([]models.Book, error) {
+	var books []models.Book
+	if len(isbns) == 0 {
+		return books, nil
+	}
+	err := r.db.Where("isbn IN ?", isbns).Find(&books).Error
+	return books, err
+}
+
+func (r *bookRepo) GetBooksByIDs(ids []uint) ([]models.Book, error) {
+	var books []models.Book
+	if len(ids) == 0 {
+		return books, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&books).Error
+	return books, err
+}
+
+// SearchBooks ищет подстроку в названии или авторе без учёта регистра
+func (r *bookRepo) SearchBooks(query string, page, limit int) ([]models.Book, int64, error) {
+	var books []models.Book
+	var total int64
+
+	pattern := "%" + strings.NewReplacer("%", `\%`, "_", `\_`).Replace(query) + "%"
+	db := r.db.Model(&models.Book{}).Where("title ILIKE ? OR author ILIKE ?", pattern, pattern)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order("id").Offset(offset).Limit(limit).Find(&books).Error
+	return books, total, err
+}
+
+func (r *bookRepo) GetNewestBooks(page, limit int) ([]models.Book, int64, error) {
+	var books []models.Book
+	var total int64
+
+	db := r.db.Model(&models.Book{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&books).Error
+	return books, total, err
+}
+
+// UpdateBook не трогает счётчики рецензий: их ведёт ReviewRepository
 func (r *bookRepo) UpdateBook(book models.Book) error {
-	return r.db.Save(&book).Error
+	return r.db.Omit("RatingSum", "RatingCount", "ReviewCount").Save(&book).Error
 }
 
 func (r *bookRepo) DeleteBook(id string) error {
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository interface {
+	// ListItems возвращает позиции корзины с книгами; удалённые из каталога книги пропускаются
+	ListItems(userID uint) ([]models.CartItem, error)
+	CountItems(userID uint) (int64, error)
+	// SetItem добавляет книгу или меняет её количество
+	SetItem(item *models.CartItem) error
+	RemoveItem(userID, bookID uint) (bool, error)
+	Clear(userID uint) error
+}
+
+type cartRepo struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepo{db: db}
+}
+
+func (r *cartRepo) ListItems(userID uint) ([]models.CartItem, error) {
+	var items []models.CartItem
+	err := r.db.Preload("Book").
+		Joins("JOIN books ON books.id = cart_items.book_id AND books.deleted_at IS NULL").
+		Where("cart_items.user_id = ?", userID).
+		Order("cart_items.created_at, cart_items.book_id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *cartRepo) CountItems(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CartItem{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *cartRepo) SetItem(item *models.CartItem) error {
+	return r.db.Omit("Book").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "book_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+	}).Create(item).Error
+}
+
+func (r *cartRepo) RemoveItem(userID, bookID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).Delete(&models.CartItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *cartRepo) Clear(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.CartItem{}).Error
+}
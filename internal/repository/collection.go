@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectionRepository interface {
+	CreateCollection(c *models.Collection) error
+	UpdateCollection(c *models.Collection) error
+	DeleteCollection(id uint) error
+	GetCollection(id uint) (models.Collection, error)
+	GetCollectionByToken(token string) (models.Collection, error)
+	ListPublic(page, limit int) ([]models.Collection, int64, error)
+	// ListForUser возвращает коллекции, которыми пользователь владеет или которые редактирует
+	ListForUser(userID uint, page, limit int) ([]models.Collection, int64, error)
+	// GetItems - книги коллекции по позиции; книги, удалённые из каталога, не показываются и не считаются
+	GetItems(collectionID uint, page, limit int) ([]models.CollectionItem, int64, error)
+	// AddItem ставит книгу в конец коллекции, false - книга уже там
+	AddItem(collectionID, bookID, addedBy uint) (bool, error)
+	RemoveItem(collectionID, bookID uint) (bool, error)
+	GetItemBookIDs(collectionID uint) ([]uint, error)
+	ReorderItems(collectionID uint, bookIDs []uint) error
+	IsCollaborator(collectionID, userID uint) (bool, error)
+	GetCollaborators(collectionID uint) ([]uint, error)
+	AddCollaborator(collectionID, userID uint) error
+	RemoveCollaborator(collectionID, userID uint) error
+}
+
+type collectionRepo struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &collectionRepo{db: db}
+}
+
+func (r *collectionRepo) CreateCollection(c *models.Collection) error {
+	return r.db.Create(c).Error
+}
+
+func (r *collectionRepo) UpdateCollection(c *models.Collection) error {
+	return r.db.Save(c).Error
+}
+
+func (r *collectionRepo) DeleteCollection(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionCollaborator{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Collection{}, id).Error
+	})
+}
+
+func (r *collectionRepo) GetCollection(id uint) (models.Collection, error) {
+	var c models.Collection
+	err := r.db.First(&c, id).Error
+	return c, err
+}
+
+func (r *collectionRepo) GetCollectionByToken(token string) (models.Collection, error) {
+	var c models.Collection
+	err := r.db.Where("share_token = ?", token).First(&c).Error
+	return c, err
+}
+
+func (r *collectionRepo) ListPublic(page, limit int) ([]models.Collection, int64, error) {
+	var collections []models.Collection
+	var total int64
+
+	db := r.db.Model(&models.Collection{}).Where("visibility = ?", models.CollectionPublic)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&collections).Error
+	return collections, total, err
+}
+
+func (r *collectionRepo) ListForUser(userID uint, page, limit int) ([]models.Collection, int64, error) {
+	var collections []models.Collection
+	var total int64
+
+	db := r.db.Model(&models.Collection{}).Where(
+		"owner_id = ? OR id IN (?)", userID,
+		r.db.Model(&models.CollectionCollaborator{}).Select("collection_id").Where("user_id = ?", userID),
+	)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&collections).Error
+	return collections, total, err
+}
+
+func (r *collectionRepo) GetItems(collectionID uint, page, limit int) ([]models.CollectionItem, int64, error) {
+	var items []models.CollectionItem
+	var total int64
+
+	db := withLiveBook(r.db.Model(&models.CollectionItem{}), "collection_items").Where("collection_id = ?", collectionID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Preload("Book").Order("position, created_at").Offset(offset).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+func (r *collectionRepo) AddItem(collectionID, bookID, addedBy uint) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка коллекции выстраивает параллельные добавления в очередь: без неё оба
+		// INSERT увидят один и тот же MAX(position) и книги получат одинаковую позицию
+		var collection models.Collection
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&collection, collectionID).Error; err != nil {
+			return err
+		}
+
+		res := tx.Exec(`
+			INSERT INTO collection_items (collection_id, book_id, position, added_by, created_at)
+			SELECT ?, ?, COALESCE(MAX(position), 0) + 1, ?, NOW()
+			FROM collection_items WHERE collection_id = ?
+			ON CONFLICT DO NOTHING
+		`, collectionID, bookID, addedBy, collectionID)
+		if res.Error != nil {
+			return res.Error
+		}
+		added = res.RowsAffected > 0
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+	return added, err
+}
+
+func (r *collectionRepo) RemoveItem(collectionID, bookID uint) (bool, error) {
+	res := r.db.Where("collection_id = ? AND book_id = ?", collectionID, bookID).Delete(&models.CollectionItem{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	r.touch(collectionID)
+	return res.RowsAffected > 0, nil
+}
+
+func (r *collectionRepo) GetItemBookIDs(collectionID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.CollectionItem{}).Where("collection_id = ?", collectionID).
+		Order("position").Pluck("book_id", &ids).Error
+	return ids, err
+}
+
+// ReorderItems проставляет позиции 1..n в порядке bookIDs
+func (r *collectionRepo) ReorderItems(collectionID uint, bookIDs []uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i, bookID := range bookIDs {
+			err := tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND book_id = ?", collectionID, bookID).
+				Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		r.touch(collectionID)
+	}
+	return err
+}
+
+func (r *collectionRepo) IsCollaborator(collectionID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CollectionCollaborator{}).
+		Where("collection_id = ? AND user_id = ?", collectionID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *collectionRepo) GetCollaborators(collectionID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.CollectionCollaborator{}).Where("collection_id = ?", collectionID).
+		Order("created_at").Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *collectionRepo) AddCollaborator(collectionID, userID uint) error {
+	return r.db.Exec(`
+		INSERT INTO collection_collaborators (collection_id, user_id, created_at)
+		VALUES (?, ?, NOW())
+		ON CONFLICT DO NOTHING
+	`, collectionID, userID).Error
+}
+
+func (r *collectionRepo) RemoveCollaborator(collectionID, userID uint) error {
+	return r.db.Where("collection_id = ? AND user_id = ?", collectionID, userID).
+		Delete(&models.CollectionCollaborator{}).Error
+}
+
+// touch поднимает коллекцию в списках после изменения состава
+func (r *collectionRepo) touch(collectionID uint) {
+	r.db.Model(&models.Collection{}).Where("id = ?", collectionID).Update("updated_at", gorm.Expr("NOW()"))
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DiscountRepository interface {
+	CreateDiscount(discount *models.Discount) error
+	GetDiscount(id uint) (models.Discount, error)
+	UpdateDiscount(discount *models.Discount) error
+	DeleteDiscount(id uint) (bool, error)
+	ListDiscounts(page, limit int) ([]models.Discount, int64, error)
+	// ListSales - распродажи (скидки без кода), которые ещё не закончились к моменту now, включая будущие
+	ListSales(now time.Time) ([]models.Discount, error)
+	GetByCode(code string) (models.Discount, error)
+}
+
+type discountRepo struct {
+	db *gorm.DB
+}
+
+func NewDiscountRepository(db *gorm.DB) DiscountRepository {
+	return &discountRepo{db: db}
+}
+
+func (r *discountRepo) CreateDiscount(discount *models.Discount) error {
+	return r.db.Create(discount).Error
+}
+
+func (r *discountRepo) GetDiscount(id uint) (models.Discount, error) {
+	var discount models.Discount
+	err := r.db.First(&discount, id).Error
+	return discount, err
+}
+
+// UpdateDiscount не трогает счётчик использований: его меняет только оформление и отмена заказов
+func (r *discountRepo) UpdateDiscount(discount *models.Discount) error {
+	return r.db.Model(discount).Select("*").Omit("id", "used_count", "created_at").Updates(discount).Error
+}
+
+func (r *discountRepo) DeleteDiscount(id uint) (bool, error) {
+	result := r.db.Delete(&models.Discount{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *discountRepo) ListDiscounts(page, limit int) ([]models.Discount, int64, error) {
+	var discounts []models.Discount
+	var total int64
+
+	if err := r.db.Model(&models.Discount{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := r.db.Order("starts_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&discounts).Error
+	return discounts, total, err
+}
+
+func (r *discountRepo) ListSales(now time.Time) ([]models.Discount, error) {
+	var discounts []models.Discount
+	err := r.db.Where("code IS NULL AND (ends_at IS NULL OR ends_at > ?)", now).Order("id").Find(&discounts).Error
+	return discounts, err
+}
+
+func (r *discountRepo) GetByCode(code string) (models.Discount, error) {
+	var discount models.Discount
+	err := r.db.Where("code = ?", code).First(&discount).Error
+	return discount, err
+}
@@ -7,9 +7,13 @@ import (
 )
 
 type FavouriteRepository interface {
-	AddFavourite(userID, bookID uint) error
+	// AddFavourite добавляет книгу в избранное, false - книга уже там
+	AddFavourite(userID, bookID uint) (bool, error)
 	RemoveFavourite(userID, bookID uint) error
 	GetFavourites(userID uint, page, limit int) ([]models.Book, int64, error)
+	GetFavouriteIDs(userID uint) ([]uint, error)
+	// GetFavouritedBy возвращает пользователей, у которых книга в избранном
+	GetFavouritedBy(bookID uint) ([]uint, error)
 }
 
 type favouriteRepo struct {
@@ -20,17 +24,18 @@ func NewFavouriteRepository(db *gorm.DB) FavouriteRepository {
 	return &favouriteRepo{db: db}
 }
 
-func (r *favouriteRepo) AddFavourite(userID, bookID uint) error {
-	return r.db.Exec(`
-		INSERT INTO favourite_books (user_id, book_id)
+func (r *favouriteRepo) AddFavourite(userID, bookID uint) (bool, error) {
+	res := r.db.Exec(`
+		INSERT INTO user_favourites (user_id, book_id)
 		VALUES (?, ?)
 		ON CONFLICT DO NOTHING
-	`, userID, bookID).Error
+	`, userID, bookID)
+	return res.RowsAffected > 0, res.Error
 }
 
 func (r *favouriteRepo) RemoveFavourite(userID, bookID uint) error {
 	return r.db.Exec(`
-		DELETE FROM favourite_books
+		DELETE FROM user_favourites
 		WHERE user_id = ? AND book_id = ?
 	`, userID, bookID).Error
 }
@@ -42,8 +47,8 @@ func (r *favouriteRepo) GetFavourites(userID uint, page, limit int) ([]models.Bo
 	offset := (page - 1) * limit
 
 	err := r.db.Model(&models.Book{}).
-		Joins("JOIN favourite_books ON books.id = favourite_books.book_id").
-		Where("favourite_books.user_id = ?", userID).
+		Joins("JOIN user_favourites ON books.id = user_favourites.book_id").
+		Where("user_favourites.user_id = ?", userID).
 		Count(&total).
 		Offset(offset).
 		Limit(limit).
@@ -51,3 +56,19 @@ func (r *favouriteRepo) GetFavourites(userID uint, page, limit int) ([]models.Bo
 
 	return books, total, err
 }
+
+func (r *favouriteRepo) GetFavouriteIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("user_favourites").
+		Where("user_id = ?", userID).
+		Pluck("book_id", &ids).Error
+	return ids, err
+}
+
+func (r *favouriteRepo) GetFavouritedBy(bookID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("user_favourites").
+		Where("book_id = ?", bookID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
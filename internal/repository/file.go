@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FileRepository interface {
+	// ReplaceFile сохраняет файл книги, заменяя прежний того же формата; возвращает ключ прежнего файла
+	ReplaceFile(file *models.BookFile) (string, error)
+	GetFile(id uint) (models.BookFile, error)
+	GetBookFile(bookID uint, format string) (models.BookFile, error)
+	ListFiles(bookID uint) ([]models.BookFile, error)
+	ListFilesByBooks(bookIDs []uint) ([]models.BookFile, error)
+	// FindByDigest ищет файл по идентификатору документа KOReader
+	FindByDigest(digest string) (models.BookFile, error)
+	DeleteFile(id uint) error
+	// HasPurchased - есть ли у пользователя оплаченный или выполненный заказ с книгой
+	HasPurchased(userID, bookID uint) (bool, error)
+}
+
+type fileRepo struct {
+	db *gorm.DB
+}
+
+func NewFileRepository(db *gorm.DB) FileRepository {
+	return &fileRepo{db: db}
+}
+
+func (r *fileRepo) ReplaceFile(file *models.BookFile) (string, error) {
+	previousKey := ""
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var previous []models.BookFile
+		err := tx.Where("book_id = ? AND format = ?", file.BookID, file.Format).
+			Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&previous).Error
+		if err != nil {
+			return err
+		}
+		if len(previous) == 0 {
+			return tx.Create(file).Error
+		}
+		previousKey = previous[0].StorageKey
+		file.ID = previous[0].ID
+		file.CreatedAt = previous[0].CreatedAt
+		return tx.Save(file).Error
+	})
+	return previousKey, err
+}
+
+func (r *fileRepo) GetFile(id uint) (models.BookFile, error) {
+	var file models.BookFile
+	err := r.db.First(&file, id).Error
+	return file, err
+}
+
+func (r *fileRepo) GetBookFile(bookID uint, format string) (models.BookFile, error) {
+	var file models.BookFile
+	err := r.db.Where("book_id = ? AND format = ?", bookID, format).First(&file).Error
+	return file, err
+}
+
+func (r *fileRepo) ListFiles(bookID uint) ([]models.BookFile, error) {
+	var files []models.BookFile
+	err := r.db.Where("book_id = ?", bookID).Order("format").Find(&files).Error
+	return files, err
+}
+
+func (r *fileRepo) ListFilesByBooks(bookIDs []uint) ([]models.BookFile, error) {
+	var files []models.BookFile
+	if len(bookIDs) == 0 {
+		return files, nil
+	}
+	err := r.db.Where("book_id IN ?", bookIDs).Order("book_id, format").Find(&files).Error
+	return files, err
+}
+
+func (r *fileRepo) FindByDigest(digest string) (models.BookFile, error) {
+	var file models.BookFile
+	err := r.db.Where("koreader_digest = ?", digest).Order("id").First(&file).Error
+	return file, err
+}
+
+func (r *fileRepo) DeleteFile(id uint) error {
+	return r.db.Delete(&models.BookFile{}, id).Error
+}
+
+func (r *fileRepo) HasPurchased(userID, bookID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND order_items.book_id = ? AND orders.status IN ?",
+			userID, bookID, []string{models.OrderPaid, models.OrderFulfilled}).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FineBalance struct {
+	UserID   uint
+	Username string
+	Balance  money.Amount
+}
+
+type FineRepository interface {
+	// ListUnfinedLoans - выдачи, у которых дней просрочки (до возврата или до now) больше, чем уже начислено
+	ListUnfinedLoans(now time.Time) ([]models.Loan, error)
+	// ChargeLoan отмечает начисленными days дней просрочки и пишет начисление в журнал (entry может быть nil,
+	// если достигнут потолок). false - выдачу уже обработал параллельный запуск
+	ChargeLoan(loanID uint, prevDays, days int, entry *models.FineEntry) (bool, error)
+	// ListDueSoon - активные выдачи со сроком до until, о которых ещё не напоминали
+	ListDueSoon(now, until time.Time) ([]models.Loan, error)
+	// ListOverdueToRemind - просроченные выдачи без напоминания после remindedBefore
+	ListOverdueToRemind(now, remindedBefore time.Time) ([]models.Loan, error)
+	// MarkReminded запоминает отправку напоминания: о просрочке (overdue) или о скором сроке
+	MarkReminded(loanIDs []uint, overdue bool, at time.Time) error
+	GetRoles(userIDs []uint) (map[uint]string, error)
+	GetBalance(userID uint) (money.Amount, error)
+	ListEntries(userID uint, page, limit int) ([]models.FineEntry, int64, error)
+	// ListBalances - пользователи с непогашенным долгом, крупные первыми
+	ListBalances(page, limit int) ([]FineBalance, int64, error)
+	// Settle записывает оплату или списание, если они не превышают долг; баланс проверяется
+	// под блокировкой пользователя. false - сумма больше долга
+	Settle(entry *models.FineEntry) (bool, error)
+}
+
+type fineRepo struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepo{db: db}
+}
+
+func (r *fineRepo) ListUnfinedLoans(now time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := preloadLoan(r.db).
+		Where("due_at < COALESCE(returned_at, ?)", now).
+		Where("fined_days < FLOOR(EXTRACT(EPOCH FROM COALESCE(returned_at, ?) - due_at) / 86400)", now).
+		Order("id").
+		Find(&loans).Error
+	return loans, err
+}
+
+func (r *fineRepo) ChargeLoan(loanID uint, prevDays, days int, entry *models.FineEntry) (bool, error) {
+	charged := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Loan{}).
+			Where("id = ? AND fined_days = ?", loanID, prevDays).
+			Update("fined_days", days)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if entry != nil {
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+		charged = true
+		return nil
+	})
+	return charged, err
+}
+
+func (r *fineRepo) ListDueSoon(now, until time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := preloadLoan(r.db).
+		Where("returned_at IS NULL AND due_reminder_at IS NULL AND due_at >= ? AND due_at < ?", now, until).
+		Order("id").
+		Find(&loans).Error
+	return loans, err
+}
+
+func (r *fineRepo) ListOverdueToRemind(now, remindedBefore time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := preloadLoan(r.db).
+		Where("returned_at IS NULL AND due_at < ?", now).
+		Where("overdue_reminder_at IS NULL OR overdue_reminder_at < ?", remindedBefore).
+		Order("id").
+		Find(&loans).Error
+	return loans, err
+}
+
+func (r *fineRepo) MarkReminded(loanIDs []uint, overdue bool, at time.Time) error {
+	if len(loanIDs) == 0 {
+		return nil
+	}
+	column := "due_reminder_at"
+	if overdue {
+		column = "overdue_reminder_at"
+	}
+	return r.db.Model(&models.Loan{}).Where("id IN ?", loanIDs).Update(column, at).Error
+}
+
+func (r *fineRepo) GetRoles(userIDs []uint) (map[uint]string, error) {
+	var users []models.User
+	if len(userIDs) > 0 {
+		if err := r.db.Select("id", "role").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+	roles := make(map[uint]string, len(users))
+	for _, u := range users {
+		roles[u.ID] = u.Role
+	}
+	return roles, nil
+}
+
+func (r *fineRepo) GetBalance(userID uint) (money.Amount, error) {
+	var balance money.Amount
+	err := r.db.Model(&models.FineEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance).Error
+	return balance, err
+}
+
+func (r *fineRepo) ListEntries(userID uint, page, limit int) ([]models.FineEntry, int64, error) {
+	var entries []models.FineEntry
+	var total int64
+
+	db := r.db.Model(&models.FineEntry{}).Where("user_id = ?", userID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	return entries, total, err
+}
+
+func (r *fineRepo) ListBalances(page, limit int) ([]FineBalance, int64, error) {
+	var balances []FineBalance
+	var total int64
+
+	debtors := r.db.Model(&models.FineEntry{}).
+		Select("user_id, SUM(amount) AS balance").
+		Group("user_id").
+		Having("SUM(amount) > 0")
+	if err := r.db.Table("(?) AS d", debtors).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.Table("(?) AS d", debtors).
+		Select("d.user_id, users.username, d.balance").
+		Joins("JOIN users ON users.id = d.user_id").
+		Order("d.balance DESC, d.user_id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&balances).Error
+	return balances, total, err
+}
+
+func (r *fineRepo) Settle(entry *models.FineEntry) (bool, error) {
+	settled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, entry.UserID).Error; err != nil {
+			return err
+		}
+
+		var balance money.Amount
+		if err := tx.Model(&models.FineEntry{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("user_id = ?", entry.UserID).
+			Scan(&balance).Error; err != nil {
+			return err
+		}
+		if balance+entry.Amount < 0 {
+			return nil
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		settled = true
+		return nil
+	})
+	return settled, err
+}
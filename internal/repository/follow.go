@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository interface {
+	// CreateFollow возвращает false, если подписка уже есть
+	CreateFollow(f *models.Follow) (bool, error)
+	DeleteFollow(userID, id uint) (bool, error)
+	// ListFollows: пустой targetType - подписки всех типов
+	ListFollows(userID uint, targetType string) ([]models.Follow, error)
+	GetFollowers(targetType string, keys []string) ([]models.Follow, error)
+}
+
+type followRepo struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepo{db: db}
+}
+
+func (r *followRepo) CreateFollow(f *models.Follow) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(f)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *followRepo) DeleteFollow(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *followRepo) ListFollows(userID uint, targetType string) ([]models.Follow, error) {
+	var follows []models.Follow
+	db := r.db.Where("user_id = ?", userID)
+	if targetType != "" {
+		db = db.Where("target_type = ?", targetType)
+	}
+	err := db.Order("target_type, target").Find(&follows).Error
+	return follows, err
+}
+
+func (r *followRepo) GetFollowers(targetType string, keys []string) ([]models.Follow, error) {
+	var follows []models.Follow
+	if len(keys) == 0 {
+		return follows, nil
+	}
+	err := r.db.Where("target_type = ? AND target_key IN ?", targetType, keys).Find(&follows).Error
+	return follows, err
+}
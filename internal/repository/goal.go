@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type GoalRepository interface {
+	CreateGoal(goal *models.ReadingGoal) error
+	GetGoal(id uint) (models.ReadingGoal, error)
+	// ListGoals возвращает цели пользователя, последние по периоду первыми
+	ListGoals(userID uint) ([]models.ReadingGoal, error)
+	UpdateGoal(goal *models.ReadingGoal) error
+	DeleteGoal(userID, id uint) (bool, error)
+}
+
+type goalRepo struct {
+	db *gorm.DB
+}
+
+func NewGoalRepository(db *gorm.DB) GoalRepository {
+	return &goalRepo{db: db}
+}
+
+func (r *goalRepo) CreateGoal(goal *models.ReadingGoal) error {
+	return r.db.Create(goal).Error
+}
+
+func (r *goalRepo) GetGoal(id uint) (models.ReadingGoal, error) {
+	var goal models.ReadingGoal
+	err := r.db.First(&goal, id).Error
+	return goal, err
+}
+
+func (r *goalRepo) ListGoals(userID uint) ([]models.ReadingGoal, error) {
+	var goals []models.ReadingGoal
+	err := r.db.Where("user_id = ?", userID).Order("end_date DESC, id DESC").Find(&goals).Error
+	return goals, err
+}
+
+func (r *goalRepo) UpdateGoal(goal *models.ReadingGoal) error {
+	return r.db.Save(goal).Error
+}
+
+func (r *goalRepo) DeleteGoal(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.ReadingGoal{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HoldRepository interface {
+	CreateHold(hold *models.Hold) error
+	GetHold(id uint) (models.Hold, error)
+	// ListActiveHolds - ожидающие и отложенные брони пользователя, старые первыми
+	ListActiveHolds(userID uint) ([]models.Hold, error)
+	// GetQueuePositions - место каждой ожидающей брони пользователя в очереди на её книгу (с 1)
+	GetQueuePositions(userID uint) (map[uint]int, error)
+	HasActiveLoan(userID, bookID uint) (bool, error)
+	// CancelHold отменяет активную бронь; отложенный под неё экземпляр уходит следующему в очереди.
+	// false - бронь уже не активна
+	CancelHold(id uint, pickupUntil time.Time) (bool, error)
+	// ExpireHolds закрывает брони, которые не забрали до срока, и передаёт экземпляры дальше по очереди
+	ExpireHolds(now, pickupUntil time.Time) (int, error)
+	// OfferAvailableCopies откладывает свободные экземпляры книг, на которые есть очередь
+	OfferAvailableCopies(pickupUntil time.Time) (int, error)
+}
+
+type holdRepo struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepo{db: db}
+}
+
+// offerCopy откладывает экземпляр первому ожидающему в очереди на книгу. false - очередь пуста.
+// Без SKIP LOCKED: пропуск заблокированной брони отдал бы экземпляр следующему в очереди в обход первого.
+// Если первую бронь параллельно уже обслужили, запрос вернёт пусто - экземпляр останется свободным
+// до следующего прохода OfferAvailableCopies
+func offerCopy(tx *gorm.DB, copyID, bookID uint, pickupUntil time.Time) (bool, error) {
+	var hold models.Hold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("book_id = ? AND status = ?", bookID, models.HoldWaiting).
+		Order("id").
+		First(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	if err := tx.Model(&hold).Updates(map[string]interface{}{
+		"status":     models.HoldReady,
+		"copy_id":    copyID,
+		"ready_at":   now,
+		"expires_at": pickupUntil,
+	}).Error; err != nil {
+		return false, err
+	}
+	if err := tx.Model(&models.Copy{}).Where("id = ?", copyID).Update("status", models.CopyOnHold).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// releaseCopy снимает экземпляр с брони: отдаёт следующему в очереди или возвращает на полку
+func releaseCopy(tx *gorm.DB, copyID, bookID uint, pickupUntil time.Time) error {
+	offered, err := offerCopy(tx, copyID, bookID, pickupUntil)
+	if err != nil || offered {
+		return err
+	}
+	return tx.Model(&models.Copy{}).
+		Where("id = ? AND status = ?", copyID, models.CopyOnHold).
+		Update("status", models.CopyAvailable).Error
+}
+
+func (r *holdRepo) CreateHold(hold *models.Hold) error {
+	return r.db.Omit("Book", "Copy").Create(hold).Error
+}
+
+func (r *holdRepo) GetHold(id uint) (models.Hold, error) {
+	var hold models.Hold
+	err := preloadHold(r.db).First(&hold, id).Error
+	return hold, err
+}
+
+// preloadHold подгружает книгу и отложенный экземпляр, даже если их уже списали из каталога
+func preloadHold(db *gorm.DB) *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return db.Preload("Book", unscoped).Preload("Copy", unscoped)
+}
+
+func (r *holdRepo) ListActiveHolds(userID uint) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := preloadHold(r.db).
+		Where("user_id = ? AND status IN ?", userID, []string{models.HoldWaiting, models.HoldReady}).
+		Order("id").
+		Find(&holds).Error
+	return holds, err
+}
+
+func (r *holdRepo) GetQueuePositions(userID uint) (map[uint]int, error) {
+	var rows []struct {
+		ID       uint
+		Position int
+	}
+	err := r.db.Table("holds AS h").
+		Select(`h.id, (SELECT COUNT(*) FROM holds q
+			WHERE q.book_id = h.book_id AND q.status = ? AND q.id <= h.id) AS position`, models.HoldWaiting).
+		Where("h.user_id = ? AND h.status = ?", userID, models.HoldWaiting).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		positions[row.ID] = row.Position
+	}
+	return positions, nil
+}
+
+func (r *holdRepo) HasActiveLoan(userID, bookID uint) (bool, error) {
+	var ids []uint
+	err := r.db.Model(&models.Loan{}).
+		Where("user_id = ? AND book_id = ? AND returned_at IS NULL", userID, bookID).
+		Limit(1).
+		Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
+
+func (r *holdRepo) CancelHold(id uint, pickupUntil time.Time) (bool, error) {
+	cancelled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var hold models.Hold
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status IN ?", []string{models.HoldWaiting, models.HoldReady}).
+			First(&hold, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&hold).Update("status", models.HoldCancelled).Error; err != nil {
+			return err
+		}
+		if hold.Status == models.HoldReady && hold.CopyID != nil {
+			if err := releaseCopy(tx, *hold.CopyID, hold.BookID, pickupUntil); err != nil {
+				return err
+			}
+		}
+		cancelled = true
+		return nil
+	})
+	return cancelled, err
+}
+
+func (r *holdRepo) ExpireHolds(now, pickupUntil time.Time) (int, error) {
+	expired := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var holds []models.Hold
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at < ?", models.HoldReady, now).
+			Order("id").
+			Find(&holds).Error
+		if err != nil {
+			return err
+		}
+
+		for _, hold := range holds {
+			if err := tx.Model(&hold).Update("status", models.HoldExpired).Error; err != nil {
+				return err
+			}
+			if hold.CopyID != nil {
+				if err := releaseCopy(tx, *hold.CopyID, hold.BookID, pickupUntil); err != nil {
+					return err
+				}
+			}
+		}
+		expired = len(holds)
+		return nil
+	})
+	return expired, err
+}
+
+func (r *holdRepo) OfferAvailableCopies(pickupUntil time.Time) (int, error) {
+	offered := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var copies []models.Copy
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND book_id IN (?)", models.CopyAvailable,
+				tx.Model(&models.Hold{}).Select("book_id").Where("status = ?", models.HoldWaiting)).
+			Order("id").
+			Find(&copies).Error
+		if err != nil {
+			return err
+		}
+
+		for _, c := range copies {
+			ok, err := offerCopy(tx, c.ID, c.BookID, pickupUntil)
+			if err != nil {
+				return err
+			}
+			if ok {
+				offered++
+			}
+		}
+		return nil
+	})
+	return offered, err
+}
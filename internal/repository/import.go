@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Задачи в этих статусах ещё выполняет какой-то экземпляр сервера
+var unfinishedImportStatuses = []string{models.ImportStatusPending, models.ImportStatusRunning}
+
+type ImportRepository interface {
+	CreateJob(job *models.ImportJob) error
+	// UpdateJob сохраняет задачу, только пока она не завершена; false - её уже завершили, например
+	// признали зависшей, и менять её статус больше нельзя
+	UpdateJob(job *models.ImportJob) (bool, error)
+	// UpdateProgress пишет число вставленных строк и продлевает heartbeat выполняющейся задачи
+	UpdateProgress(id uint, processed int, now time.Time) error
+	// Heartbeat отмечает, что экземпляр, выполняющий задачу, жив
+	Heartbeat(id uint, now time.Time) error
+	GetJobByID(id string) (models.ImportJob, error)
+	// FailStaleJobs завершает отказом незавершённые задачи, heartbeat которых старше staleBefore, возвращает их число
+	FailStaleJobs(staleBefore time.Time, message string, finishedAt time.Time) (int64, error)
+}
+
+type importRepo struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepo{db: db}
+}
+
+func (r *importRepo) CreateJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importRepo) UpdateJob(job *models.ImportJob) (bool, error) {
+	result := r.db.Model(job).
+		Where("status IN ?", unfinishedImportStatuses).
+		Select("*").Omit("CreatedAt").
+		Updates(job)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *importRepo) UpdateProgress(id uint, processed int, now time.Time) error {
+	return r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND status = ?", id, models.ImportStatusRunning).
+		Updates(map[string]interface{}{"processed_rows": processed, "heartbeat_at": now}).Error
+}
+
+func (r *importRepo) Heartbeat(id uint, now time.Time) error {
+	return r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND status IN ?", id, unfinishedImportStatuses).
+		UpdateColumn("heartbeat_at", now).Error
+}
+
+func (r *importRepo) GetJobByID(id string) (models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.First(&job, "id = ?", id).Error
+	return job, err
+}
+
+func (r *importRepo) FailStaleJobs(staleBefore time.Time, message string, finishedAt time.Time) (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN ?", unfinishedImportStatuses).
+		Where("heartbeat_at IS NULL OR heartbeat_at < ?", staleBefore).
+		Updates(map[string]interface{}{
+			"status":      models.ImportStatusFailed,
+			"message":     message,
+			"finished_at": finishedAt,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNoCopyAvailable = errors.New("no copy available")
+	ErrLoanLimit       = errors.New("loan limit reached")
+)
+
+type Availability struct {
+	// Total - экземпляры, которые числятся в библиотеке (без потерянных)
+	Total     int64
+	Available int64
+}
+
+type LoanFilter struct {
+	UserID uint
+	// ActiveOnly - только невозвращённые, Overdue - только просроченные из них
+	ActiveOnly bool
+	Overdue    bool
+}
+
+type LendingRepository interface {
+	CreateCopy(c *models.Copy) error
+	GetCopy(id uint) (models.Copy, error)
+	ListCopies(bookID uint) ([]models.Copy, error)
+	// UpdateCopy и DeleteCopy срабатывают, только если статус не поменялся с момента чтения
+	// (например, экземпляр не выдали параллельно); иначе возвращают false
+	UpdateCopy(c *models.Copy, prevStatus string) (bool, error)
+	DeleteCopy(id uint, prevStatus string) (bool, error)
+	GetAvailability(bookID uint) (Availability, error)
+	// Checkout в одной транзакции проверяет лимит пользователя, занимает свободный или отложенный для него
+	// экземпляр, закрывает его брони на книгу и создаёт выдачу. Пустой barcode - любой подходящий экземпляр книги.
+	// pickupUntil - срок для следующего в очереди, если освободился отложенный экземпляр
+	Checkout(userID, bookID uint, barcode string, limit int, dueAt, pickupUntil time.Time) (models.Loan, error)
+	GetLoan(id uint) (models.Loan, error)
+	ListLoans(filter LoanFilter, page, limit int) ([]models.Loan, int64, error)
+	// ReturnLoan закрывает выдачу и переводит экземпляр в copyStatus; свободный экземпляр сразу откладывается
+	// первому в очереди до pickupUntil. false - выдача уже закрыта
+	ReturnLoan(loan *models.Loan, condition, copyStatus string, pickupUntil time.Time) (bool, error)
+	// RenewLoan переносит срок возврата на dueAt, если выдача открыта и её не продлили параллельно.
+	// false - выдачу успели вернуть или продлить
+	RenewLoan(loan *models.Loan, dueAt time.Time) (bool, error)
+	HasWaitingHolds(bookID uint) (bool, error)
+}
+
+type lendingRepo struct {
+	db *gorm.DB
+}
+
+func NewLendingRepository(db *gorm.DB) LendingRepository {
+	return &lendingRepo{db: db}
+}
+
+func (r *lendingRepo) CreateCopy(c *models.Copy) error {
+	return r.db.Create(c).Error
+}
+
+func (r *lendingRepo) GetCopy(id uint) (models.Copy, error) {
+	var c models.Copy
+	err := r.db.First(&c, id).Error
+	return c, err
+}
+
+func (r *lendingRepo) ListCopies(bookID uint) ([]models.Copy, error) {
+	var copies []models.Copy
+	err := r.db.Where("book_id = ?", bookID).Order("barcode").Find(&copies).Error
+	return copies, err
+}
+
+func (r *lendingRepo) UpdateCopy(c *models.Copy, prevStatus string) (bool, error) {
+	result := r.db.Model(c).
+		Where("status = ?", prevStatus).
+		Select("Barcode", "Location", "Condition", "Status").
+		Updates(c)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *lendingRepo) DeleteCopy(id uint, prevStatus string) (bool, error) {
+	result := r.db.Where("id = ? AND status = ?", id, prevStatus).Delete(&models.Copy{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *lendingRepo) GetAvailability(bookID uint) (Availability, error) {
+	var a Availability
+	err := r.db.Model(&models.Copy{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE status = ?) AS available", models.CopyAvailable).
+		Where("book_id = ? AND status <> ?", bookID, models.CopyLost).
+		Scan(&a).Error
+	return a, err
+}
+
+func (r *lendingRepo) Checkout(userID, bookID uint, barcode string, limit int, dueAt, pickupUntil time.Time) (models.Loan, error) {
+	var loan models.Loan
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка пользователя выстраивает его параллельные выдачи в очередь, иначе лимит можно обойти
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&models.Loan{}).Where("user_id = ? AND returned_at IS NULL", userID).Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= int64(limit) {
+			return ErrLoanLimit
+		}
+
+		// Экземпляр, отложенный по брони пользователя, выдаётся в первую очередь;
+		// иначе SKIP LOCKED: параллельная выдача берёт следующий свободный экземпляр, а не ждёт этот
+		var c models.Copy
+		q := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND id IN (?))", models.CopyAvailable, models.CopyOnHold,
+				tx.Model(&models.Hold{}).Select("copy_id").Where("user_id = ? AND status = ?", userID, models.HoldReady))
+		if bookID != 0 {
+			q = q.Where("book_id = ?", bookID)
+		}
+		if barcode != "" {
+			q = q.Where("barcode = ?", barcode)
+		}
+		if err := q.Order(clause.OrderBy{Expression: clause.Expr{SQL: "status = ? DESC, id", Vars: []interface{}{models.CopyOnHold}}}).Take(&c).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoCopyAvailable
+			}
+			return err
+		}
+		if err := tx.Model(&c).Update("status", models.CopyOnLoan).Error; err != nil {
+			return err
+		}
+
+		// Книга получена - бронь на неё больше не нужна. Если взят свободный экземпляр, а отложенный
+		// по брони остался, он уходит следующему в очереди
+		var holds []models.Hold
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND book_id = ? AND status IN ?", userID, c.BookID, []string{models.HoldWaiting, models.HoldReady}).
+			Find(&holds).Error; err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if err := tx.Model(&hold).Update("status", models.HoldFulfilled).Error; err != nil {
+				return err
+			}
+			if hold.CopyID != nil && *hold.CopyID != c.ID {
+				if err := releaseCopy(tx, *hold.CopyID, hold.BookID, pickupUntil); err != nil {
+					return err
+				}
+			}
+		}
+
+		loan = models.Loan{
+			CopyID:       c.ID,
+			BookID:       c.BookID,
+			UserID:       userID,
+			CheckedOutAt: time.Now(),
+			DueAt:        dueAt,
+		}
+		return tx.Omit("Copy", "Book").Create(&loan).Error
+	})
+	if err != nil {
+		return models.Loan{}, err
+	}
+	return r.GetLoan(loan.ID)
+}
+
+func (r *lendingRepo) GetLoan(id uint) (models.Loan, error) {
+	var loan models.Loan
+	err := preloadLoan(r.db).First(&loan, id).Error
+	return loan, err
+}
+
+// preloadLoan подгружает экземпляр и книгу, даже если их уже списали из каталога
+func preloadLoan(db *gorm.DB) *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return db.Preload("Copy", unscoped).Preload("Book", unscoped)
+}
+
+func (r *lendingRepo) ListLoans(filter LoanFilter, page, limit int) ([]models.Loan, int64, error) {
+	var loans []models.Loan
+	var total int64
+
+	db := r.db.Model(&models.Loan{})
+	if filter.UserID != 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ActiveOnly || filter.Overdue {
+		db = db.Where("returned_at IS NULL")
+	}
+	if filter.Overdue {
+		db = db.Where("due_at < ?", time.Now())
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "checked_out_at DESC, id DESC"
+	if filter.ActiveOnly || filter.Overdue {
+		order = "due_at, id"
+	}
+	err := preloadLoan(db).
+		Order(order).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&loans).Error
+	return loans, total, err
+}
+
+func (r *lendingRepo) ReturnLoan(loan *models.Loan, condition, copyStatus string, pickupUntil time.Time) (bool, error) {
+	returned := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Loan{}).
+			Where("id = ? AND returned_at IS NULL", loan.ID).
+			Update("returned_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		updates := map[string]interface{}{"status": copyStatus}
+		if condition != "" {
+			updates["condition"] = condition
+		}
+		if err := tx.Model(&models.Copy{}).Where("id = ?", loan.CopyID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if copyStatus == models.CopyAvailable {
+			if _, err := offerCopy(tx, loan.CopyID, loan.BookID, pickupUntil); err != nil {
+				return err
+			}
+		}
+		loan.ReturnedAt = &now
+		returned = true
+		return nil
+	})
+	return returned, err
+}
+
+func (r *lendingRepo) RenewLoan(loan *models.Loan, dueAt time.Time) (bool, error) {
+	res := r.db.Exec(`
+		UPDATE loans SET due_at = ?, renewals = renewals + 1, due_reminder_at = NULL
+		WHERE id = ? AND returned_at IS NULL AND renewals = ?
+	`, dueAt, loan.ID, loan.Renewals)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	loan.DueAt = dueAt
+	loan.Renewals++
+	loan.DueReminderAt = nil
+	return true, nil
+}
+
+func (r *lendingRepo) HasWaitingHolds(bookID uint) (bool, error) {
+	var ids []uint
+	err := r.db.Model(&models.Hold{}).
+		Where("book_id = ? AND status = ?", bookID, models.HoldWaiting).
+		Limit(1).
+		Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type LibraryImportRepository interface {
+	CreateImport(imp *models.LibraryImport) error
+	UpdateImport(imp *models.LibraryImport) error
+	GetImport(userID uint, id string) (models.LibraryImport, error)
+}
+
+type libraryImportRepo struct {
+	db *gorm.DB
+}
+
+func NewLibraryImportRepository(db *gorm.DB) LibraryImportRepository {
+	return &libraryImportRepo{db: db}
+}
+
+func (r *libraryImportRepo) CreateImport(imp *models.LibraryImport) error {
+	return r.db.Create(imp).Error
+}
+
+func (r *libraryImportRepo) UpdateImport(imp *models.LibraryImport) error {
+	return r.db.Save(imp).Error
+}
+
+func (r *libraryImportRepo) GetImport(userID uint, id string) (models.LibraryImport, error) {
+	var imp models.LibraryImport
+	err := r.db.Where("user_id = ?", userID).First(&imp, "id = ?", id).Error
+	return imp, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"encoding/json"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NoteFilter: пустые поля не фильтруют. Query - полнотекстовый запрос в синтаксисе websearch
+type NoteFilter struct {
+	UserID     uint
+	BookID     uint
+	Tag        string
+	Query      string
+	PublicOnly bool
+}
+
+type NoteRepository interface {
+	CreateNote(note *models.Note) error
+	UpdateNote(note *models.Note) error
+	DeleteNote(id uint) error
+	GetNote(id uint) (models.Note, error)
+	ListNotes(filter NoteFilter, page, limit int) ([]models.Note, int64, error)
+	// GetBookNotes возвращает все заметки пользователя к книге по порядку страниц
+	GetBookNotes(userID, bookID uint) ([]models.Note, error)
+}
+
+type noteRepo struct {
+	db *gorm.DB
+}
+
+func NewNoteRepository(db *gorm.DB) NoteRepository {
+	return &noteRepo{db: db}
+}
+
+func (r *noteRepo) CreateNote(note *models.Note) error {
+	return r.db.Omit("Book").Create(note).Error
+}
+
+func (r *noteRepo) UpdateNote(note *models.Note) error {
+	return r.db.Omit("Book").Save(note).Error
+}
+
+func (r *noteRepo) DeleteNote(id uint) error {
+	return r.db.Delete(&models.Note{}, id).Error
+}
+
+func (r *noteRepo) GetNote(id uint) (models.Note, error) {
+	var note models.Note
+	err := r.db.First(&note, id).Error
+	return note, err
+}
+
+func (r *noteRepo) ListNotes(filter NoteFilter, page, limit int) ([]models.Note, int64, error) {
+	var notes []models.Note
+	var total int64
+
+	db := r.db.Model(&models.Note{})
+	if filter.UserID != 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.BookID != 0 {
+		db = db.Where("book_id = ?", filter.BookID)
+	}
+	if filter.PublicOnly {
+		db = db.Where("visibility = ?", models.NoteVisibilityPublic)
+	}
+	if filter.Tag != "" {
+		tag, _ := json.Marshal([]string{filter.Tag})
+		db = db.Where("tags @> ?::jsonb", string(tag))
+	}
+	if filter.Query != "" {
+		db = db.Where(models.NoteSearchVector+" @@ websearch_to_tsquery('simple', ?)", filter.Query)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Query != "" {
+		db = db.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + models.NoteSearchVector + ", websearch_to_tsquery('simple', ?)) DESC, id DESC",
+			Vars: []any{filter.Query},
+		}})
+	} else {
+		db = db.Order("created_at DESC, id DESC")
+	}
+
+	offset := (page - 1) * limit
+	err := db.Offset(offset).Limit(limit).Find(&notes).Error
+	return notes, total, err
+}
+
+func (r *noteRepo) GetBookNotes(userID, bookID uint) ([]models.Note, error) {
+	var notes []models.Note
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).
+		Order("page NULLS LAST, created_at, id").Find(&notes).Error
+	return notes, err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateNotifications(items []models.Notification) error
+	ListNotifications(userID uint, unreadOnly bool, page, limit int) ([]models.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, id uint) (bool, error)
+	MarkAllRead(userID uint) error
+	GetSettings(userID uint) (models.NotificationSettings, error)
+	// GetSettingsFor возвращает только сохранённые настройки; для остальных действуют значения по умолчанию
+	GetSettingsFor(userIDs []uint) ([]models.NotificationSettings, error)
+	SaveSettings(settings *models.NotificationSettings) error
+}
+
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) CreateNotifications(items []models.Notification) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(items, 500).Error
+}
+
+func (r *notificationRepo) ListNotifications(userID uint, unreadOnly bool, page, limit int) ([]models.Notification, int64, error) {
+	var items []models.Notification
+	var total int64
+
+	db := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		db = db.Where("read_at IS NULL")
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&items).Error
+	return items, total, err
+}
+
+func (r *notificationRepo) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepo) MarkRead(userID, id uint) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *notificationRepo) MarkAllRead(userID uint) error {
+	return r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepo) GetSettings(userID uint) (models.NotificationSettings, error) {
+	var settings models.NotificationSettings
+	err := r.db.First(&settings, "user_id = ?", userID).Error
+	return settings, err
+}
+
+func (r *notificationRepo) GetSettingsFor(userIDs []uint) ([]models.NotificationSettings, error) {
+	var settings []models.NotificationSettings
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+	err := r.db.Where("user_id IN ?", userIDs).Find(&settings).Error
+	return settings, err
+}
+
+func (r *notificationRepo) SaveSettings(settings *models.NotificationSettings) error {
+	return r.db.Save(settings).Error
+}
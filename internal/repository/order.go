@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCartChanged - корзину изменили, пока оформлялся заказ
+	ErrCartChanged = errors.New("cart changed")
+	// ErrCouponExhausted - лимит использований купона исчерпан, пока оформлялся заказ
+	ErrCouponExhausted = errors.New("coupon exhausted")
+)
+
+type OrderFilter struct {
+	UserID uint
+	Status string
+}
+
+type OrderRepository interface {
+	// CreateFromCart в одной транзакции сохраняет заказ и убирает из корзины ровно те позиции, из которых
+	// он собран. Если корзину успели изменить (или оформить параллельно), возвращает ErrCartChanged.
+	// Купон заказа засчитывается в той же транзакции, сверх лимита - ErrCouponExhausted.
+	// Книги с учётом остатков откладываются до order.ReservedUntil, нехватка - *OutOfStockError
+	CreateFromCart(order *models.Order, cart []models.CartItem) error
+	GetOrder(id uint) (models.Order, error)
+	ListOrders(filter OrderFilter, page, limit int) ([]models.Order, int64, error)
+	// UpdateStatus переводит заказ из from в to и ставит время перехода; false - статус уже другой.
+	// Отмена возвращает использование купона. Оплата списывает отложенный товар со склада, отмена неоплаченного
+	// заказа снимает резерв, отмена или возврат оплаченного возвращает товар на склад
+	UpdateStatus(order *models.Order, from, to string) (bool, error)
+	// ListExpiredReservations - неоплаченные заказы, резерв которых истёк к now
+	ListExpiredReservations(now time.Time) ([]models.Order, error)
+}
+
+type orderRepo struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepo{db: db}
+}
+
+func (r *orderRepo) CreateFromCart(order *models.Order, cart []models.CartItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range cart {
+			result := tx.Where("user_id = ? AND book_id = ? AND quantity = ?", item.UserID, item.BookID, item.Quantity).
+				Delete(&models.CartItem{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrCartChanged
+			}
+		}
+		if order.CouponID != nil {
+			result := tx.Model(&models.Discount{}).
+				Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", *order.CouponID).
+				UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrCouponExhausted
+			}
+		}
+		reservations, err := reserveStock(tx, order.Items)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			order.ReservedUntil = nil
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		for i := range reservations {
+			reservations[i].OrderID = order.ID
+		}
+		if len(reservations) == 0 {
+			return nil
+		}
+		return tx.Create(&reservations).Error
+	})
+}
+
+func (r *orderRepo) GetOrder(id uint) (models.Order, error) {
+	var order models.Order
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&order, id).Error
+	return order, err
+}
+
+func (r *orderRepo) ListOrders(filter OrderFilter, page, limit int) ([]models.Order, int64, error) {
+	var orders []models.Order
+	var total int64
+
+	db := r.db.Model(&models.Order{})
+	if filter.UserID != 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// statusColumns - время перехода в каждый статус
+var statusColumns = map[string]string{
+	models.OrderPaid:      "paid_at",
+	models.OrderFulfilled: "fulfilled_at",
+	models.OrderCancelled: "cancelled_at",
+	models.OrderRefunded:  "refunded_at",
+}
+
+func (r *orderRepo) UpdateStatus(order *models.Order, from, to string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": to}
+	if column, ok := statusColumns[to]; ok {
+		updates[column] = now
+	}
+	if from == models.OrderPending {
+		updates["reserved_until"] = nil
+	}
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).Where("id = ? AND status = ?", order.ID, from).Updates(updates)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		if err := updateOrderStock(tx, order.ID, from, to); err != nil {
+			return err
+		}
+		if to != models.OrderCancelled || order.CouponID == nil {
+			return nil
+		}
+		return tx.Model(&models.Discount{}).Where("id = ? AND used_count > 0", *order.CouponID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+	})
+	if err != nil || !updated {
+		return false, err
+	}
+
+	order.Status = to
+	if from == models.OrderPending {
+		order.ReservedUntil = nil
+	}
+	switch to {
+	case models.OrderPaid:
+		order.PaidAt = &now
+	case models.OrderFulfilled:
+		order.FulfilledAt = &now
+	case models.OrderCancelled:
+		order.CancelledAt = &now
+	case models.OrderRefunded:
+		order.RefundedAt = &now
+	}
+	return true, nil
+}
+
+// updateOrderStock двигает товар при смене статуса заказа; выполненный заказ уже отправлен, его возврат склад не меняет
+func updateOrderStock(tx *gorm.DB, orderID uint, from, to string) error {
+	switch {
+	case from == models.OrderPending && to == models.OrderPaid:
+		return commitStock(tx, orderID)
+	case from == models.OrderPending && to == models.OrderCancelled:
+		return releaseStock(tx, orderID)
+	case from == models.OrderPaid && to == models.OrderCancelled:
+		return restockOrder(tx, orderID, models.StockOrderCancelled)
+	case from == models.OrderPaid && to == models.OrderRefunded:
+		return restockOrder(tx, orderID, models.StockOrderRefunded)
+	}
+	return nil
+}
+
+func (r *orderRepo) ListExpiredReservations(now time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("status = ? AND reserved_until < ?", models.OrderPending, now).Order("id").Find(&orders).Error
+	return orders, err
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository interface {
+	CreatePayment(payment *models.Payment) error
+	GetByIntent(provider, intentID string) (models.Payment, error)
+	// GetActiveForOrder - последняя попытка оплаты заказа, которая не завершилась отказом
+	GetActiveForOrder(orderID uint) (models.Payment, error)
+	CountPayments(orderID uint) (int64, error)
+	// UpdateStatus переводит платёж в to, если он сейчас в одном из from; false - статус уже другой
+	UpdateStatus(payment *models.Payment, from []string, to, reason string) (bool, error)
+	// StartRefund переводит списанный платёж в refund_pending и увеличивает счётчик попыток возврата;
+	// false - платёж уже не succeeded
+	StartRefund(payment *models.Payment) (bool, error)
+	HasEvent(provider, eventID string) (bool, error)
+	// RecordEvent запоминает обработанное событие; повторная запись ничего не делает
+	RecordEvent(event *models.PaymentEvent) error
+}
+
+type paymentRepo struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepo{db: db}
+}
+
+func (r *paymentRepo) CreatePayment(payment *models.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepo) GetByIntent(provider, intentID string) (models.Payment, error) {
+	var payment models.Payment
+	err := r.db.Where("provider = ? AND intent_id = ?", provider, intentID).First(&payment).Error
+	return payment, err
+}
+
+func (r *paymentRepo) GetActiveForOrder(orderID uint) (models.Payment, error) {
+	var payment models.Payment
+	err := r.db.Where("order_id = ? AND status <> ?", orderID, models.PaymentFailed).
+		Order("id DESC").
+		First(&payment).Error
+	return payment, err
+}
+
+func (r *paymentRepo) CountPayments(orderID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Payment{}).Where("order_id = ?", orderID).Count(&count).Error
+	return count, err
+}
+
+func (r *paymentRepo) UpdateStatus(payment *models.Payment, from []string, to, reason string) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	if reason != "" {
+		updates["failure_reason"] = reason
+	}
+	result := r.db.Model(&models.Payment{}).Where("id = ? AND status IN ?", payment.ID, from).Updates(updates)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	payment.Status = to
+	if reason != "" {
+		payment.FailureReason = reason
+	}
+	return true, nil
+}
+
+func (r *paymentRepo) StartRefund(payment *models.Payment) (bool, error) {
+	var updated models.Payment
+	result := r.db.Model(&updated).Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", payment.ID, models.PaymentSucceeded).
+		Updates(map[string]interface{}{
+			"status":          models.PaymentRefundPending,
+			"refund_attempts": gorm.Expr("refund_attempts + 1"),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	payment.Status = updated.Status
+	payment.RefundAttempts = updated.RefundAttempts
+	return true, nil
+}
+
+func (r *paymentRepo) HasEvent(provider, eventID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PaymentEvent{}).
+		Where("provider = ? AND event_id = ?", provider, eventID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *paymentRepo) RecordEvent(event *models.PaymentEvent) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookScore struct {
+	BookID     uint
+	Views      int64
+	Favourites int64
+	Score      float64
+}
+
+type PopularityRepository interface {
+	// AddDailyStats прибавляет счётчики к уже накопленным за тот же день
+	AddDailyStats(rows []models.BookStatsDaily) error
+	// GetTrending ранжирует книги за последние windowDays дней, вклад дня убывает вдвое каждые halfLifeDays
+	GetTrending(windowDays int, halfLifeDays, favouriteWeight float64, page, limit int) ([]BookScore, int64, error)
+	// GetPopular ранжирует по сумме за последние days дней, days = 0 - за всё время
+	GetPopular(days int, favouriteWeight float64, page, limit int) ([]BookScore, int64, error)
+}
+
+type popularityRepo struct {
+	db *gorm.DB
+}
+
+func NewPopularityRepository(db *gorm.DB) PopularityRepository {
+	return &popularityRepo{db: db}
+}
+
+func (r *popularityRepo) AddDailyStats(rows []models.BookStatsDaily) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "book_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"views":      gorm.Expr("book_stats_daily.views + excluded.views"),
+			"favourites": gorm.Expr("book_stats_daily.favourites + excluded.favourites"),
+		}),
+	}).CreateInBatches(rows, 500).Error
+}
+
+// stats - статистика только по существующим (не удалённым) книгам
+func (r *popularityRepo) stats(days int) *gorm.DB {
+	db := r.db.Table("book_stats_daily AS s").
+		Joins("JOIN books ON books.id = s.book_id AND books.deleted_at IS NULL")
+	if days > 0 {
+		db = db.Where("s.day > CURRENT_DATE - ?::int", days)
+	}
+	return db
+}
+
+func (r *popularityRepo) rank(days int, score clause.Expr, page, limit int) ([]BookScore, int64, error) {
+	var total int64
+	if err := r.stats(days).Distinct("s.book_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []BookScore
+	err := r.stats(days).
+		Select("s.book_id AS book_id, SUM(s.views) AS views, SUM(s.favourites) AS favourites, ? AS score", score).
+		Group("s.book_id").
+		Order("score DESC, s.book_id").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&scores).Error
+	return scores, total, err
+}
+
+func (r *popularityRepo) GetTrending(windowDays int, halfLifeDays, favouriteWeight float64, page, limit int) ([]BookScore, int64, error) {
+	score := gorm.Expr("SUM((s.views + ? * s.favourites) * POWER(0.5, (CURRENT_DATE - s.day) / ?::float))", favouriteWeight, halfLifeDays)
+	return r.rank(windowDays, score, page, limit)
+}
+
+func (r *popularityRepo) GetPopular(days int, favouriteWeight float64, page, limit int) ([]BookScore, int64, error) {
+	score := gorm.Expr("SUM(s.views + ? * s.favourites)", favouriteWeight)
+	return r.rank(days, score, page, limit)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgUniqueViolation - SQLSTATE нарушения уникального индекса
+const pgUniqueViolation = "23505"
+
+// IsDuplicateKey распознаёт нарушение уникального индекса Postgres по коду ошибки
+func IsDuplicateKey(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
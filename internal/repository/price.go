@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceRepository interface {
+	ListBookPrices(bookID uint) ([]models.BookPrice, error)
+	// GetBookPrices - цены из прайс-листа в валюте currency для книг bookIDs; книги без цены в карту не попадают
+	GetBookPrices(bookIDs []uint, currency string) (map[uint]money.Amount, error)
+	SetBookPrice(price *models.BookPrice) error
+	DeleteBookPrice(bookID uint, currency string) (bool, error)
+	ListRates() ([]models.ExchangeRate, error)
+	// ReplaceRates заменяет всю таблицу курсов в одной транзакции
+	ReplaceRates(rates []models.ExchangeRate) error
+}
+
+type priceRepo struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &priceRepo{db: db}
+}
+
+func (r *priceRepo) ListBookPrices(bookID uint) ([]models.BookPrice, error) {
+	var prices []models.BookPrice
+	err := r.db.Where("book_id = ?", bookID).Order("currency").Find(&prices).Error
+	return prices, err
+}
+
+func (r *priceRepo) GetBookPrices(bookIDs []uint, currency string) (map[uint]money.Amount, error) {
+	result := make(map[uint]money.Amount)
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+	var prices []models.BookPrice
+	if err := r.db.Where("book_id IN ? AND currency = ?", bookIDs, currency).Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range prices {
+		result[p.BookID] = p.Amount
+	}
+	return result, nil
+}
+
+func (r *priceRepo) SetBookPrice(price *models.BookPrice) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "book_id"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "updated_at"}),
+	}).Create(price).Error
+}
+
+func (r *priceRepo) DeleteBookPrice(bookID uint, currency string) (bool, error) {
+	result := r.db.Where("book_id = ? AND currency = ?", bookID, currency).Delete(&models.BookPrice{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *priceRepo) ListRates() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+func (r *priceRepo) ReplaceRates(rates []models.ExchangeRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.ExchangeRate{}).Error; err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return nil
+		}
+		return tx.Create(&rates).Error
+	})
+}
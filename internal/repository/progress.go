@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxProgressHistory - сколько последних записей истории хранится на документ
+const maxProgressHistory = 200
+
+type ProgressRepository interface {
+	// SaveProgress пишет позицию в историю и делает её текущей, если она не старше текущей
+	// (тогда entry.Applied = true). Возвращает текущую позицию после записи
+	SaveProgress(entry *models.ReadingProgressEntry) (models.ReadingProgress, error)
+	GetProgress(userID uint, document string) (models.ReadingProgress, error)
+	ListHistory(userID uint, document string, page, limit int) ([]models.ReadingProgressEntry, int64, error)
+}
+
+type progressRepo struct {
+	db *gorm.DB
+}
+
+func NewProgressRepository(db *gorm.DB) ProgressRepository {
+	return &progressRepo{db: db}
+}
+
+func (r *progressRepo) SaveProgress(entry *models.ReadingProgressEntry) (models.ReadingProgress, error) {
+	var current models.ReadingProgress
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		progress := models.ReadingProgress{
+			UserID:      entry.UserID,
+			Document:    entry.Document,
+			BookID:      entry.BookID,
+			Locator:     entry.Locator,
+			LocatorType: entry.LocatorType,
+			Percent:     entry.Percent,
+			Device:      entry.Device,
+			DeviceID:    entry.DeviceID,
+			RecordedAt:  entry.RecordedAt,
+		}
+		// Условный upsert: более старая запись с другого устройства не затирает текущую
+		res := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "document"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"book_id", "locator", "locator_type", "percent", "device", "device_id", "recorded_at", "updated_at",
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "reading_progresses.recorded_at <= excluded.recorded_at"},
+			}},
+		}).Create(&progress)
+		if res.Error != nil {
+			return res.Error
+		}
+		entry.Applied = res.RowsAffected > 0
+
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		err := tx.Where("user_id = ? AND document = ? AND id NOT IN (?)", entry.UserID, entry.Document,
+			tx.Model(&models.ReadingProgressEntry{}).Select("id").
+				Where("user_id = ? AND document = ?", entry.UserID, entry.Document).
+				Order("id DESC").Limit(maxProgressHistory),
+		).Delete(&models.ReadingProgressEntry{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND document = ?", entry.UserID, entry.Document).First(&current).Error
+	})
+	return current, err
+}
+
+func (r *progressRepo) GetProgress(userID uint, document string) (models.ReadingProgress, error) {
+	var progress models.ReadingProgress
+	err := r.db.Where("user_id = ? AND document = ?", userID, document).First(&progress).Error
+	return progress, err
+}
+
+func (r *progressRepo) ListHistory(userID uint, document string, page, limit int) ([]models.ReadingProgressEntry, int64, error) {
+	var entries []models.ReadingProgressEntry
+	var total int64
+
+	db := r.db.Model(&models.ReadingProgressEntry{}).Where("user_id = ? AND document = ?", userID, document)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	return entries, total, err
+}
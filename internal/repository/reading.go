@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MonthlyReading struct {
+	Month int
+	Books int
+	Pages int
+}
+
+type ReadingTotals struct {
+	Books int
+	Pages int
+}
+
+type GenreCount struct {
+	Genre string
+	Books int
+}
+
+type RatingSummary struct {
+	Average float64
+	Count   int
+}
+
+type ReadingRepository interface {
+	GetStatus(userID, bookID uint) (models.ReadingStatus, error)
+	// GetOpenReadThrough возвращает последнее незавершённое прочтение
+	GetOpenReadThrough(userID, bookID uint) (models.ReadThrough, error)
+	GetLastReadThrough(userID, bookID uint) (models.ReadThrough, error)
+	// SaveStatus сохраняет статус и прочтение (если передано) в одной транзакции.
+	// При возврате книги в want_to_read незавершённое прочтение удаляется
+	SaveStatus(status *models.ReadingStatus, readThrough *models.ReadThrough) error
+	DeleteStatus(userID, bookID uint) error
+	// ListShelf - книги на полке, новые изменения первыми; книги, удалённые из каталога, не показываются и не считаются
+	ListShelf(userID uint, shelf string, page, limit int) ([]models.ReadingStatus, int64, error)
+	GetMonthlyReading(userID uint, from, to time.Time) ([]MonthlyReading, error)
+	// Итоги по прочтениям, завершённым в [from, to)
+	GetReadingTotals(userID uint, from, to time.Time) (ReadingTotals, error)
+	GetTopGenres(userID uint, from, to time.Time, limit int) ([]GenreCount, error)
+	GetLongestReadThrough(userID uint, from, to time.Time) (models.ReadThrough, error)
+	// GetRatingSummary - средняя оценка пользователя по книгам, дочитанным в [from, to)
+	GetRatingSummary(userID uint, from, to time.Time) (RatingSummary, error)
+}
+
+type readingRepo struct {
+	db *gorm.DB
+}
+
+func NewReadingRepository(db *gorm.DB) ReadingRepository {
+	return &readingRepo{db: db}
+}
+
+func (r *readingRepo) GetStatus(userID, bookID uint) (models.ReadingStatus, error) {
+	var status models.ReadingStatus
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).First(&status).Error
+	return status, err
+}
+
+func (r *readingRepo) GetOpenReadThrough(userID, bookID uint) (models.ReadThrough, error) {
+	var rt models.ReadThrough
+	err := r.db.Where("user_id = ? AND book_id = ? AND finished_at IS NULL", userID, bookID).
+		Order("id DESC").First(&rt).Error
+	return rt, err
+}
+
+func (r *readingRepo) GetLastReadThrough(userID, bookID uint) (models.ReadThrough, error) {
+	var rt models.ReadThrough
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).Order("id DESC").First(&rt).Error
+	return rt, err
+}
+
+func (r *readingRepo) SaveStatus(status *models.ReadingStatus, readThrough *models.ReadThrough) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Book").Save(status).Error; err != nil {
+			return err
+		}
+		if status.Shelf == models.ShelfWantToRead {
+			// Брошенное прочтение не должно висеть открытым и попадать в статистику
+			err := tx.Where("user_id = ? AND book_id = ? AND finished_at IS NULL", status.UserID, status.BookID).
+				Delete(&models.ReadThrough{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if readThrough == nil {
+			return nil
+		}
+		return tx.Save(readThrough).Error
+	})
+}
+
+// DeleteStatus снимает книгу с полок. Завершённые прочтения остаются в статистике,
+// незавершённое удаляется
+func (r *readingRepo) DeleteStatus(userID, bookID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND book_id = ? AND finished_at IS NULL", userID, bookID).
+			Delete(&models.ReadThrough{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ? AND book_id = ?", userID, bookID).
+			Delete(&models.ReadingStatus{}).Error
+	})
+}
+
+func (r *readingRepo) ListShelf(userID uint, shelf string, page, limit int) ([]models.ReadingStatus, int64, error) {
+	var statuses []models.ReadingStatus
+	var total int64
+
+	db := withLiveBook(r.db.Model(&models.ReadingStatus{}), "reading_statuses").Where("user_id = ? AND shelf = ?", userID, shelf)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Preload("Book").Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&statuses).Error
+	return statuses, total, err
+}
+
+func (r *readingRepo) GetMonthlyReading(userID uint, from, to time.Time) ([]MonthlyReading, error) {
+	var months []MonthlyReading
+	err := r.db.Model(&models.ReadThrough{}).
+		Select("CAST(EXTRACT(MONTH FROM finished_at) AS INTEGER) AS month, COUNT(*) AS books, COALESCE(SUM(pages), 0) AS pages").
+		Where("user_id = ? AND finished_at >= ? AND finished_at < ?", userID, from, to).
+		Group("month").
+		Order("month").
+		Scan(&months).Error
+	return months, err
+}
+
+func (r *readingRepo) finishedBetween(userID uint, from, to time.Time) *gorm.DB {
+	return r.db.Model(&models.ReadThrough{}).
+		Where("user_id = ? AND finished_at >= ? AND finished_at < ?", userID, from, to)
+}
+
+func (r *readingRepo) GetReadingTotals(userID uint, from, to time.Time) (ReadingTotals, error) {
+	var totals ReadingTotals
+	err := r.finishedBetween(userID, from, to).
+		Select("COUNT(*) AS books, COALESCE(SUM(pages), 0) AS pages").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// GetTopGenres считает каждую книгу один раз, даже если её перечитали
+func (r *readingRepo) GetTopGenres(userID uint, from, to time.Time, limit int) ([]GenreCount, error) {
+	var genres []GenreCount
+	err := r.db.Table("read_throughs AS rt").
+		Select("b.genre AS genre, COUNT(DISTINCT rt.book_id) AS books").
+		Joins("JOIN books b ON b.id = rt.book_id").
+		Where("rt.user_id = ? AND rt.finished_at >= ? AND rt.finished_at < ?", userID, from, to).
+		Where("rt.deleted_at IS NULL AND b.genre <> ''").
+		Group("b.genre").
+		Order("books DESC, genre").
+		Limit(limit).
+		Scan(&genres).Error
+	return genres, err
+}
+
+func (r *readingRepo) GetLongestReadThrough(userID uint, from, to time.Time) (models.ReadThrough, error) {
+	var rt models.ReadThrough
+	err := r.finishedBetween(userID, from, to).
+		Where("pages > 0").
+		Order("pages DESC, finished_at").
+		First(&rt).Error
+	return rt, err
+}
+
+func (r *readingRepo) GetRatingSummary(userID uint, from, to time.Time) (RatingSummary, error) {
+	var summary RatingSummary
+	finished := r.finishedBetween(userID, from, to).Select("book_id")
+	err := r.db.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("user_id = ? AND book_id IN (?)", userID, finished).
+		Scan(&summary).Error
+	return summary, err
+}
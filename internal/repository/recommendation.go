@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CoFavourite - пара книг, которые добавили в избранное одни и те же пользователи
+type CoFavourite struct {
+	BookID        uint
+	SimilarBookID uint
+	Together      int
+	BookUsers     int
+	SimilarUsers  int
+}
+
+type RecommendationRepository interface {
+	GetCoFavourites() ([]CoFavourite, error)
+	// GetCatalog возвращает поля книг, нужные для контентной похожести
+	GetCatalog() ([]models.Book, error)
+	ReplaceSimilarities(rows []models.BookSimilarity) error
+	GetSimilarities(bookIDs []uint) ([]models.BookSimilarity, error)
+}
+
+type recommendationRepo struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &recommendationRepo{db: db}
+}
+
+func (r *recommendationRepo) GetCoFavourites() ([]CoFavourite, error) {
+	var pairs []CoFavourite
+	err := r.db.Raw(`
+		WITH counts AS (
+			SELECT book_id, COUNT(*) AS users FROM user_favourites GROUP BY book_id
+		)
+		SELECT a.book_id AS book_id, b.book_id AS similar_book_id, COUNT(*) AS together,
+			ca.users AS book_users, cb.users AS similar_users
+		FROM user_favourites a
+		JOIN user_favourites b ON b.user_id = a.user_id AND b.book_id <> a.book_id
+		JOIN counts ca ON ca.book_id = a.book_id
+		JOIN counts cb ON cb.book_id = b.book_id
+		GROUP BY a.book_id, b.book_id, ca.users, cb.users
+	`).Scan(&pairs).Error
+	return pairs, err
+}
+
+func (r *recommendationRepo) GetCatalog() ([]models.Book, error) {
+	var books []models.Book
+	err := r.db.Select("id", "title", "author", "genre", "description").Find(&books).Error
+	return books, err
+}
+
+func (r *recommendationRepo) ReplaceSimilarities(rows []models.BookSimilarity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.BookSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
+func (r *recommendationRepo) GetSimilarities(bookIDs []uint) ([]models.BookSimilarity, error) {
+	var rows []models.BookSimilarity
+	if len(bookIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Where("book_id IN ?", bookIDs).Find(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Порядок сортировки списка рецензий
+const (
+	ReviewSortHelpful = "helpful"
+	ReviewSortNewest  = "newest"
+	ReviewSortRating  = "rating"
+	ReviewSortOldest  = "oldest"
+)
+
+var reviewOrders = map[string]string{
+	ReviewSortHelpful: "helpful_count DESC, created_at DESC, id DESC",
+	ReviewSortNewest:  "created_at DESC, id DESC",
+	ReviewSortRating:  "rating DESC, helpful_count DESC, id DESC",
+	ReviewSortOldest:  "created_at ASC, id ASC",
+}
+
+// ErrReviewChanged - рецензию изменили после того, как её прочитали
+var ErrReviewChanged = errors.New("review changed")
+
+type ReviewFilter struct {
+	BookID uint
+	Status string
+	Sort   string
+}
+
+type ReviewRepository interface {
+	GetReview(userID, bookID uint) (models.Review, error)
+	GetReviewByID(id uint) (models.Review, error)
+	// UpsertReview читает рецензию пользователя под блокировкой (пустую, если её ещё нет), применяет
+	// к ней change и сохраняет в той же транзакции. Возвращает прежнюю и сохранённую версии
+	UpsertReview(userID, bookID uint, change func(review *models.Review) error) (models.Review, models.Review, error)
+	// ModerateReview ставит решение модератора, только если статус и updated_at рецензии всё ещё
+	// такие же, как у expected; иначе ErrReviewChanged
+	ModerateReview(expected models.Review, moderatorID uint, status, note string) (models.Review, error)
+	DeleteReview(id uint) error
+	ListReviews(filter ReviewFilter, page, limit int) ([]models.Review, int64, error)
+	AddVote(userID, reviewID uint) error
+	RemoveVote(userID, reviewID uint) error
+}
+
+type reviewRepo struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepo{db: db}
+}
+
+func (r *reviewRepo) GetReview(userID, bookID uint) (models.Review, error) {
+	var review models.Review
+	err := r.db.Where("user_id = ? AND book_id = ?", userID, bookID).First(&review).Error
+	return review, err
+}
+
+func (r *reviewRepo) GetReviewByID(id uint) (models.Review, error) {
+	var review models.Review
+	err := r.db.First(&review, id).Error
+	return review, err
+}
+
+// UpsertReview меняет рецензию, прочитанную под блокировкой, и в той же транзакции сдвигает счётчики
+// книги на разницу между прежним и новым вкладом: параллельная модерация или правка ждёт и не теряется.
+// Пока рецензии нет, блокировать нечего, и две первые рецензии пользователя на книгу могут вставляться
+// одновременно. Проигравшая упрётся в idx_reviews_user_book; её транзакция повторяется один раз и уже
+// находит и блокирует рецензию победителя
+func (r *reviewRepo) UpsertReview(userID, bookID uint, change func(review *models.Review) error) (models.Review, models.Review, error) {
+	old, review, err := r.upsertReview(userID, bookID, change)
+	if IsDuplicateKey(err) {
+		return r.upsertReview(userID, bookID, change)
+	}
+	return old, review, err
+}
+
+func (r *reviewRepo) upsertReview(userID, bookID uint, change func(review *models.Review) error) (models.Review, models.Review, error) {
+	var old, review models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var found []models.Review
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND book_id = ?", userID, bookID).Limit(1).Find(&found).Error
+		if err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			old = found[0]
+		}
+
+		review = old
+		review.UserID = userID
+		review.BookID = bookID
+		if err := change(&review); err != nil {
+			return err
+		}
+		// helpful_count меняется только голосами
+		if err := tx.Omit("HelpfulCount").Save(&review).Error; err != nil {
+			return err
+		}
+		return applyRatingDelta(tx, bookID, old, review)
+	})
+	return old, review, err
+}
+
+func (r *reviewRepo) ModerateReview(expected models.Review, moderatorID uint, status, note string) (models.Review, error) {
+	var review models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.Review{}).
+			Where("id = ? AND status = ? AND updated_at = ?", expected.ID, expected.Status, expected.UpdatedAt).
+			Updates(map[string]any{
+				"status":          status,
+				"moderator_id":    moderatorID,
+				"moderation_note": note,
+				"moderated_at":    now,
+				"updated_at":      now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReviewChanged
+		}
+		if err := tx.First(&review, expected.ID).Error; err != nil {
+			return err
+		}
+		// Текст и оценка не менялись с expected, иначе сдвинулся бы updated_at
+		return applyRatingDelta(tx, review.BookID, expected, review)
+	})
+	return review, err
+}
+
+func (r *reviewRepo) DeleteReview(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var old models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", id).Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		// Удаляем физически, иначе уникальный индекс не даст написать рецензию заново
+		if err := tx.Unscoped().Delete(&old).Error; err != nil {
+			return err
+		}
+		return applyRatingDelta(tx, old.BookID, old, models.Review{})
+	})
+}
+
+func applyRatingDelta(tx *gorm.DB, bookID uint, old, updated models.Review) error {
+	oldSum, oldRatings, oldReviews := old.RatingContribution()
+	newSum, newRatings, newReviews := updated.RatingContribution()
+	if oldSum == newSum && oldRatings == newRatings && oldReviews == newReviews {
+		return nil
+	}
+
+	return tx.Model(&models.Book{}).Where("id = ?", bookID).UpdateColumns(map[string]any{
+		"rating_sum":   gorm.Expr("rating_sum + ?", newSum-oldSum),
+		"rating_count": gorm.Expr("rating_count + ?", newRatings-oldRatings),
+		"review_count": gorm.Expr("review_count + ?", newReviews-oldReviews),
+	}).Error
+}
+
+func (r *reviewRepo) ListReviews(filter ReviewFilter, page, limit int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	db := r.db.Model(&models.Review{})
+	if filter.BookID != 0 {
+		db = db.Where("book_id = ?", filter.BookID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := reviewOrders[filter.Sort]
+	if !ok {
+		order = reviewOrders[ReviewSortHelpful]
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order(order).Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// AddVote идемпотентен: повторный голос не увеличивает счётчик
+func (r *reviewRepo) AddVote(userID, reviewID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ReviewVote{UserID: userID, ReviewID: reviewID})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	})
+}
+
+func (r *reviewRepo) RemoveVote(userID, reviewID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND review_id = ?", userID, reviewID).Delete(&models.ReviewVote{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&models.Review{}).Where("id = ?", reviewID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	})
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"bookshelf/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStockMismatch - движение по заказу не применилось: учёта книги нет или остатка меньше отложенного.
+// Смена статуса заказа откатывается вместе с ним, чтобы журнал движений и заказ не разошлись
+var ErrStockMismatch = errors.New("stock mismatch")
+
+// OutOfStockError - при оформлении заказа книги на складе меньше, чем в корзине
+type OutOfStockError struct {
+	BookID    uint
+	Available int
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("out of stock: book %d, %d available", e.BookID, e.Available)
+}
+
+type StockRepository interface {
+	GetLevel(bookID uint) (models.StockLevel, error)
+	// ListLevels - остатки с названиями книг; lowOnly - только не выше порога, меньшие первыми
+	ListLevels(lowOnly bool, page, limit int) ([]models.StockLevel, int64, error)
+	// SetThreshold задаёт порог и включает учёт книги, если его ещё не было
+	SetThreshold(bookID uint, threshold int) error
+	// Adjust пишет ручное движение и меняет остаток; threshold - порог, если учёт книги только начинается.
+	// false - после списания остатка не хватит на отложенные экземпляры
+	Adjust(movement *models.StockMovement, threshold int) (bool, error)
+	ListMovements(bookID uint, page, limit int) ([]models.StockMovement, int64, error)
+	// ListLowStockToAlert - книги с остатком не выше порога, о которых ещё не уведомляли
+	ListLowStockToAlert() ([]models.StockLevel, error)
+	MarkLowStockAlerted(bookIDs []uint, at time.Time) error
+	// ResetLowStockAlerts снимает отметку об уведомлении с книг, остаток которых снова выше порога
+	ResetLowStockAlerts() error
+	ListAdminIDs() ([]uint, error)
+}
+
+type stockRepo struct {
+	db *gorm.DB
+}
+
+func NewStockRepository(db *gorm.DB) StockRepository {
+	return &stockRepo{db: db}
+}
+
+func (r *stockRepo) GetLevel(bookID uint) (models.StockLevel, error) {
+	var level models.StockLevel
+	err := r.db.Preload("Book").Where("book_id = ?", bookID).First(&level).Error
+	return level, err
+}
+
+func (r *stockRepo) ListLevels(lowOnly bool, page, limit int) ([]models.StockLevel, int64, error) {
+	var levels []models.StockLevel
+	var total int64
+
+	db := r.db.Model(&models.StockLevel{})
+	order := "book_id"
+	if lowOnly {
+		db = db.Where("on_hand - reserved <= low_stock_threshold")
+		order = "on_hand - reserved, book_id"
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Preload("Book").
+		Order(order).
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&levels).Error
+	return levels, total, err
+}
+
+func (r *stockRepo) SetThreshold(bookID uint, threshold int) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "book_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"low_stock_threshold", "updated_at"}),
+	}).Create(&models.StockLevel{BookID: bookID, LowStockThreshold: threshold}).Error
+}
+
+func (r *stockRepo) Adjust(movement *models.StockMovement, threshold int) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		level := models.StockLevel{BookID: movement.BookID, LowStockThreshold: threshold}
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+			return err
+		}
+		var err error
+		applied, err = moveStock(tx, movement, 0)
+		return err
+	})
+	return applied, err
+}
+
+func (r *stockRepo) ListMovements(bookID uint, page, limit int) ([]models.StockMovement, int64, error) {
+	var movements []models.StockMovement
+	var total int64
+
+	db := r.db.Model(&models.StockMovement{}).Where("book_id = ?", bookID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+func (r *stockRepo) ListLowStockToAlert() ([]models.StockLevel, error) {
+	var levels []models.StockLevel
+	err := r.db.Preload("Book").
+		Where("low_stock_alerted_at IS NULL AND on_hand - reserved <= low_stock_threshold").
+		Order("book_id").
+		Find(&levels).Error
+	return levels, err
+}
+
+func (r *stockRepo) MarkLowStockAlerted(bookIDs []uint, at time.Time) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.StockLevel{}).Where("book_id IN ?", bookIDs).
+		UpdateColumn("low_stock_alerted_at", at).Error
+}
+
+func (r *stockRepo) ResetLowStockAlerts() error {
+	return r.db.Model(&models.StockLevel{}).
+		Where("low_stock_alerted_at IS NOT NULL AND on_hand - reserved > low_stock_threshold").
+		UpdateColumn("low_stock_alerted_at", nil).Error
+}
+
+func (r *stockRepo) ListAdminIDs() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.User{}).Where("role = ?", "admin").Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// moveStock меняет остаток на movement.Delta и снимает released отложенных экземпляров, затем пишет
+// движение в журнал. Условие в UPDATE не даёт остатку опуститься ниже отложенного при любой конкуренции.
+// false - учёта книги нет или остатка не хватает
+func moveStock(tx *gorm.DB, movement *models.StockMovement, released int) (bool, error) {
+	var level models.StockLevel
+	result := tx.Model(&level).Clauses(clause.Returning{}).
+		Where("book_id = ? AND on_hand + ? >= reserved - ?", movement.BookID, movement.Delta, released).
+		Updates(map[string]interface{}{
+			"on_hand":    gorm.Expr("on_hand + ?", movement.Delta),
+			"reserved":   gorm.Expr("reserved - ?", released),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	movement.OnHand = level.OnHand
+	return true, tx.Create(movement).Error
+}
+
+// reserveStock откладывает экземпляры под заказ. Книги без учёта пропускаются; строки остатков
+// блокируются по возрастанию ID книги, чтобы параллельные заказы не ждали друг друга по кругу
+func reserveStock(tx *gorm.DB, items []models.OrderItem) ([]models.StockReservation, error) {
+	sorted := append([]models.OrderItem(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BookID < sorted[j].BookID })
+
+	var reservations []models.StockReservation
+	for _, item := range sorted {
+		result := tx.Model(&models.StockLevel{}).
+			Where("book_id = ? AND on_hand - reserved >= ?", item.BookID, item.Quantity).
+			UpdateColumn("reserved", gorm.Expr("reserved + ?", item.Quantity))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			reservations = append(reservations, models.StockReservation{BookID: item.BookID, Quantity: item.Quantity})
+			continue
+		}
+
+		var levels []models.StockLevel
+		if err := tx.Where("book_id = ?", item.BookID).Limit(1).Find(&levels).Error; err != nil {
+			return nil, err
+		}
+		if len(levels) > 0 {
+			return nil, &OutOfStockError{BookID: item.BookID, Available: max(levels[0].Available(), 0)}
+		}
+	}
+	return reservations, nil
+}
+
+func orderReservations(tx *gorm.DB, orderID uint) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	err := tx.Where("order_id = ?", orderID).Order("book_id").Find(&reservations).Error
+	return reservations, err
+}
+
+// commitStock списывает отложенные под оплаченный заказ экземпляры как продажу
+func commitStock(tx *gorm.DB, orderID uint) error {
+	reservations, err := orderReservations(tx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, reservation := range reservations {
+		movement := models.StockMovement{
+			BookID:  reservation.BookID,
+			Delta:   -reservation.Quantity,
+			Reason:  models.StockSale,
+			OrderID: &orderID,
+		}
+		applied, err := moveStock(tx, &movement, reservation.Quantity)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return fmt.Errorf("%w: order %d, book %d: reserved copies not committed", ErrStockMismatch, orderID, reservation.BookID)
+		}
+	}
+	return tx.Where("order_id = ?", orderID).Delete(&models.StockReservation{}).Error
+}
+
+// releaseStock возвращает в продажу экземпляры, отложенные под отменённый заказ
+func releaseStock(tx *gorm.DB, orderID uint) error {
+	reservations, err := orderReservations(tx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, reservation := range reservations {
+		err := tx.Model(&models.StockLevel{}).Where("book_id = ?", reservation.BookID).
+			UpdateColumn("reserved", gorm.Expr("reserved - ?", reservation.Quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Where("order_id = ?", orderID).Delete(&models.StockReservation{}).Error
+}
+
+// restockOrder возвращает на склад проданные по заказу экземпляры, если заказ отменён или возвращён до отправки
+func restockOrder(tx *gorm.DB, orderID uint, reason string) error {
+	var sales []models.StockMovement
+	if err := tx.Where("order_id = ? AND reason = ?", orderID, models.StockSale).Order("book_id").Find(&sales).Error; err != nil {
+		return err
+	}
+	for _, sale := range sales {
+		movement := models.StockMovement{
+			BookID:  sale.BookID,
+			Delta:   -sale.Delta,
+			Reason:  reason,
+			OrderID: &orderID,
+		}
+		applied, err := moveStock(tx, &movement, 0)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return fmt.Errorf("%w: order %d, book %d: sold copies not restocked", ErrStockMismatch, orderID, sale.BookID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/textutil"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Повторные правки одной подборки за час попадают в ленту одним событием
+	collectionActivityWindow = time.Hour
+	// Книга, которую убрали из избранного и вернули, не появляется в ленте снова в течение суток
+	favouriteActivityWindow = 24 * time.Hour
+)
+
+type PrivacyRequest struct {
+	ShareFavourites  bool `json:"share_favourites" example:"false"`
+	ShareReviews     bool `json:"share_reviews" example:"true"`
+	ShareCollections bool `json:"share_collections" example:"true"`
+}
+
+type FeedEntry struct {
+	Event models.ActivityEvent
+	// Book - nil, если событие не про книгу или книга удалена
+	Book *BookBrief
+}
+
+type ActivityService interface {
+	// Новые книги, рецензии, правки подборок и избранное пишутся в журнал активности
+	BookListener
+	ReviewListener
+	CollectionListener
+	FavouriteListener
+	GetFeed(userID uint, page, limit int) ([]FeedEntry, int64, error)
+	GetPrivacy(userID uint) (models.PrivacySettings, error)
+	UpdatePrivacy(userID uint, req PrivacyRequest) (models.PrivacySettings, error)
+}
+
+type activityService struct {
+	repo     repository.ActivityRepository
+	bookRepo repository.BookRepository
+	authRepo repository.AuthRepository
+}
+
+func NewActivityService(repo repository.ActivityRepository, bookRepo repository.BookRepository, authRepo repository.AuthRepository) ActivityService {
+	return &activityService{repo: repo, bookRepo: bookRepo, authRepo: authRepo}
+}
+
+func (s *activityService) BookChanged(event BookEvent) {
+	if event.Type != BooksCreated {
+		return
+	}
+	for _, book := range event.Books {
+		bookID := book.ID
+		s.publish(models.ActivityEvent{
+			Type:    models.ActivityBookAdded,
+			BookID:  &bookID,
+			Summary: fmt.Sprintf("Новая книга: «%s», %s", book.Title, book.Author),
+		}, bookTargets(book))
+	}
+}
+
+func (s *activityService) ReviewPublished(review models.Review) {
+	actor, ok := s.actor(review.UserID, func(p models.PrivacySettings) bool { return p.ShareReviews })
+	if !ok {
+		return
+	}
+	book, err := s.book(review.BookID)
+	if err != nil {
+		return
+	}
+
+	summary := fmt.Sprintf("%s оценил(а) книгу «%s» на %d", actor.Username, book.Title, review.Rating)
+	if review.Text != "" {
+		summary = fmt.Sprintf("%s написал(а) рецензию на книгу «%s»", actor.Username, book.Title)
+	}
+	reviewID := review.ID
+	s.publish(models.ActivityEvent{
+		Type:      models.ActivityReviewPublished,
+		ActorID:   &actor.ID,
+		ActorName: actor.Username,
+		BookID:    &book.ID,
+		ReviewID:  &reviewID,
+		Summary:   summary,
+	}, userTargets(actor.ID))
+}
+
+func (s *activityService) CollectionUpdated(c models.Collection, actorID, bookID uint) {
+	if c.Visibility != models.CollectionPublic {
+		return
+	}
+	actor, ok := s.actor(actorID, func(p models.PrivacySettings) bool { return p.ShareCollections })
+	if !ok {
+		return
+	}
+
+	collectionID := c.ID
+	event := models.ActivityEvent{
+		Type:         models.ActivityCollectionUpdated,
+		ActorID:      &actor.ID,
+		ActorName:    actor.Username,
+		CollectionID: &collectionID,
+		Summary:      fmt.Sprintf("%s обновил(а) подборку «%s»", actor.Username, c.Name),
+	}
+	if s.seenRecently(event, collectionActivityWindow) {
+		return
+	}
+	if bookID != 0 {
+		if book, err := s.book(bookID); err == nil {
+			event.BookID = &book.ID
+			event.Summary = fmt.Sprintf("%s добавил(а) «%s» в подборку «%s»", actor.Username, book.Title, c.Name)
+		}
+	}
+	s.publish(event, userTargets(actor.ID))
+}
+
+func (s *activityService) FavouriteAdded(userID, bookID uint) {
+	actor, ok := s.actor(userID, func(p models.PrivacySettings) bool { return p.ShareFavourites })
+	if !ok {
+		return
+	}
+	book, err := s.book(bookID)
+	if err != nil {
+		return
+	}
+
+	event := models.ActivityEvent{
+		Type:      models.ActivityFavouriteAdded,
+		ActorID:   &actor.ID,
+		ActorName: actor.Username,
+		BookID:    &book.ID,
+		Summary:   fmt.Sprintf("%s добавил(а) «%s» в избранное", actor.Username, book.Title),
+	}
+	if s.seenRecently(event, favouriteActivityWindow) {
+		return
+	}
+	s.publish(event, userTargets(actor.ID))
+}
+
+// actor возвращает автора действия, если его настройки приватности разрешают показать действие
+func (s *activityService) actor(userID uint, allowed func(models.PrivacySettings) bool) (models.User, bool) {
+	privacy, err := s.GetPrivacy(userID)
+	if err != nil {
+		log.Printf("activity: failed to load privacy settings of user %d: %s", userID, err.Error())
+		return models.User{}, false
+	}
+	if !allowed(privacy) {
+		return models.User{}, false
+	}
+	user, err := s.authRepo.GetUserByID(strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		log.Printf("activity: failed to load user %d: %s", userID, err.Error())
+		return models.User{}, false
+	}
+	return user, true
+}
+
+func (s *activityService) book(bookID uint) (models.Book, error) {
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		log.Printf("activity: failed to load book %d: %s", bookID, err.Error())
+	}
+	return book, err
+}
+
+func (s *activityService) seenRecently(event models.ActivityEvent, window time.Duration) bool {
+	seen, err := s.repo.HasRecent(event, time.Now().Add(-window))
+	if err != nil {
+		log.Printf("activity: failed to check recent %s events: %s", event.Type, err.Error())
+		return false
+	}
+	return seen
+}
+
+func (s *activityService) publish(event models.ActivityEvent, targets []repository.FeedTarget) {
+	if err := s.repo.Publish(&event, targets); err != nil {
+		log.Printf("activity: failed to publish %s event: %s", event.Type, err.Error())
+	}
+}
+
+func bookTargets(book models.Book) []repository.FeedTarget {
+	var targets []repository.FeedTarget
+	if key := textutil.Normalize(book.Author); key != "" {
+		targets = append(targets, repository.FeedTarget{Type: models.FollowAuthor, Key: key})
+	}
+	if key := textutil.Normalize(book.Genre); key != "" {
+		targets = append(targets, repository.FeedTarget{Type: models.FollowGenre, Key: key})
+	}
+	return targets
+}
+
+func userTargets(userID uint) []repository.FeedTarget {
+	return []repository.FeedTarget{{Type: models.FollowUser, Key: strconv.FormatUint(uint64(userID), 10)}}
+}
+
+func (s *activityService) GetFeed(userID uint, page, limit int) ([]FeedEntry, int64, error) {
+	events, total, err := s.repo.GetFeed(userID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var bookIDs []uint
+	for _, e := range events {
+		if e.BookID != nil {
+			bookIDs = append(bookIDs, *e.BookID)
+		}
+	}
+	books := make(map[uint]BookBrief, len(bookIDs))
+	if len(bookIDs) > 0 {
+		found, err := s.bookRepo.GetBooksByIDs(bookIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, brief := range toBookBriefs(found) {
+			books[brief.ID] = brief
+		}
+	}
+
+	entries := make([]FeedEntry, len(events))
+	for i, e := range events {
+		entries[i] = FeedEntry{Event: e}
+		if e.BookID != nil {
+			if brief, ok := books[*e.BookID]; ok {
+				entries[i].Book = &brief
+			}
+		}
+	}
+	return entries, total, nil
+}
+
+func (s *activityService) GetPrivacy(userID uint) (models.PrivacySettings, error) {
+	settings, err := s.repo.GetPrivacy(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultPrivacySettings(userID), nil
+	}
+	return settings, err
+}
+
+func (s *activityService) UpdatePrivacy(userID uint, req PrivacyRequest) (models.PrivacySettings, error) {
+	settings := models.PrivacySettings{
+		UserID:           userID,
+		ShareFavourites:  req.ShareFavourites,
+		ShareReviews:     req.ShareReviews,
+		ShareCollections: req.ShareCollections,
+	}
+	if err := s.repo.SavePrivacy(&settings); err != nil {
+		return models.PrivacySettings{}, err
+	}
+	return settings, nil
+}
@@ -4,8 +4,10 @@ package service
 import (
 	"bookshelf/internal/models"
 	"bookshelf/internal/repository"
+	"bookshelf/pkg/kosync"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -58,9 +60,15 @@ func (s *authService) RegisterUser(username, password string) error {
 		return err
 	}
 
+	syncKeyHash, err := hashSyncKey(password)
+	if err != nil {
+		return err
+	}
+
 	newUser := models.User{
 		Username:     username,
 		PasswordHash: string(hashedPassword),
+		SyncKeyHash:  syncKeyHash,
 		Role:         "user",
 	}
 
@@ -86,10 +94,26 @@ func (s *authService) LoginUser(username, password string) (models.User, error)
 		return models.User{}, errors.New("invalid credentials")
 	}
 
+	// У аккаунтов, созданных до синхронизации с KOReader, ключ появляется при первом входе
+	if user.SyncKeyHash == "" {
+		if user.SyncKeyHash, err = hashSyncKey(password); err == nil {
+			err = s.repo.UpdateUser(user)
+		}
+		if err != nil {
+			log.Printf("auth: set sync key for user %d: %v", user.ID, err)
+		}
+	}
+
 	user.PasswordHash = ""
 	return user, nil
 }
 
+// hashSyncKey хэширует ключ, который KOReader передаёт вместо пароля
+func hashSyncKey(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(kosync.Key(password)), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
 func (s *authService) GetUser(currentUserID string, targetUserID string) (models.User, error) {
 	ctx := s.getUserContext(currentUserID)
 	if ctx.err != nil {
@@ -123,8 +147,8 @@ func (s *authService) GetAllUsers() ([]models.User, error) {
 }
 
 func (s *authService) UpdateUserRole(targetUserID, newRole string) (models.User, error) {
-	if newRole != "admin" && newRole != "user" {
-		return models.User{}, errors.New("invalid role, must be 'admin' or 'user'")
+	if newRole != "admin" && newRole != "moderator" && newRole != "user" {
+		return models.User{}, errors.New("invalid role, must be 'admin', 'moderator' or 'user'")
 	}
 
 	user, err := s.repo.GetUserByID(targetUserID)
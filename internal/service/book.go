@@ -4,24 +4,36 @@ import (
 	"bookshelf/internal/models"
 	"bookshelf/internal/repository"
 	"bookshelf/pkg/cache"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/textutil"
 	"fmt"
+	"strconv"
 	"time"
 )
 
 type BookRequest struct {
-	Title       string  `json:"title" binding:"required"`
-	Author      string  `json:"author" binding:"required"`
-	Genre       string  `json:"genre" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
+	Title       string        `json:"title" binding:"required"`
+	Author      string        `json:"author" binding:"required"`
+	Genre       string        `json:"genre" binding:"required"`
+	Description string        `json:"description" binding:"required"`
+	Price       money.Decimal `json:"price" binding:"required,gt=0" swaggertype:"string" example:"49.99"`
+	// Currency - валюта цены (ISO 4217); пусто - базовая валюта каталога
+	Currency  string   `json:"currency" example:"USD"`
+	ISBN      string   `json:"isbn"`
+	Publisher string   `json:"publisher"`
+	Subjects  []string `json:"subjects"`
+	Pages     int      `json:"pages"`
 }
 
 type BookBrief struct {
-	ID     uint    `json:"id"`
-	Title  string  `json:"title"`
-	Author string  `json:"author"`
-	Genre  string  `json:"genre"`
-	Price  float64 `json:"price"`
+	ID     uint        `json:"id"`
+	Title  string      `json:"title"`
+	Author string      `json:"author"`
+	Genre  string      `json:"genre"`
+	Price  money.Money `json:"price"`
+	// EffectivePrice - цена с учётом распродаж; считается только в списках сервиса книг, иначе nil
+	EffectivePrice *money.Money `json:"effective_price,omitempty"`
+	SaleEndsAt     *time.Time   `json:"sale_ends_at,omitempty"`
 }
 
 type BookService interface {
@@ -29,33 +41,113 @@ type BookService interface {
 	GetBookByID(id string) (models.Book, error)
 	GetAllBooks(genre string, page, limit int) ([]BookBrief, int64, error)
 	GetAllGenres() ([]string, error)
+	GetNewestBooks(page, limit int) ([]BookBrief, int64, error)
+	SearchBooks(query string, page, limit int) ([]BookBrief, int64, error)
 	UpdateBook(id string, update BookRequest) (models.Book, error)
 	DeleteBook(id string) error
 }
 
+const (
+	BooksCreated = "created"
+	BookUpdated  = "updated"
+	BookDeleted  = "deleted"
+)
+
+// BookEvent - изменение каталога. Импорт присылает все книги одним событием,
+// у удалённой книги заполнен только ID
+type BookEvent struct {
+	Type  string
+	Books []models.Book
+	// Previous - книга до изменения, только для BookUpdated
+	Previous *models.Book
+}
+
+// BookListener узнаёт об изменениях каталога, например чтобы обновить индекс
+type BookListener interface {
+	BookChanged(event BookEvent)
+}
+
 type bookService struct {
-	repo  repository.BookRepository
-	cache cache.RedisCache
+	repo         repository.BookRepository
+	cache        cache.RedisCache
+	discounts    DiscountService
+	baseCurrency string
+	listeners    []BookListener
+}
+
+// NewBookService: baseCurrency подставляется в книги, у которых валюта цены не указана
+func NewBookService(repo repository.BookRepository, cache *cache.RedisCache, discounts DiscountService, baseCurrency string, listeners ...BookListener) BookService {
+	return &bookService{repo: repo, cache: *cache, discounts: discounts, baseCurrency: baseCurrency, listeners: listeners}
+}
+
+// cacheWithSales кладёт в кэш результат с ценами по распродажам не дольше, чем до ближайшего
+// начала или конца распродажи next; если он уже наступил, результат не кэшируется
+func (s *bookService) cacheWithSales(key string, value any, ttl time.Duration, next time.Time) {
+	if !next.IsZero() {
+		ttl = min(ttl, time.Until(next))
+	}
+	if ttl >= time.Second {
+		s.cache.Set(key, value, ttl)
+	}
+}
+
+// pricedBriefs - краткие карточки с ценой по действующим распродажам
+func (s *bookService) pricedBriefs(books []models.Book) ([]BookBrief, time.Time, error) {
+	next, err := s.discounts.ApplySales(books)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	briefs := toBookBriefs(books)
+	for i, book := range books {
+		effective := briefs[i].Price
+		if book.SalePrice != nil {
+			effective.Amount = *book.SalePrice
+		}
+		briefs[i].EffectivePrice = &effective
+		briefs[i].SaleEndsAt = book.SaleEndsAt
+	}
+	return briefs, next, nil
 }
 
-func NewBookService(repo repository.BookRepository, cache *cache.RedisCache) BookService {
-	return &bookService{repo: repo, cache: *cache}
+// requestPrice переводит цену из запроса в минимальные единицы её валюты
+func requestPrice(req BookRequest, baseCurrency string) (money.Money, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = baseCurrency
+	}
+	if req.Price == "" {
+		req.Price = "0"
+	}
+	price, err := money.ParseIn(string(req.Price), currency)
+	if err != nil || price.Amount < 0 {
+		return money.Money{}, fmt.Errorf("invalid price %q %s", req.Price, currency)
+	}
+	return price, nil
 }
 
 func (s *bookService) CreateBook(req BookRequest) (models.Book, error) {
+	price, err := requestPrice(req, s.baseCurrency)
+	if err != nil {
+		return models.Book{}, err
+	}
 	book := models.Book{
 		Title:       req.Title,
 		Author:      req.Author,
 		Genre:       req.Genre,
 		Description: req.Description,
-		Price:       req.Price,
+		Price:       price.Amount,
+		Currency:    price.Currency,
+		ISBN:        textutil.NormalizeISBN(req.ISBN),
+		Publisher:   req.Publisher,
+		Subjects:    req.Subjects,
+		Pages:       req.Pages,
 	}
 
-	err := s.repo.CreateBook(book)
-	if err != nil {
+	if err := s.repo.CreateBook(&book); err != nil {
 		return models.Book{}, err
 	}
 	s.cache.InvalidatePattern("books:*")
+	s.notify(BookEvent{Type: BooksCreated, Books: []models.Book{book}})
 	return book, nil
 }
 
@@ -70,8 +162,13 @@ func (s *bookService) GetBookByID(id string) (models.Book, error) {
 	if err != nil {
 		return models.Book{}, err
 	}
-	s.cache.Set(cacheKey, book, 10*time.Minute)
-	return book, nil
+	books := []models.Book{book}
+	next, err := s.discounts.ApplySales(books)
+	if err != nil {
+		return models.Book{}, err
+	}
+	s.cacheWithSales(cacheKey, books[0], 10*time.Minute, next)
+	return books[0], nil
 }
 
 func (s *bookService) GetAllBooks(genre string, page, limit int) ([]BookBrief, int64, error) {
@@ -82,7 +179,7 @@ func (s *bookService) GetAllBooks(genre string, page, limit int) ([]BookBrief, i
 		Total int64
 	}
 
-	if s.cache.Get(cacheKey, cachedResult) {
+	if s.cache.Get(cacheKey, &cachedResult) {
 		return cachedResult.Books, cachedResult.Total, nil
 	}
 
@@ -91,15 +188,9 @@ func (s *bookService) GetAllBooks(genre string, page, limit int) ([]BookBrief, i
 		return nil, 0, err
 	}
 
-	briefs := make([]BookBrief, len(books))
-	for i, book := range books {
-		briefs[i] = BookBrief{
-			ID:     book.ID,
-			Title:  book.Title,
-			Author: book.Author,
-			Genre:  book.Genre,
-			Price:  book.Price,
-		}
+	briefs, next, err := s.pricedBriefs(books)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	result := struct {
@@ -107,7 +198,7 @@ func (s *bookService) GetAllBooks(genre string, page, limit int) ([]BookBrief, i
 		Total int64
 	}{briefs, total}
 
-	s.cache.Set(cacheKey, result, 5*time.Minute)
+	s.cacheWithSales(cacheKey, result, 5*time.Minute, next)
 
 	return briefs, total, nil
 }
@@ -128,16 +219,76 @@ func (s *bookService) GetAllGenres() ([]string, error) {
 	return genres, nil
 }
 
+func (s *bookService) GetNewestBooks(page, limit int) ([]BookBrief, int64, error) {
+	cacheKey := fmt.Sprintf("books:newest:%d:%d", page, limit)
+
+	var cachedResult struct {
+		Books []BookBrief
+		Total int64
+	}
+	if s.cache.Get(cacheKey, &cachedResult) {
+		return cachedResult.Books, cachedResult.Total, nil
+	}
+
+	books, total, err := s.repo.GetNewestBooks(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	briefs, next, err := s.pricedBriefs(books)
+	if err != nil {
+		return nil, 0, err
+	}
+	cachedResult.Books = briefs
+	cachedResult.Total = total
+
+	s.cacheWithSales(cacheKey, cachedResult, 5*time.Minute, next)
+	return cachedResult.Books, total, nil
+}
+
+// SearchBooks не кэшируется: запросы слишком разнообразны
+func (s *bookService) SearchBooks(query string, page, limit int) ([]BookBrief, int64, error) {
+	books, total, err := s.repo.SearchBooks(query, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	briefs, _, err := s.pricedBriefs(books)
+	return briefs, total, err
+}
+
+func toBookBriefs(books []models.Book) []BookBrief {
+	briefs := make([]BookBrief, len(books))
+	for i, book := range books {
+		briefs[i] = BookBrief{
+			ID:     book.ID,
+			Title:  book.Title,
+			Author: book.Author,
+			Genre:  book.Genre,
+			Price:  money.New(book.Price, book.Currency),
+		}
+	}
+	return briefs
+}
+
 func (s *bookService) UpdateBook(id string, update BookRequest) (models.Book, error) {
+	price, err := requestPrice(update, s.baseCurrency)
+	if err != nil {
+		return models.Book{}, err
+	}
 	book, err := s.repo.GetBookByID(id)
 	if err != nil {
 		return models.Book{}, err
 	}
+	previous := book
 	book.Title = update.Title
 	book.Author = update.Author
 	book.Genre = update.Genre
 	book.Description = update.Description
-	book.Price = update.Price
+	book.Price = price.Amount
+	book.Currency = price.Currency
+	book.ISBN = textutil.NormalizeISBN(update.ISBN)
+	book.Publisher = update.Publisher
+	book.Subjects = update.Subjects
+	book.Pages = update.Pages
 
 	if err := s.repo.UpdateBook(book); err != nil {
 		return models.Book{}, err
@@ -149,6 +300,7 @@ func (s *bookService) UpdateBook(id string, update BookRequest) (models.Book, er
 	if book.Genre != update.Genre {
 		s.cache.Delete("genres:all")
 	}
+	s.notify(BookEvent{Type: BookUpdated, Books: []models.Book{book}, Previous: &previous})
 	return book, nil
 }
 
@@ -158,5 +310,16 @@ func (s *bookService) DeleteBook(id string) error {
 	}
 	s.cache.InvalidatePattern("book:*")
 	s.cache.Delete(fmt.Sprintf("book:%s", id))
+	if bookID, err := strconv.ParseUint(id, 10, 64); err == nil {
+		var deleted models.Book
+		deleted.ID = uint(bookID)
+		s.notify(BookEvent{Type: BookDeleted, Books: []models.Book{deleted}})
+	}
 	return nil
 }
+
+func (s *bookService) notify(event BookEvent) {
+	for _, l := range s.listeners {
+		l.BookChanged(event)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	maxCartQuantity = 99
+	maxCartItems    = 100
+)
+
+type CartItemRequest struct {
+	// Quantity 0 убирает книгу из корзины
+	Quantity int `json:"quantity" example:"2"`
+}
+
+type CartLine struct {
+	Book      BookBrief
+	Quantity  int
+	UnitPrice money.Amount
+	LineTotal money.Amount
+}
+
+// Cart - корзина по текущим ценам со скидками в одной валюте; в заказ цены копируются при оформлении
+type Cart struct {
+	Lines    []CartLine
+	Count    int
+	Total    money.Amount
+	Currency string
+	// Coupon - код купона, если он снизил цену хотя бы одной книги
+	Coupon string
+}
+
+type CartService interface {
+	// GetCart считает корзину в валюте currency; пусто - базовая валюта каталога
+	GetCart(userID uint, currency string) (Cart, error)
+	SetItem(userID, bookID uint, currency string, req CartItemRequest) (Cart, error)
+	RemoveItem(userID, bookID uint) error
+	Clear(userID uint) error
+}
+
+type cartService struct {
+	repo      repository.CartRepository
+	bookRepo  repository.BookRepository
+	pricing   PricingService
+	discounts DiscountService
+}
+
+func NewCartService(repo repository.CartRepository, bookRepo repository.BookRepository, pricing PricingService, discounts DiscountService) CartService {
+	return &cartService{repo: repo, bookRepo: bookRepo, pricing: pricing, discounts: discounts}
+}
+
+// priceCart считает строки и итог корзины точно, в минимальных единицах валюты currency.
+// Книга идёт по самой низкой цене из распродаж и купона coupon (nil - без купона)
+func priceCart(pricing PricingService, discounts DiscountService, items []models.CartItem, currency string, coupon *models.Discount) (Cart, error) {
+	code, err := cartCurrency(pricing, currency)
+	if err != nil {
+		return Cart{}, err
+	}
+	books := make([]models.Book, len(items))
+	for i, item := range items {
+		books[i] = item.Book
+	}
+	if _, err := discounts.ApplySales(books); err != nil {
+		return Cart{}, err
+	}
+	cart := Cart{Lines: make([]CartLine, 0, len(items)), Currency: code}
+	for i := range books {
+		if coupon != nil && applyDiscountPrice(&books[i], *coupon) {
+			books[i].SaleEndsAt = coupon.EndsAt
+			cart.Coupon = *coupon.Code
+		}
+	}
+	prices, err := pricing.PriceBooks(books, code)
+	if err != nil {
+		return Cart{}, err
+	}
+
+	for i, item := range items {
+		unit := prices[i].Effective.Amount
+		brief := toBookBriefs([]models.Book{books[i]})[0]
+		brief.Price = prices[i].List
+		brief.EffectivePrice = &prices[i].Effective
+		brief.SaleEndsAt = books[i].SaleEndsAt
+		line := CartLine{
+			Book:      brief,
+			Quantity:  item.Quantity,
+			UnitPrice: unit,
+			LineTotal: unit.Mul(item.Quantity),
+		}
+		cart.Lines = append(cart.Lines, line)
+		cart.Count += item.Quantity
+		cart.Total += line.LineTotal
+	}
+	return cart, nil
+}
+
+// cartCurrency - валюта корзины: указанная или базовая
+func cartCurrency(pricing PricingService, currency string) (string, error) {
+	if currency == "" {
+		return pricing.BaseCurrency(), nil
+	}
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return "", fmt.Errorf("invalid currency %q", currency)
+	}
+	return code, nil
+}
+
+func (s *cartService) GetCart(userID uint, currency string) (Cart, error) {
+	items, err := s.repo.ListItems(userID)
+	if err != nil {
+		return Cart{}, err
+	}
+	return priceCart(s.pricing, s.discounts, items, currency, nil)
+}
+
+func (s *cartService) SetItem(userID, bookID uint, currency string, req CartItemRequest) (Cart, error) {
+	if req.Quantity < 0 || req.Quantity > maxCartQuantity {
+		return Cart{}, fmt.Errorf("invalid quantity, must be between 0 and %d", maxCartQuantity)
+	}
+	if _, err := cartCurrency(s.pricing, currency); err != nil {
+		return Cart{}, err
+	}
+	if req.Quantity == 0 {
+		if _, err := s.repo.RemoveItem(userID, bookID); err != nil {
+			return Cart{}, err
+		}
+		return s.GetCart(userID, currency)
+	}
+
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return Cart{}, errors.New("book not found")
+	}
+	count, err := s.repo.CountItems(userID)
+	if err != nil {
+		return Cart{}, err
+	}
+	if count >= maxCartItems {
+		// Замена количества уже лежащей книги лимит не увеличивает
+		items, err := s.repo.ListItems(userID)
+		if err != nil {
+			return Cart{}, err
+		}
+		if !containsBook(items, bookID) {
+			return Cart{}, fmt.Errorf("cart limit reached: at most %d books", maxCartItems)
+		}
+	}
+
+	if err := s.repo.SetItem(&models.CartItem{UserID: userID, BookID: bookID, Quantity: req.Quantity}); err != nil {
+		return Cart{}, err
+	}
+	return s.GetCart(userID, currency)
+}
+
+func containsBook(items []models.CartItem, bookID uint) bool {
+	for _, item := range items {
+		if item.BookID == bookID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *cartService) RemoveItem(userID, bookID uint) error {
+	removed, err := s.repo.RemoveItem(userID, bookID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("book not found in cart")
+	}
+	return nil
+}
+
+func (s *cartService) Clear(userID uint) error {
+	return s.repo.Clear(userID)
+}
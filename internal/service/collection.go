@@ -0,0 +1,356 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxCollectionName        = 200
+	maxCollectionDescription = 2000
+)
+
+type CollectionRequest struct {
+	Name        string `json:"name" example:"Go для новичков"`
+	Description string `json:"description" example:"Что читать в первый месяц"`
+	Visibility  string `json:"visibility" example:"unlisted"`
+}
+
+type CollectionDetail struct {
+	Collection    models.Collection
+	Books         []BookBrief
+	Total         int64
+	Collaborators []uint
+	CanEdit       bool
+}
+
+type CollectionService interface {
+	CreateCollection(ownerID uint, req CollectionRequest) (models.Collection, error)
+	UpdateCollection(userID, id uint, req CollectionRequest) (models.Collection, error)
+	DeleteCollection(userID, id uint) error
+	// GetCollection: viewerID = 0 для анонимного запроса
+	GetCollection(viewerID, id uint, page, limit int) (CollectionDetail, error)
+	GetSharedCollection(token string, page, limit int) (CollectionDetail, error)
+	ListPublic(page, limit int) ([]models.Collection, int64, error)
+	ListUserCollections(userID uint, page, limit int) ([]models.Collection, int64, error)
+	RotateShareToken(userID, id uint) (models.Collection, error)
+	AddBook(userID, id, bookID uint) error
+	RemoveBook(userID, id, bookID uint) error
+	ReorderBooks(userID, id uint, bookIDs []uint) error
+	AddCollaborator(ownerID, id, collaboratorID uint) error
+	RemoveCollaborator(ownerID, id, collaboratorID uint) error
+}
+
+// CollectionListener узнаёт об изменениях подборок. bookID - добавленная книга или 0,
+// если изменились сами свойства подборки
+type CollectionListener interface {
+	CollectionUpdated(c models.Collection, actorID, bookID uint)
+}
+
+type collectionService struct {
+	repo      repository.CollectionRepository
+	bookRepo  repository.BookRepository
+	authRepo  repository.AuthRepository
+	listeners []CollectionListener
+}
+
+func NewCollectionService(repo repository.CollectionRepository, bookRepo repository.BookRepository, authRepo repository.AuthRepository, listeners ...CollectionListener) CollectionService {
+	return &collectionService{repo: repo, bookRepo: bookRepo, authRepo: authRepo, listeners: listeners}
+}
+
+func validateCollection(req *CollectionRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Visibility == "" {
+		req.Visibility = models.CollectionPrivate
+	}
+
+	if req.Name == "" {
+		return errors.New("collection name is required")
+	}
+	if utf8.RuneCountInString(req.Name) > maxCollectionName || utf8.RuneCountInString(req.Description) > maxCollectionDescription {
+		return errors.New("invalid collection: name or description is too long")
+	}
+	switch req.Visibility {
+	case models.CollectionPrivate, models.CollectionUnlisted, models.CollectionPublic:
+		return nil
+	}
+	return errors.New("invalid visibility, must be 'private', 'unlisted' or 'public'")
+}
+
+func newShareToken() (*string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b)
+	return &token, nil
+}
+
+// applyVisibility выдаёт токен ссылки при переходе в unlisted. Токен сохраняется при смене видимости,
+// чтобы ссылка снова заработала после возврата в unlisted; сбросить её можно через RotateShareToken
+func applyVisibility(c *models.Collection, visibility string) error {
+	c.Visibility = visibility
+	if visibility == models.CollectionUnlisted && c.ShareToken == nil {
+		token, err := newShareToken()
+		if err != nil {
+			return err
+		}
+		c.ShareToken = token
+	}
+	return nil
+}
+
+func (s *collectionService) CreateCollection(ownerID uint, req CollectionRequest) (models.Collection, error) {
+	if err := validateCollection(&req); err != nil {
+		return models.Collection{}, err
+	}
+
+	c := models.Collection{OwnerID: ownerID, Name: req.Name, Description: req.Description}
+	if err := applyVisibility(&c, req.Visibility); err != nil {
+		return models.Collection{}, err
+	}
+	if err := s.repo.CreateCollection(&c); err != nil {
+		return models.Collection{}, err
+	}
+	s.notify(c, ownerID, 0)
+	return c, nil
+}
+
+// getOwned возвращает коллекцию, если пользователь её владелец. Чужая приватная коллекция
+// выглядит как несуществующая
+func (s *collectionService) getOwned(userID, id uint) (models.Collection, error) {
+	c, err := s.repo.GetCollection(id)
+	if err != nil {
+		return models.Collection{}, errors.New("collection not found")
+	}
+	if c.OwnerID == userID {
+		return c, nil
+	}
+	if canEdit, _ := s.canEdit(c, userID); canEdit || c.Visibility == models.CollectionPublic {
+		return models.Collection{}, errors.New("access denied: only the owner can do this")
+	}
+	return models.Collection{}, errors.New("collection not found")
+}
+
+func (s *collectionService) getEditable(userID, id uint) (models.Collection, error) {
+	c, err := s.repo.GetCollection(id)
+	if err != nil {
+		return models.Collection{}, errors.New("collection not found")
+	}
+	canEdit, err := s.canEdit(c, userID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	if canEdit {
+		return c, nil
+	}
+	if c.Visibility == models.CollectionPublic {
+		return models.Collection{}, errors.New("access denied: only the owner and collaborators can edit")
+	}
+	return models.Collection{}, errors.New("collection not found")
+}
+
+func (s *collectionService) canEdit(c models.Collection, userID uint) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	if c.OwnerID == userID {
+		return true, nil
+	}
+	return s.repo.IsCollaborator(c.ID, userID)
+}
+
+func (s *collectionService) UpdateCollection(userID, id uint, req CollectionRequest) (models.Collection, error) {
+	if err := validateCollection(&req); err != nil {
+		return models.Collection{}, err
+	}
+	c, err := s.getOwned(userID, id)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	c.Name = req.Name
+	c.Description = req.Description
+	if err := applyVisibility(&c, req.Visibility); err != nil {
+		return models.Collection{}, err
+	}
+	if err := s.repo.UpdateCollection(&c); err != nil {
+		return models.Collection{}, err
+	}
+	s.notify(c, userID, 0)
+	return c, nil
+}
+
+func (s *collectionService) notify(c models.Collection, actorID, bookID uint) {
+	for _, l := range s.listeners {
+		l.CollectionUpdated(c, actorID, bookID)
+	}
+}
+
+func (s *collectionService) DeleteCollection(userID, id uint) error {
+	if _, err := s.getOwned(userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteCollection(id)
+}
+
+func (s *collectionService) GetCollection(viewerID, id uint, page, limit int) (CollectionDetail, error) {
+	c, err := s.repo.GetCollection(id)
+	if err != nil {
+		return CollectionDetail{}, errors.New("collection not found")
+	}
+	canEdit, err := s.canEdit(c, viewerID)
+	if err != nil {
+		return CollectionDetail{}, err
+	}
+	// unlisted по id открывается только участникам, остальным - по ссылке
+	if !canEdit && c.Visibility != models.CollectionPublic {
+		return CollectionDetail{}, errors.New("collection not found")
+	}
+	return s.detail(c, canEdit, page, limit)
+}
+
+func (s *collectionService) GetSharedCollection(token string, page, limit int) (CollectionDetail, error) {
+	c, err := s.repo.GetCollectionByToken(token)
+	if err != nil || c.Visibility == models.CollectionPrivate {
+		return CollectionDetail{}, errors.New("collection not found")
+	}
+	return s.detail(c, false, page, limit)
+}
+
+func (s *collectionService) detail(c models.Collection, canEdit bool, page, limit int) (CollectionDetail, error) {
+	items, total, err := s.repo.GetItems(c.ID, page, limit)
+	if err != nil {
+		return CollectionDetail{}, err
+	}
+
+	books := make([]models.Book, len(items))
+	for i, item := range items {
+		books[i] = item.Book
+		books[i].ID = item.BookID
+	}
+
+	detail := CollectionDetail{Collection: c, Books: toBookBriefs(books), Total: total, CanEdit: canEdit}
+	if canEdit {
+		if detail.Collaborators, err = s.repo.GetCollaborators(c.ID); err != nil {
+			return CollectionDetail{}, err
+		}
+	}
+	return detail, nil
+}
+
+func (s *collectionService) ListPublic(page, limit int) ([]models.Collection, int64, error) {
+	return s.repo.ListPublic(page, limit)
+}
+
+func (s *collectionService) ListUserCollections(userID uint, page, limit int) ([]models.Collection, int64, error) {
+	return s.repo.ListForUser(userID, page, limit)
+}
+
+func (s *collectionService) RotateShareToken(userID, id uint) (models.Collection, error) {
+	c, err := s.getOwned(userID, id)
+	if err != nil {
+		return models.Collection{}, err
+	}
+	if c.Visibility != models.CollectionUnlisted {
+		return models.Collection{}, errors.New("invalid visibility: share links exist only for unlisted collections")
+	}
+	if c.ShareToken, err = newShareToken(); err != nil {
+		return models.Collection{}, err
+	}
+	if err := s.repo.UpdateCollection(&c); err != nil {
+		return models.Collection{}, err
+	}
+	return c, nil
+}
+
+func (s *collectionService) AddBook(userID, id, bookID uint) error {
+	c, err := s.getEditable(userID, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return errors.New("book not found")
+	}
+
+	added, err := s.repo.AddItem(id, bookID, userID)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errors.New("book already exists in collection")
+	}
+	s.notify(c, userID, bookID)
+	return nil
+}
+
+func (s *collectionService) RemoveBook(userID, id, bookID uint) error {
+	if _, err := s.getEditable(userID, id); err != nil {
+		return err
+	}
+	removed, err := s.repo.RemoveItem(id, bookID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("book not found in collection")
+	}
+	return nil
+}
+
+// ReorderBooks принимает полный список книг коллекции в новом порядке
+func (s *collectionService) ReorderBooks(userID, id uint, bookIDs []uint) error {
+	if _, err := s.getEditable(userID, id); err != nil {
+		return err
+	}
+
+	current, err := s.repo.GetItemBookIDs(id)
+	if err != nil {
+		return err
+	}
+	if len(current) != len(bookIDs) {
+		return errors.New("invalid order: must list every book of the collection exactly once")
+	}
+	remaining := make(map[uint]bool, len(current))
+	for _, bookID := range current {
+		remaining[bookID] = true
+	}
+	for _, bookID := range bookIDs {
+		if !remaining[bookID] {
+			return errors.New("invalid order: must list every book of the collection exactly once")
+		}
+		delete(remaining, bookID)
+	}
+
+	return s.repo.ReorderItems(id, bookIDs)
+}
+
+func (s *collectionService) AddCollaborator(ownerID, id, collaboratorID uint) error {
+	if _, err := s.getOwned(ownerID, id); err != nil {
+		return err
+	}
+	if collaboratorID == ownerID {
+		return errors.New("invalid collaborator: owner already has edit rights")
+	}
+	if _, err := s.authRepo.GetUserByID(strconv.FormatUint(uint64(collaboratorID), 10)); err != nil {
+		return errors.New("user not found")
+	}
+	return s.repo.AddCollaborator(id, collaboratorID)
+}
+
+// RemoveCollaborator доступен владельцу, а соавтор может выйти сам
+func (s *collectionService) RemoveCollaborator(userID, id, collaboratorID uint) error {
+	if userID != collaboratorID {
+		if _, err := s.getOwned(userID, id); err != nil {
+			return err
+		}
+	}
+	return s.repo.RemoveCollaborator(id, collaboratorID)
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"bookshelf/pkg/money"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	salesCacheKey   = "discounts:sales"
+	maxDiscountName = 200
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// DiscountRequest: распродажа, если code пустой, иначе купон
+type DiscountRequest struct {
+	Name   string `json:"name" example:"Неделя Go"`
+	Target string `json:"target" example:"genre"`
+	Value  string `json:"value" example:"Programming"`
+	Kind   string `json:"kind" example:"percent"`
+	// Percent - для kind=percent
+	Percent int `json:"percent" example:"20"`
+	// Amount и Currency - для kind=fixed; пустая currency - базовая валюта каталога
+	Amount   money.Decimal `json:"amount" swaggertype:"string" example:"5.00"`
+	Currency string        `json:"currency" example:"USD"`
+	// StartsAt по умолчанию - сейчас; EndsAt пусто - бессрочно
+	StartsAt   *time.Time `json:"starts_at" example:"2026-11-02T00:00:00Z"`
+	EndsAt     *time.Time `json:"ends_at" example:"2026-11-09T00:00:00Z"`
+	Code       string     `json:"code" example:"GOPHER20"`
+	UsageLimit int        `json:"usage_limit" example:"100"`
+}
+
+type DiscountService interface {
+	CreateDiscount(req DiscountRequest) (models.Discount, error)
+	GetDiscount(id uint) (models.Discount, error)
+	UpdateDiscount(id uint, req DiscountRequest) (models.Discount, error)
+	DeleteDiscount(id uint) error
+	ListDiscounts(page, limit int) ([]models.Discount, int64, error)
+	// ApplySales проставляет книгам цену по действующим распродажам и возвращает ближайший момент,
+	// когда начнётся или закончится какая-нибудь распродажа (нулевое время - таких нет)
+	ApplySales(books []models.Book) (time.Time, error)
+	// Coupon находит купон по коду и проверяет, что он действует и лимит не исчерпан
+	Coupon(code string) (models.Discount, error)
+}
+
+type discountService struct {
+	repo         repository.DiscountRepository
+	bookRepo     repository.BookRepository
+	cache        cache.RedisCache
+	baseCurrency string
+}
+
+func NewDiscountService(repo repository.DiscountRepository, bookRepo repository.BookRepository, cache *cache.RedisCache, baseCurrency string) DiscountService {
+	return &discountService{repo: repo, bookRepo: bookRepo, cache: *cache, baseCurrency: baseCurrency}
+}
+
+// discountPrice - цена книги со скидкой d в валюте книги; false - скидка на книгу не действует
+func discountPrice(d models.Discount, book models.Book) (money.Amount, bool) {
+	switch d.Target {
+	case models.DiscountTargetGenre:
+		if !strings.EqualFold(book.Genre, d.Value) {
+			return 0, false
+		}
+	case models.DiscountTargetAuthor:
+		if !hasAuthor(book.Author, d.Value) {
+			return 0, false
+		}
+	case models.DiscountTargetBook:
+		if d.Value != strconv.FormatUint(uint64(book.ID), 10) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	switch d.Kind {
+	case models.DiscountPercent:
+		// Округление до минимальной единицы, половина - в пользу покупателя
+		return book.Price - (book.Price*money.Amount(d.Percent)+50)/100, true
+	case models.DiscountFixed:
+		if d.Currency != book.Currency {
+			return 0, false
+		}
+		return max(book.Price-d.Amount, 0), true
+	}
+	return 0, false
+}
+
+func hasAuthor(authors, name string) bool {
+	for _, author := range splitAuthors(authors) {
+		if strings.EqualFold(author, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDiscountPrice снижает SalePrice книги до цены по скидке d, если та ниже
+func applyDiscountPrice(book *models.Book, d models.Discount) bool {
+	price, ok := discountPrice(d, *book)
+	if !ok || price >= book.Price || (book.SalePrice != nil && price >= *book.SalePrice) {
+		return false
+	}
+	book.SalePrice = &price
+	return true
+}
+
+func (s *discountService) validate(d *models.Discount, req DiscountRequest) error {
+	d.Name = strings.TrimSpace(req.Name)
+	if d.Name == "" || len([]rune(d.Name)) > maxDiscountName {
+		return fmt.Errorf("invalid name, must be 1 to %d characters", maxDiscountName)
+	}
+
+	d.Value = strings.TrimSpace(req.Value)
+	switch req.Target {
+	case models.DiscountTargetGenre, models.DiscountTargetAuthor:
+		if d.Value == "" {
+			return errors.New("value is required: genre or author name")
+		}
+	case models.DiscountTargetBook:
+		if _, err := strconv.ParseUint(d.Value, 10, 64); err != nil {
+			return errors.New("invalid value, must be a book ID")
+		}
+		if _, err := s.bookRepo.GetBookByID(d.Value); err != nil {
+			return errors.New("book not found")
+		}
+	default:
+		return errors.New("invalid target, must be 'genre', 'author' or 'book'")
+	}
+	d.Target = req.Target
+
+	d.Kind = req.Kind
+	d.Percent, d.Amount, d.Currency = 0, 0, ""
+	switch req.Kind {
+	case models.DiscountPercent:
+		if req.Percent < 1 || req.Percent > 100 {
+			return errors.New("invalid percent, must be between 1 and 100")
+		}
+		d.Percent = req.Percent
+	case models.DiscountFixed:
+		currency := req.Currency
+		if currency == "" {
+			currency = s.baseCurrency
+		}
+		amount, err := money.ParseIn(string(req.Amount), currency)
+		if err != nil || amount.Amount <= 0 {
+			return fmt.Errorf("invalid amount %q %s, must be positive", req.Amount, currency)
+		}
+		d.Amount, d.Currency = amount.Amount, amount.Currency
+	default:
+		return errors.New("invalid kind, must be 'percent' or 'fixed'")
+	}
+
+	if req.StartsAt != nil {
+		d.StartsAt = *req.StartsAt
+	} else if d.StartsAt.IsZero() {
+		d.StartsAt = time.Now()
+	}
+	d.EndsAt = req.EndsAt
+	if d.EndsAt != nil && !d.EndsAt.After(d.StartsAt) {
+		return errors.New("invalid period: ends_at must be after starts_at")
+	}
+
+	d.Code = nil
+	if code := strings.ToUpper(strings.TrimSpace(req.Code)); code != "" {
+		if !couponCodePattern.MatchString(code) {
+			return errors.New("invalid code, must be 3 to 32 letters, digits, '-' or '_'")
+		}
+		d.Code = &code
+	}
+	if req.UsageLimit < 0 || (req.UsageLimit > 0 && d.Code == nil) {
+		return errors.New("invalid usage_limit, must be non-negative and set only for coupons")
+	}
+	d.UsageLimit = req.UsageLimit
+	return nil
+}
+
+// invalidate сбрасывает кэш распродаж и книг: в них уже посчитаны цены со скидкой
+func (s *discountService) invalidate() {
+	s.cache.Delete(salesCacheKey)
+	s.cache.InvalidatePattern("books:*")
+	s.cache.InvalidatePattern("book:*")
+}
+
+func (s *discountService) CreateDiscount(req DiscountRequest) (models.Discount, error) {
+	var discount models.Discount
+	if err := s.validate(&discount, req); err != nil {
+		return models.Discount{}, err
+	}
+	if err := s.repo.CreateDiscount(&discount); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return models.Discount{}, errors.New("coupon code already exists")
+		}
+		return models.Discount{}, err
+	}
+	if discount.Code == nil {
+		s.invalidate()
+	}
+	return discount, nil
+}
+
+func (s *discountService) GetDiscount(id uint) (models.Discount, error) {
+	discount, err := s.repo.GetDiscount(id)
+	if err != nil {
+		return models.Discount{}, errors.New("discount not found")
+	}
+	return discount, nil
+}
+
+func (s *discountService) UpdateDiscount(id uint, req DiscountRequest) (models.Discount, error) {
+	discount, err := s.GetDiscount(id)
+	if err != nil {
+		return models.Discount{}, err
+	}
+	wasSale := discount.Code == nil
+	if err := s.validate(&discount, req); err != nil {
+		return models.Discount{}, err
+	}
+	if err := s.repo.UpdateDiscount(&discount); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return models.Discount{}, errors.New("coupon code already exists")
+		}
+		return models.Discount{}, err
+	}
+	if wasSale || discount.Code == nil {
+		s.invalidate()
+	}
+	return discount, nil
+}
+
+func (s *discountService) DeleteDiscount(id uint) error {
+	deleted, err := s.repo.DeleteDiscount(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("discount not found")
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *discountService) ListDiscounts(page, limit int) ([]models.Discount, int64, error) {
+	return s.repo.ListDiscounts(page, limit)
+}
+
+// sales - незакончившиеся распродажи. Кэш не зависит от времени: будущие распродажи в нём уже есть,
+// а закончившиеся отсекает ActiveAt
+func (s *discountService) sales() ([]models.Discount, error) {
+	var sales []models.Discount
+	if s.cache.Get(salesCacheKey, &sales) {
+		return sales, nil
+	}
+	sales, err := s.repo.ListSales(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(salesCacheKey, sales, 10*time.Minute)
+	return sales, nil
+}
+
+func (s *discountService) ApplySales(books []models.Book) (time.Time, error) {
+	sales, err := s.sales()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	var next time.Time
+	for _, sale := range sales {
+		if !sale.ActiveAt(now) {
+			if sale.StartsAt.After(now) && (next.IsZero() || sale.StartsAt.Before(next)) {
+				next = sale.StartsAt
+			}
+			continue
+		}
+		if sale.EndsAt != nil && (next.IsZero() || sale.EndsAt.Before(next)) {
+			next = *sale.EndsAt
+		}
+		for i := range books {
+			if applyDiscountPrice(&books[i], sale) {
+				books[i].SaleEndsAt = sale.EndsAt
+			}
+		}
+	}
+	return next, nil
+}
+
+func (s *discountService) Coupon(code string) (models.Discount, error) {
+	coupon, err := s.repo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Discount{}, errors.New("coupon not found")
+	}
+	if err != nil {
+		return models.Discount{}, err
+	}
+	if !coupon.ActiveAt(time.Now()) {
+		return models.Discount{}, errors.New("invalid coupon: it has expired or is not active yet")
+	}
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return models.Discount{}, errors.New("coupon unavailable: usage limit reached")
+	}
+	return coupon, nil
+}
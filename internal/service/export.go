@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/marc"
+	"bookshelf/pkg/money"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatMARC    = "marc"
+	ExportFormatMARCXML = "marcxml"
+
+	exportFlushEvery = 1000
+)
+
+type BookExportRecord struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	Genre       string    `json:"genre"`
+	Description string    `json:"description"`
+	Price       string    `json:"price"`
+	Currency    string    `json:"currency"`
+	ISBN        string    `json:"isbn"`
+	Publisher   string    `json:"publisher"`
+	Subjects    []string  `json:"subjects"`
+	Pages       int       `json:"pages"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ExportService interface {
+	// ExportBooks пишет весь каталог (с фильтром по жанру) в w и возвращает число выгруженных книг
+	ExportBooks(w io.Writer, format, genre string) (int, error)
+}
+
+type exportService struct {
+	repo repository.BookRepository
+}
+
+func NewExportService(repo repository.BookRepository) ExportService {
+	return &exportService{repo: repo}
+}
+
+func IsExportFormat(format string) bool {
+	switch format {
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatMARC, ExportFormatMARCXML:
+		return true
+	}
+	return false
+}
+
+func (s *exportService) ExportBooks(w io.Writer, format, genre string) (int, error) {
+	switch format {
+	case ExportFormatCSV:
+		return s.exportCSV(w, genre)
+	case ExportFormatNDJSON:
+		return s.exportNDJSON(w, genre)
+	case ExportFormatMARC:
+		return s.exportMARC(w, genre)
+	case ExportFormatMARCXML:
+		return s.exportMARCXML(w, genre)
+	}
+	return 0, errors.New("invalid export format, must be 'csv', 'ndjson', 'marc' or 'marcxml'")
+}
+
+func (s *exportService) exportCSV(w io.Writer, genre string) (int, error) {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"id", "title", "author", "genre", "description", "price", "currency", "isbn",
+		"publisher", "subjects", "pages", "created_at", "updated_at",
+	}
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.repo.StreamBooks(genre, func(book models.Book) error {
+		record := toBookExportRecord(book)
+		err := writer.Write([]string{
+			strconv.FormatUint(uint64(record.ID), 10),
+			record.Title,
+			record.Author,
+			record.Genre,
+			record.Description,
+			record.Price,
+			record.Currency,
+			record.ISBN,
+			record.Publisher,
+			strings.Join(record.Subjects, csvSubjectSeparator+" "),
+			strconv.Itoa(record.Pages),
+			record.CreatedAt.Format(time.RFC3339),
+			record.UpdatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+func (s *exportService) exportNDJSON(w io.Writer, genre string) (int, error) {
+	encoder := json.NewEncoder(w)
+
+	count := 0
+	err := s.repo.StreamBooks(genre, func(book models.Book) error {
+		if err := encoder.Encode(toBookExportRecord(book)); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func (s *exportService) exportMARC(w io.Writer, genre string) (int, error) {
+	count := 0
+	err := s.repo.StreamBooks(genre, func(book models.Book) error {
+		if err := marc.WriteBinary(w, BookToMARC(book)); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func (s *exportService) exportMARCXML(w io.Writer, genre string) (int, error) {
+	writer := marc.NewXMLWriter(w)
+
+	count := 0
+	err := s.repo.StreamBooks(genre, func(book models.Book) error {
+		if err := writer.Write(BookToMARC(book)); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
+
+func toBookExportRecord(book models.Book) BookExportRecord {
+	return BookExportRecord{
+		ID:          book.ID,
+		Title:       book.Title,
+		Author:      book.Author,
+		Genre:       book.Genre,
+		Description: book.Description,
+		Price:       money.New(book.Price, book.Currency).Decimal(),
+		Currency:    book.Currency,
+		ISBN:        book.ISBN,
+		Publisher:   book.Publisher,
+		Subjects:    book.Subjects,
+		Pages:       book.Pages,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+	}
+}
@@ -8,16 +8,29 @@ type FavouriteService interface {
 	GetFavourites(userID uint, page, limit int) ([]BookBrief, int64, error)
 }
 
+// FavouriteListener узнаёт о добавлении книг в избранное; повторное добавление не сообщается
+type FavouriteListener interface {
+	FavouriteAdded(userID, bookID uint)
+}
+
 type favouriteService struct {
-	repo repository.FavouriteRepository
+	repo      repository.FavouriteRepository
+	listeners []FavouriteListener
 }
 
-func NewFavouriteService(repo repository.FavouriteRepository) FavouriteService {
-	return &favouriteService{repo: repo}
+func NewFavouriteService(repo repository.FavouriteRepository, listeners ...FavouriteListener) FavouriteService {
+	return &favouriteService{repo: repo, listeners: listeners}
 }
 
 func (s *favouriteService) AddFavourite(userID, bookID uint) error {
-	return s.repo.AddFavourite(userID, bookID)
+	added, err := s.repo.AddFavourite(userID, bookID)
+	if err != nil || !added {
+		return err
+	}
+	for _, l := range s.listeners {
+		l.FavouriteAdded(userID, bookID)
+	}
+	return nil
 }
 
 func (s *favouriteService) RemoveFavourite(userID, bookID uint) error {
@@ -30,15 +43,5 @@ func (s *favouriteService) GetFavourites(userID uint, page, limit int) ([]BookBr
 		return nil, 0, err
 	}
 
-	briefs := make([]BookBrief, len(books))
-	for i, book := range books {
-		briefs[i] = BookBrief{
-			ID:     book.ID,
-			Title:  book.Title,
-			Author: book.Author,
-			Genre:  book.Genre,
-			Price:  book.Price,
-		}
-	}
-	return briefs, total, nil
+	return toBookBriefs(books), total, nil
 }
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/epub"
+	"bookshelf/pkg/kosync"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/storage"
+	"bookshelf/pkg/textutil"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pdfContentType = "application/pdf"
+	maxCoverSize   = 5 << 20
+	// minTitleSimilarity - ниже этой похожести название в файле считается другим
+	minTitleSimilarity = 0.8
+)
+
+// FileUpload - загруженный файл, метаданные EPUB и их сверка с карточкой книги
+type FileUpload struct {
+	File models.BookFile
+	// Cover - обложка, извлечённая из EPUB
+	Cover    *models.BookFile
+	Metadata *epub.Metadata
+	// Prefilled - пустые поля книги, заполненные из файла
+	Prefilled []string
+	// Mismatches - расхождения файла с карточкой; карточка при этом не меняется
+	Mismatches []string
+}
+
+type FileService interface {
+	// Upload сохраняет EPUB, PDF или изображение обложки; формат определяется по содержимому
+	Upload(ctx context.Context, bookID uint, filename string, data []byte) (FileUpload, error)
+	ListFiles(bookID uint) ([]models.BookFile, error)
+	// FilesByBooks - файлы нескольких книг разом, для лент каталога
+	FilesByBooks(bookIDs []uint) (map[uint][]models.BookFile, error)
+	DeleteFile(ctx context.Context, bookID uint, format string) error
+	// DownloadURL выдаёт короткоживущую подписанную ссылку; скачать книгу может покупатель или администратор
+	DownloadURL(userID uint, role string, bookID uint, format string) (string, time.Time, error)
+	// OpenSigned открывает файл по подписанной ссылке. Object нужно закрыть
+	OpenSigned(ctx context.Context, fileID uint, expires int64, signature string) (models.BookFile, *storage.Object, error)
+	OpenCover(ctx context.Context, bookID uint) (models.BookFile, *storage.Object, error)
+}
+
+type fileService struct {
+	repo    repository.FileRepository
+	books   BookService
+	store   storage.Storage
+	signer  *storage.Signer
+	linkTTL time.Duration
+}
+
+func NewFileService(repo repository.FileRepository, books BookService, store storage.Storage, signer *storage.Signer, linkTTL time.Duration) FileService {
+	return &fileService{repo: repo, books: books, store: store, signer: signer, linkTTL: linkTTL}
+}
+
+// detectFormat определяет формат по сигнатуре файла, расширению не доверяем
+func detectFormat(data []byte) (string, string, error) {
+	switch {
+	case epub.IsEPUB(data):
+		return models.FileEPUB, epub.MimeType, nil
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return models.FilePDF, pdfContentType, nil
+	}
+	if contentType, ok := coverContentType(data); ok {
+		if len(data) > maxCoverSize {
+			return "", "", fmt.Errorf("invalid cover, must be at most %d MB", maxCoverSize>>20)
+		}
+		return models.FileCover, contentType, nil
+	}
+	return "", "", errors.New("invalid file, must be EPUB, PDF or a cover image")
+}
+
+// coverContentType определяет тип обложки по содержимому. Разрешены только растровые форматы:
+// SVG и прочее, что браузер может исполнить, обложкой не считается
+func coverContentType(data []byte) (string, bool) {
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return contentType, true
+	}
+	return "", false
+}
+
+func (s *fileService) Upload(ctx context.Context, bookID uint, filename string, data []byte) (FileUpload, error) {
+	if len(data) == 0 {
+		return FileUpload{}, errors.New("file is required")
+	}
+	format, contentType, err := detectFormat(data)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	book, err := s.books.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return FileUpload{}, errors.New("book not found")
+	}
+
+	var upload FileUpload
+	if format == models.FileEPUB {
+		meta, err := epub.Parse(data)
+		if err != nil {
+			return FileUpload{}, errors.New("invalid EPUB: container.xml or OPF package is missing or malformed")
+		}
+		upload.Metadata = &meta
+	}
+
+	if filename == "" {
+		filename = fmt.Sprintf("book-%d.%s", bookID, format)
+	}
+	if upload.File, err = s.save(ctx, bookID, format, filename, contentType, data); err != nil {
+		return FileUpload{}, err
+	}
+	if upload.Metadata == nil {
+		return upload, nil
+	}
+
+	meta := upload.Metadata
+	// media-type из OPF задаёт автор файла, поэтому тип обложки определяется по её байтам
+	if coverType, ok := coverContentType(meta.Cover); ok && len(meta.Cover) <= maxCoverSize {
+		cover, err := s.save(ctx, bookID, models.FileCover, "cover", coverType, meta.Cover)
+		if err != nil {
+			return FileUpload{}, err
+		}
+		upload.Cover = &cover
+	}
+	upload.Mismatches = compareMetadata(book, *meta)
+	if upload.Prefilled, err = s.prefill(book, *meta); err != nil {
+		return FileUpload{}, err
+	}
+	return upload, nil
+}
+
+// save кладёт файл в хранилище под ключом с хэшем и только потом переключает на него запись в БД,
+// после чего удаляет прежний файл того же формата
+func (s *fileService) save(ctx context.Context, bookID uint, format, filename, contentType string, data []byte) (models.BookFile, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	key := fmt.Sprintf("books/%d/%s-%s", bookID, format, digest[:16])
+	if err := s.store.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return models.BookFile{}, err
+	}
+
+	file := models.BookFile{
+		BookID:      bookID,
+		Format:      format,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      digest,
+		StorageKey:  key,
+	}
+	if format != models.FileCover {
+		file.KOReaderDigest = kosync.PartialMD5(data)
+	}
+	previousKey, err := s.repo.ReplaceFile(&file)
+	if err != nil {
+		s.discard(ctx, file)
+		if repository.IsDuplicateKey(err) {
+			return models.BookFile{}, errors.New("file unavailable: another upload for this book is in progress")
+		}
+		return models.BookFile{}, err
+	}
+	if previousKey != "" && previousKey != key {
+		if err := s.store.Delete(ctx, previousKey); err != nil {
+			log.Printf("files: delete replaced %s: %v", previousKey, err)
+		}
+	}
+	return file, nil
+}
+
+// discard удаляет файл, который не удалось записать в БД. Тот же ключ может уже принадлежать записи:
+// повторная загрузка того же содержимого или параллельная загрузка, успевшая сохраниться, - такой файл остаётся
+func (s *fileService) discard(ctx context.Context, file models.BookFile) {
+	current, err := s.repo.GetBookFile(file.BookID, file.Format)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("files: keep %s, cannot check its owner: %v", file.StorageKey, err)
+		return
+	}
+	if err == nil && current.StorageKey == file.StorageKey {
+		return
+	}
+	if err := s.store.Delete(ctx, file.StorageKey); err != nil {
+		log.Printf("files: delete orphaned %s: %v", file.StorageKey, err)
+	}
+}
+
+func compareMetadata(book models.Book, meta epub.Metadata) []string {
+	var mismatches []string
+	if meta.Title != "" && textutil.Similarity(textutil.Normalize(meta.Title), textutil.Normalize(book.Title)) < minTitleSimilarity {
+		mismatches = append(mismatches, fmt.Sprintf("title: «%s» in file, «%s» in catalog", meta.Title, book.Title))
+	}
+	if len(meta.Authors) > 0 && !sharesAuthor(book.Author, meta.Authors) {
+		mismatches = append(mismatches, fmt.Sprintf("author: «%s» in file, «%s» in catalog", strings.Join(meta.Authors, ", "), book.Author))
+	}
+	if meta.ISBN != "" && book.ISBN != "" && textutil.NormalizeISBN(meta.ISBN) != book.ISBN {
+		mismatches = append(mismatches, fmt.Sprintf("isbn: %s in file, %s in catalog", meta.ISBN, book.ISBN))
+	}
+	return mismatches
+}
+
+func sharesAuthor(catalog string, authors []string) bool {
+	for _, author := range authors {
+		for _, name := range splitAuthors(catalog) {
+			if textutil.Normalize(author) == textutil.Normalize(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prefill заполняет из файла только пустые поля книги: заполненные поля проверяет compareMetadata
+func (s *fileService) prefill(book models.Book, meta epub.Metadata) ([]string, error) {
+	req := BookRequest{
+		Title:       book.Title,
+		Author:      book.Author,
+		Genre:       book.Genre,
+		Description: book.Description,
+		Price:       money.Decimal(money.New(book.Price, book.Currency).Decimal()),
+		Currency:    book.Currency,
+		ISBN:        book.ISBN,
+		Publisher:   book.Publisher,
+		Subjects:    book.Subjects,
+		Pages:       book.Pages,
+	}
+	var prefilled []string
+	if req.ISBN == "" && meta.ISBN != "" {
+		req.ISBN = meta.ISBN
+		prefilled = append(prefilled, "isbn")
+	}
+	if req.Publisher == "" && meta.Publisher != "" {
+		req.Publisher = meta.Publisher
+		prefilled = append(prefilled, "publisher")
+	}
+	if len(req.Subjects) == 0 && len(meta.Subjects) > 0 {
+		req.Subjects = meta.Subjects
+		prefilled = append(prefilled, "subjects")
+	}
+	if len(prefilled) == 0 {
+		return nil, nil
+	}
+	if _, err := s.books.UpdateBook(strconv.FormatUint(uint64(book.ID), 10), req); err != nil {
+		return nil, err
+	}
+	return prefilled, nil
+}
+
+func (s *fileService) ListFiles(bookID uint) ([]models.BookFile, error) {
+	return s.repo.ListFiles(bookID)
+}
+
+func (s *fileService) FilesByBooks(bookIDs []uint) (map[uint][]models.BookFile, error) {
+	files, err := s.repo.ListFilesByBooks(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	byBook := make(map[uint][]models.BookFile, len(bookIDs))
+	for _, file := range files {
+		byBook[file.BookID] = append(byBook[file.BookID], file)
+	}
+	return byBook, nil
+}
+
+func (s *fileService) getBookFile(bookID uint, format string) (models.BookFile, error) {
+	file, err := s.repo.GetBookFile(bookID, strings.ToLower(format))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.BookFile{}, errors.New("file not found")
+	}
+	return file, err
+}
+
+func (s *fileService) DeleteFile(ctx context.Context, bookID uint, format string) error {
+	file, err := s.getBookFile(bookID, format)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteFile(file.ID); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, file.StorageKey)
+}
+
+// signedResource привязывает подпись к содержимому: после замены файла старые ссылки не действуют
+func signedResource(file models.BookFile) string {
+	return fmt.Sprintf("files/%d/%s", file.ID, file.SHA256)
+}
+
+func (s *fileService) DownloadURL(userID uint, role string, bookID uint, format string) (string, time.Time, error) {
+	if format == models.FileCover {
+		return "", time.Time{}, errors.New("invalid format, the cover is public")
+	}
+	file, err := s.getBookFile(bookID, format)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if role != "admin" {
+		purchased, err := s.repo.HasPurchased(userID, bookID)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if !purchased {
+			return "", time.Time{}, errors.New("access denied: buy the book to download it")
+		}
+	}
+
+	expires := time.Now().Add(s.linkTTL).Truncate(time.Second)
+	signature := s.signer.Sign(signedResource(file), expires)
+	return fmt.Sprintf("/files/%d?expires=%d&signature=%s", file.ID, expires.Unix(), signature), expires, nil
+}
+
+func (s *fileService) OpenSigned(ctx context.Context, fileID uint, expires int64, signature string) (models.BookFile, *storage.Object, error) {
+	file, err := s.repo.GetFile(fileID)
+	if err != nil {
+		return models.BookFile{}, nil, errors.New("file not found")
+	}
+	if !s.signer.Verify(signedResource(file), expires, signature, time.Now()) {
+		return models.BookFile{}, nil, errors.New("access denied: download link is invalid or expired")
+	}
+	return s.open(ctx, file)
+}
+
+func (s *fileService) OpenCover(ctx context.Context, bookID uint) (models.BookFile, *storage.Object, error) {
+	file, err := s.getBookFile(bookID, models.FileCover)
+	if err != nil {
+		return models.BookFile{}, nil, err
+	}
+	return s.open(ctx, file)
+}
+
+func (s *fileService) open(ctx context.Context, file models.BookFile) (models.BookFile, *storage.Object, error) {
+	object, err := s.store.Open(ctx, file.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return models.BookFile{}, nil, errors.New("file not found in storage")
+	}
+	if err != nil {
+		return models.BookFile{}, nil, err
+	}
+	return file, object, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const maxFineNote = 500
+
+// FinePolicy - правила штрафов и напоминаний. Суммы в минимальных единицах валюты Currency
+type FinePolicy struct {
+	// Currency - валюта штрафов, базовая валюта каталога
+	Currency string
+	// Rates - штраф за полный день просрочки по ролям; роль без записи не штрафуется
+	Rates map[string]money.Amount
+	// MaxPerLoan - потолок штрафа за одну выдачу, 0 - без потолка
+	MaxPerLoan money.Amount
+	// RemindBefore - за сколько до срока напомнить о возврате
+	RemindBefore time.Duration
+	// RemindEvery - как часто повторять напоминание о просрочке
+	RemindEvery time.Duration
+}
+
+// ParseFineRates разбирает штрафы за день в валюте currency вида "user=0.50,moderator=0"
+func ParseFineRates(raw, currency string) (map[string]money.Amount, error) {
+	rates := make(map[string]money.Amount)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		role, value, ok := strings.Cut(part, "=")
+		rate, err := money.ParseIn(strings.TrimSpace(value), currency)
+		if !ok || err != nil || rate.Amount < 0 {
+			return nil, fmt.Errorf("invalid fine rate %q, must be role=amount in %s", part, currency)
+		}
+		rates[strings.TrimSpace(role)] = rate.Amount
+	}
+	return rates, nil
+}
+
+// FineAdjustmentRequest: оплата (payment) или списание (waiver) долга; amount - положительная сумма
+// в валюте штрафов
+type FineAdjustmentRequest struct {
+	Type   string        `json:"type" example:"payment"`
+	Amount money.Decimal `json:"amount" swaggertype:"string" example:"1.50"`
+	Note   string        `json:"note" example:"Оплата наличными на стойке"`
+}
+
+type FineService interface {
+	// ProcessOverdue начисляет штрафы за просрочку и рассылает напоминания; вызывается планировщиком.
+	// Повторный запуск ничего не начисляет дважды
+	ProcessOverdue(ctx context.Context) error
+	// Currency - валюта штрафов и долгов
+	Currency() string
+	// GetAccount возвращает долг пользователя и страницу журнала
+	GetAccount(userID uint, page, limit int) (money.Amount, []models.FineEntry, int64, error)
+	ListBalances(page, limit int) ([]repository.FineBalance, int64, error)
+	AddEntry(adminID, userID uint, req FineAdjustmentRequest) (models.FineEntry, error)
+}
+
+type fineService struct {
+	repo     repository.FineRepository
+	notifier Notifier
+	policy   FinePolicy
+}
+
+func NewFineService(repo repository.FineRepository, notifier Notifier, policy FinePolicy) FineService {
+	return &fineService{repo: repo, notifier: notifier, policy: policy}
+}
+
+func (s *fineService) ProcessOverdue(ctx context.Context) error {
+	now := time.Now()
+	if err := s.accrue(now); err != nil {
+		return err
+	}
+	if err := s.remindDueSoon(now); err != nil {
+		return err
+	}
+	return s.remindOverdue(now)
+}
+
+func (s *fineService) Currency() string {
+	return s.policy.Currency
+}
+
+// capped - штраф за days дней с учётом потолка на выдачу
+func (s *fineService) capped(days int, rate money.Amount) money.Amount {
+	amount := rate.Mul(days)
+	if s.policy.MaxPerLoan > 0 && amount > s.policy.MaxPerLoan {
+		return s.policy.MaxPerLoan
+	}
+	return amount
+}
+
+// accrue дописывает в журнал штраф за дни просрочки, которые ещё не начислены. Для возвращённой
+// выдачи просрочка считается до возврата, поэтому дни после последнего запуска не теряются
+func (s *fineService) accrue(now time.Time) error {
+	loans, err := s.repo.ListUnfinedLoans(now)
+	if err != nil {
+		return err
+	}
+	roles, err := s.repo.GetRoles(loanUsers(loans))
+	if err != nil {
+		return err
+	}
+
+	charged := 0
+	for _, loan := range loans {
+		end := now
+		if loan.ReturnedAt != nil {
+			end = *loan.ReturnedAt
+		}
+		days := int(end.Sub(loan.DueAt) / (24 * time.Hour))
+		rate := s.policy.Rates[roles[loan.UserID]]
+		amount := s.capped(days, rate) - s.capped(loan.FinedDays, rate)
+
+		var entry *models.FineEntry
+		if amount > 0 {
+			loanID := loan.ID
+			entry = &models.FineEntry{
+				UserID:   loan.UserID,
+				Type:     models.FineCharge,
+				Amount:   amount,
+				Currency: s.policy.Currency,
+				LoanID:   &loanID,
+				Note:     fmt.Sprintf("Просрочка «%s»: дни %d-%d", loan.Book.Title, loan.FinedDays+1, days),
+			}
+		}
+		ok, err := s.repo.ChargeLoan(loan.ID, loan.FinedDays, days, entry)
+		if err != nil {
+			return err
+		}
+		if ok && entry != nil {
+			charged++
+		}
+	}
+	if charged > 0 {
+		log.Printf("fines: %d overdue charges recorded", charged)
+	}
+	return nil
+}
+
+func loanUsers(loans []models.Loan) []uint {
+	seen := make(map[uint]bool)
+	ids := make([]uint, 0, len(loans))
+	for _, loan := range loans {
+		if !seen[loan.UserID] {
+			seen[loan.UserID] = true
+			ids = append(ids, loan.UserID)
+		}
+	}
+	return ids
+}
+
+func (s *fineService) remindDueSoon(now time.Time) error {
+	loans, err := s.repo.ListDueSoon(now, now.Add(s.policy.RemindBefore))
+	if err != nil {
+		return err
+	}
+	items := make([]models.Notification, 0, len(loans))
+	ids := make([]uint, 0, len(loans))
+	for _, loan := range loans {
+		items = append(items, models.Notification{
+			UserID: loan.UserID,
+			Type:   models.NotificationLoanDue,
+			BookID: loan.BookID,
+			Title:  fmt.Sprintf("Скоро вернуть: «%s»", loan.Book.Title),
+			Body:   fmt.Sprintf("Книгу «%s» нужно вернуть до %s. Если её никто не ждёт, выдачу можно продлить", loan.Book.Title, loan.DueAt.Format("02.01.2006 15:04")),
+		})
+		ids = append(ids, loan.ID)
+	}
+	if err := s.notifier.Notify(items); err != nil {
+		return err
+	}
+	return s.repo.MarkReminded(ids, false, now)
+}
+
+func (s *fineService) remindOverdue(now time.Time) error {
+	loans, err := s.repo.ListOverdueToRemind(now, now.Add(-s.policy.RemindEvery))
+	if err != nil {
+		return err
+	}
+	roles, err := s.repo.GetRoles(loanUsers(loans))
+	if err != nil {
+		return err
+	}
+
+	items := make([]models.Notification, 0, len(loans))
+	ids := make([]uint, 0, len(loans))
+	for _, loan := range loans {
+		body := fmt.Sprintf("Срок возврата книги «%s» истёк %s, верните её в библиотеку", loan.Book.Title, loan.DueAt.Format("02.01.2006"))
+		if rate := s.policy.Rates[roles[loan.UserID]]; rate > 0 {
+			body += fmt.Sprintf(". Штраф - %s за каждый день просрочки", money.New(rate, s.policy.Currency))
+		}
+		items = append(items, models.Notification{
+			UserID: loan.UserID,
+			Type:   models.NotificationLoanOverdue,
+			BookID: loan.BookID,
+			Title:  fmt.Sprintf("Просрочен возврат: «%s»", loan.Book.Title),
+			Body:   body,
+		})
+		ids = append(ids, loan.ID)
+	}
+	if err := s.notifier.Notify(items); err != nil {
+		return err
+	}
+	return s.repo.MarkReminded(ids, true, now)
+}
+
+func (s *fineService) GetAccount(userID uint, page, limit int) (money.Amount, []models.FineEntry, int64, error) {
+	balance, err := s.repo.GetBalance(userID)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	entries, total, err := s.repo.ListEntries(userID, page, limit)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	return balance, entries, total, nil
+}
+
+func (s *fineService) ListBalances(page, limit int) ([]repository.FineBalance, int64, error) {
+	return s.repo.ListBalances(page, limit)
+}
+
+// AddEntry записывает оплату или списание. Начисления делает только ProcessOverdue
+func (s *fineService) AddEntry(adminID, userID uint, req FineAdjustmentRequest) (models.FineEntry, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	if req.Type != models.FinePayment && req.Type != models.FineWaiver {
+		return models.FineEntry{}, errors.New("invalid type, must be 'payment' or 'waiver'")
+	}
+	amount, err := money.ParseIn(string(req.Amount), s.policy.Currency)
+	if err != nil || amount.Amount <= 0 {
+		return models.FineEntry{}, fmt.Errorf("invalid amount, must be a positive sum in %s", s.policy.Currency)
+	}
+	if utf8.RuneCountInString(req.Note) > maxFineNote {
+		return models.FineEntry{}, errors.New("invalid note, too long")
+	}
+
+	entry := models.FineEntry{
+		UserID:    userID,
+		Type:      req.Type,
+		Amount:    -amount.Amount,
+		Currency:  amount.Currency,
+		Note:      req.Note,
+		CreatedBy: &adminID,
+	}
+	settled, err := s.repo.Settle(&entry)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.FineEntry{}, errors.New("user not found")
+		}
+		return models.FineEntry{}, err
+	}
+	if !settled {
+		return models.FineEntry{}, errors.New("invalid amount, exceeds outstanding balance")
+	}
+	return entry, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFineRepo хранит выдачи и журнал в памяти и повторяет условия запросов fineRepo
+type fakeFineRepo struct {
+	repository.FineRepository
+	loans   []models.Loan
+	entries []models.FineEntry
+}
+
+func overdueDays(loan models.Loan, now time.Time) int {
+	end := now
+	if loan.ReturnedAt != nil {
+		end = *loan.ReturnedAt
+	}
+	return int(end.Sub(loan.DueAt) / (24 * time.Hour))
+}
+
+func (r *fakeFineRepo) ListUnfinedLoans(now time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	for _, loan := range r.loans {
+		if overdueDays(loan, now) > loan.FinedDays {
+			loans = append(loans, loan)
+		}
+	}
+	return loans, nil
+}
+
+func (r *fakeFineRepo) ChargeLoan(loanID uint, prevDays, days int, entry *models.FineEntry) (bool, error) {
+	for i := range r.loans {
+		if r.loans[i].ID != loanID || r.loans[i].FinedDays != prevDays {
+			continue
+		}
+		r.loans[i].FinedDays = days
+		if entry != nil {
+			r.entries = append(r.entries, *entry)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *fakeFineRepo) GetRoles(userIDs []uint) (map[uint]string, error) {
+	roles := make(map[uint]string, len(userIDs))
+	for _, id := range userIDs {
+		roles[id] = "user"
+	}
+	return roles, nil
+}
+
+func (r *fakeFineRepo) charged() money.Amount {
+	var total money.Amount
+	for _, entry := range r.entries {
+		total += entry.Amount
+	}
+	return total
+}
+
+func newFineTestService(repo *fakeFineRepo, maxPerLoan money.Amount) *fineService {
+	return &fineService{repo: repo, policy: FinePolicy{
+		Currency:   "USD",
+		Rates:      map[string]money.Amount{"user": 50},
+		MaxPerLoan: maxPerLoan,
+	}}
+}
+
+func TestFineService_Accrue_RerunDoesNotChargeTwice(t *testing.T) {
+	now := time.Now()
+	repo := &fakeFineRepo{loans: []models.Loan{
+		{ID: 1, UserID: 2, Book: models.Book{Title: "Dune"}, DueAt: now.Add(-3*24*time.Hour - time.Hour)},
+	}}
+	s := newFineTestService(repo, 0)
+
+	assert.NoError(t, s.accrue(now))
+	assert.NoError(t, s.accrue(now.Add(time.Hour)))
+
+	// Проверки
+	assert.Len(t, repo.entries, 1)
+	assert.Equal(t, money.Amount(150), repo.entries[0].Amount)
+	assert.Equal(t, "USD", repo.entries[0].Currency)
+
+	// Следующий полный день начисляется отдельной записью
+	assert.NoError(t, s.accrue(now.Add(24*time.Hour)))
+	assert.Len(t, repo.entries, 2)
+	assert.Equal(t, money.Amount(50), repo.entries[1].Amount)
+	assert.Equal(t, money.Amount(200), repo.charged())
+}
+
+func TestFineService_Accrue_CapHoldsAcrossRuns(t *testing.T) {
+	now := time.Now()
+	repo := &fakeFineRepo{loans: []models.Loan{
+		{ID: 1, UserID: 2, Book: models.Book{Title: "Dune"}, DueAt: now.Add(-3*24*time.Hour - time.Hour)},
+	}}
+	s := newFineTestService(repo, 200)
+
+	for _, at := range []time.Time{now, now.Add(2 * 24 * time.Hour), now.Add(5 * 24 * time.Hour), now.Add(30 * 24 * time.Hour)} {
+		assert.NoError(t, s.accrue(at))
+	}
+
+	// Проверки
+	assert.Equal(t, money.Amount(200), repo.charged())
+	assert.Len(t, repo.entries, 2)
+	assert.Equal(t, 33, repo.loans[0].FinedDays)
+}
+
+func TestFineService_Accrue_ReturnedLoanChargedUntilReturn(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-10 * 24 * time.Hour)
+	returned := due.Add(2*24*time.Hour + time.Hour)
+	repo := &fakeFineRepo{loans: []models.Loan{
+		{ID: 1, UserID: 2, Book: models.Book{Title: "Dune"}, DueAt: due, ReturnedAt: &returned},
+	}}
+	s := newFineTestService(repo, 0)
+
+	assert.NoError(t, s.accrue(now))
+	assert.NoError(t, s.accrue(now.Add(3*24*time.Hour)))
+
+	// Проверки
+	assert.Len(t, repo.entries, 1)
+	assert.Equal(t, money.Amount(100), repo.entries[0].Amount)
+	assert.Equal(t, 2, repo.loans[0].FinedDays)
+}
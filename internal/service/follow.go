@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/textutil"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxFollowTarget = 200
+
+type FollowRequest struct {
+	Type   string `json:"type" example:"author"`
+	Target string `json:"target" example:"Ursula K. Le Guin"`
+}
+
+type FollowService interface {
+	Follow(userID uint, req FollowRequest) (models.Follow, error)
+	Unfollow(userID, id uint) error
+	ListFollows(userID uint, targetType string) ([]models.Follow, error)
+}
+
+type followService struct {
+	repo     repository.FollowRepository
+	authRepo repository.AuthRepository
+}
+
+func NewFollowService(repo repository.FollowRepository, authRepo repository.AuthRepository) FollowService {
+	return &followService{repo: repo, authRepo: authRepo}
+}
+
+func isFollowType(targetType string) bool {
+	switch targetType {
+	case models.FollowAuthor, models.FollowGenre, models.FollowUser:
+		return true
+	}
+	return false
+}
+
+func (s *followService) Follow(userID uint, req FollowRequest) (models.Follow, error) {
+	if !isFollowType(req.Type) {
+		return models.Follow{}, errors.New("invalid type, must be 'author', 'genre' or 'user'")
+	}
+	target := strings.TrimSpace(req.Target)
+	key := textutil.Normalize(target)
+	if key == "" {
+		return models.Follow{}, errors.New("target is required")
+	}
+	if utf8.RuneCountInString(target) > maxFollowTarget {
+		return models.Follow{}, errors.New("invalid target, too long")
+	}
+	// На пользователя подписываются по имени, а храним ID: имя можно сменить
+	if req.Type == models.FollowUser {
+		user, err := s.authRepo.GetUserByUsername(target)
+		if err != nil {
+			return models.Follow{}, errors.New("user not found")
+		}
+		if user.ID == userID {
+			return models.Follow{}, errors.New("invalid target: cannot follow yourself")
+		}
+		key = strconv.FormatUint(uint64(user.ID), 10)
+		target = user.Username
+	}
+
+	follow := models.Follow{UserID: userID, TargetType: req.Type, TargetKey: key, Target: target}
+	created, err := s.repo.CreateFollow(&follow)
+	if err != nil {
+		return models.Follow{}, err
+	}
+	if !created {
+		return models.Follow{}, errors.New("follow already exists")
+	}
+	return follow, nil
+}
+
+func (s *followService) Unfollow(userID, id uint) error {
+	deleted, err := s.repo.DeleteFollow(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("follow not found")
+	}
+	return nil
+}
+
+func (s *followService) ListFollows(userID uint, targetType string) ([]models.Follow, error) {
+	if targetType != "" && !isFollowType(targetType) {
+		return nil, errors.New("invalid type, must be 'author', 'genre' or 'user'")
+	}
+	return s.repo.ListFollows(userID, targetType)
+}
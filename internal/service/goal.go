@@ -0,0 +1,299 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxGoalTarget     = 100000
+	maxGoalTitle      = 200
+	maxGoalYears      = 5
+	topGenresInReview = 5
+	dateLayout        = "2006-01-02"
+)
+
+// GoalRequest: цель ставится на календарный год (year) или на произвольный период (start_date и end_date включительно)
+type GoalRequest struct {
+	Title     string `json:"title" example:"30 книг за 2026"`
+	Metric    string `json:"metric" example:"books"`
+	Target    int    `json:"target" example:"30"`
+	Year      int    `json:"year" example:"2026"`
+	StartDate string `json:"start_date" example:"2026-06-01"`
+	EndDate   string `json:"end_date" example:"2026-08-31"`
+}
+
+type GoalProgress struct {
+	Goal    models.ReadingGoal
+	Current int
+	// Expected - сколько нужно было прочитать к сегодняшнему дню при равномерном темпе
+	Expected  int
+	Percent   int
+	Completed bool
+	OnTrack   bool
+	DaysLeft  int
+}
+
+type YearInReview struct {
+	Year      int
+	BooksRead int
+	PagesRead int
+	TopGenres []repository.GenreCount
+	// LongestBook - nil, если за год не дочитано ни одной книги с известным объёмом
+	LongestBook      *BookBrief
+	LongestBookPages int
+	// AverageRating - nil, если пользователь не оценил ни одну из прочитанных за год книг
+	AverageRating *float64
+	RatedBooks    int
+	Goals         []GoalProgress
+}
+
+type GoalService interface {
+	CreateGoal(userID uint, req GoalRequest) (GoalProgress, error)
+	UpdateGoal(userID, id uint, req GoalRequest) (GoalProgress, error)
+	DeleteGoal(userID, id uint) error
+	GetGoal(userID, id uint) (GoalProgress, error)
+	ListGoals(userID uint) ([]GoalProgress, error)
+	GetYearInReview(userID uint, year int) (YearInReview, error)
+}
+
+type goalService struct {
+	repo        repository.GoalRepository
+	readingRepo repository.ReadingRepository
+	bookRepo    repository.BookRepository
+}
+
+func NewGoalService(repo repository.GoalRepository, readingRepo repository.ReadingRepository, bookRepo repository.BookRepository) GoalService {
+	return &goalService{repo: repo, readingRepo: readingRepo, bookRepo: bookRepo}
+}
+
+func yearStart(year int) time.Time {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// goalPeriod разбирает период запроса в даты начала и конца включительно
+func goalPeriod(req GoalRequest) (time.Time, time.Time, error) {
+	if req.Year != 0 {
+		if req.StartDate != "" || req.EndDate != "" {
+			return time.Time{}, time.Time{}, errors.New("invalid period: set either year or start_date and end_date")
+		}
+		if req.Year < 1900 || req.Year > 9999 {
+			return time.Time{}, time.Time{}, errors.New("invalid year")
+		}
+		start := yearStart(req.Year)
+		return start, start.AddDate(1, 0, -1), nil
+	}
+
+	if req.StartDate == "" || req.EndDate == "" {
+		return time.Time{}, time.Time{}, errors.New("year or start_date and end_date are required")
+	}
+	start, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start_date, must be YYYY-MM-DD")
+	}
+	end, err := time.Parse(dateLayout, req.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end_date, must be YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("invalid period: end_date is before start_date")
+	}
+	if end.After(start.AddDate(maxGoalYears, 0, 0)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period, must be at most %d years", maxGoalYears)
+	}
+	return start, end, nil
+}
+
+func applyGoal(goal *models.ReadingGoal, req GoalRequest) error {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Metric != models.GoalBooks && req.Metric != models.GoalPages {
+		return errors.New("invalid metric, must be 'books' or 'pages'")
+	}
+	if req.Target < 1 || req.Target > maxGoalTarget {
+		return fmt.Errorf("invalid target, must be between 1 and %d", maxGoalTarget)
+	}
+	if utf8.RuneCountInString(req.Title) > maxGoalTitle {
+		return errors.New("invalid title, too long")
+	}
+	start, end, err := goalPeriod(req)
+	if err != nil {
+		return err
+	}
+
+	if req.Title == "" {
+		req.Title = fmt.Sprintf("Цель на %s - %s", start.Format(dateLayout), end.Format(dateLayout))
+		if req.Year != 0 {
+			req.Title = fmt.Sprintf("Цель на %d год", req.Year)
+		}
+	}
+	goal.Title = req.Title
+	goal.Metric = req.Metric
+	goal.Target = req.Target
+	goal.StartDate = start
+	goal.EndDate = end
+	return nil
+}
+
+func (s *goalService) CreateGoal(userID uint, req GoalRequest) (GoalProgress, error) {
+	goal := models.ReadingGoal{UserID: userID}
+	if err := applyGoal(&goal, req); err != nil {
+		return GoalProgress{}, err
+	}
+	if err := s.repo.CreateGoal(&goal); err != nil {
+		return GoalProgress{}, err
+	}
+	return s.progress(goal, time.Now())
+}
+
+// getOwn: чужая цель выглядит как несуществующая
+func (s *goalService) getOwn(userID, id uint) (models.ReadingGoal, error) {
+	goal, err := s.repo.GetGoal(id)
+	if err != nil || goal.UserID != userID {
+		return models.ReadingGoal{}, errors.New("goal not found")
+	}
+	return goal, nil
+}
+
+func (s *goalService) UpdateGoal(userID, id uint, req GoalRequest) (GoalProgress, error) {
+	goal, err := s.getOwn(userID, id)
+	if err != nil {
+		return GoalProgress{}, err
+	}
+	if err := applyGoal(&goal, req); err != nil {
+		return GoalProgress{}, err
+	}
+	if err := s.repo.UpdateGoal(&goal); err != nil {
+		return GoalProgress{}, err
+	}
+	return s.progress(goal, time.Now())
+}
+
+func (s *goalService) DeleteGoal(userID, id uint) error {
+	deleted, err := s.repo.DeleteGoal(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("goal not found")
+	}
+	return nil
+}
+
+func (s *goalService) GetGoal(userID, id uint) (GoalProgress, error) {
+	goal, err := s.getOwn(userID, id)
+	if err != nil {
+		return GoalProgress{}, err
+	}
+	return s.progress(goal, time.Now())
+}
+
+func (s *goalService) ListGoals(userID uint) ([]GoalProgress, error) {
+	goals, err := s.repo.ListGoals(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.progressAll(goals, time.Now())
+}
+
+func (s *goalService) progressAll(goals []models.ReadingGoal, now time.Time) ([]GoalProgress, error) {
+	result := make([]GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		p, err := s.progress(goal, now)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// progress считает завершённые за период прочтения: перечитанная книга засчитывается снова
+func (s *goalService) progress(goal models.ReadingGoal, now time.Time) (GoalProgress, error) {
+	from := goal.StartDate
+	to := goal.EndDate.AddDate(0, 0, 1)
+	totals, err := s.readingRepo.GetReadingTotals(goal.UserID, from, to)
+	if err != nil {
+		return GoalProgress{}, err
+	}
+
+	p := GoalProgress{Goal: goal, Current: totals.Books}
+	if goal.Metric == models.GoalPages {
+		p.Current = totals.Pages
+	}
+	p.Completed = p.Current >= goal.Target
+	p.Percent = min(100, p.Current*100/goal.Target)
+
+	switch {
+	case now.Before(from):
+		p.DaysLeft = int(math.Ceil(to.Sub(from).Hours() / 24))
+	case now.Before(to):
+		elapsed := now.Sub(from).Seconds() / to.Sub(from).Seconds()
+		p.Expected = int(float64(goal.Target) * elapsed)
+		p.DaysLeft = int(math.Ceil(to.Sub(now).Hours() / 24))
+	default:
+		p.Expected = goal.Target
+	}
+	p.OnTrack = p.Current >= p.Expected
+	return p, nil
+}
+
+func (s *goalService) GetYearInReview(userID uint, year int) (YearInReview, error) {
+	from := yearStart(year)
+	to := from.AddDate(1, 0, 0)
+
+	totals, err := s.readingRepo.GetReadingTotals(userID, from, to)
+	if err != nil {
+		return YearInReview{}, err
+	}
+	review := YearInReview{Year: year, BooksRead: totals.Books, PagesRead: totals.Pages}
+
+	if review.TopGenres, err = s.readingRepo.GetTopGenres(userID, from, to, topGenresInReview); err != nil {
+		return YearInReview{}, err
+	}
+
+	longest, err := s.readingRepo.GetLongestReadThrough(userID, from, to)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return YearInReview{}, err
+	}
+	if err == nil {
+		if book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(longest.BookID), 10)); err == nil {
+			brief := toBookBriefs([]models.Book{book})[0]
+			review.LongestBook = &brief
+			review.LongestBookPages = longest.Pages
+		}
+	}
+
+	rating, err := s.readingRepo.GetRatingSummary(userID, from, to)
+	if err != nil {
+		return YearInReview{}, err
+	}
+	if rating.Count > 0 {
+		average := math.Round(rating.Average*100) / 100
+		review.AverageRating = &average
+		review.RatedBooks = rating.Count
+	}
+
+	goals, err := s.repo.ListGoals(userID)
+	if err != nil {
+		return YearInReview{}, err
+	}
+	var overlapping []models.ReadingGoal
+	for _, goal := range goals {
+		if goal.StartDate.Before(to) && !goal.EndDate.Before(from) {
+			overlapping = append(overlapping, goal)
+		}
+	}
+	if review.Goals, err = s.progressAll(overlapping, time.Now()); err != nil {
+		return YearInReview{}, err
+	}
+	return review, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+type HoldEntry struct {
+	Hold models.Hold
+	// Position - место в очереди (с 1) для ожидающей брони, 0 - экземпляр уже отложен
+	Position int
+}
+
+type HoldService interface {
+	PlaceHold(userID uint, role string, bookID uint) (HoldEntry, error)
+	CancelHold(userID, id uint) error
+	ListUserHolds(userID uint) ([]HoldEntry, error)
+	// ProcessHolds снимает просроченные брони и откладывает свободные экземпляры по очередям; вызывается планировщиком
+	ProcessHolds(ctx context.Context) error
+}
+
+type holdService struct {
+	repo        repository.HoldRepository
+	lendingRepo repository.LendingRepository
+	bookRepo    repository.BookRepository
+	config      LendingConfig
+}
+
+func NewHoldService(repo repository.HoldRepository, lendingRepo repository.LendingRepository, bookRepo repository.BookRepository, config LendingConfig) HoldService {
+	return &holdService{repo: repo, lendingRepo: lendingRepo, bookRepo: bookRepo, config: config}
+}
+
+// PlaceHold ставит пользователя в конец очереди. Бронь доступна, только когда все экземпляры книги на руках
+func (s *holdService) PlaceHold(userID uint, role string, bookID uint) (HoldEntry, error) {
+	if s.config.Limits[role] == 0 {
+		return HoldEntry{}, errors.New("access denied: your role cannot borrow books")
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return HoldEntry{}, errors.New("book not found")
+	}
+
+	availability, err := s.lendingRepo.GetAvailability(bookID)
+	if err != nil {
+		return HoldEntry{}, err
+	}
+	if availability.Total == 0 {
+		return HoldEntry{}, errors.New("hold unavailable: the library has no copies of this book")
+	}
+	if availability.Available > 0 {
+		return HoldEntry{}, errors.New("hold unavailable: free copies are on the shelf, borrow one instead")
+	}
+	onLoan, err := s.repo.HasActiveLoan(userID, bookID)
+	if err != nil {
+		return HoldEntry{}, err
+	}
+	if onLoan {
+		return HoldEntry{}, errors.New("hold unavailable: you already have this book on loan")
+	}
+
+	hold := models.Hold{BookID: bookID, UserID: userID, Status: models.HoldWaiting}
+	if err := s.repo.CreateHold(&hold); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return HoldEntry{}, errors.New("hold for this book already exists")
+		}
+		return HoldEntry{}, err
+	}
+
+	entries, err := s.ListUserHolds(userID)
+	if err != nil {
+		return HoldEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.Hold.ID == hold.ID {
+			return entry, nil
+		}
+	}
+	return HoldEntry{}, errors.New("hold not found")
+}
+
+func (s *holdService) CancelHold(userID, id uint) error {
+	hold, err := s.repo.GetHold(id)
+	if err != nil || hold.UserID != userID {
+		return errors.New("hold not found")
+	}
+	cancelled, err := s.repo.CancelHold(id, time.Now().Add(s.config.PickupWindow))
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return errors.New("invalid hold: already closed")
+	}
+	return nil
+}
+
+func (s *holdService) ListUserHolds(userID uint) ([]HoldEntry, error) {
+	holds, err := s.repo.ListActiveHolds(userID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.repo.GetQueuePositions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]HoldEntry, 0, len(holds))
+	for _, hold := range holds {
+		entries = append(entries, HoldEntry{Hold: hold, Position: positions[hold.ID]})
+	}
+	return entries, nil
+}
+
+func (s *holdService) ProcessHolds(ctx context.Context) error {
+	now := time.Now()
+	pickupUntil := now.Add(s.config.PickupWindow)
+
+	expired, err := s.repo.ExpireHolds(now, pickupUntil)
+	if err != nil {
+		return err
+	}
+	// Экземпляры, которые освободились мимо возврата (новые, после обслуживания), тоже уходят очереди
+	offered, err := s.repo.OfferAvailableCopies(pickupUntil)
+	if err != nil {
+		return err
+	}
+	if expired > 0 || offered > 0 {
+		log.Printf("holds: %d expired, %d copies offered", expired, offered)
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"bookshelf/pkg/marc"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/textutil"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ImportFormatCSV     = "csv"
+	ImportFormatNDJSON  = "ndjson"
+	ImportFormatMARC    = "marc"
+	ImportFormatMARCXML = "marcxml"
+
+	importBatchSize = 500
+	// Больше ошибок в задаче не храним, иначе строка в БД разрастается
+	maxImportErrors = 1000
+
+	// Выполняющийся экземпляр продлевает heartbeat задачи с этим интервалом; задача, heartbeat которой
+	// не продлевали дольше importStaleAfter, считается брошенной упавшим или перезапущенным экземпляром
+	importHeartbeatInterval = 30 * time.Second
+	importStaleAfter        = 5 * time.Minute
+)
+
+type ImportOptions struct {
+	Format string
+	DryRun bool
+	// Columns переопределяет соответствие поле BookRequest -> заголовок CSV
+	Columns map[string]string
+}
+
+type ImportService interface {
+	StartImport(userID uint, opts ImportOptions, data []byte) (models.ImportJob, error)
+	GetImportJob(id string) (models.ImportJob, error)
+	// FailStaleJobs завершает отказом задачи, брошенные упавшими или перезапущенными экземплярами:
+	// файл задачи хранится только в памяти процесса, и продолжить её никто не сможет
+	FailStaleJobs(ctx context.Context) error
+}
+
+type importRow struct {
+	line      int
+	req       BookRequest
+	errors    []models.ImportRowError
+	malformed bool
+}
+
+type importService struct {
+	repo         repository.ImportRepository
+	bookRepo     repository.BookRepository
+	cache        cache.RedisCache
+	baseCurrency string
+	listeners    []BookListener
+}
+
+// NewImportService: baseCurrency - валюта цен в строках, где она не указана
+func NewImportService(repo repository.ImportRepository, bookRepo repository.BookRepository, cache *cache.RedisCache, baseCurrency string, listeners ...BookListener) ImportService {
+	return &importService{repo: repo, bookRepo: bookRepo, cache: *cache, baseCurrency: baseCurrency, listeners: listeners}
+}
+
+// Синонимы заголовков CSV для полей BookRequest
+var importColumnAliases = map[string][]string{
+	"title":       {"title", "name", "book_title"},
+	"author":      {"author", "authors", "writer"},
+	"genre":       {"genre", "category"},
+	"description": {"description", "summary", "annotation"},
+	"price":       {"price", "cost"},
+	"currency":    {"currency", "currency_code"},
+	"isbn":        {"isbn", "isbn13", "isbn10"},
+	"publisher":   {"publisher"},
+	"subjects":    {"subjects", "tags"},
+	"pages":       {"pages", "page_count", "num_pages"},
+}
+
+// Колонки, без которых файл всё равно можно импортировать
+var optionalImportColumns = map[string]bool{
+	"isbn":      true,
+	"currency":  true,
+	"publisher": true,
+	"subjects":  true,
+	"pages":     true,
+}
+
+// csvSubjectSeparator разделяет рубрики в одной ячейке CSV, так же их пишет выгрузка
+const csvSubjectSeparator = ";"
+
+func (s *importService) StartImport(userID uint, opts ImportOptions, data []byte) (models.ImportJob, error) {
+	switch opts.Format {
+	case ImportFormatCSV, ImportFormatNDJSON, ImportFormatMARC, ImportFormatMARCXML:
+	default:
+		return models.ImportJob{}, errors.New("invalid import format, must be 'csv', 'ndjson', 'marc' or 'marcxml'")
+	}
+	if len(data) == 0 {
+		return models.ImportJob{}, errors.New("import file is required")
+	}
+	for field := range opts.Columns {
+		if _, ok := importColumnAliases[field]; !ok {
+			return models.ImportJob{}, fmt.Errorf("invalid column mapping: unknown field %q", field)
+		}
+	}
+
+	now := time.Now()
+	job := models.ImportJob{
+		UserID:      userID,
+		Format:      opts.Format,
+		DryRun:      opts.DryRun,
+		Status:      models.ImportStatusPending,
+		HeartbeatAt: &now,
+	}
+	if err := s.repo.CreateJob(&job); err != nil {
+		return models.ImportJob{}, err
+	}
+
+	go s.run(job, opts, data)
+
+	return job, nil
+}
+
+func (s *importService) GetImportJob(id string) (models.ImportJob, error) {
+	return s.repo.GetJobByID(id)
+}
+
+func (s *importService) FailStaleJobs(ctx context.Context) error {
+	now := time.Now()
+	failed, err := s.repo.FailStaleJobs(now.Add(-importStaleAfter), "interrupted by server restart, upload the file again", now)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		log.Printf("import: %d stale jobs marked as failed", failed)
+	}
+	return nil
+}
+
+// keepAlive продлевает heartbeat задачи, пока её выполняет этот экземпляр; stop останавливает продление
+func (s *importService) keepAlive(id uint) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(importHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := s.repo.Heartbeat(id, now); err != nil {
+					log.Printf("import %d: failed to update heartbeat: %s", id, err.Error())
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (s *importService) run(job models.ImportJob, opts ImportOptions, data []byte) {
+	defer s.keepAlive(job.ID)()
+	// Задача работает вне запроса, и Recoverer роутера её не защищает: паника уронила бы весь сервер
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("import %d: panic: %v\n%s", job.ID, r, debug.Stack())
+			s.finish(&job, models.ImportStatusFailed, fmt.Sprintf("internal error: %v", r))
+		}
+	}()
+
+	job.Status = models.ImportStatusRunning
+	if !s.save(&job) {
+		return
+	}
+
+	rows, err := parseImport(opts, data)
+	if err != nil {
+		s.finish(&job, models.ImportStatusFailed, err.Error())
+		return
+	}
+
+	books := make([]models.Book, 0, len(rows))
+	for _, row := range rows {
+		if !row.malformed {
+			row.errors = append(row.errors, validateBookRequest(row.line, row.req, s.baseCurrency)...)
+		}
+		if len(row.errors) > 0 {
+			if len(job.Errors)+len(row.errors) <= maxImportErrors {
+				job.Errors = append(job.Errors, row.errors...)
+			}
+			continue
+		}
+		books = append(books, importBook(row.req, s.baseCurrency))
+	}
+	job.TotalRows = len(rows)
+	job.ValidRows = len(books)
+
+	if job.DryRun {
+		s.finish(&job, models.ImportStatusCompleted, "dry run, nothing imported")
+		return
+	}
+	if job.ValidRows != job.TotalRows {
+		s.finish(&job, models.ImportStatusFailed, "validation failed, nothing imported")
+		return
+	}
+	if !s.save(&job) {
+		return
+	}
+
+	err = s.bookRepo.CreateBooks(books, importBatchSize, func(inserted int) {
+		if err := s.repo.UpdateProgress(job.ID, inserted, time.Now()); err != nil {
+			log.Printf("import %d: failed to update progress: %s", job.ID, err.Error())
+		}
+	})
+	if err != nil {
+		job.ProcessedRows = 0
+		s.finish(&job, models.ImportStatusFailed, fmt.Sprintf("insert failed, nothing imported: %s", err.Error()))
+		return
+	}
+
+	// Кэш сбрасываем один раз на весь импорт, а не на каждую книгу
+	s.cache.InvalidatePattern("books:*")
+	s.cache.Delete("genres:all")
+	for _, l := range s.listeners {
+		l.BookChanged(BookEvent{Type: BooksCreated, Books: books})
+	}
+
+	job.ProcessedRows = len(books)
+	s.finish(&job, models.ImportStatusCompleted, fmt.Sprintf("imported %d books", len(books)))
+}
+
+func (s *importService) finish(job *models.ImportJob, status, message string) {
+	now := time.Now()
+	job.Status = status
+	job.Message = message
+	job.FinishedAt = &now
+	s.save(job)
+}
+
+// save сохраняет задачу и продлевает её heartbeat. false - задачу уже завершили без этого экземпляра,
+// например признали брошенной, и продолжать её нельзя
+func (s *importService) save(job *models.ImportJob) bool {
+	now := time.Now()
+	job.HeartbeatAt = &now
+	saved, err := s.repo.UpdateJob(job)
+	if err != nil {
+		log.Printf("import %d: failed to save job: %s", job.ID, err.Error())
+		return true
+	}
+	if !saved {
+		log.Printf("import %d: job was already finished elsewhere, stopping", job.ID)
+	}
+	return saved
+}
+
+// importBook строит книгу из проверенной строки импорта
+func importBook(req BookRequest, baseCurrency string) models.Book {
+	price, _ := requestPrice(req, baseCurrency)
+	return models.Book{
+		Title:       req.Title,
+		Author:      req.Author,
+		Genre:       req.Genre,
+		Description: req.Description,
+		Price:       price.Amount,
+		Currency:    price.Currency,
+		ISBN:        textutil.NormalizeISBN(req.ISBN),
+		Publisher:   req.Publisher,
+		Subjects:    req.Subjects,
+		Pages:       req.Pages,
+	}
+}
+
+func parseImport(opts ImportOptions, data []byte) ([]importRow, error) {
+	switch opts.Format {
+	case ImportFormatCSV:
+		return parseImportCSV(data, opts.Columns)
+	case ImportFormatNDJSON:
+		return parseImportNDJSON(data)
+	case ImportFormatMARC:
+		return parseImportMARC(data, false)
+	case ImportFormatMARCXML:
+		return parseImportMARC(data, true)
+	}
+	return nil, errors.New("invalid import format")
+}
+
+func parseImportCSV(data []byte, columns map[string]string) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	index, err := mapImportColumns(header, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rows = append(rows, importRow{
+				line:      line,
+				errors:    []models.ImportRowError{{Row: line, Message: err.Error()}},
+				malformed: true,
+			})
+			continue
+		}
+
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := importRow{line: line}
+		row.req = BookRequest{
+			Title:       get("title"),
+			Author:      get("author"),
+			Genre:       get("genre"),
+			Description: get("description"),
+			ISBN:        get("isbn"),
+			Price:       money.Decimal(strings.ReplaceAll(get("price"), ",", ".")),
+			Currency:    get("currency"),
+			Publisher:   get("publisher"),
+		}
+		for _, subject := range strings.Split(get("subjects"), csvSubjectSeparator) {
+			if subject = strings.TrimSpace(subject); subject != "" {
+				row.req.Subjects = append(row.req.Subjects, subject)
+			}
+		}
+		if pages := get("pages"); pages != "" {
+			n, err := strconv.Atoi(pages)
+			if err != nil {
+				row.errors = append(row.errors, models.ImportRowError{Row: line, Field: "pages", Message: "pages must be a whole number"})
+			}
+			row.req.Pages = n
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func mapImportColumns(header []string, columns map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		positions[name] = i
+	}
+
+	index := make(map[string]int, len(importColumnAliases))
+	for field, aliases := range importColumnAliases {
+		if column, ok := columns[field]; ok {
+			aliases = []string{column}
+		}
+		found := false
+		for _, alias := range aliases {
+			if i, ok := positions[strings.ToLower(alias)]; ok {
+				index[field] = i
+				found = true
+				break
+			}
+		}
+		if !found && !optionalImportColumns[field] {
+			return nil, fmt.Errorf("invalid csv header: no column for field %q", field)
+		}
+	}
+	return index, nil
+}
+
+func parseImportNDJSON(data []byte) ([]importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := importRow{line: line}
+		if err := json.Unmarshal([]byte(text), &row.req); err != nil {
+			row.errors = append(row.errors, models.ImportRowError{Row: line, Message: "invalid json: " + err.Error()})
+			row.malformed = true
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid ndjson: %w", err)
+	}
+	return rows, nil
+}
+
+// parseImportMARC нумерует строки отчёта по порядковому номеру записи
+func parseImportMARC(data []byte, isXML bool) ([]importRow, error) {
+	var records []marc.Record
+	var err error
+	if isXML {
+		records, err = marc.ReadXML(bytes.NewReader(data))
+	} else {
+		records, err = marc.ReadAll(bytes.NewReader(data))
+	}
+	if err != nil && len(records) == 0 {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0, len(records)+1)
+	for i, rec := range records {
+		rows = append(rows, importRow{line: i + 1, req: MARCToBookRequest(rec)})
+	}
+	// Битая запись в бинарном MARC не даёт читать дальше: помечаем её и останавливаемся
+	if err != nil {
+		line := len(records) + 1
+		rows = append(rows, importRow{
+			line:      line,
+			errors:    []models.ImportRowError{{Row: line, Message: err.Error()}},
+			malformed: true,
+		})
+	}
+	return rows, nil
+}
+
+func validateBookRequest(line int, req BookRequest, baseCurrency string) []models.ImportRowError {
+	var errs []models.ImportRowError
+	required := []struct{ field, value string }{
+		{"title", req.Title},
+		{"author", req.Author},
+		{"genre", req.Genre},
+		{"description", req.Description},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, models.ImportRowError{Row: line, Field: r.field, Message: r.field + " is required"})
+		}
+	}
+	if req.Currency != "" {
+		if _, err := money.NormalizeCurrency(req.Currency); err != nil {
+			return append(errs, models.ImportRowError{Row: line, Field: "currency", Message: "currency must be an ISO 4217 code"})
+		}
+	}
+	if _, err := requestPrice(req, baseCurrency); err != nil {
+		errs = append(errs, models.ImportRowError{Row: line, Field: "price", Message: "price must be a non-negative number with no more decimals than the currency allows"})
+	}
+	if req.Pages < 0 {
+		errs = append(errs, models.ImportRowError{Row: line, Field: "pages", Message: "pages must not be negative"})
+	}
+	return errs
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingImportRepo запоминает последнее сохранённое состояние задачи
+type recordingImportRepo struct {
+	repository.ImportRepository
+	saved models.ImportJob
+}
+
+func (r *recordingImportRepo) UpdateJob(job *models.ImportJob) (bool, error) {
+	r.saved = *job
+	return true, nil
+}
+
+func (r *recordingImportRepo) Heartbeat(id uint, now time.Time) error {
+	return nil
+}
+
+// finishedImportRepo ведёт себя как репозиторий, где задачу уже признали брошенной
+type finishedImportRepo struct {
+	repository.ImportRepository
+	updates int
+}
+
+func (r *finishedImportRepo) UpdateJob(job *models.ImportJob) (bool, error) {
+	r.updates++
+	return false, nil
+}
+
+func (r *finishedImportRepo) Heartbeat(id uint, now time.Time) error {
+	return nil
+}
+
+// panickingBookRepo падает при вставке книг
+type panickingBookRepo struct {
+	repository.BookRepository
+}
+
+func (r *panickingBookRepo) CreateBooks(books []models.Book, batchSize int, onBatch func(inserted int)) error {
+	panic("insert exploded")
+}
+
+// streamBookRepo отдаёт выгрузке фиксированный список книг
+type streamBookRepo struct {
+	repository.BookRepository
+	books []models.Book
+}
+
+func (r *streamBookRepo) StreamBooks(genre string, fn func(book models.Book) error) error {
+	for _, book := range r.books {
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestImport_RoundTripKeepsCatalogueFields(t *testing.T) {
+	book := models.Book{
+		Title:       "Dune",
+		Author:      "Frank Herbert",
+		Genre:       "sci-fi",
+		Description: "A desert planet and its spice",
+		Price:       1999,
+		Currency:    "USD",
+		ISBN:        "9780441013593",
+		Publisher:   "Ace",
+		Subjects:    []string{"Science fiction", "Desert planets"},
+		Pages:       412,
+	}
+	export := NewExportService(&streamBookRepo{books: []models.Book{book}})
+
+	for _, format := range []string{ExportFormatCSV, ExportFormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := export.ExportBooks(&buf, format, "")
+			assert.NoError(t, err)
+
+			rows, err := parseImport(ImportOptions{Format: format}, buf.Bytes())
+			assert.NoError(t, err)
+			assert.Len(t, rows, 1)
+			assert.Empty(t, rows[0].errors)
+			assert.Empty(t, validateBookRequest(rows[0].line, rows[0].req, "USD"))
+
+			// Проверки
+			imported := importBook(rows[0].req, "USD")
+			assert.Equal(t, book.Price, imported.Price)
+			assert.Equal(t, book.Publisher, imported.Publisher)
+			assert.Equal(t, book.Subjects, imported.Subjects)
+			assert.Equal(t, book.Pages, imported.Pages)
+		})
+	}
+}
+
+func TestParseImportCSV_Pages(t *testing.T) {
+	data := []byte("title,author,genre,description,price,page_count\n" +
+		"Dune,Frank Herbert,sci-fi,Spice,19.99,412\n" +
+		"Emma,Jane Austen,classic,Matchmaking,9.99,many\n")
+
+	rows, err := parseImportCSV(data, nil)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	// Проверки
+	assert.Equal(t, 412, rows[0].req.Pages)
+	assert.Empty(t, rows[0].errors)
+	assert.Len(t, rows[1].errors, 1)
+	assert.Equal(t, "pages", rows[1].errors[0].Field)
+}
+
+func TestImport_PanicFailsJob(t *testing.T) {
+	repo := &recordingImportRepo{}
+	s := &importService{repo: repo, bookRepo: &panickingBookRepo{}, baseCurrency: "USD"}
+	data := []byte("title,author,genre,description,price\nDune,Frank Herbert,sci-fi,Spice,19.99\n")
+
+	assert.NotPanics(t, func() {
+		s.run(models.ImportJob{Format: ImportFormatCSV}, ImportOptions{Format: ImportFormatCSV}, data)
+	})
+
+	// Проверки
+	assert.Equal(t, models.ImportStatusFailed, repo.saved.Status)
+	assert.Contains(t, repo.saved.Message, "insert exploded")
+	assert.NotNil(t, repo.saved.FinishedAt)
+}
+
+func TestImport_StopsWhenJobFinishedElsewhere(t *testing.T) {
+	repo := &finishedImportRepo{}
+	s := &importService{repo: repo, bookRepo: &panickingBookRepo{}, baseCurrency: "USD"}
+	data := []byte("title,author,genre,description,price\nDune,Frank Herbert,sci-fi,Spice,19.99\n")
+
+	s.run(models.ImportJob{Format: ImportFormatCSV}, ImportOptions{Format: ImportFormatCSV}, data)
+
+	// Проверки: задача не вставляла книги и не пыталась сменить статус после отказа
+	assert.Equal(t, 1, repo.updates)
+}
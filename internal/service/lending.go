@@ -0,0 +1,332 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxBarcodeLength = 64
+
+// LendingConfig - правила выдачи. Limits - сколько книг одновременно может держать роль;
+// роль без записи брать книги не может. PickupWindow - сколько экземпляр ждёт читателя по брони.
+// FineBlockThreshold - при долге по штрафам больше этой суммы (в валюте FineCurrency) новые выдачи
+// запрещены, 0 - без блокировки
+type LendingConfig struct {
+	LoanPeriod         time.Duration
+	MaxRenewals        int
+	Limits             map[string]int
+	PickupWindow       time.Duration
+	FineBlockThreshold money.Amount
+	FineCurrency       string
+}
+
+// parseRoleValues разбирает числа по ролям вида "user=3,moderator=5,admin=10"
+func parseRoleValues(raw, setting string) (map[string]int64, error) {
+	values := make(map[string]int64)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		role, value, ok := strings.Cut(part, "=")
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if !ok || err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q, must be role=number", setting, part)
+		}
+		values[strings.TrimSpace(role)] = n
+	}
+	return values, nil
+}
+
+// ParseLoanLimits разбирает лимиты вида "user=3,moderator=5,admin=10"
+func ParseLoanLimits(raw string) (map[string]int, error) {
+	values, err := parseRoleValues(raw, "loan limit")
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]int, len(values))
+	for role, n := range values {
+		limits[role] = int(n)
+	}
+	return limits, nil
+}
+
+type CopyRequest struct {
+	Barcode   string `json:"barcode" example:"LIB-000123"`
+	Location  string `json:"location" example:"Шкаф 2, полка 3"`
+	Condition string `json:"condition" example:"good"`
+	// Status можно менять только у невыданного экземпляра: available, maintenance или lost
+	Status string `json:"status" example:"available"`
+}
+
+// CheckoutRequest: book_id - любой свободный экземпляр книги, barcode - конкретный экземпляр
+type CheckoutRequest struct {
+	BookID  uint   `json:"book_id" example:"1"`
+	Barcode string `json:"barcode" example:"LIB-000123"`
+}
+
+type ReturnRequest struct {
+	// Condition - состояние экземпляра при возврате; damaged отправляет его на обслуживание
+	Condition string `json:"condition" example:"good"`
+}
+
+type LendingService interface {
+	AddCopy(bookID uint, req CopyRequest) (models.Copy, error)
+	UpdateCopy(id uint, req CopyRequest) (models.Copy, error)
+	DeleteCopy(id uint) error
+	ListCopies(bookID uint) ([]models.Copy, error)
+	GetAvailability(bookID uint) (repository.Availability, error)
+	Checkout(userID uint, role string, req CheckoutRequest) (models.Loan, error)
+	// Return: вернуть книгу может сам читатель или библиотекарь (moderator, admin)
+	Return(userID uint, role string, loanID uint, req ReturnRequest) (models.Loan, error)
+	Renew(userID, loanID uint) (models.Loan, error)
+	ListUserLoans(userID uint, activeOnly bool, page, limit int) ([]models.Loan, int64, error)
+	ListLoans(overdue bool, page, limit int) ([]models.Loan, int64, error)
+}
+
+type lendingService struct {
+	repo     repository.LendingRepository
+	bookRepo repository.BookRepository
+	fineRepo repository.FineRepository
+	config   LendingConfig
+}
+
+func NewLendingService(repo repository.LendingRepository, bookRepo repository.BookRepository, fineRepo repository.FineRepository, config LendingConfig) LendingService {
+	return &lendingService{repo: repo, bookRepo: bookRepo, fineRepo: fineRepo, config: config}
+}
+
+func isLibrarian(role string) bool {
+	return role == "moderator" || role == "admin"
+}
+
+func isCondition(condition string) bool {
+	switch condition {
+	case models.ConditionNew, models.ConditionGood, models.ConditionFair, models.ConditionPoor, models.ConditionDamaged:
+		return true
+	}
+	return false
+}
+
+func validateCopy(req *CopyRequest) error {
+	req.Barcode = strings.TrimSpace(req.Barcode)
+	req.Location = strings.TrimSpace(req.Location)
+	if req.Condition == "" {
+		req.Condition = models.ConditionGood
+	}
+	if req.Status == "" {
+		req.Status = models.CopyAvailable
+	}
+
+	if req.Barcode == "" {
+		return errors.New("barcode is required")
+	}
+	if len(req.Barcode) > maxBarcodeLength {
+		return errors.New("invalid barcode, too long")
+	}
+	if !isCondition(req.Condition) {
+		return errors.New("invalid condition, must be 'new', 'good', 'fair', 'poor' or 'damaged'")
+	}
+	switch req.Status {
+	case models.CopyAvailable, models.CopyMaintenance, models.CopyLost:
+		return nil
+	}
+	return errors.New("invalid status, must be 'available', 'maintenance' or 'lost'")
+}
+
+func (s *lendingService) AddCopy(bookID uint, req CopyRequest) (models.Copy, error) {
+	if err := validateCopy(&req); err != nil {
+		return models.Copy{}, err
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return models.Copy{}, errors.New("book not found")
+	}
+
+	c := models.Copy{
+		BookID:    bookID,
+		Barcode:   req.Barcode,
+		Location:  req.Location,
+		Condition: req.Condition,
+		Status:    req.Status,
+	}
+	if err := s.repo.CreateCopy(&c); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return models.Copy{}, errors.New("copy with this barcode already exists")
+		}
+		return models.Copy{}, err
+	}
+	return c, nil
+}
+
+func (s *lendingService) UpdateCopy(id uint, req CopyRequest) (models.Copy, error) {
+	if err := validateCopy(&req); err != nil {
+		return models.Copy{}, err
+	}
+	c, err := s.repo.GetCopy(id)
+	if err != nil {
+		return models.Copy{}, errors.New("copy not found")
+	}
+
+	prevStatus := c.Status
+	if prevStatus == models.CopyOnLoan || prevStatus == models.CopyOnHold {
+		// Статус выданного или отложенного экземпляра меняется только возвратом, выдачей или снятием брони
+		req.Status = prevStatus
+	}
+	c.Barcode = req.Barcode
+	c.Location = req.Location
+	c.Condition = req.Condition
+	c.Status = req.Status
+
+	updated, err := s.repo.UpdateCopy(&c, prevStatus)
+	if err != nil {
+		if repository.IsDuplicateKey(err) {
+			return models.Copy{}, errors.New("copy with this barcode already exists")
+		}
+		return models.Copy{}, err
+	}
+	if !updated {
+		return models.Copy{}, errors.New("copy unavailable: it was checked out or changed concurrently, try again")
+	}
+	return c, nil
+}
+
+func (s *lendingService) DeleteCopy(id uint) error {
+	c, err := s.repo.GetCopy(id)
+	if err != nil {
+		return errors.New("copy not found")
+	}
+	if c.Status == models.CopyOnLoan {
+		return errors.New("copy unavailable: it is on loan, return it first")
+	}
+	if c.Status == models.CopyOnHold {
+		return errors.New("copy unavailable: it is reserved for a hold")
+	}
+	deleted, err := s.repo.DeleteCopy(id, c.Status)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("copy unavailable: it was checked out or changed concurrently, try again")
+	}
+	return nil
+}
+
+func (s *lendingService) ListCopies(bookID uint) ([]models.Copy, error) {
+	return s.repo.ListCopies(bookID)
+}
+
+func (s *lendingService) GetAvailability(bookID uint) (repository.Availability, error) {
+	return s.repo.GetAvailability(bookID)
+}
+
+func (s *lendingService) Checkout(userID uint, role string, req CheckoutRequest) (models.Loan, error) {
+	req.Barcode = strings.TrimSpace(req.Barcode)
+	if req.BookID == 0 && req.Barcode == "" {
+		return models.Loan{}, errors.New("book_id or barcode is required")
+	}
+	limit := s.config.Limits[role]
+	if limit == 0 {
+		return models.Loan{}, errors.New("access denied: your role cannot borrow books")
+	}
+	if s.config.FineBlockThreshold > 0 {
+		balance, err := s.fineRepo.GetBalance(userID)
+		if err != nil {
+			return models.Loan{}, err
+		}
+		if balance > s.config.FineBlockThreshold {
+			return models.Loan{}, fmt.Errorf("access denied: outstanding fines of %s exceed %s, pay them first",
+				money.New(balance, s.config.FineCurrency), money.New(s.config.FineBlockThreshold, s.config.FineCurrency))
+		}
+	}
+
+	now := time.Now()
+	loan, err := s.repo.Checkout(userID, req.BookID, req.Barcode, limit, now.Add(s.config.LoanPeriod), now.Add(s.config.PickupWindow))
+	switch {
+	case errors.Is(err, repository.ErrLoanLimit):
+		return models.Loan{}, fmt.Errorf("loan limit reached: at most %d books at a time", limit)
+	case errors.Is(err, repository.ErrNoCopyAvailable):
+		return models.Loan{}, errors.New("copy unavailable: no free copies, try again later")
+	case err != nil:
+		return models.Loan{}, err
+	}
+	return loan, nil
+}
+
+// getLoan: чужая выдача выглядит как несуществующая, библиотекарю доступны все
+func (s *lendingService) getLoan(userID uint, librarian bool, id uint) (models.Loan, error) {
+	loan, err := s.repo.GetLoan(id)
+	if err != nil || (!librarian && loan.UserID != userID) {
+		return models.Loan{}, errors.New("loan not found")
+	}
+	return loan, nil
+}
+
+func (s *lendingService) Return(userID uint, role string, loanID uint, req ReturnRequest) (models.Loan, error) {
+	if req.Condition != "" && !isCondition(req.Condition) {
+		return models.Loan{}, errors.New("invalid condition, must be 'new', 'good', 'fair', 'poor' or 'damaged'")
+	}
+	loan, err := s.getLoan(userID, isLibrarian(role), loanID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+
+	status := models.CopyAvailable
+	if req.Condition == models.ConditionDamaged {
+		status = models.CopyMaintenance
+	}
+	returned, err := s.repo.ReturnLoan(&loan, req.Condition, status, time.Now().Add(s.config.PickupWindow))
+	if err != nil {
+		return models.Loan{}, err
+	}
+	if !returned {
+		return models.Loan{}, errors.New("invalid loan: already returned")
+	}
+	return loan, nil
+}
+
+// Renew продлевает выдачу на ещё один срок от текущей даты возврата. Просроченную выдачу и книгу,
+// которую ждут в очереди, продлить нельзя
+func (s *lendingService) Renew(userID, loanID uint) (models.Loan, error) {
+	loan, err := s.getLoan(userID, false, loanID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+	if loan.ReturnedAt != nil {
+		return models.Loan{}, errors.New("invalid loan: already returned")
+	}
+	if time.Now().After(loan.DueAt) {
+		return models.Loan{}, errors.New("renewal unavailable: loan is overdue, return the book")
+	}
+	if loan.Renewals >= s.config.MaxRenewals {
+		return models.Loan{}, fmt.Errorf("renewal limit reached: at most %d renewals", s.config.MaxRenewals)
+	}
+	waiting, err := s.repo.HasWaitingHolds(loan.BookID)
+	if err != nil {
+		return models.Loan{}, err
+	}
+	if waiting {
+		return models.Loan{}, errors.New("renewal unavailable: other readers are waiting for this book")
+	}
+
+	renewed, err := s.repo.RenewLoan(&loan, loan.DueAt.Add(s.config.LoanPeriod))
+	if err != nil {
+		return models.Loan{}, err
+	}
+	if !renewed {
+		return models.Loan{}, errors.New("renewal unavailable: loan was already returned or changed, reload it and try again")
+	}
+	return loan, nil
+}
+
+func (s *lendingService) ListUserLoans(userID uint, activeOnly bool, page, limit int) ([]models.Loan, int64, error) {
+	return s.repo.ListLoans(repository.LoanFilter{UserID: userID, ActiveOnly: activeOnly}, page, limit)
+}
+
+func (s *lendingService) ListLoans(overdue bool, page, limit int) ([]models.Loan, int64, error) {
+	return s.repo.ListLoans(repository.LoanFilter{ActiveOnly: true, Overdue: overdue}, page, limit)
+}
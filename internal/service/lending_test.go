@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// barcodeIndexWhere возвращает условие уникального индекса штрихкода из тегов модели
+func barcodeIndexWhere(t *testing.T) string {
+	s, err := schema.Parse(&models.Copy{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, idx := range s.ParseIndexes() {
+		if idx.Class == "UNIQUE" && len(idx.Fields) == 1 && idx.Fields[0].DBName == "barcode" {
+			return idx.Where
+		}
+	}
+	t.Fatal("unique barcode index not found")
+	return ""
+}
+
+// fakeLendingRepo хранит экземпляры в памяти; уникальность штрихкода проверяется так же,
+// как её проверяет индекс из модели: удалённые строки учитываются, если индекс не частичный
+type fakeLendingRepo struct {
+	repository.LendingRepository
+	copies  []models.Copy
+	partial bool
+}
+
+func (r *fakeLendingRepo) CreateCopy(c *models.Copy) error {
+	for _, existing := range r.copies {
+		if existing.Barcode == c.Barcode && (!r.partial || !existing.DeletedAt.Valid) {
+			return &pgconn.PgError{Code: "23505", ConstraintName: "idx_copies_barcode"}
+		}
+	}
+	c.ID = uint(len(r.copies) + 1)
+	r.copies = append(r.copies, *c)
+	return nil
+}
+
+func (r *fakeLendingRepo) GetCopy(id uint) (models.Copy, error) {
+	for _, c := range r.copies {
+		if c.ID == id && !c.DeletedAt.Valid {
+			return c, nil
+		}
+	}
+	return models.Copy{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeLendingRepo) DeleteCopy(id uint, prevStatus string) (bool, error) {
+	for i := range r.copies {
+		if r.copies[i].ID == id && r.copies[i].Status == prevStatus && !r.copies[i].DeletedAt.Valid {
+			r.copies[i].DeletedAt = gorm.DeletedAt{Valid: true}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeLendingBookRepo struct {
+	repository.BookRepository
+}
+
+func (r *fakeLendingBookRepo) GetBookByID(id string) (models.Book, error) {
+	return models.Book{Title: "Dune"}, nil
+}
+
+func TestLendingService_AddCopy_ReusesBarcodeOfDeletedCopy(t *testing.T) {
+	repo := &fakeLendingRepo{partial: barcodeIndexWhere(t) == "deleted_at IS NULL"}
+	s := &lendingService{repo: repo, bookRepo: &fakeLendingBookRepo{}}
+
+	c, err := s.AddCopy(1, CopyRequest{Barcode: "LIB-000123"})
+	assert.NoError(t, err)
+	_, err = s.AddCopy(1, CopyRequest{Barcode: "LIB-000123"})
+	assert.EqualError(t, err, "copy with this barcode already exists")
+
+	assert.NoError(t, s.DeleteCopy(c.ID))
+	readded, err := s.AddCopy(1, CopyRequest{Barcode: "LIB-000123"})
+
+	// Проверки
+	assert.NoError(t, err)
+	assert.NotEqual(t, c.ID, readded.ID)
+	assert.Equal(t, "LIB-000123", readded.Barcode)
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/textutil"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LibrarySourceGoodreads  = "goodreads"
+	LibrarySourceStoryGraph = "storygraph"
+
+	// Пороги нечёткого сопоставления по названию и автору
+	libraryMatchScore     = 0.9
+	libraryCandidateScore = 0.6
+	libraryMatchGap       = 0.05
+	libraryMaxCandidates  = 3
+	librarySearchLimit    = 50
+)
+
+type LibraryImportService interface {
+	ImportLibrary(userID uint, data []byte) (models.LibraryImport, error)
+	GetImport(userID uint, id string) (models.LibraryImport, error)
+	// ResolveRow привязывает строку к книге вручную, bookID == nil пропускает строку
+	ResolveRow(userID uint, importID string, row int, bookID *uint) (models.LibraryImport, error)
+}
+
+type libraryImportService struct {
+	repo           repository.LibraryImportRepository
+	bookRepo       repository.BookRepository
+	favService     FavouriteService
+	readingService ReadingService
+	reviewService  ReviewService
+}
+
+func NewLibraryImportService(
+	repo repository.LibraryImportRepository,
+	bookRepo repository.BookRepository,
+	favService FavouriteService,
+	readingService ReadingService,
+	reviewService ReviewService,
+) LibraryImportService {
+	return &libraryImportService{
+		repo:           repo,
+		bookRepo:       bookRepo,
+		favService:     favService,
+		readingService: readingService,
+		reviewService:  reviewService,
+	}
+}
+
+// Полки Goodreads и статусы StoryGraph; did-not-finish и пользовательские полки не переносятся
+var libraryShelves = map[string]string{
+	"read":              models.ShelfRead,
+	"currently-reading": models.ShelfReading,
+	"to-read":           models.ShelfWantToRead,
+}
+
+// Колонки выгрузок: для каждого поля - варианты заголовков Goodreads и StoryGraph
+var libraryColumns = map[string][]string{
+	"title":     {"Title"},
+	"author":    {"Author", "Authors"},
+	"isbn13":    {"ISBN13"},
+	"isbn":      {"ISBN", "ISBN/UID"},
+	"rating":    {"My Rating", "Star Rating"},
+	"shelf":     {"Exclusive Shelf", "Read Status"},
+	"date_read": {"Date Read", "Last Date Read"},
+}
+
+func (s *libraryImportService) ImportLibrary(userID uint, data []byte) (models.LibraryImport, error) {
+	if len(data) == 0 {
+		return models.LibraryImport{}, errors.New("import file is required")
+	}
+
+	source, rows, err := parseLibraryCSV(data)
+	if err != nil {
+		return models.LibraryImport{}, err
+	}
+
+	if err := s.match(rows); err != nil {
+		return models.LibraryImport{}, err
+	}
+
+	// Отчёт сохраняется до переноса, а исход каждой строки - сразу после неё: если импорт прервётся,
+	// пользователь увидит, какие строки уже перенесены, и повторно их не применит
+	imp := models.LibraryImport{
+		UserID: userID,
+		Source: source,
+		Rows:   rows,
+	}
+	if err := s.repo.CreateImport(&imp); err != nil {
+		return models.LibraryImport{}, err
+	}
+
+	for i := range imp.Rows {
+		if imp.Rows[i].Status != models.LibraryRowMatched {
+			continue
+		}
+		s.applyRow(userID, &imp.Rows[i])
+		if err := s.repo.UpdateImport(&imp); err != nil {
+			return models.LibraryImport{}, err
+		}
+	}
+	return imp, nil
+}
+
+func (s *libraryImportService) GetImport(userID uint, id string) (models.LibraryImport, error) {
+	imp, err := s.repo.GetImport(userID, id)
+	if err != nil {
+		return models.LibraryImport{}, errors.New("import not found")
+	}
+	return imp, nil
+}
+
+func (s *libraryImportService) ResolveRow(userID uint, importID string, row int, bookID *uint) (models.LibraryImport, error) {
+	imp, err := s.GetImport(userID, importID)
+	if err != nil {
+		return models.LibraryImport{}, err
+	}
+
+	var target *models.LibraryImportRow
+	for i := range imp.Rows {
+		if imp.Rows[i].Row == row {
+			target = &imp.Rows[i]
+			break
+		}
+	}
+	if target == nil {
+		return models.LibraryImport{}, errors.New("row not found")
+	}
+	switch target.Status {
+	case models.LibraryRowAmbiguous, models.LibraryRowUnmatched, models.LibraryRowFailed:
+	default:
+		return models.LibraryImport{}, errors.New("invalid row: only ambiguous, unmatched or failed rows can be resolved")
+	}
+
+	if bookID == nil {
+		target.Status = models.LibraryRowSkipped
+		target.Error = ""
+	} else {
+		if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(*bookID), 10)); err != nil {
+			return models.LibraryImport{}, errors.New("book not found")
+		}
+		target.Status = models.LibraryRowResolved
+		target.MatchedBy = "manual"
+		target.BookID = bookID
+		s.applyRow(userID, target)
+	}
+
+	if err := s.repo.UpdateImport(&imp); err != nil {
+		return models.LibraryImport{}, err
+	}
+	if target.Status == models.LibraryRowFailed {
+		return models.LibraryImport{}, errors.New(target.Error)
+	}
+	return imp, nil
+}
+
+// applyRow переносит строку и записывает в неё исход: при ошибке строка становится failed с причиной
+func (s *libraryImportService) applyRow(userID uint, row *models.LibraryImportRow) {
+	row.Applied = nil
+	row.Skipped = nil
+	row.Error = ""
+	if err := s.apply(userID, row); err != nil {
+		row.Status = models.LibraryRowFailed
+		row.Error = err.Error()
+	}
+}
+
+// apply переносит строку на найденную книгу: избранное, полку и оценку. Уже заданные пользователем
+// полка и рецензия не перезаписываются; что не перенесено, попадает в Skipped с причиной
+func (s *libraryImportService) apply(userID uint, row *models.LibraryImportRow) error {
+	bookID := *row.BookID
+	if err := s.favService.AddFavourite(userID, bookID); err != nil {
+		return fmt.Errorf("failed to add favourite: %w", err)
+	}
+	row.Applied = append(row.Applied, "favourite")
+
+	if shelf, ok := libraryShelves[strings.ToLower(row.Shelf)]; ok {
+		if reason := s.applyShelf(userID, bookID, shelf, row.DateRead); reason != "" {
+			row.Skipped = append(row.Skipped, "shelf: "+reason)
+		} else {
+			row.Applied = append(row.Applied, "shelf")
+		}
+	} else if row.Shelf != "" {
+		row.Skipped = append(row.Skipped, fmt.Sprintf("shelf: %q has no matching reading status", row.Shelf))
+	}
+
+	if row.Rating >= 1 && row.Rating <= 5 {
+		if _, err := s.reviewService.GetUserReview(userID, bookID); err == nil {
+			row.Skipped = append(row.Skipped, "rating: book already has your review")
+		} else if _, err := s.reviewService.UpsertReview(userID, bookID, ReviewRequest{Rating: row.Rating}); err != nil {
+			row.Skipped = append(row.Skipped, "rating: "+err.Error())
+		} else {
+			row.Applied = append(row.Applied, "rating")
+		}
+	}
+	return nil
+}
+
+// applyShelf возвращает причину, по которой полка не перенесена, или пустую строку
+func (s *libraryImportService) applyShelf(userID, bookID uint, shelf, dateRead string) string {
+	if _, err := s.readingService.GetStatus(userID, bookID); err == nil {
+		return "book is already on your shelf"
+	}
+
+	req := ReadingStatusRequest{Shelf: shelf}
+	if shelf == models.ShelfRead {
+		// Без даты прочтения книга засчиталась бы в текущий год и исказила цели и итоги года
+		finished, ok := parseLibraryDate(dateRead)
+		if !ok {
+			return "no date read"
+		}
+		req.FinishedAt = &finished
+		req.StartedAt = &finished
+	}
+	if _, err := s.readingService.SetStatus(userID, bookID, req); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func parseLibraryDate(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006/01/02", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// match сначала ищет книги по ISBN одним запросом, остальные строки - по названию и автору
+func (s *libraryImportService) match(rows []models.LibraryImportRow) error {
+	isbns := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.ISBN != "" {
+			isbns = append(isbns, row.ISBN)
+		}
+	}
+
+	books, err := s.bookRepo.FindBooksByISBN(isbns)
+	if err != nil {
+		return err
+	}
+	byISBN := make(map[string]uint, len(books))
+	for _, book := range books {
+		byISBN[book.ISBN] = book.ID
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if id, ok := byISBN[row.ISBN]; ok && row.ISBN != "" {
+			row.Status = models.LibraryRowMatched
+			row.MatchedBy = "isbn"
+			row.BookID = &id
+			continue
+		}
+
+		candidates, err := s.fuzzyCandidates(row.Title, row.Author)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(candidates) > 0 && candidates[0].Score >= libraryMatchScore &&
+			(len(candidates) == 1 || candidates[0].Score-candidates[1].Score >= libraryMatchGap):
+			id := candidates[0].BookID
+			row.Status = models.LibraryRowMatched
+			row.MatchedBy = "title_author"
+			row.BookID = &id
+		case len(candidates) > 0:
+			row.Status = models.LibraryRowAmbiguous
+			row.Candidates = candidates
+		default:
+			row.Status = models.LibraryRowUnmatched
+		}
+	}
+	return nil
+}
+
+func (s *libraryImportService) fuzzyCandidates(title, author string) ([]models.LibraryImportCandidate, error) {
+	// В БД ищем по самому длинному слову названия, точную оценку считаем в Go
+	keyword := ""
+	for _, token := range textutil.Tokens(mainTitle(title)) {
+		if len([]rune(token)) > len([]rune(keyword)) {
+			keyword = token
+		}
+	}
+	if keyword == "" {
+		return nil, nil
+	}
+
+	books, _, err := s.bookRepo.SearchBooks(keyword, 1, librarySearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []models.LibraryImportCandidate
+	for _, book := range books {
+		score := 0.7*titleSimilarity(title, book.Title) + 0.3*authorSimilarity(author, book.Author)
+		if score < libraryCandidateScore {
+			continue
+		}
+		candidates = append(candidates, models.LibraryImportCandidate{
+			BookID: book.ID,
+			Title:  book.Title,
+			Author: book.Author,
+			Score:  math.Round(score*100) / 100,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > libraryMaxCandidates {
+		candidates = candidates[:libraryMaxCandidates]
+	}
+	return candidates, nil
+}
+
+// mainTitle отбрасывает серию в скобках и подзаголовок: "Dune (Dune, #1)" -> "Dune"
+func mainTitle(title string) string {
+	if i := strings.Index(title, "("); i > 0 {
+		title = title[:i]
+	}
+	if i := strings.Index(title, ":"); i > 0 {
+		title = title[:i]
+	}
+	return title
+}
+
+func titleSimilarity(a, b string) float64 {
+	full := textutil.Similarity(textutil.Normalize(a), textutil.Normalize(b))
+	short := textutil.Similarity(textutil.Normalize(mainTitle(a)), textutil.Normalize(mainTitle(b)))
+	return max(full, short)
+}
+
+// authorSimilarity сравнивает автора строки с лучшим из соавторов книги
+func authorSimilarity(author, bookAuthors string) float64 {
+	normalized := textutil.Normalize(author)
+	best := 0.0
+	splitter := strings.NewReplacer("&", ",", " and ", ",", ";", ",")
+	for _, name := range strings.Split(splitter.Replace(bookAuthors), ",") {
+		best = max(best, textutil.Similarity(normalized, textutil.Normalize(name)))
+	}
+	return best
+}
+
+func parseLibraryCSV(data []byte) (string, []models.LibraryImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	source := ""
+	switch {
+	case hasColumn(positions, "Exclusive Shelf"):
+		source = LibrarySourceGoodreads
+	case hasColumn(positions, "Read Status"):
+		source = LibrarySourceStoryGraph
+	default:
+		return "", nil, errors.New("invalid file: expected a Goodreads or StoryGraph export")
+	}
+	if !hasColumn(positions, "Title") {
+		return "", nil, errors.New("invalid file: no Title column")
+	}
+
+	index := make(map[string]int, len(libraryColumns))
+	for field, names := range libraryColumns {
+		for _, name := range names {
+			if i, ok := positions[name]; ok {
+				index[field] = i
+				break
+			}
+		}
+	}
+
+	var rows []models.LibraryImportRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid csv at line %d: %w", line, err)
+		}
+
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		isbn := textutil.NormalizeISBN(get("isbn13"))
+		if isbn == "" {
+			isbn = textutil.NormalizeISBN(get("isbn"))
+		}
+		// StoryGraph кладёт в ISBN/UID свои идентификаторы, они нам не помогут
+		if len(isbn) != 13 {
+			isbn = ""
+		}
+
+		rating, _ := strconv.ParseFloat(get("rating"), 64)
+
+		rows = append(rows, models.LibraryImportRow{
+			Row:      line,
+			Title:    get("title"),
+			Author:   get("author"),
+			ISBN:     isbn,
+			Shelf:    get("shelf"),
+			Rating:   int(math.Round(rating)),
+			DateRead: get("date_read"),
+		})
+	}
+	return source, rows, nil
+}
+
+func hasColumn(positions map[string]int, name string) bool {
+	_, ok := positions[name]
+	return ok
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLibraryImportRepo хранит копию отчёта, как она лежала бы в БД
+type fakeLibraryImportRepo struct {
+	repository.LibraryImportRepository
+	stored  models.LibraryImport
+	creates int
+}
+
+func storedCopy(imp *models.LibraryImport) models.LibraryImport {
+	stored := *imp
+	stored.Rows = append([]models.LibraryImportRow(nil), imp.Rows...)
+	return stored
+}
+
+func (r *fakeLibraryImportRepo) CreateImport(imp *models.LibraryImport) error {
+	r.creates++
+	imp.ID = 1
+	r.stored = storedCopy(imp)
+	return nil
+}
+
+func (r *fakeLibraryImportRepo) UpdateImport(imp *models.LibraryImport) error {
+	r.stored = storedCopy(imp)
+	return nil
+}
+
+func (r *fakeLibraryImportRepo) GetImport(userID uint, id string) (models.LibraryImport, error) {
+	return storedCopy(&r.stored), nil
+}
+
+// isbnBookRepo находит книги только по ISBN
+type isbnBookRepo struct {
+	repository.BookRepository
+	books []models.Book
+}
+
+func (r *isbnBookRepo) FindBooksByISBN(isbns []string) ([]models.Book, error) {
+	return r.books, nil
+}
+
+func (r *isbnBookRepo) SearchBooks(query string, page, limit int) ([]models.Book, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *isbnBookRepo) GetBookByID(id string) (models.Book, error) {
+	return models.Book{}, nil
+}
+
+// failingFavouriteService отказывает для книг из failFor и запоминает, что добавлено
+type failingFavouriteService struct {
+	FavouriteService
+	failFor map[uint]bool
+	added   []uint
+}
+
+func (s *failingFavouriteService) AddFavourite(userID, bookID uint) error {
+	if s.failFor[bookID] {
+		return errors.New("connection refused")
+	}
+	s.added = append(s.added, bookID)
+	return nil
+}
+
+func TestLibraryImport_RecordsEachRowOutcome(t *testing.T) {
+	books := []models.Book{{ISBN: "9780441013593"}, {ISBN: "9780141439518"}}
+	books[0].ID = 1
+	books[1].ID = 2
+	repo := &fakeLibraryImportRepo{}
+	favs := &failingFavouriteService{failFor: map[uint]bool{2: true}}
+	s := &libraryImportService{repo: repo, bookRepo: &isbnBookRepo{books: books}, favService: favs}
+	data := []byte("Title,Author,ISBN13,Exclusive Shelf\n" +
+		"Dune,Frank Herbert,=\"9780441013593\",\n" +
+		"Emma,Jane Austen,=\"9780141439518\",\n")
+
+	imp, err := s.ImportLibrary(7, data)
+	assert.NoError(t, err)
+
+	// Проверки
+	assert.Equal(t, 1, repo.creates)
+	assert.Equal(t, imp.Rows, repo.stored.Rows)
+	assert.Equal(t, models.LibraryRowMatched, repo.stored.Rows[0].Status)
+	assert.Equal(t, []string{"favourite"}, repo.stored.Rows[0].Applied)
+	assert.Equal(t, models.LibraryRowFailed, repo.stored.Rows[1].Status)
+	assert.Contains(t, repo.stored.Rows[1].Error, "connection refused")
+	assert.Empty(t, repo.stored.Rows[1].Applied)
+
+	// Упавшую строку можно перенести заново
+	delete(favs.failFor, 2)
+	bookID := uint(2)
+	imp, err = s.ResolveRow(7, "1", 3, &bookID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LibraryRowResolved, imp.Rows[1].Status)
+	assert.Empty(t, imp.Rows[1].Error)
+	assert.Equal(t, []uint{1, 2}, favs.added)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/pkg/marc"
+	"bookshelf/pkg/money"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultMARCGenre = "Uncategorized"
+	// maxMARCDescription - аннотация 520 с индикаторами и разделителями должна поместиться в поле ISO 2709
+	maxMARCDescription = marc.MaxFieldLength - 16
+)
+
+var (
+	marcPricePattern = regexp.MustCompile(`\d+(?:[.,]\d{1,3})?`)
+	// 020 $c: "USD 39.99", "39.99 EUR"
+	marcCurrencyPattern = regexp.MustCompile(`\b[A-Z]{3}\b`)
+	// 300 $a: "xvi, 380 p." или "380 pages"
+	marcPagesPattern = regexp.MustCompile(`(\d+)\s*(?:pages?|p\b)`)
+)
+
+// truncateUTF8 обрезает строку до max байт, не разрывая символ
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// BookToMARC переводит книгу в запись MARC21:
+// 020 ISBN и цена, 100/700 авторы, 245 название, 264 издатель, 300 объём, 520 аннотация, 650 темы, 655 жанр
+func BookToMARC(book models.Book) marc.Record {
+	var rec marc.Record
+	rec.AddControl("001", strconv.FormatUint(uint64(book.ID), 10))
+	rec.AddControl("003", "bookshelf")
+	if !book.UpdatedAt.IsZero() {
+		rec.AddControl("005", book.UpdatedAt.UTC().Format("20060102150405.0"))
+	}
+
+	price := ""
+	if book.Price > 0 {
+		price = money.New(book.Price, book.Currency).String()
+	}
+	rec.AddField("020", " ", " ", "a", book.ISBN, "c", price)
+
+	authors := splitAuthors(book.Author)
+	if len(authors) > 0 {
+		rec.AddField("100", "1", " ", "a", invertName(authors[0]))
+	}
+
+	title, subtitle, _ := strings.Cut(book.Title, ": ")
+	ind1 := "0"
+	if len(authors) > 0 {
+		ind1 = "1"
+	}
+	rec.AddField("245", ind1, "0", "a", title, "b", subtitle)
+	rec.AddField("264", " ", "1", "b", book.Publisher)
+	if book.Pages > 0 {
+		rec.AddField("300", " ", " ", "a", fmt.Sprintf("%d pages", book.Pages))
+	}
+	rec.AddField("520", " ", " ", "a", truncateUTF8(book.Description, maxMARCDescription))
+	for _, subject := range book.Subjects {
+		rec.AddField("650", " ", "0", "a", subject)
+	}
+	rec.AddField("655", " ", "7", "a", book.Genre, "2", "local")
+	for _, author := range authors[min(1, len(authors)):] {
+		rec.AddField("700", "1", " ", "a", invertName(author))
+	}
+	return rec
+}
+
+// MARCToBookRequest переводит запись MARC21 в BookRequest. Жанр берётся из 655, затем из первой темы 650.
+// Если в записи нет аннотации 520, в описание попадает полная область заглавия 245,
+// чтобы запись партнёрской библиотеки не отбрасывалась целиком
+func MARCToBookRequest(rec marc.Record) BookRequest {
+	req := BookRequest{}
+
+	title := rec.Field("245")
+	req.Title = marc.Clean(title.Subfield("a")) + subtitlePart(title.Subfield("b"))
+
+	var authors []string
+	if main := cleanName(rec.Field("100").Subfield("a")); main != "" {
+		authors = append(authors, uninvertName(main))
+	}
+	for _, added := range rec.Fields("700") {
+		if name := cleanName(added.Subfield("a")); name != "" {
+			authors = append(authors, uninvertName(name))
+		}
+	}
+	req.Author = strings.Join(authors, ", ")
+
+	for _, f := range rec.Fields("020") {
+		// В 020 $a после ISBN бывает уточнение: "9780134190440 (paperback)"
+		if parts := strings.Fields(f.Subfield("a")); req.ISBN == "" && len(parts) > 0 {
+			req.ISBN = parts[0]
+		}
+		if req.Price == "" {
+			req.Price, req.Currency = parseMARCPrice(f.Subfield("c"))
+		}
+	}
+
+	publisher := rec.Field("264")
+	if publisher == nil {
+		publisher = rec.Field("260")
+	}
+	req.Publisher = marc.Clean(publisher.Subfield("b"))
+
+	if match := marcPagesPattern.FindStringSubmatch(rec.Field("300").Subfield("a")); match != nil {
+		req.Pages, _ = strconv.Atoi(match[1])
+	}
+
+	for _, f := range rec.Fields("650") {
+		if subject := marc.Clean(f.Subfield("a")); subject != "" {
+			req.Subjects = append(req.Subjects, subject)
+		}
+	}
+
+	req.Genre = marc.Clean(rec.Field("655").Subfield("a"))
+	if req.Genre == "" && len(req.Subjects) > 0 {
+		req.Genre = req.Subjects[0]
+	}
+	if req.Genre == "" {
+		req.Genre = defaultMARCGenre
+	}
+
+	req.Description = strings.TrimSpace(rec.Field("520").Subfield("a"))
+	if req.Description == "" && title != nil {
+		parts := make([]string, 0, len(title.Subfields))
+		for _, sf := range title.Subfields {
+			parts = append(parts, strings.TrimSpace(sf.Value))
+		}
+		req.Description = marc.Clean(strings.Join(parts, " "))
+	}
+	return req
+}
+
+func subtitlePart(subtitle string) string {
+	subtitle = marc.Clean(subtitle)
+	if subtitle == "" {
+		return ""
+	}
+	return ": " + subtitle
+}
+
+func splitAuthors(author string) []string {
+	var authors []string
+	for _, name := range strings.Split(author, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// invertName: "Alan A. A. Donovan" -> "Donovan, Alan A. A."
+func invertName(name string) string {
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name
+	}
+	return fmt.Sprintf("%s, %s", name[i+1:], name[:i])
+}
+
+// cleanName убирает пунктуацию ISBD, но не точку после инициала: "Donovan, Alan A. A.," -> "Donovan, Alan A. A."
+func cleanName(name string) string {
+	name = strings.TrimRight(strings.TrimSpace(name), " ,;:/")
+	if strings.HasSuffix(name, ".") {
+		words := strings.Fields(name)
+		if last := words[len(words)-1]; len([]rune(last)) > 2 {
+			name = strings.TrimSuffix(name, ".")
+		}
+	}
+	return name
+}
+
+// uninvertName: "Donovan, Alan A. A." -> "Alan A. A. Donovan"
+func uninvertName(name string) string {
+	last, first, ok := strings.Cut(name, ", ")
+	if !ok {
+		return name
+	}
+	return strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+}
+
+// parseMARCPrice достаёт из 020 $c сумму и код валюты, если он указан; иначе валюта пустая (базовая)
+func parseMARCPrice(raw string) (money.Decimal, string) {
+	match := marcPricePattern.FindString(raw)
+	if match == "" {
+		return "", ""
+	}
+	currency, err := money.NormalizeCurrency(marcCurrencyPattern.FindString(raw))
+	if err != nil {
+		currency = ""
+	}
+	return money.Decimal(strings.ReplaceAll(match, ",", ".")), currency
+}
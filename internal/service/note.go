@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxNoteLength = 10000
+	maxNoteTags   = 20
+	maxTagLength  = 50
+)
+
+type NoteRequest struct {
+	Kind       string   `json:"kind" example:"highlight"`
+	Quote      string   `json:"quote" example:"Don't communicate by sharing memory"`
+	Text       string   `json:"text" example:"Главная идея главы про каналы"`
+	Page       *int     `json:"page" example:"231"`
+	Location   string   `json:"location" example:"epubcfi(/6/14!/4/2/1:0)"`
+	Tags       []string `json:"tags" example:"concurrency"`
+	Visibility string   `json:"visibility" example:"private"`
+}
+
+type NoteService interface {
+	CreateNote(userID, bookID uint, req NoteRequest) (models.Note, error)
+	UpdateNote(userID, id uint, req NoteRequest) (models.Note, error)
+	DeleteNote(userID, id uint) error
+	// ListUserNotes ищет по своим заметкам: query - полнотекстовый запрос, tag и bookID сужают выборку
+	ListUserNotes(userID, bookID uint, tag, query string, page, limit int) ([]models.Note, int64, error)
+	ListPublicBookNotes(bookID uint, page, limit int) ([]models.Note, int64, error)
+	ExportMarkdown(userID, bookID uint) (string, error)
+}
+
+type noteService struct {
+	repo     repository.NoteRepository
+	bookRepo repository.BookRepository
+}
+
+func NewNoteService(repo repository.NoteRepository, bookRepo repository.BookRepository) NoteService {
+	return &noteService{repo: repo, bookRepo: bookRepo}
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+}
+
+func validateNote(req *NoteRequest) error {
+	req.Quote = strings.TrimSpace(req.Quote)
+	req.Text = strings.TrimSpace(req.Text)
+	req.Location = strings.TrimSpace(req.Location)
+	if req.Kind == "" {
+		req.Kind = models.NoteKindNote
+		if req.Quote != "" {
+			req.Kind = models.NoteKindHighlight
+		}
+	}
+	if req.Visibility == "" {
+		req.Visibility = models.NoteVisibilityPrivate
+	}
+
+	switch req.Kind {
+	case models.NoteKindNote, models.NoteKindHighlight, models.NoteKindQuote:
+	default:
+		return errors.New("invalid kind, must be 'note', 'highlight' or 'quote'")
+	}
+	if req.Visibility != models.NoteVisibilityPrivate && req.Visibility != models.NoteVisibilityPublic {
+		return errors.New("invalid visibility, must be 'private' or 'public'")
+	}
+	if req.Kind != models.NoteKindNote && req.Quote == "" {
+		return errors.New("quote is required for highlights and quotes")
+	}
+	if req.Quote == "" && req.Text == "" {
+		return errors.New("text is required")
+	}
+	if utf8.RuneCountInString(req.Quote)+utf8.RuneCountInString(req.Text) > maxNoteLength {
+		return fmt.Errorf("invalid note, must be at most %d characters", maxNoteLength)
+	}
+	if req.Page != nil && *req.Page < 1 {
+		return errors.New("invalid page, must be positive")
+	}
+
+	seen := make(map[string]bool)
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return fmt.Errorf("invalid tag %q, must be at most %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxNoteTags {
+		return fmt.Errorf("invalid tags, at most %d allowed", maxNoteTags)
+	}
+	req.Tags = tags
+	return nil
+}
+
+func applyNote(note *models.Note, req NoteRequest) {
+	note.Kind = req.Kind
+	note.Quote = req.Quote
+	note.Text = req.Text
+	note.Page = req.Page
+	note.Location = req.Location
+	note.Tags = req.Tags
+	note.Visibility = req.Visibility
+}
+
+func (s *noteService) CreateNote(userID, bookID uint, req NoteRequest) (models.Note, error) {
+	if err := validateNote(&req); err != nil {
+		return models.Note{}, err
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return models.Note{}, errors.New("book not found")
+	}
+
+	note := models.Note{UserID: userID, BookID: bookID}
+	applyNote(&note, req)
+	if err := s.repo.CreateNote(&note); err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// getOwn: чужая заметка выглядит как несуществующая
+func (s *noteService) getOwn(userID, id uint) (models.Note, error) {
+	note, err := s.repo.GetNote(id)
+	if err != nil || note.UserID != userID {
+		return models.Note{}, errors.New("note not found")
+	}
+	return note, nil
+}
+
+func (s *noteService) UpdateNote(userID, id uint, req NoteRequest) (models.Note, error) {
+	if err := validateNote(&req); err != nil {
+		return models.Note{}, err
+	}
+	note, err := s.getOwn(userID, id)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	applyNote(&note, req)
+	if err := s.repo.UpdateNote(&note); err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+func (s *noteService) DeleteNote(userID, id uint) error {
+	if _, err := s.getOwn(userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteNote(id)
+}
+
+func (s *noteService) ListUserNotes(userID, bookID uint, tag, query string, page, limit int) ([]models.Note, int64, error) {
+	return s.repo.ListNotes(repository.NoteFilter{
+		UserID: userID,
+		BookID: bookID,
+		Tag:    normalizeTag(tag),
+		Query:  strings.TrimSpace(query),
+	}, page, limit)
+}
+
+func (s *noteService) ListPublicBookNotes(bookID uint, page, limit int) ([]models.Note, int64, error) {
+	return s.repo.ListNotes(repository.NoteFilter{BookID: bookID, PublicOnly: true}, page, limit)
+}
+
+// ExportMarkdown собирает все заметки пользователя к книге в один Markdown-документ по порядку страниц
+func (s *noteService) ExportMarkdown(userID, bookID uint) (string, error) {
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return "", errors.New("book not found")
+	}
+	notes, err := s.repo.GetBookNotes(userID, bookID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", book.Title)
+	if book.Author != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", book.Author)
+	}
+	if len(notes) == 0 {
+		b.WriteString("Заметок пока нет.\n")
+		return b.String(), nil
+	}
+
+	for i, note := range notes {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", noteHeading(note))
+		if note.Quote != "" {
+			for _, line := range strings.Split(note.Quote, "\n") {
+				fmt.Fprintf(&b, "> %s\n", line)
+			}
+			b.WriteString("\n")
+		}
+		if note.Text != "" {
+			fmt.Fprintf(&b, "%s\n\n", note.Text)
+		}
+		if len(note.Tags) > 0 {
+			tags := make([]string, len(note.Tags))
+			for i, tag := range note.Tags {
+				tags[i] = "#" + strings.ReplaceAll(tag, " ", "-")
+			}
+			fmt.Fprintf(&b, "%s\n\n", strings.Join(tags, " "))
+		}
+		fmt.Fprintf(&b, "_%s_\n", note.CreatedAt.Format("2006-01-02"))
+	}
+	return b.String(), nil
+}
+
+var noteKindTitles = map[string]string{
+	models.NoteKindNote:      "Заметка",
+	models.NoteKindHighlight: "Выделение",
+	models.NoteKindQuote:     "Цитата",
+}
+
+func noteHeading(note models.Note) string {
+	parts := []string{noteKindTitles[note.Kind]}
+	if note.Page != nil {
+		parts = append(parts, fmt.Sprintf("стр. %d", *note.Page))
+	}
+	if note.Location != "" {
+		parts = append(parts, note.Location)
+	}
+	return strings.Join(parts, " · ")
+}
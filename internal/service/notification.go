@@ -0,0 +1,346 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/notify"
+	"bookshelf/pkg/textutil"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationSettingsRequest struct {
+	PriceDrops  bool   `json:"price_drops" example:"true"`
+	NewArrivals bool   `json:"new_arrivals" example:"true"`
+	Email       string `json:"email" example:"reader@example.com"`
+	WebhookURL  string `json:"webhook_url" example:"https://example.com/hooks/bookshelf"`
+}
+
+// Notifier сохраняет уведомления и рассылает их по внешним каналам пользователей
+type Notifier interface {
+	Notify(items []models.Notification) error
+}
+
+type NotificationService interface {
+	// Снижение цены и новые книги из событий каталога превращаются в уведомления
+	BookListener
+	// Notify - служебные уведомления (напоминания о выдачах), настройки событий на них не действуют
+	Notifier
+	// ListNotifications возвращает страницу уведомлений и общее число непрочитанных
+	ListNotifications(userID uint, unreadOnly bool, page, limit int) ([]models.Notification, int64, int64, error)
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) error
+	GetSettings(userID uint) (models.NotificationSettings, error)
+	UpdateSettings(userID uint, req NotificationSettingsRequest) (models.NotificationSettings, error)
+}
+
+type notificationService struct {
+	repo       repository.NotificationRepository
+	favRepo    repository.FavouriteRepository
+	followRepo repository.FollowRepository
+	channels   []notify.Channel
+}
+
+func NewNotificationService(
+	repo repository.NotificationRepository,
+	favRepo repository.FavouriteRepository,
+	followRepo repository.FollowRepository,
+	channels ...notify.Channel,
+) NotificationService {
+	return &notificationService{repo: repo, favRepo: favRepo, followRepo: followRepo, channels: channels}
+}
+
+func (s *notificationService) ListNotifications(userID uint, unreadOnly bool, page, limit int) ([]models.Notification, int64, int64, error) {
+	items, total, err := s.repo.ListNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return items, total, unread, nil
+}
+
+func (s *notificationService) MarkRead(userID, id uint) error {
+	found, err := s.repo.MarkRead(userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(userID uint) error {
+	return s.repo.MarkAllRead(userID)
+}
+
+func (s *notificationService) GetSettings(userID uint) (models.NotificationSettings, error) {
+	settings, err := s.repo.GetSettings(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultNotificationSettings(userID), nil
+	}
+	return settings, err
+}
+
+func (s *notificationService) UpdateSettings(userID uint, req NotificationSettingsRequest) (models.NotificationSettings, error) {
+	req.Email = strings.TrimSpace(req.Email)
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	if req.Email != "" {
+		if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+			return models.NotificationSettings{}, errors.New("invalid email")
+		}
+	}
+	if req.WebhookURL != "" {
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return models.NotificationSettings{}, errors.New("invalid webhook_url, must be an http(s) URL")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = notify.CheckWebhookURL(ctx, req.WebhookURL)
+		cancel()
+		if errors.Is(err, notify.ErrPrivateAddress) {
+			return models.NotificationSettings{}, errors.New("invalid webhook_url, must point to a public address")
+		}
+		if err != nil {
+			return models.NotificationSettings{}, errors.New("invalid webhook_url, host cannot be resolved")
+		}
+	}
+
+	settings := models.NotificationSettings{
+		UserID:      userID,
+		PriceDrops:  req.PriceDrops,
+		NewArrivals: req.NewArrivals,
+		Email:       req.Email,
+		WebhookURL:  req.WebhookURL,
+	}
+	if err := s.repo.SaveSettings(&settings); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	return settings, nil
+}
+
+func (s *notificationService) BookChanged(event BookEvent) {
+	var items []models.Notification
+	var err error
+	switch event.Type {
+	case BookUpdated:
+		items, err = s.priceDrops(event)
+	case BooksCreated:
+		items, err = s.newArrivals(event.Books)
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("notifications: %s", err.Error())
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	settings, err := s.settingsFor(items)
+	if err != nil {
+		log.Printf("notifications: %s", err.Error())
+		return
+	}
+	items = filterBySettings(items, settings)
+	if err := s.repo.CreateNotifications(items); err != nil {
+		log.Printf("notifications: %s", err.Error())
+		return
+	}
+	// Внешние каналы медленные и ненадёжные, запрос изменения книги их не ждёт
+	go s.deliver(items, settings)
+}
+
+func (s *notificationService) Notify(items []models.Notification) error {
+	if len(items) == 0 {
+		return nil
+	}
+	settings, err := s.settingsFor(items)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateNotifications(items); err != nil {
+		return err
+	}
+	go s.deliver(items, settings)
+	return nil
+}
+
+func (s *notificationService) priceDrops(event BookEvent) ([]models.Notification, error) {
+	if event.Previous == nil || len(event.Books) == 0 {
+		return nil, nil
+	}
+	// Цены в разных валютах не сравниваются: смена валюты - не скидка
+	book, oldPrice := event.Books[0], event.Previous.Price
+	if book.Currency != event.Previous.Currency || book.Price >= oldPrice {
+		return nil, nil
+	}
+
+	userIDs, err := s.favRepo.GetFavouritedBy(book.ID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		newPrice := book.Price
+		items = append(items, models.Notification{
+			UserID: userID,
+			Type:   models.NotificationPriceDrop,
+			BookID: book.ID,
+			Title:  fmt.Sprintf("Цена снижена: «%s»", book.Title),
+			Body: fmt.Sprintf("Цена книги «%s» из вашего избранного снизилась с %s до %s", book.Title,
+				money.New(oldPrice, book.Currency), money.New(newPrice, book.Currency)),
+			OldPrice: &oldPrice,
+			NewPrice: &newPrice,
+			Currency: book.Currency,
+		})
+	}
+	return items, nil
+}
+
+// newArrivals уведомляет подписчиков автора и жанра. Подписчик и автора, и жанра получает одно уведомление
+func (s *notificationService) newArrivals(books []models.Book) ([]models.Notification, error) {
+	authorKeys := make([]string, 0, len(books))
+	genreKeys := make([]string, 0, len(books))
+	for _, book := range books {
+		authorKeys = append(authorKeys, textutil.Normalize(book.Author))
+		genreKeys = append(genreKeys, textutil.Normalize(book.Genre))
+	}
+
+	authorFollowers, err := s.followers(models.FollowAuthor, authorKeys)
+	if err != nil {
+		return nil, err
+	}
+	genreFollowers, err := s.followers(models.FollowGenre, genreKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.Notification
+	for i, book := range books {
+		notified := make(map[uint]bool)
+		for _, userID := range authorFollowers[authorKeys[i]] {
+			notified[userID] = true
+			items = append(items, models.Notification{
+				UserID: userID,
+				Type:   models.NotificationAuthorArrival,
+				BookID: book.ID,
+				Title:  fmt.Sprintf("Новая книга: %s, «%s»", book.Author, book.Title),
+				Body:   fmt.Sprintf("В каталоге появилась книга «%s» автора %s, на которого вы подписаны", book.Title, book.Author),
+			})
+		}
+		for _, userID := range genreFollowers[genreKeys[i]] {
+			if notified[userID] {
+				continue
+			}
+			items = append(items, models.Notification{
+				UserID: userID,
+				Type:   models.NotificationGenreArrival,
+				BookID: book.ID,
+				Title:  fmt.Sprintf("Новая книга в жанре %s: «%s»", book.Genre, book.Title),
+				Body:   fmt.Sprintf("В каталоге появилась книга «%s» (%s) в жанре %s, на который вы подписаны", book.Title, book.Author, book.Genre),
+			})
+		}
+	}
+	return items, nil
+}
+
+func (s *notificationService) followers(targetType string, keys []string) (map[string][]uint, error) {
+	follows, err := s.followRepo.GetFollowers(targetType, keys)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string][]uint)
+	for _, f := range follows {
+		byKey[f.TargetKey] = append(byKey[f.TargetKey], f.UserID)
+	}
+	return byKey, nil
+}
+
+func (s *notificationService) settingsFor(items []models.Notification) (map[uint]models.NotificationSettings, error) {
+	settings := make(map[uint]models.NotificationSettings)
+	userIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if _, ok := settings[item.UserID]; !ok {
+			settings[item.UserID] = models.DefaultNotificationSettings(item.UserID)
+			userIDs = append(userIDs, item.UserID)
+		}
+	}
+
+	saved, err := s.repo.GetSettingsFor(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range saved {
+		settings[st.UserID] = st
+	}
+	return settings, nil
+}
+
+func filterBySettings(items []models.Notification, settings map[uint]models.NotificationSettings) []models.Notification {
+	filtered := items[:0]
+	for _, item := range items {
+		st := settings[item.UserID]
+		if item.Type == models.NotificationPriceDrop && !st.PriceDrops {
+			continue
+		}
+		if item.Type != models.NotificationPriceDrop && !st.NewArrivals {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// recipient - адрес пользователя для канала; пустой адрес означает, что канал выключен
+func recipient(settings models.NotificationSettings, channel string) string {
+	switch channel {
+	case "email":
+		return settings.Email
+	case "webhook":
+		return settings.WebhookURL
+	}
+	return ""
+}
+
+func (s *notificationService) deliver(items []models.Notification, settings map[uint]models.NotificationSettings) {
+	if len(s.channels) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	for _, item := range items {
+		for _, ch := range s.channels {
+			to := recipient(settings[item.UserID], ch.Name())
+			if to == "" {
+				continue
+			}
+			err := ch.Send(ctx, notify.Message{
+				To:        to,
+				UserID:    item.UserID,
+				Type:      item.Type,
+				Subject:   item.Title,
+				Body:      item.Body,
+				BookID:    item.BookID,
+				CreatedAt: item.CreatedAt,
+			})
+			if err != nil {
+				log.Printf("notifications: %s delivery to user %d failed: %s", ch.Name(), item.UserID, err.Error())
+			}
+		}
+	}
+}
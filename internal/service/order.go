@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OrderCheckoutRequest: тело запроса необязательно
+type OrderCheckoutRequest struct {
+	Coupon string `json:"coupon" example:"GOPHER20"`
+}
+
+type OrderStatusRequest struct {
+	Status string `json:"status" example:"fulfilled"`
+}
+
+// orderTransitions - допустимые переходы статусов заказа
+var orderTransitions = map[string][]string{
+	models.OrderPending:   {models.OrderPaid, models.OrderCancelled},
+	models.OrderPaid:      {models.OrderFulfilled, models.OrderCancelled, models.OrderRefunded},
+	models.OrderFulfilled: {models.OrderRefunded},
+}
+
+func canTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderService interface {
+	// Checkout оформляет заказ из корзины по текущим ценам со скидками в валюте currency (пусто - базовая)
+	// и очищает корзину. Купон засчитывается, только если снизил цену хотя бы одной книги.
+	// Книги с учётом остатков откладываются на складе до оплаты, но не дольше срока резерва
+	Checkout(userID uint, currency string, req OrderCheckoutRequest) (models.Order, error)
+	// GetOrder: покупатель видит свои заказы, администратор - все
+	GetOrder(userID uint, role string, id uint) (models.Order, error)
+	ListUserOrders(userID uint, page, limit int) ([]models.Order, int64, error)
+	ListOrders(status string, page, limit int) ([]models.Order, int64, error)
+	// Cancel - отмена покупателем, только пока заказ не оплачен
+	Cancel(userID, id uint) (models.Order, error)
+	// UpdateStatus - ручная смена статуса администратором, только отметка о выполнении оплаченного заказа.
+	// Оплата проходит через шлюз, возврат и отмена оплаченного заказа - через PaymentService.Refund
+	UpdateStatus(id uint, req OrderStatusRequest) (models.Order, error)
+}
+
+type orderService struct {
+	repo      repository.OrderRepository
+	cartRepo  repository.CartRepository
+	pricing   PricingService
+	discounts DiscountService
+	// reserveFor - сколько неоплаченный заказ держит товар на складе
+	reserveFor time.Duration
+}
+
+func NewOrderService(repo repository.OrderRepository, cartRepo repository.CartRepository, pricing PricingService, discounts DiscountService, reserveFor time.Duration) OrderService {
+	return &orderService{repo: repo, cartRepo: cartRepo, pricing: pricing, discounts: discounts, reserveFor: reserveFor}
+}
+
+func (s *orderService) Checkout(userID uint, currency string, req OrderCheckoutRequest) (models.Order, error) {
+	items, err := s.cartRepo.ListItems(userID)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if len(items) == 0 {
+		return models.Order{}, errors.New("invalid cart: it is empty")
+	}
+
+	var coupon *models.Discount
+	if req.Coupon != "" {
+		found, err := s.discounts.Coupon(req.Coupon)
+		if err != nil {
+			return models.Order{}, err
+		}
+		coupon = &found
+	}
+	cart, err := priceCart(s.pricing, s.discounts, items, currency, coupon)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if coupon != nil && cart.Coupon == "" {
+		return models.Order{}, errors.New("invalid coupon: it gives no discount on the books in the cart")
+	}
+	reservedUntil := time.Now().Add(s.reserveFor)
+	order := models.Order{
+		UserID:        userID,
+		Status:        models.OrderPending,
+		Total:         cart.Total,
+		Currency:      cart.Currency,
+		ReservedUntil: &reservedUntil,
+		Items:         make([]models.OrderItem, 0, len(cart.Lines)),
+	}
+	if coupon != nil {
+		order.CouponID = &coupon.ID
+		order.CouponCode = cart.Coupon
+	}
+	for i, line := range cart.Lines {
+		order.Items = append(order.Items, models.OrderItem{
+			BookID:    line.Book.ID,
+			Title:     items[i].Book.Title,
+			Author:    items[i].Book.Author,
+			UnitPrice: line.UnitPrice,
+			Quantity:  line.Quantity,
+			LineTotal: line.LineTotal,
+		})
+	}
+
+	if err := s.repo.CreateFromCart(&order, items); err != nil {
+		if errors.Is(err, repository.ErrCartChanged) {
+			return models.Order{}, errors.New("checkout unavailable: cart changed, review it and try again")
+		}
+		if errors.Is(err, repository.ErrCouponExhausted) {
+			return models.Order{}, errors.New("coupon unavailable: usage limit reached")
+		}
+		var stockErr *repository.OutOfStockError
+		if errors.As(err, &stockErr) {
+			return models.Order{}, fmt.Errorf("checkout unavailable: only %d of «%s» left in stock", stockErr.Available, bookTitle(order.Items, stockErr.BookID))
+		}
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+func bookTitle(items []models.OrderItem, bookID uint) string {
+	for _, item := range items {
+		if item.BookID == bookID {
+			return item.Title
+		}
+	}
+	return ""
+}
+
+func (s *orderService) GetOrder(userID uint, role string, id uint) (models.Order, error) {
+	order, err := s.repo.GetOrder(id)
+	if err != nil || (role != "admin" && order.UserID != userID) {
+		return models.Order{}, errors.New("order not found")
+	}
+	return order, nil
+}
+
+func (s *orderService) ListUserOrders(userID uint, page, limit int) ([]models.Order, int64, error) {
+	return s.repo.ListOrders(repository.OrderFilter{UserID: userID}, page, limit)
+}
+
+func (s *orderService) ListOrders(status string, page, limit int) ([]models.Order, int64, error) {
+	if status != "" && !isOrderStatus(status) {
+		return nil, 0, errors.New("invalid status")
+	}
+	return s.repo.ListOrders(repository.OrderFilter{Status: status}, page, limit)
+}
+
+func isOrderStatus(status string) bool {
+	switch status {
+	case models.OrderPending, models.OrderPaid, models.OrderFulfilled, models.OrderCancelled, models.OrderRefunded:
+		return true
+	}
+	return false
+}
+
+func (s *orderService) Cancel(userID, id uint) (models.Order, error) {
+	order, err := s.GetOrder(userID, "", id)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if order.Status != models.OrderPending {
+		return models.Order{}, fmt.Errorf("invalid status transition: %s order cannot be cancelled, contact support", order.Status)
+	}
+	return s.transition(order, models.OrderCancelled)
+}
+
+func (s *orderService) UpdateStatus(id uint, req OrderStatusRequest) (models.Order, error) {
+	switch req.Status {
+	case models.OrderFulfilled:
+	case models.OrderPaid:
+		return models.Order{}, errors.New("invalid status: orders are marked paid only by the payment gateway")
+	case models.OrderRefunded, models.OrderCancelled:
+		return models.Order{}, errors.New("invalid status: refund or cancel a paid order with POST /admin/orders/{id}/refund")
+	default:
+		return models.Order{}, errors.New("invalid status, must be 'fulfilled'")
+	}
+	order, err := s.repo.GetOrder(id)
+	if err != nil {
+		return models.Order{}, errors.New("order not found")
+	}
+	return s.transition(order, req.Status)
+}
+
+func (s *orderService) transition(order models.Order, to string) (models.Order, error) {
+	if !canTransition(order.Status, to) {
+		return models.Order{}, fmt.Errorf("invalid status transition from %s to %s", order.Status, to)
+	}
+	updated, err := s.repo.UpdateStatus(&order, order.Status, to)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if !updated {
+		return models.Order{}, errors.New("order unavailable: its status changed concurrently, reload it")
+	}
+	return order, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/payment"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type PaymentService interface {
+	// Pay начинает оплату своего заказа в статусе pending. Повторный вызов возвращает ту же попытку,
+	// пока она не завершилась отказом
+	Pay(ctx context.Context, userID, orderID uint) (models.Payment, error)
+	// HandleWebhook проверяет подпись события провайдера и переводит платёж и заказ в новый статус.
+	// Повторная доставка того же события ничего не меняет
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+	// Refund возвращает деньги за оплаченный или выполненный заказ; заказ станет refunded по вебхуку
+	Refund(ctx context.Context, orderID uint) (models.Payment, error)
+}
+
+type paymentService struct {
+	repo      repository.PaymentRepository
+	orderRepo repository.OrderRepository
+	provider  payment.Provider
+}
+
+func NewPaymentService(repo repository.PaymentRepository, orderRepo repository.OrderRepository, provider payment.Provider) PaymentService {
+	return &paymentService{repo: repo, orderRepo: orderRepo, provider: provider}
+}
+
+func (s *paymentService) Pay(ctx context.Context, userID, orderID uint) (models.Payment, error) {
+	order, err := s.orderRepo.GetOrder(orderID)
+	if err != nil || order.UserID != userID {
+		return models.Payment{}, errors.New("order not found")
+	}
+	if order.Status != models.OrderPending {
+		return models.Payment{}, fmt.Errorf("payment unavailable: order is %s", order.Status)
+	}
+
+	active, err := s.repo.GetActiveForOrder(order.ID)
+	if err == nil {
+		return active, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Payment{}, err
+	}
+
+	// Ключ идемпотентности зависит от номера попытки: параллельные запросы получат от провайдера один
+	// и тот же платёж, а после отказа можно начать новый
+	attempts, err := s.repo.CountPayments(order.ID)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	intent, err := s.provider.CreateIntent(ctx, payment.IntentParams{
+		Amount:         order.Total,
+		Currency:       order.Currency,
+		OrderID:        order.ID,
+		IdempotencyKey: fmt.Sprintf("order-%d-attempt-%d", order.ID, attempts+1),
+	})
+	if err != nil {
+		log.Printf("payments: create intent for order %d: %v", order.ID, err)
+		return models.Payment{}, errors.New("payment provider unavailable, try again later")
+	}
+
+	p := models.Payment{
+		OrderID:      order.ID,
+		Provider:     s.provider.Name(),
+		IntentID:     intent.ID,
+		ClientSecret: intent.ClientSecret,
+		Amount:       order.Total,
+		Currency:     order.Currency,
+		Status:       models.PaymentPending,
+	}
+	if err := s.repo.CreatePayment(&p); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return s.repo.GetByIntent(p.Provider, p.IntentID)
+		}
+		return models.Payment{}, err
+	}
+	return p, nil
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	event, err := s.provider.VerifyWebhook(payload, header)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return errors.New("invalid webhook signature")
+		}
+		return errors.New("invalid webhook payload")
+	}
+
+	seen, err := s.repo.HasEvent(s.provider.Name(), event.ID)
+	if err != nil || seen {
+		return err
+	}
+	// Событие запоминается только после обработки: при ошибке провайдер доставит его снова,
+	// а переходы статусов условные, поэтому параллельная повторная доставка ничего не испортит
+	if err := s.apply(ctx, event); err != nil {
+		return err
+	}
+	return s.repo.RecordEvent(&models.PaymentEvent{Provider: s.provider.Name(), EventID: event.ID, Type: event.Type})
+}
+
+func (s *paymentService) apply(ctx context.Context, event payment.Event) error {
+	if event.Type == "" {
+		return nil
+	}
+	p, err := s.repo.GetByIntent(s.provider.Name(), event.IntentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("payments: %s event %s for unknown intent %s ignored", event.Type, event.ID, event.IntentID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	order, err := s.orderRepo.GetOrder(p.OrderID)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payment.EventAuthorized:
+		return s.capture(ctx, &p, order, event)
+	case payment.EventSucceeded:
+		return s.succeed(ctx, &p, order)
+	case payment.EventFailed:
+		_, err := s.repo.UpdateStatus(&p, []string{models.PaymentPending, models.PaymentAuthorized}, models.PaymentFailed, event.Reason)
+		return err
+	case payment.EventRefunded:
+		if _, err := s.repo.UpdateStatus(&p, []string{models.PaymentSucceeded, models.PaymentRefundPending}, models.PaymentRefunded, ""); err != nil {
+			return err
+		}
+		if canTransition(order.Status, models.OrderRefunded) {
+			_, err := s.orderRepo.UpdateStatus(&order, order.Status, models.OrderRefunded)
+			return err
+		}
+	}
+	return nil
+}
+
+// capture списывает заблокированные деньги, только если заказ всё ещё ждёт оплаты, а сумма и валюта совпадают.
+// Иначе списания не будет, и провайдер сам снимет блокировку
+func (s *paymentService) capture(ctx context.Context, p *models.Payment, order models.Order, event payment.Event) error {
+	if order.Status != models.OrderPending || event.Amount != p.Amount || event.Currency != p.Currency {
+		reason := fmt.Sprintf("not captured: order is %s, authorized %s %s of %s %s",
+			order.Status, event.Amount, event.Currency, p.Amount, p.Currency)
+		_, err := s.repo.UpdateStatus(p, []string{models.PaymentPending}, models.PaymentFailed, reason)
+		return err
+	}
+	if _, err := s.repo.UpdateStatus(p, []string{models.PaymentPending}, models.PaymentAuthorized, ""); err != nil {
+		return err
+	}
+	if p.Status != models.PaymentAuthorized {
+		return nil
+	}
+	_, err := s.provider.Capture(ctx, p.IntentID)
+	return err
+}
+
+func (s *paymentService) succeed(ctx context.Context, p *models.Payment, order models.Order) error {
+	if _, err := s.repo.UpdateStatus(p, []string{models.PaymentPending, models.PaymentAuthorized}, models.PaymentSucceeded, ""); err != nil {
+		return err
+	}
+	if order.Status == models.OrderPending {
+		updated, err := s.orderRepo.UpdateStatus(&order, models.OrderPending, models.OrderPaid)
+		if err != nil || updated {
+			return err
+		}
+		if order, err = s.orderRepo.GetOrder(order.ID); err != nil {
+			return err
+		}
+	}
+	if order.Status == models.OrderPaid || p.Status != models.PaymentSucceeded {
+		return nil
+	}
+
+	// Деньги списаны, но заказ уже отменён: возвращаем их
+	log.Printf("payments: order %d is %s, refunding captured payment %d", order.ID, order.Status, p.ID)
+	return s.refund(ctx, p)
+}
+
+func (s *paymentService) Refund(ctx context.Context, orderID uint) (models.Payment, error) {
+	order, err := s.orderRepo.GetOrder(orderID)
+	if err != nil {
+		return models.Payment{}, errors.New("order not found")
+	}
+	if !canTransition(order.Status, models.OrderRefunded) {
+		return models.Payment{}, fmt.Errorf("invalid status: %s order cannot be refunded", order.Status)
+	}
+	p, err := s.repo.GetActiveForOrder(order.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Payment{}, errors.New("payment not found, order was paid outside the gateway")
+	}
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if p.Status != models.PaymentSucceeded {
+		return models.Payment{}, fmt.Errorf("refund unavailable: payment is %s", p.Status)
+	}
+	if err := s.refund(ctx, &p); err != nil {
+		return models.Payment{}, err
+	}
+	return p, nil
+}
+
+func (s *paymentService) refund(ctx context.Context, p *models.Payment) error {
+	updated, err := s.repo.StartRefund(p)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("refund unavailable: payment status changed concurrently")
+	}
+	// Провайдер хранит ответ на ключ вместе с ошибкой, поэтому у каждой попытки возврата свой ключ
+	key := fmt.Sprintf("payment-%d-refund-%d", p.ID, p.RefundAttempts)
+	if _, err := s.provider.Refund(ctx, p.IntentID, p.Amount, key); err != nil {
+		log.Printf("payments: refund payment %d: %v", p.ID, err)
+		if _, rollbackErr := s.repo.UpdateStatus(p, []string{models.PaymentRefundPending}, models.PaymentSucceeded, ""); rollbackErr != nil {
+			return rollbackErr
+		}
+		return errors.New("payment provider unavailable, refund not started")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/money"
+	"bookshelf/pkg/payment"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePaymentRepo хранит один платёж и повторяет условные переходы paymentRepo
+type fakePaymentRepo struct {
+	repository.PaymentRepository
+	payment models.Payment
+}
+
+func (r *fakePaymentRepo) UpdateStatus(p *models.Payment, from []string, to, reason string) (bool, error) {
+	for _, status := range from {
+		if r.payment.Status != status {
+			continue
+		}
+		r.payment.Status = to
+		if reason != "" {
+			r.payment.FailureReason = reason
+		}
+		*p = r.payment
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *fakePaymentRepo) StartRefund(p *models.Payment) (bool, error) {
+	if r.payment.Status != models.PaymentSucceeded {
+		return false, nil
+	}
+	r.payment.Status = models.PaymentRefundPending
+	r.payment.RefundAttempts++
+	*p = r.payment
+	return true, nil
+}
+
+// fakeCaptureProvider считает списания
+type fakeCaptureProvider struct {
+	payment.Provider
+	captured []string
+}
+
+func (p *fakeCaptureProvider) Capture(ctx context.Context, intentID string) (payment.Intent, error) {
+	p.captured = append(p.captured, intentID)
+	return payment.Intent{ID: intentID, Status: "succeeded"}, nil
+}
+
+// fakeRefundProvider запоминает ключи идемпотентности возвратов и отказывает, пока fail не сброшен
+type fakeRefundProvider struct {
+	payment.Provider
+	fail bool
+	keys []string
+}
+
+func (p *fakeRefundProvider) Refund(ctx context.Context, intentID string, amount money.Amount, idempotencyKey string) (payment.Refund, error) {
+	p.keys = append(p.keys, idempotencyKey)
+	if p.fail {
+		return payment.Refund{}, errors.New("payment: stripe responded with 500")
+	}
+	return payment.Refund{IntentID: intentID, Amount: amount}, nil
+}
+
+func TestPaymentService_Refund_RetryUsesNewIdempotencyKey(t *testing.T) {
+	repo := &fakePaymentRepo{payment: models.Payment{ID: 7, IntentID: "pi_1", Amount: 3998, Currency: "USD", Status: models.PaymentSucceeded}}
+	provider := &fakeRefundProvider{fail: true}
+	s := &paymentService{repo: repo, provider: provider}
+
+	p := repo.payment
+	assert.Error(t, s.refund(context.Background(), &p))
+	assert.Equal(t, models.PaymentSucceeded, repo.payment.Status)
+
+	provider.fail = false
+	p = repo.payment
+	assert.NoError(t, s.refund(context.Background(), &p))
+
+	// Проверки
+	assert.Equal(t, models.PaymentRefundPending, repo.payment.Status)
+	assert.Equal(t, []string{"payment-7-refund-1", "payment-7-refund-2"}, provider.keys)
+}
+
+func TestPaymentService_Capture_ChecksAmountAndCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   money.Amount
+		currency string
+		captured bool
+		status   string
+	}{
+		{"matching", 3998, "USD", true, models.PaymentAuthorized},
+		{"other amount", 3997, "USD", false, models.PaymentFailed},
+		{"same minor units in another currency", 3998, "JPY", false, models.PaymentFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePaymentRepo{payment: models.Payment{ID: 7, IntentID: "pi_1", Amount: 3998, Currency: "USD", Status: models.PaymentPending}}
+			provider := &fakeCaptureProvider{}
+			s := &paymentService{repo: repo, provider: provider}
+
+			p := repo.payment
+			order := models.Order{ID: 3, Status: models.OrderPending}
+			event := payment.Event{Type: payment.EventAuthorized, IntentID: "pi_1", Amount: tt.amount, Currency: tt.currency}
+			assert.NoError(t, s.capture(context.Background(), &p, order, event))
+
+			// Проверки
+			assert.Equal(t, tt.captured, len(provider.captured) == 1)
+			assert.Equal(t, tt.status, repo.payment.Status)
+			if !tt.captured {
+				assert.Contains(t, repo.payment.FailureReason, tt.currency)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	viewsBufferKey      = "stats:views"
+	favouritesBufferKey = "stats:favourites"
+
+	// Повторный просмотр тем же посетителем в этом окне не считается
+	viewDedupeWindow      = 30 * time.Minute
+	favouriteDedupeWindow = 24 * time.Hour
+
+	// Добавление в избранное весит как несколько просмотров
+	favouriteWeight    = 5
+	trendingWindow     = 7
+	trendingHalfLife   = 2
+	popularityCacheTTL = 5 * time.Minute
+)
+
+var popularPeriods = map[string]int{"week": 7, "month": 30, "all": 0}
+
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|preview|headless|curl|wget|python-requests|go-http-client|okhttp|httpclient`)
+
+type PopularBook struct {
+	Book       BookBrief `json:"book"`
+	Views      int64     `json:"views"`
+	Favourites int64     `json:"favourites"`
+	Score      float64   `json:"score"`
+}
+
+type PopularityService interface {
+	// RecordView учитывает просмотр книги. viewer - устойчивый идентификатор посетителя
+	RecordView(bookID uint, viewer, userAgent string)
+	FavouriteAdded(userID, bookID uint)
+	// Flush переносит накопленные в Redis счётчики в book_stats_daily; запускается планировщиком
+	Flush(ctx context.Context) error
+	GetTrending(page, limit int) ([]PopularBook, int64, error)
+	GetPopular(period string, page, limit int) ([]PopularBook, int64, error)
+}
+
+type popularityService struct {
+	repo     repository.PopularityRepository
+	bookRepo repository.BookRepository
+	cache    cache.RedisCache
+}
+
+func NewPopularityService(repo repository.PopularityRepository, bookRepo repository.BookRepository, cache *cache.RedisCache) PopularityService {
+	return &popularityService{repo: repo, bookRepo: bookRepo, cache: *cache}
+}
+
+func IsBot(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botPattern.MatchString(userAgent)
+}
+
+func statsField(bookID uint, now time.Time) string {
+	return fmt.Sprintf("%d:%s", bookID, now.UTC().Format(time.DateOnly))
+}
+
+// Счётчики не должны ломать основной запрос, поэтому ошибки Redis здесь игнорируются
+func (s *popularityService) RecordView(bookID uint, viewer, userAgent string) {
+	if IsBot(userAgent) {
+		return
+	}
+	first, err := s.cache.SetIfAbsent(fmt.Sprintf("stats:seen:%d:%s", bookID, viewer), viewDedupeWindow)
+	if err != nil || !first {
+		return
+	}
+	s.cache.IncrementField(viewsBufferKey, statsField(bookID, time.Now()), 1)
+}
+
+func (s *popularityService) FavouriteAdded(userID, bookID uint) {
+	first, err := s.cache.SetIfAbsent(fmt.Sprintf("stats:faved:%d:%d", bookID, userID), favouriteDedupeWindow)
+	if err != nil || !first {
+		return
+	}
+	s.cache.IncrementField(favouritesBufferKey, statsField(bookID, time.Now()), 1)
+}
+
+func (s *popularityService) Flush(ctx context.Context) error {
+	views, err := s.cache.DrainHash(viewsBufferKey)
+	if err != nil {
+		return err
+	}
+	favourites, err := s.cache.DrainHash(favouritesBufferKey)
+	if err != nil {
+		s.restore(viewsBufferKey, views)
+		return err
+	}
+
+	rows := make(map[string]*models.BookStatsDaily)
+	collect := func(counters map[string]int64, apply func(row *models.BookStatsDaily, n int64)) {
+		for field, n := range counters {
+			id, day, ok := strings.Cut(field, ":")
+			bookID, err := strconv.ParseUint(id, 10, 64)
+			date, dateErr := time.Parse(time.DateOnly, day)
+			if !ok || err != nil || dateErr != nil {
+				continue
+			}
+			row, ok := rows[field]
+			if !ok {
+				row = &models.BookStatsDaily{BookID: uint(bookID), Day: date}
+				rows[field] = row
+			}
+			apply(row, n)
+		}
+	}
+	collect(views, func(row *models.BookStatsDaily, n int64) { row.Views += n })
+	collect(favourites, func(row *models.BookStatsDaily, n int64) { row.Favourites += n })
+
+	batch := make([]models.BookStatsDaily, 0, len(rows))
+	for _, row := range rows {
+		batch = append(batch, *row)
+	}
+	if err := s.repo.AddDailyStats(batch); err != nil {
+		// Возвращаем счётчики в буфер, следующая выгрузка попробует снова
+		s.restore(viewsBufferKey, views)
+		s.restore(favouritesBufferKey, favourites)
+		return err
+	}
+	return nil
+}
+
+func (s *popularityService) restore(key string, counters map[string]int64) {
+	for field, n := range counters {
+		s.cache.IncrementField(key, field, n)
+	}
+}
+
+func (s *popularityService) GetTrending(page, limit int) ([]PopularBook, int64, error) {
+	cacheKey := fmt.Sprintf("books:trending:%d:%d", page, limit)
+	return s.ranked(cacheKey, func() ([]repository.BookScore, int64, error) {
+		return s.repo.GetTrending(trendingWindow, trendingHalfLife, favouriteWeight, page, limit)
+	})
+}
+
+func (s *popularityService) GetPopular(period string, page, limit int) ([]PopularBook, int64, error) {
+	if period == "" {
+		period = "week"
+	}
+	days, ok := popularPeriods[period]
+	if !ok {
+		return nil, 0, errors.New("invalid period, must be 'week', 'month' or 'all'")
+	}
+
+	cacheKey := fmt.Sprintf("books:popular:%s:%d:%d", period, page, limit)
+	return s.ranked(cacheKey, func() ([]repository.BookScore, int64, error) {
+		return s.repo.GetPopular(days, favouriteWeight, page, limit)
+	})
+}
+
+// ranked кэширует рейтинг с книгами; ключи books:* сбрасываются при изменении каталога
+func (s *popularityService) ranked(cacheKey string, load func() ([]repository.BookScore, int64, error)) ([]PopularBook, int64, error) {
+	var cached struct {
+		Books []PopularBook
+		Total int64
+	}
+	if s.cache.Get(cacheKey, &cached) {
+		return cached.Books, cached.Total, nil
+	}
+
+	scores, total, err := load()
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]uint, len(scores))
+	for i, score := range scores {
+		ids[i] = score.BookID
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]models.Book, len(books))
+	for _, book := range books {
+		byID[book.ID] = book
+	}
+
+	popular := make([]PopularBook, 0, len(scores))
+	for _, score := range scores {
+		book, ok := byID[score.BookID]
+		if !ok {
+			continue
+		}
+		popular = append(popular, PopularBook{
+			Book:       toBookBriefs([]models.Book{book})[0],
+			Views:      score.Views,
+			Favourites: score.Favourites,
+			Score:      math.Round(score.Score*100) / 100,
+		})
+	}
+
+	cached.Books, cached.Total = popular, total
+	s.cache.Set(cacheKey, cached, popularityCacheTTL)
+	return popular, total, nil
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"bookshelf/pkg/money"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ratesCacheKey = "exchange_rates"
+
+// ExchangeRatesRequest - новая таблица курсов целиком: сколько единиц валюты за одну единицу base
+type ExchangeRatesRequest struct {
+	// Base должна совпадать с базовой валютой каталога; пусто - базовая
+	Base  string            `json:"base" example:"USD"`
+	Rates map[string]string `json:"rates" example:"EUR:0.9215,RUB:91.5"`
+}
+
+type BookPriceRequest struct {
+	Amount money.Decimal `json:"amount" swaggertype:"string" example:"45.00"`
+}
+
+// PriceQuote - цена книги в валюте показа: по прайсу (List) и с учётом скидки (Effective)
+type PriceQuote struct {
+	List      money.Money
+	Effective money.Money
+}
+
+type PricingService interface {
+	BaseCurrency() string
+	// PriceBooks возвращает цены книг в валюте currency: из прайс-листа, иначе пересчёт по курсу.
+	// Пустая currency - цена в валюте самой книги. Скидка (SalePrice) переносится в той же доле от цены
+	PriceBooks(books []models.Book, currency string) ([]PriceQuote, error)
+	PriceBriefs(briefs []BookBrief, currency string) ([]BookBrief, error)
+	GetRates() ([]models.ExchangeRate, error)
+	UploadRates(req ExchangeRatesRequest) ([]models.ExchangeRate, error)
+	// ListBookPrices возвращает книгу (с ценой в её собственной валюте) и её прайс-лист
+	ListBookPrices(bookID uint) (models.Book, []models.BookPrice, error)
+	SetBookPrice(bookID uint, currency string, req BookPriceRequest) (models.BookPrice, error)
+	DeleteBookPrice(bookID uint, currency string) error
+}
+
+type pricingService struct {
+	repo     repository.PriceRepository
+	bookRepo repository.BookRepository
+	cache    cache.RedisCache
+	base     string
+}
+
+// NewPricingService: base - базовая валюта каталога, к ней привязаны курсы
+func NewPricingService(repo repository.PriceRepository, bookRepo repository.BookRepository, cache *cache.RedisCache, base string) PricingService {
+	return &pricingService{repo: repo, bookRepo: bookRepo, cache: *cache, base: base}
+}
+
+func (s *pricingService) BaseCurrency() string {
+	return s.base
+}
+
+// quote - цена по прайсу list и цена со скидкой книги в той же валюте
+func quote(book models.Book, list money.Money) PriceQuote {
+	if book.SalePrice == nil {
+		return PriceQuote{List: list, Effective: list}
+	}
+	return PriceQuote{List: list, Effective: money.Prorate(list, *book.SalePrice, book.Price)}
+}
+
+func (s *pricingService) PriceBooks(books []models.Book, currency string) ([]PriceQuote, error) {
+	prices := make([]PriceQuote, len(books))
+	if currency == "" {
+		for i, book := range books {
+			prices[i] = quote(book, money.New(book.Price, book.Currency))
+		}
+		return prices, nil
+	}
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid currency %q", currency)
+	}
+
+	ids := make([]uint, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	listed, err := s.repo.GetBookPrices(ids, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates map[string]*big.Rat
+	for i, book := range books {
+		if amount, ok := listed[book.ID]; ok {
+			prices[i] = quote(book, money.New(amount, code))
+			continue
+		}
+		if book.Currency == code {
+			prices[i] = quote(book, money.New(book.Price, code))
+			continue
+		}
+		if rates == nil {
+			if rates, err = s.loadRates(); err != nil {
+				return nil, err
+			}
+		}
+		rate, err := s.crossRate(rates, book.Currency, code)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = quote(book, money.Convert(money.New(book.Price, book.Currency), code, rate))
+	}
+	return prices, nil
+}
+
+func (s *pricingService) PriceBriefs(briefs []BookBrief, currency string) ([]BookBrief, error) {
+	if currency == "" {
+		return briefs, nil
+	}
+	books := make([]models.Book, len(briefs))
+	for i, brief := range briefs {
+		books[i].ID = brief.ID
+		books[i].Price = brief.Price.Amount
+		books[i].Currency = brief.Price.Currency
+		if brief.EffectivePrice != nil {
+			books[i].SalePrice = &brief.EffectivePrice.Amount
+		}
+	}
+	prices, err := s.PriceBooks(books, currency)
+	if err != nil {
+		return nil, err
+	}
+	priced := make([]BookBrief, len(briefs))
+	for i, brief := range briefs {
+		brief.Price = prices[i].List
+		if brief.EffectivePrice != nil {
+			brief.EffectivePrice = &prices[i].Effective
+		}
+		priced[i] = brief
+	}
+	return priced, nil
+}
+
+// loadRates - курсы к базовой валюте; у самой базовой валюты курс 1
+func (s *pricingService) loadRates() (map[string]*big.Rat, error) {
+	rates, err := s.GetRates()
+	if err != nil {
+		return nil, err
+	}
+	parsed := map[string]*big.Rat{s.base: big.NewRat(1, 1)}
+	for _, r := range rates {
+		rate, err := money.ParseRate(r.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rate for %s is corrupted: %w", r.Currency, err)
+		}
+		parsed[r.Currency] = rate
+	}
+	return parsed, nil
+}
+
+// crossRate - курс from -> to через базовую валюту
+func (s *pricingService) crossRate(rates map[string]*big.Rat, from, to string) (*big.Rat, error) {
+	fromRate, ok := rates[from]
+	if !ok {
+		return nil, fmt.Errorf("invalid currency: no exchange rate for %s", from)
+	}
+	toRate, ok := rates[to]
+	if !ok {
+		return nil, fmt.Errorf("invalid currency: no exchange rate for %s", to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+func (s *pricingService) GetRates() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	if s.cache.Get(ratesCacheKey, &rates) {
+		return rates, nil
+	}
+	rates, err := s.repo.ListRates()
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(ratesCacheKey, rates, 10*time.Minute)
+	return rates, nil
+}
+
+func (s *pricingService) UploadRates(req ExchangeRatesRequest) ([]models.ExchangeRate, error) {
+	if req.Base != "" {
+		base, err := money.NormalizeCurrency(req.Base)
+		if err != nil || base != s.base {
+			return nil, fmt.Errorf("invalid base currency, rates must be quoted against %s", s.base)
+		}
+	}
+
+	rates := make([]models.ExchangeRate, 0, len(req.Rates))
+	now := time.Now()
+	for currency, raw := range req.Rates {
+		code, err := money.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency %q", currency)
+		}
+		if code == s.base {
+			continue
+		}
+		rate, err := money.ParseRate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate for %s: must be a positive decimal", code)
+		}
+		rates = append(rates, models.ExchangeRate{Currency: code, Rate: money.FormatRate(rate), UpdatedAt: now})
+	}
+
+	if err := s.repo.ReplaceRates(rates); err != nil {
+		return nil, err
+	}
+	s.cache.Delete(ratesCacheKey)
+	return s.repo.ListRates()
+}
+
+func (s *pricingService) ListBookPrices(bookID uint) (models.Book, []models.BookPrice, error) {
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return models.Book{}, nil, errors.New("book not found")
+	}
+	prices, err := s.repo.ListBookPrices(bookID)
+	return book, prices, err
+}
+
+func (s *pricingService) SetBookPrice(bookID uint, currency string, req BookPriceRequest) (models.BookPrice, error) {
+	price, err := money.ParseIn(string(req.Amount), currency)
+	if errors.Is(err, money.ErrInvalidCurrency) {
+		return models.BookPrice{}, fmt.Errorf("invalid currency %q", currency)
+	}
+	if err != nil || price.Amount <= 0 {
+		return models.BookPrice{}, fmt.Errorf("invalid amount for %s, must be positive and fit the currency's minor units", strings.ToUpper(currency))
+	}
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return models.BookPrice{}, errors.New("book not found")
+	}
+	// Цена в собственной валюте книги задаётся в самой книге, иначе их стало бы две
+	if book.Currency == price.Currency {
+		return models.BookPrice{}, fmt.Errorf("invalid currency: book is priced in %s, update the book instead", book.Currency)
+	}
+
+	entry := models.BookPrice{BookID: bookID, Currency: price.Currency, Amount: price.Amount}
+	if err := s.repo.SetBookPrice(&entry); err != nil {
+		return models.BookPrice{}, err
+	}
+	return entry, nil
+}
+
+func (s *pricingService) DeleteBookPrice(bookID uint, currency string) error {
+	code, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return fmt.Errorf("invalid currency %q", currency)
+	}
+	deleted, err := s.repo.DeleteBookPrice(bookID, code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("price not found")
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	maxLocatorLength = 2048
+	maxDeviceLength  = 128
+)
+
+// ReadingPositionRequest - позиция в книге из веб-читалки или приложения: EPUB CFI, процент или оба
+type ReadingPositionRequest struct {
+	CFI      string   `json:"cfi" example:"epubcfi(/6/14!/4/2/10/2:352)"`
+	Percent  *float64 `json:"percent" example:"42.5"`
+	Device   string   `json:"device" example:"Kobo Libra 2"`
+	DeviceID string   `json:"device_id" example:"kobo-5f2c"`
+	// RecordedAt - когда позиция достигнута на устройстве, по умолчанию - время запроса.
+	// Устройство, синхронизирующееся после работы офлайн, передаёт время чтения
+	RecordedAt *time.Time `json:"recorded_at"`
+}
+
+// SyncProgressRequest - позиция в формате протокола синхронизации KOReader
+type SyncProgressRequest struct {
+	Document string `json:"document" example:"0b229176d4e8db7f6d2b5a4952368d7a"`
+	// Progress - XPointer для EPUB или номер страницы для PDF
+	Progress string `json:"progress" example:"/body/DocFragment[20]/body/p[22]/img.0"`
+	// Percentage - доля прочитанного от 0 до 1
+	Percentage float64 `json:"percentage" example:"0.425"`
+	Device     string  `json:"device" example:"Kobo Libra 2"`
+	DeviceID   string  `json:"device_id" example:"0FE37C4C7A5A4B1DB4B45F1A7C8E4D2A"`
+}
+
+type ProgressService interface {
+	// SaveProgress сохраняет позицию. Возвращает текущую позицию и стала ли текущей переданная:
+	// позиция, прочитанная раньше текущей, остаётся только в истории
+	SaveProgress(userID, bookID uint, req ReadingPositionRequest) (models.ReadingProgress, bool, error)
+	GetProgress(userID, bookID uint) (models.ReadingProgress, error)
+	ListHistory(userID, bookID uint, page, limit int) ([]models.ReadingProgressEntry, int64, error)
+
+	// AuthenticateSync проверяет учётные данные KOReader: имя пользователя и MD5 пароля
+	AuthenticateSync(username, key string) (uint, error)
+	// SaveSyncProgress сохраняет позицию из KOReader. Документ, совпадающий с файлом книги каталога,
+	// делит позицию с этой книгой
+	SaveSyncProgress(userID uint, req SyncProgressRequest) (models.ReadingProgress, error)
+	GetSyncProgress(userID uint, document string) (models.ReadingProgress, error)
+}
+
+type progressService struct {
+	repo     repository.ProgressRepository
+	files    repository.FileRepository
+	bookRepo repository.BookRepository
+	authRepo repository.AuthRepository
+}
+
+func NewProgressService(repo repository.ProgressRepository, files repository.FileRepository, bookRepo repository.BookRepository, authRepo repository.AuthRepository) ProgressService {
+	return &progressService{repo: repo, files: files, bookRepo: bookRepo, authRepo: authRepo}
+}
+
+func bookDocument(bookID uint) string {
+	return fmt.Sprintf("book:%d", bookID)
+}
+
+func validateDevice(device, deviceID string) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return errors.New("device_id is required")
+	}
+	if len(device) > maxDeviceLength || len(deviceID) > maxDeviceLength {
+		return fmt.Errorf("invalid device, must be at most %d characters", maxDeviceLength)
+	}
+	return nil
+}
+
+// recordedAt не даёт устройству с убежавшими вперёд часами навсегда перекрыть остальные
+func recordedAt(at *time.Time, now time.Time) time.Time {
+	if at == nil || at.IsZero() || at.After(now) {
+		return now
+	}
+	return *at
+}
+
+func (s *progressService) SaveProgress(userID, bookID uint, req ReadingPositionRequest) (models.ReadingProgress, bool, error) {
+	req.CFI = strings.TrimSpace(req.CFI)
+	if req.CFI == "" && req.Percent == nil {
+		return models.ReadingProgress{}, false, errors.New("cfi or percent is required")
+	}
+	if req.CFI != "" && (!strings.HasPrefix(req.CFI, "epubcfi(") || !strings.HasSuffix(req.CFI, ")") || len(req.CFI) > maxLocatorLength) {
+		return models.ReadingProgress{}, false, errors.New("invalid cfi, must look like epubcfi(...)")
+	}
+	if req.Percent != nil && (*req.Percent < 0 || *req.Percent > 100) {
+		return models.ReadingProgress{}, false, errors.New("invalid percent, must be between 0 and 100")
+	}
+	if err := validateDevice(req.Device, req.DeviceID); err != nil {
+		return models.ReadingProgress{}, false, err
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return models.ReadingProgress{}, false, errors.New("book not found")
+	}
+
+	entry := models.ReadingProgressEntry{
+		UserID:     userID,
+		Document:   bookDocument(bookID),
+		BookID:     &bookID,
+		Device:     strings.TrimSpace(req.Device),
+		DeviceID:   strings.TrimSpace(req.DeviceID),
+		RecordedAt: recordedAt(req.RecordedAt, time.Now()),
+	}
+	if req.CFI != "" {
+		entry.Locator = req.CFI
+		entry.LocatorType = models.LocatorCFI
+	}
+	if req.Percent != nil {
+		entry.Percent = *req.Percent
+	}
+
+	progress, err := s.repo.SaveProgress(&entry)
+	return progress, entry.Applied, err
+}
+
+func (s *progressService) GetProgress(userID, bookID uint) (models.ReadingProgress, error) {
+	progress, err := s.repo.GetProgress(userID, bookDocument(bookID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ReadingProgress{}, errors.New("progress not found")
+	}
+	return progress, err
+}
+
+func (s *progressService) ListHistory(userID, bookID uint, page, limit int) ([]models.ReadingProgressEntry, int64, error) {
+	return s.repo.ListHistory(userID, bookDocument(bookID), page, limit)
+}
+
+func (s *progressService) AuthenticateSync(username, key string) (uint, error) {
+	if username == "" || key == "" {
+		return 0, errors.New("invalid credentials")
+	}
+	user, err := s.authRepo.GetUserByUsername(username)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	if err != nil || user.SyncKeyHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(user.SyncKeyHash), []byte(strings.ToLower(key))) != nil {
+		time.Sleep(2 * time.Second) // Замедление атак перебора
+		return 0, errors.New("invalid credentials")
+	}
+	return user.ID, nil
+}
+
+// syncDocument сопоставляет документ KOReader с книгой каталога по хэшу загруженного файла
+func (s *progressService) syncDocument(document string) (string, *uint, error) {
+	document = strings.ToLower(strings.TrimSpace(document))
+	if document == "" {
+		return "", nil, errors.New("document is required")
+	}
+	if len(document) > 64 || strings.Trim(document, "0123456789abcdef") != "" {
+		return "", nil, errors.New("invalid document, must be a hex digest")
+	}
+
+	file, err := s.files.FindByDigest(document)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return document, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return bookDocument(file.BookID), &file.BookID, nil
+}
+
+func (s *progressService) SaveSyncProgress(userID uint, req SyncProgressRequest) (models.ReadingProgress, error) {
+	document, bookID, err := s.syncDocument(req.Document)
+	if err != nil {
+		return models.ReadingProgress{}, err
+	}
+	if req.Percentage < 0 || req.Percentage > 1 {
+		return models.ReadingProgress{}, errors.New("invalid percentage, must be between 0 and 1")
+	}
+	if len(req.Progress) > maxLocatorLength {
+		return models.ReadingProgress{}, fmt.Errorf("invalid progress, must be at most %d characters", maxLocatorLength)
+	}
+	if err := validateDevice(req.Device, req.DeviceID); err != nil {
+		return models.ReadingProgress{}, err
+	}
+
+	entry := models.ReadingProgressEntry{
+		UserID:      userID,
+		Document:    document,
+		BookID:      bookID,
+		Locator:     req.Progress,
+		LocatorType: models.LocatorXPointer,
+		Percent:     req.Percentage * 100,
+		Device:      req.Device,
+		DeviceID:    req.DeviceID,
+		RecordedAt:  time.Now(),
+	}
+	if _, err := strconv.Atoi(req.Progress); err == nil {
+		entry.LocatorType = models.LocatorPage
+	}
+	return s.repo.SaveProgress(&entry)
+}
+
+func (s *progressService) GetSyncProgress(userID uint, document string) (models.ReadingProgress, error) {
+	document, _, err := s.syncDocument(document)
+	if err != nil {
+		return models.ReadingProgress{}, err
+	}
+	progress, err := s.repo.GetProgress(userID, document)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ReadingProgress{}, errors.New("progress not found")
+	}
+	return progress, err
+}
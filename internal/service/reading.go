@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReadingStatusRequest struct {
+	Shelf      string     `json:"shelf" example:"read"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// ProgressRequest: достаточно страницы или процента. Если у книги указан объём, процент считается по странице
+type ProgressRequest struct {
+	Page    *int `json:"page" example:"120"`
+	Percent *int `json:"percent" example:"32"`
+}
+
+type ShelfItem struct {
+	Status models.ReadingStatus
+	Book   BookBrief
+}
+
+type MonthlyStats struct {
+	Month int `json:"month" example:"3"`
+	Books int `json:"books" example:"2"`
+	Pages int `json:"pages" example:"640"`
+}
+
+type ReadingStats struct {
+	Year      int            `json:"year" example:"2026"`
+	BooksRead int            `json:"books_read" example:"24"`
+	PagesRead int            `json:"pages_read" example:"8120"`
+	Months    []MonthlyStats `json:"months"`
+}
+
+type ReadingService interface {
+	GetStatus(userID, bookID uint) (models.ReadingStatus, error)
+	SetStatus(userID, bookID uint, req ReadingStatusRequest) (models.ReadingStatus, error)
+	UpdateProgress(userID, bookID uint, req ProgressRequest) (models.ReadingStatus, error)
+	RemoveStatus(userID, bookID uint) error
+	GetShelf(userID uint, shelf string, page, limit int) ([]ShelfItem, int64, error)
+	GetYearStats(userID uint, year int) (ReadingStats, error)
+}
+
+type readingService struct {
+	repo     repository.ReadingRepository
+	bookRepo repository.BookRepository
+}
+
+func NewReadingService(repo repository.ReadingRepository, bookRepo repository.BookRepository) ReadingService {
+	return &readingService{repo: repo, bookRepo: bookRepo}
+}
+
+func IsShelf(shelf string) bool {
+	switch shelf {
+	case models.ShelfWantToRead, models.ShelfReading, models.ShelfRead:
+		return true
+	}
+	return false
+}
+
+func (s *readingService) GetStatus(userID, bookID uint) (models.ReadingStatus, error) {
+	status, err := s.repo.GetStatus(userID, bookID)
+	if err != nil {
+		return models.ReadingStatus{}, errors.New("reading status not found")
+	}
+	return status, nil
+}
+
+// SetStatus переставляет книгу на полку. Переход в reading начинает новое прочтение,
+// переход в read завершает открытое (или создаёт завершённое, если книгу сразу отметили прочитанной)
+func (s *readingService) SetStatus(userID, bookID uint, req ReadingStatusRequest) (models.ReadingStatus, error) {
+	if !IsShelf(req.Shelf) {
+		return models.ReadingStatus{}, errors.New("invalid shelf, must be 'want_to_read', 'reading' or 'read'")
+	}
+	if req.StartedAt != nil && req.FinishedAt != nil && req.FinishedAt.Before(*req.StartedAt) {
+		return models.ReadingStatus{}, errors.New("invalid dates: finished_at is before started_at")
+	}
+
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return models.ReadingStatus{}, errors.New("book not found")
+	}
+
+	status, err := s.repo.GetStatus(userID, bookID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ReadingStatus{}, err
+	}
+	status.UserID = userID
+	status.BookID = bookID
+
+	now := time.Now()
+	var readThrough *models.ReadThrough
+
+	switch req.Shelf {
+	case models.ShelfWantToRead:
+		status.CurrentPage, status.Percent = 0, 0
+		status.StartedAt, status.FinishedAt = nil, nil
+
+	case models.ShelfReading:
+		if status.Shelf != models.ShelfReading {
+			status.StartedAt = orNow(req.StartedAt, now)
+			status.FinishedAt = nil
+			status.CurrentPage, status.Percent = 0, 0
+			readThrough = &models.ReadThrough{UserID: userID, BookID: bookID, StartedAt: status.StartedAt}
+		} else if req.StartedAt != nil {
+			status.StartedAt = req.StartedAt
+			if open, err := s.repo.GetOpenReadThrough(userID, bookID); err == nil {
+				open.StartedAt = req.StartedAt
+				readThrough = &open
+			}
+		}
+
+	case models.ShelfRead:
+		if status.Shelf == models.ShelfRead {
+			// Повторная отметка только правит даты последнего прочтения
+			if last, err := s.repo.GetLastReadThrough(userID, bookID); err == nil {
+				if req.StartedAt != nil {
+					last.StartedAt = req.StartedAt
+					status.StartedAt = req.StartedAt
+				}
+				if req.FinishedAt != nil {
+					last.FinishedAt = req.FinishedAt
+					status.FinishedAt = req.FinishedAt
+				}
+				readThrough = &last
+			}
+			break
+		}
+
+		open, err := s.repo.GetOpenReadThrough(userID, bookID)
+		if err != nil {
+			open = models.ReadThrough{UserID: userID, BookID: bookID, StartedAt: req.StartedAt}
+		} else if req.StartedAt != nil {
+			open.StartedAt = req.StartedAt
+		}
+		open.FinishedAt = orNow(req.FinishedAt, now)
+		open.Pages = book.Pages
+		readThrough = &open
+
+		status.StartedAt = open.StartedAt
+		status.FinishedAt = open.FinishedAt
+		status.CurrentPage = book.Pages
+		status.Percent = 100
+		status.ReadCount++
+	}
+	status.Shelf = req.Shelf
+
+	if err := s.repo.SaveStatus(&status, readThrough); err != nil {
+		return models.ReadingStatus{}, err
+	}
+	return status, nil
+}
+
+// UpdateProgress обновляет страницу или процент. Книга, которой ещё нет на полке reading, переносится туда
+func (s *readingService) UpdateProgress(userID, bookID uint, req ProgressRequest) (models.ReadingStatus, error) {
+	if req.Page == nil && req.Percent == nil {
+		return models.ReadingStatus{}, errors.New("page or percent is required")
+	}
+	if req.Percent != nil && (*req.Percent < 0 || *req.Percent > 100) {
+		return models.ReadingStatus{}, errors.New("invalid percent, must be between 0 and 100")
+	}
+	if req.Page != nil && *req.Page < 0 {
+		return models.ReadingStatus{}, errors.New("invalid page, must be non-negative")
+	}
+
+	book, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10))
+	if err != nil {
+		return models.ReadingStatus{}, errors.New("book not found")
+	}
+	if req.Page != nil && book.Pages > 0 && *req.Page > book.Pages {
+		return models.ReadingStatus{}, errors.New("invalid page, book has fewer pages")
+	}
+
+	status, err := s.repo.GetStatus(userID, bookID)
+	if err != nil || status.Shelf != models.ShelfReading {
+		if status, err = s.SetStatus(userID, bookID, ReadingStatusRequest{Shelf: models.ShelfReading}); err != nil {
+			return models.ReadingStatus{}, err
+		}
+	}
+
+	if req.Page != nil {
+		status.CurrentPage = *req.Page
+		if book.Pages > 0 {
+			status.Percent = status.CurrentPage * 100 / book.Pages
+		}
+	}
+	if req.Percent != nil {
+		status.Percent = *req.Percent
+		if req.Page == nil && book.Pages > 0 {
+			status.CurrentPage = book.Pages * status.Percent / 100
+		}
+	}
+
+	if err := s.repo.SaveStatus(&status, nil); err != nil {
+		return models.ReadingStatus{}, err
+	}
+	return status, nil
+}
+
+func (s *readingService) RemoveStatus(userID, bookID uint) error {
+	if _, err := s.GetStatus(userID, bookID); err != nil {
+		return err
+	}
+	return s.repo.DeleteStatus(userID, bookID)
+}
+
+func (s *readingService) GetShelf(userID uint, shelf string, page, limit int) ([]ShelfItem, int64, error) {
+	if !IsShelf(shelf) {
+		return nil, 0, errors.New("invalid shelf, must be 'want_to_read', 'reading' or 'read'")
+	}
+
+	statuses, total, err := s.repo.ListShelf(userID, shelf, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]ShelfItem, len(statuses))
+	for i, status := range statuses {
+		items[i] = ShelfItem{Status: status, Book: toBookBriefs([]models.Book{status.Book})[0]}
+	}
+	return items, total, nil
+}
+
+// GetYearStats считает завершённые за год прочтения; перечитанная книга учитывается каждый раз
+func (s *readingService) GetYearStats(userID uint, year int) (ReadingStats, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	months, err := s.repo.GetMonthlyReading(userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return ReadingStats{}, err
+	}
+
+	stats := ReadingStats{Year: year, Months: make([]MonthlyStats, 12)}
+	for i := range stats.Months {
+		stats.Months[i].Month = i + 1
+	}
+	for _, m := range months {
+		if m.Month < 1 || m.Month > 12 {
+			continue
+		}
+		stats.Months[m.Month-1].Books = m.Books
+		stats.Months[m.Month-1].Pages = m.Pages
+		stats.BooksRead += m.Books
+		stats.PagesRead += m.Pages
+	}
+	return stats, nil
+}
+
+func orNow(t *time.Time, now time.Time) *time.Time {
+	if t != nil {
+		return t
+	}
+	return &now
+}
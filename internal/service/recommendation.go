@@ -0,0 +1,332 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"bookshelf/pkg/textutil"
+	"bookshelf/pkg/tfidf"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// Сколько ближайших соседей хранится для каждой книги
+	similarNeighbours  = 20
+	minSimilarity      = 0.05
+	maxRecommendations = 50
+
+	coFavouriteWeight = 0.6
+	contentWeight     = 0.4
+	authorWeight      = 0.35
+	genreWeight       = 0.25
+	descriptionWeight = 0.4
+
+	// Сколько ближайших по тексту книг рассматривается для каждой книги
+	maxTextMatches = 100
+	// Жанры крупнее этого сравниваются только по описанию, иначе пересчёт становится квадратичным
+	maxGenreBucket = 500
+
+	recommendationsTTL = 15 * time.Minute
+)
+
+const (
+	ReasonCoFavourite        = "co_favourite"
+	ReasonSameAuthor         = "same_author"
+	ReasonSameGenre          = "same_genre"
+	ReasonSimilarDescription = "similar_description"
+)
+
+type Recommendation struct {
+	Book        BookBrief `json:"book"`
+	Score       float64   `json:"score"`
+	Reason      string    `json:"reason"`
+	BecauseOf   BookBrief `json:"because_of"`
+	Explanation string    `json:"explanation"`
+}
+
+type RecommendationService interface {
+	// RebuildSimilarities пересчитывает таблицу похожести; запускается планировщиком
+	RebuildSimilarities(ctx context.Context) error
+	GetRecommendations(userID uint, limit int) ([]Recommendation, error)
+}
+
+type recommendationService struct {
+	repo     repository.RecommendationRepository
+	favRepo  repository.FavouriteRepository
+	bookRepo repository.BookRepository
+	cache    cache.RedisCache
+}
+
+func NewRecommendationService(
+	repo repository.RecommendationRepository,
+	favRepo repository.FavouriteRepository,
+	bookRepo repository.BookRepository,
+	cache *cache.RedisCache,
+) RecommendationService {
+	return &recommendationService{repo: repo, favRepo: favRepo, bookRepo: bookRepo, cache: *cache}
+}
+
+type bookPair struct {
+	book, similar uint
+}
+
+func (s *recommendationService) RebuildSimilarities(ctx context.Context) error {
+	books, err := s.repo.GetCatalog()
+	if err != nil {
+		return err
+	}
+	coFavourites, err := s.repo.GetCoFavourites()
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	pairs := contentSimilarities(books)
+	for _, cf := range coFavourites {
+		key := bookPair{cf.BookID, cf.SimilarBookID}
+		sim, ok := pairs[key]
+		if !ok {
+			sim = &models.BookSimilarity{BookID: cf.BookID, SimilarBookID: cf.SimilarBookID}
+			pairs[key] = sim
+		}
+		sim.CoFavourites = cf.Together
+		sim.Score += coFavouriteWeight * float64(cf.Together) / math.Sqrt(float64(cf.BookUsers*cf.SimilarUsers))
+	}
+
+	byBook := make(map[uint][]models.BookSimilarity)
+	for _, sim := range pairs {
+		if sim.Score < minSimilarity {
+			continue
+		}
+		sim.Score = math.Round(sim.Score*1000) / 1000
+		sim.ComputedAt = now
+		byBook[sim.BookID] = append(byBook[sim.BookID], *sim)
+	}
+
+	var rows []models.BookSimilarity
+	for _, sims := range byBook {
+		sort.Slice(sims, func(i, j int) bool {
+			if sims[i].Score != sims[j].Score {
+				return sims[i].Score > sims[j].Score
+			}
+			return sims[i].SimilarBookID < sims[j].SimilarBookID
+		})
+		rows = append(rows, sims[:min(len(sims), similarNeighbours)]...)
+	}
+
+	if err := s.repo.ReplaceSimilarities(rows); err != nil {
+		return err
+	}
+	s.cache.InvalidatePattern("recommendations:*")
+	return nil
+}
+
+// contentSimilarities сравнивает книги по автору, жанру и тексту. Текст сравнивается тем же
+// TF-IDF индексом, что и /books/{id}/similar, кандидаты берутся из индексов, поэтому книги
+// без общих признаков не сравниваются вовсе
+func contentSimilarities(books []models.Book) map[bookPair]*models.BookSimilarity {
+	authors := make([]string, len(books))
+	genres := make([]string, len(books))
+	texts := make(map[uint]string, len(books))
+	positions := make(map[uint]int, len(books))
+
+	byAuthor := make(map[string][]int)
+	byGenre := make(map[string][]int)
+	for i, book := range books {
+		authors[i] = textutil.Normalize(book.Author)
+		genres[i] = textutil.Normalize(book.Genre)
+		texts[book.ID] = similarityText(book)
+		positions[book.ID] = i
+
+		if authors[i] != "" {
+			byAuthor[authors[i]] = append(byAuthor[authors[i]], i)
+		}
+		if genres[i] != "" {
+			byGenre[genres[i]] = append(byGenre[genres[i]], i)
+		}
+	}
+
+	index := tfidf.New()
+	index.Reset(texts)
+
+	pairs := make(map[bookPair]*models.BookSimilarity)
+	for i, book := range books {
+		textScores := make(map[int]float64)
+		for _, match := range index.Similar(book.ID, maxTextMatches) {
+			textScores[positions[match.ID]] = match.Score
+		}
+		candidates := make(map[int]bool, len(textScores))
+		for j := range textScores {
+			candidates[j] = true
+		}
+		for _, j := range byAuthor[authors[i]] {
+			candidates[j] = true
+		}
+		if bucket := byGenre[genres[i]]; len(bucket) <= maxGenreBucket {
+			for _, j := range bucket {
+				candidates[j] = true
+			}
+		}
+
+		for j := range candidates {
+			if j == i {
+				continue
+			}
+			sim := &models.BookSimilarity{
+				BookID:           book.ID,
+				SimilarBookID:    books[j].ID,
+				SameAuthor:       authors[i] != "" && authors[i] == authors[j],
+				SameGenre:        genres[i] != "" && genres[i] == genres[j],
+				DescriptionScore: math.Round(textScores[j]*1000) / 1000,
+			}
+
+			content := descriptionWeight * sim.DescriptionScore
+			if sim.SameAuthor {
+				content += authorWeight
+			}
+			if sim.SameGenre {
+				content += genreWeight
+			}
+			sim.Score = contentWeight * content
+			pairs[bookPair{book.ID, books[j].ID}] = sim
+		}
+	}
+	return pairs
+}
+
+// GetRecommendations собирает соседей избранных книг. Кэшируется весь список, а избранное
+// отфильтровывается при каждом запросе, чтобы только что добавленная книга сразу пропадала
+func (s *recommendationService) GetRecommendations(userID uint, limit int) ([]Recommendation, error) {
+	favIDs, err := s.favRepo.GetFavouriteIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	recommendations := []Recommendation{}
+	if len(favIDs) == 0 {
+		return recommendations, nil
+	}
+
+	cacheKey := fmt.Sprintf("recommendations:%d", userID)
+	var cached []Recommendation
+	if !s.cache.Get(cacheKey, &cached) {
+		if cached, err = s.build(favIDs); err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, cached, recommendationsTTL)
+	}
+
+	favourites := make(map[uint]bool, len(favIDs))
+	for _, id := range favIDs {
+		favourites[id] = true
+	}
+	for _, rec := range cached {
+		if len(recommendations) == limit {
+			break
+		}
+		if !favourites[rec.Book.ID] {
+			recommendations = append(recommendations, rec)
+		}
+	}
+	return recommendations, nil
+}
+
+type candidate struct {
+	bookID uint
+	score  float64
+	best   models.BookSimilarity
+}
+
+func (s *recommendationService) build(favIDs []uint) ([]Recommendation, error) {
+	sims, err := s.repo.GetSimilarities(favIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	favourites := make(map[uint]bool, len(favIDs))
+	for _, id := range favIDs {
+		favourites[id] = true
+	}
+
+	// Книга, похожая сразу на несколько избранных, набирает баллы от каждой
+	byID := make(map[uint]*candidate)
+	for _, sim := range sims {
+		if favourites[sim.SimilarBookID] {
+			continue
+		}
+		c, ok := byID[sim.SimilarBookID]
+		if !ok {
+			c = &candidate{bookID: sim.SimilarBookID}
+			byID[sim.SimilarBookID] = c
+		}
+		c.score += sim.Score
+		if sim.Score > c.best.Score {
+			c.best = sim
+		}
+	}
+
+	candidates := make([]*candidate, 0, len(byID))
+	for _, c := range byID {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].bookID < candidates[j].bookID
+	})
+	candidates = candidates[:min(len(candidates), maxRecommendations)]
+
+	ids := make([]uint, 0, len(candidates)*2)
+	for _, c := range candidates {
+		ids = append(ids, c.bookID, c.best.BookID)
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	booksByID := make(map[uint]models.Book, len(books))
+	for _, book := range books {
+		booksByID[book.ID] = book
+	}
+
+	recommendations := make([]Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		// Удалённые после пересчёта книги пропускаются
+		book, ok := booksByID[c.bookID]
+		source, sourceOK := booksByID[c.best.BookID]
+		if !ok || !sourceOK {
+			continue
+		}
+		briefs := toBookBriefs([]models.Book{book, source})
+		reason, explanation := explain(c.best, book, source)
+		recommendations = append(recommendations, Recommendation{
+			Book:        briefs[0],
+			Score:       math.Round(c.score*1000) / 1000,
+			Reason:      reason,
+			BecauseOf:   briefs[1],
+			Explanation: explanation,
+		})
+	}
+	return recommendations, nil
+}
+
+// explain объясняет рекомендацию через самую сильную связь с книгой из избранного
+func explain(sim models.BookSimilarity, book, source models.Book) (string, string) {
+	switch {
+	case sim.CoFavourites > 0:
+		return ReasonCoFavourite, fmt.Sprintf("Читатели, которые добавили в избранное «%s», добавляли и эту книгу", source.Title)
+	case sim.SameAuthor:
+		return ReasonSameAuthor, fmt.Sprintf("Тот же автор (%s), что у «%s» из вашего избранного", book.Author, source.Title)
+	case sim.DescriptionScore > 0:
+		return ReasonSimilarDescription, fmt.Sprintf("Похожа по описанию на «%s» из вашего избранного", source.Title)
+	default:
+		return ReasonSameGenre, fmt.Sprintf("Тот же жанр (%s), что у «%s» из вашего избранного", book.Genre, source.Title)
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxReviewLength = 5000
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" example:"5"`
+	Text   string `json:"text" example:"Лучшая книга по Go"`
+}
+
+type ModerationRequest struct {
+	Status string `json:"status" example:"approved"`
+	Note   string `json:"note" example:"Спойлеры убраны"`
+	// UpdatedAt - updated_at версии, которую видел модератор. Если рецензию с тех пор изменили, решение не применяется
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ReviewService interface {
+	UpsertReview(userID, bookID uint, req ReviewRequest) (models.Review, error)
+	GetUserReview(userID, bookID uint) (models.Review, error)
+	DeleteReview(userID, bookID uint) error
+	GetBookReviews(bookID uint, sort string, page, limit int) ([]models.Review, int64, error)
+	VoteHelpful(userID, reviewID uint) error
+	UnvoteHelpful(userID, reviewID uint) error
+	// Модерация
+	GetModerationQueue(status string, page, limit int) ([]models.Review, int64, error)
+	ModerateReview(moderatorID, reviewID uint, req ModerationRequest) (models.Review, error)
+}
+
+// ReviewListener узнаёт о рецензиях, которые стали видны всем: сразу или после модерации
+type ReviewListener interface {
+	ReviewPublished(review models.Review)
+}
+
+type reviewService struct {
+	repo      repository.ReviewRepository
+	bookRepo  repository.BookRepository
+	cache     cache.RedisCache
+	listeners []ReviewListener
+}
+
+func NewReviewService(repo repository.ReviewRepository, bookRepo repository.BookRepository, cache *cache.RedisCache, listeners ...ReviewListener) ReviewService {
+	return &reviewService{repo: repo, bookRepo: bookRepo, cache: *cache, listeners: listeners}
+}
+
+// UpsertReview создаёт или правит рецензию пользователя. Оценка без текста публикуется сразу,
+// новый или изменённый текст уходит на модерацию
+func (s *reviewService) UpsertReview(userID, bookID uint, req ReviewRequest) (models.Review, error) {
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Rating < 1 || req.Rating > 5 {
+		return models.Review{}, errors.New("invalid rating, must be between 1 and 5")
+	}
+	if utf8.RuneCountInString(req.Text) > maxReviewLength {
+		return models.Review{}, fmt.Errorf("invalid review text, must be at most %d characters", maxReviewLength)
+	}
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return models.Review{}, errors.New("book not found")
+	}
+
+	old, review, err := s.repo.UpsertReview(userID, bookID, func(review *models.Review) error {
+		textChanged := review.ID == 0 || review.Text != req.Text
+		review.Rating = req.Rating
+		review.Text = req.Text
+
+		if textChanged {
+			review.Status = models.ReviewStatusPending
+			if review.Text == "" {
+				review.Status = models.ReviewStatusApproved
+			}
+			review.ModeratorID = nil
+			review.ModerationNote = ""
+			review.ModeratedAt = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Review{}, err
+	}
+	s.invalidateBook(bookID)
+	if old.ID == 0 || old.Status != models.ReviewStatusApproved {
+		s.published(review)
+	}
+	return review, nil
+}
+
+func (s *reviewService) GetUserReview(userID, bookID uint) (models.Review, error) {
+	review, err := s.repo.GetReview(userID, bookID)
+	if err != nil {
+		return models.Review{}, errors.New("review not found")
+	}
+	return review, nil
+}
+
+func (s *reviewService) DeleteReview(userID, bookID uint) error {
+	review, err := s.GetUserReview(userID, bookID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteReview(review.ID); err != nil {
+		return err
+	}
+	s.invalidateBook(bookID)
+	return nil
+}
+
+// GetBookReviews отдаёт только одобренные рецензии
+func (s *reviewService) GetBookReviews(bookID uint, sort string, page, limit int) ([]models.Review, int64, error) {
+	return s.repo.ListReviews(repository.ReviewFilter{
+		BookID: bookID,
+		Status: models.ReviewStatusApproved,
+		Sort:   sort,
+	}, page, limit)
+}
+
+func (s *reviewService) VoteHelpful(userID, reviewID uint) error {
+	review, err := s.repo.GetReviewByID(reviewID)
+	if err != nil || review.Status != models.ReviewStatusApproved {
+		return errors.New("review not found")
+	}
+	if review.UserID == userID {
+		return errors.New("invalid vote: cannot vote for your own review")
+	}
+	return s.repo.AddVote(userID, reviewID)
+}
+
+func (s *reviewService) UnvoteHelpful(userID, reviewID uint) error {
+	return s.repo.RemoveVote(userID, reviewID)
+}
+
+// GetModerationQueue по умолчанию показывает ожидающие рецензии, старые первыми
+func (s *reviewService) GetModerationQueue(status string, page, limit int) ([]models.Review, int64, error) {
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+	if !isReviewStatus(status) {
+		return nil, 0, errors.New("invalid status, must be 'pending', 'approved' or 'rejected'")
+	}
+	return s.repo.ListReviews(repository.ReviewFilter{
+		Status: status,
+		Sort:   repository.ReviewSortOldest,
+	}, page, limit)
+}
+
+func (s *reviewService) ModerateReview(moderatorID, reviewID uint, req ModerationRequest) (models.Review, error) {
+	if req.Status != models.ReviewStatusApproved && req.Status != models.ReviewStatusRejected {
+		return models.Review{}, errors.New("invalid status, must be 'approved' or 'rejected'")
+	}
+
+	current, err := s.repo.GetReviewByID(reviewID)
+	if err != nil {
+		return models.Review{}, errors.New("review not found")
+	}
+	changed := errors.New("moderation unavailable: review changed concurrently, reload it and moderate again")
+	if req.UpdatedAt != nil && !req.UpdatedAt.Equal(current.UpdatedAt) {
+		return models.Review{}, changed
+	}
+
+	review, err := s.repo.ModerateReview(current, moderatorID, req.Status, strings.TrimSpace(req.Note))
+	if errors.Is(err, repository.ErrReviewChanged) {
+		return models.Review{}, changed
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+	s.invalidateBook(review.BookID)
+	if current.Status != models.ReviewStatusApproved {
+		s.published(review)
+	}
+	return review, nil
+}
+
+// published сообщает слушателям о рецензии, только что ставшей одобренной
+func (s *reviewService) published(review models.Review) {
+	if review.Status != models.ReviewStatusApproved {
+		return
+	}
+	for _, l := range s.listeners {
+		l.ReviewPublished(review)
+	}
+}
+
+func (s *reviewService) invalidateBook(bookID uint) {
+	s.cache.Delete(fmt.Sprintf("book:%d", bookID))
+}
+
+func isReviewStatus(status string) bool {
+	switch status {
+	case models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusRejected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"bookshelf/pkg/cache"
+	"bookshelf/pkg/tfidf"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxSimilarBooks = 50
+
+type SimilarBook struct {
+	Book  BookBrief `json:"book"`
+	Score float64   `json:"score"`
+}
+
+// SimilarService ищет похожие книги по TF-IDF индексу названия, жанра и описания.
+// Индекс живёт в памяти и обновляется через BookListener
+type SimilarService interface {
+	BookListener
+	GetSimilarBooks(bookID uint, limit int) ([]SimilarBook, error)
+	// RebuildIndex перечитывает каталог целиком; подстраховка для изменений в обход сервисов
+	RebuildIndex(ctx context.Context) error
+}
+
+type similarService struct {
+	bookRepo repository.BookRepository
+	cache    cache.RedisCache
+	index    *tfidf.Index
+}
+
+func NewSimilarService(bookRepo repository.BookRepository, cache *cache.RedisCache) SimilarService {
+	return &similarService{bookRepo: bookRepo, cache: *cache, index: tfidf.New()}
+}
+
+// similarityText - документ книги для индекса; название повторяется, чтобы весить больше описания
+func similarityText(book models.Book) string {
+	return strings.Join([]string{book.Title, book.Title, book.Genre, book.Description}, " ")
+}
+
+func (s *similarService) BookChanged(event BookEvent) {
+	for _, book := range event.Books {
+		if event.Type == BookDeleted {
+			s.index.Remove(book.ID)
+		} else {
+			s.index.Set(book.ID, similarityText(book))
+		}
+	}
+	// Изменённая книга может стать соседом любой другой, поэтому сбрасываются все списки
+	s.cache.InvalidatePattern("similar:*")
+}
+
+func (s *similarService) RebuildIndex(ctx context.Context) error {
+	texts := make(map[uint]string)
+	err := s.bookRepo.StreamBooks("", func(book models.Book) error {
+		texts[book.ID] = similarityText(book)
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	s.index.Reset(texts)
+	s.cache.InvalidatePattern("similar:*")
+	return nil
+}
+
+func (s *similarService) GetSimilarBooks(bookID uint, limit int) ([]SimilarBook, error) {
+	cacheKey := fmt.Sprintf("similar:%d", bookID)
+	var similar []SimilarBook
+	if !s.cache.Get(cacheKey, &similar) {
+		if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+			return nil, errors.New("book not found")
+		}
+
+		matches := s.index.Similar(bookID, maxSimilarBooks)
+		ids := make([]uint, len(matches))
+		for i, match := range matches {
+			ids[i] = match.ID
+		}
+		books, err := s.bookRepo.GetBooksByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.Book, len(books))
+		for _, book := range books {
+			byID[book.ID] = book
+		}
+
+		similar = make([]SimilarBook, 0, len(matches))
+		for _, match := range matches {
+			if book, ok := byID[match.ID]; ok {
+				similar = append(similar, SimilarBook{
+					Book:  toBookBriefs([]models.Book{book})[0],
+					Score: math.Round(match.Score*1000) / 1000,
+				})
+			}
+		}
+		s.cache.Set(cacheKey, similar, time.Hour)
+	}
+
+	return similar[:min(len(similar), limit)], nil
+}
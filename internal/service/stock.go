@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bookshelf/internal/models"
+	"bookshelf/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxStockNote = 500
+
+// StockSettingsRequest: порог уведомления о малом остатке; первая настройка включает учёт книги
+type StockSettingsRequest struct {
+	LowStockThreshold int `json:"low_stock_threshold" example:"3"`
+}
+
+// StockAdjustmentRequest: ручное движение товара. delta положительная для restock и return,
+// отрицательная для damaged и lost, любая ненулевая для correction
+type StockAdjustmentRequest struct {
+	Delta  int    `json:"delta" example:"20"`
+	Reason string `json:"reason" example:"restock"`
+	Note   string `json:"note" example:"Поставка от издательства"`
+}
+
+type StockService interface {
+	GetStock(bookID uint) (models.StockLevel, error)
+	ListStock(lowOnly bool, page, limit int) ([]models.StockLevel, int64, error)
+	SetThreshold(bookID uint, req StockSettingsRequest) (models.StockLevel, error)
+	// Adjust меняет остаток и пишет движение в журнал; первое движение включает учёт книги
+	Adjust(adminID, bookID uint, req StockAdjustmentRequest) (models.StockMovement, error)
+	ListMovements(bookID uint, page, limit int) ([]models.StockMovement, int64, error)
+	// ProcessStock отменяет заказы с истёкшим резервом и уведомляет администраторов о малых остатках;
+	// вызывается планировщиком
+	ProcessStock(ctx context.Context) error
+}
+
+type stockService struct {
+	repo      repository.StockRepository
+	orderRepo repository.OrderRepository
+	bookRepo  repository.BookRepository
+	notifier  Notifier
+	// lowStock - порог для книг, учёт которых начат движением
+	lowStock int
+}
+
+func NewStockService(repo repository.StockRepository, orderRepo repository.OrderRepository, bookRepo repository.BookRepository, notifier Notifier, lowStock int) StockService {
+	return &stockService{repo: repo, orderRepo: orderRepo, bookRepo: bookRepo, notifier: notifier, lowStock: lowStock}
+}
+
+func (s *stockService) GetStock(bookID uint) (models.StockLevel, error) {
+	level, err := s.repo.GetLevel(bookID)
+	if err != nil {
+		return models.StockLevel{}, errors.New("stock not found: book is not tracked")
+	}
+	return level, nil
+}
+
+func (s *stockService) ListStock(lowOnly bool, page, limit int) ([]models.StockLevel, int64, error) {
+	return s.repo.ListLevels(lowOnly, page, limit)
+}
+
+func (s *stockService) checkBook(bookID uint) error {
+	if _, err := s.bookRepo.GetBookByID(strconv.FormatUint(uint64(bookID), 10)); err != nil {
+		return errors.New("book not found")
+	}
+	return nil
+}
+
+func (s *stockService) SetThreshold(bookID uint, req StockSettingsRequest) (models.StockLevel, error) {
+	if req.LowStockThreshold < 0 {
+		return models.StockLevel{}, errors.New("invalid low_stock_threshold, must be non-negative")
+	}
+	if err := s.checkBook(bookID); err != nil {
+		return models.StockLevel{}, err
+	}
+	if err := s.repo.SetThreshold(bookID, req.LowStockThreshold); err != nil {
+		return models.StockLevel{}, err
+	}
+	return s.repo.GetLevel(bookID)
+}
+
+func (s *stockService) Adjust(adminID, bookID uint, req StockAdjustmentRequest) (models.StockMovement, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	switch req.Reason {
+	case models.StockRestock, models.StockReturn:
+		if req.Delta <= 0 {
+			return models.StockMovement{}, fmt.Errorf("invalid delta, must be positive for %s", req.Reason)
+		}
+	case models.StockDamaged, models.StockLost:
+		if req.Delta >= 0 {
+			return models.StockMovement{}, fmt.Errorf("invalid delta, must be negative for %s", req.Reason)
+		}
+	case models.StockCorrection:
+		if req.Delta == 0 {
+			return models.StockMovement{}, errors.New("invalid delta, must not be zero")
+		}
+	default:
+		return models.StockMovement{}, errors.New("invalid reason, must be 'restock', 'return', 'damaged', 'lost' or 'correction'")
+	}
+	if utf8.RuneCountInString(req.Note) > maxStockNote {
+		return models.StockMovement{}, errors.New("invalid note, too long")
+	}
+	if err := s.checkBook(bookID); err != nil {
+		return models.StockMovement{}, err
+	}
+
+	movement := models.StockMovement{
+		BookID:    bookID,
+		Delta:     req.Delta,
+		Reason:    req.Reason,
+		Note:      req.Note,
+		CreatedBy: &adminID,
+	}
+	applied, err := s.repo.Adjust(&movement, s.lowStock)
+	if err != nil {
+		return models.StockMovement{}, err
+	}
+	if !applied {
+		return models.StockMovement{}, errors.New("invalid delta, stock would drop below units reserved for pending orders")
+	}
+	return movement, nil
+}
+
+func (s *stockService) ListMovements(bookID uint, page, limit int) ([]models.StockMovement, int64, error) {
+	return s.repo.ListMovements(bookID, page, limit)
+}
+
+func (s *stockService) ProcessStock(ctx context.Context) error {
+	now := time.Now()
+	if err := s.expireReservations(now); err != nil {
+		return err
+	}
+	return s.alertLowStock(now)
+}
+
+// expireReservations отменяет неоплаченные заказы с истёкшим резервом: отмена снимает резерв и возвращает купон.
+// Если оплата придёт позже, платёжный сервис вернёт деньги за отменённый заказ
+func (s *stockService) expireReservations(now time.Time) error {
+	orders, err := s.orderRepo.ListExpiredReservations(now)
+	if err != nil {
+		return err
+	}
+	expired := 0
+	for i := range orders {
+		cancelled, err := s.orderRepo.UpdateStatus(&orders[i], models.OrderPending, models.OrderCancelled)
+		if err != nil {
+			return err
+		}
+		if cancelled {
+			expired++
+		}
+	}
+	if expired > 0 {
+		log.Printf("stock: %d unpaid orders cancelled, reservations expired", expired)
+	}
+	return nil
+}
+
+func (s *stockService) alertLowStock(now time.Time) error {
+	if err := s.repo.ResetLowStockAlerts(); err != nil {
+		return err
+	}
+	levels, err := s.repo.ListLowStockToAlert()
+	if err != nil || len(levels) == 0 {
+		return err
+	}
+	admins, err := s.repo.ListAdminIDs()
+	if err != nil || len(admins) == 0 {
+		return err
+	}
+
+	items := make([]models.Notification, 0, len(levels)*len(admins))
+	ids := make([]uint, 0, len(levels))
+	for _, level := range levels {
+		for _, adminID := range admins {
+			items = append(items, models.Notification{
+				UserID: adminID,
+				Type:   models.NotificationLowStock,
+				BookID: level.BookID,
+				Title:  fmt.Sprintf("Заканчивается: «%s»", level.Book.Title),
+				Body: fmt.Sprintf("Доступно %d шт. (на складе %d, отложено под заказы %d), порог %d",
+					level.Available(), level.OnHand, level.Reserved, level.LowStockThreshold),
+			})
+		}
+		ids = append(ids, level.BookID)
+	}
+	if err := s.notifier.Notify(items); err != nil {
+		return err
+	}
+	return s.repo.MarkLowStockAlerted(ids, now)
+}
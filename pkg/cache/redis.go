@@ -4,6 +4,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -68,3 +69,53 @@ func (c *RedisCache) InvalidatePattern(pattern string) error {
 	}
 	return nil
 }
+
+// SetIfAbsent ставит метку на ttl и возвращает true, если её ещё не было
+func (c *RedisCache) SetIfAbsent(key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(c.ctx, key, 1, ttl).Result()
+}
+
+// IncrementField атомарно увеличивает числовое поле хэша
+func (c *RedisCache) IncrementField(key, field string, delta int64) error {
+	return c.client.HIncrBy(c.ctx, key, field, delta).Err()
+}
+
+// DrainHash забирает хэш счётчиков и удаляет его. Хэш сначала переименовывается, поэтому
+// инкременты во время выгрузки попадают уже в новый хэш и не теряются
+func (c *RedisCache) DrainHash(key string) (map[string]int64, error) {
+	draining := key + ":draining"
+
+	// Остаток прошлой выгрузки, прерванной на полпути, забирается первым
+	exists, err := c.client.Exists(c.ctx, draining).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		// Пустой хэш Redis не хранит: нет ключа - нечего выгружать. Ключ удаляет только сама
+		// выгрузка, поэтому между проверкой и RENAME он не пропадёт
+		exists, err = c.client.Exists(c.ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			return map[string]int64{}, nil
+		}
+		if err := c.client.Rename(c.ctx, key, draining).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := c.client.HGetAll(c.ctx, draining).Result()
+	if err != nil {
+		return nil, err
+	}
+	counters := make(map[string]int64, len(values))
+	for field, value := range values {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		counters[field] = n
+	}
+	return counters, c.client.Del(c.ctx, draining).Err()
+}
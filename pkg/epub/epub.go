@@ -0,0 +1,230 @@
+// Package epub
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	MimeType = "application/epub+zip"
+	// maxEntrySize - предел для распаковки служебных файлов и обложки
+	maxEntrySize = 16 << 20
+)
+
+// ErrInvalid - файл не является EPUB: нет container.xml или OPF
+var ErrInvalid = errors.New("epub: invalid file")
+
+// Metadata - метаданные из OPF. ISBN - как записан в файле, без нормализации
+type Metadata struct {
+	Title       string
+	Authors     []string
+	ISBN        string
+	Publisher   string
+	Language    string
+	Description string
+	Subjects    []string
+	// Cover - обложка, если она указана в манифесте; CoverType - её MIME-тип
+	Cover     []byte
+	CoverType string
+}
+
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type opfPackage struct {
+	Metadata struct {
+		Titles      []string        `xml:"title"`
+		Creators    []opfCreator    `xml:"creator"`
+		Identifiers []opfIdentifier `xml:"identifier"`
+		Publisher   string          `xml:"publisher"`
+		Language    string          `xml:"language"`
+		Description string          `xml:"description"`
+		Subjects    []string        `xml:"subject"`
+		Metas       []opfMeta       `xml:"meta"`
+	} `xml:"metadata"`
+	Manifest []opfItem `xml:"manifest>item"`
+}
+
+type opfCreator struct {
+	ID   string `xml:"id,attr"`
+	Role string `xml:"role,attr"`
+	Name string `xml:",chardata"`
+}
+
+type opfIdentifier struct {
+	Scheme string `xml:"scheme,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// opfMeta - EPUB 2 (<meta name content>) и EPUB 3 (<meta property refines>текст</meta>)
+type opfMeta struct {
+	Name     string `xml:"name,attr"`
+	Content  string `xml:"content,attr"`
+	Property string `xml:"property,attr"`
+	Refines  string `xml:"refines,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// IsEPUB - zip-архив, первый файл которого mimetype с типом EPUB
+func IsEPUB(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04")) && bytes.Contains(data[:min(len(data), 128)], []byte(MimeType))
+}
+
+// Parse читает метаданные EPUB 2 и EPUB 3
+func Parse(data []byte) (Metadata, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Metadata{}, ErrInvalid
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var c container
+	if err := decodeEntry(files, "META-INF/container.xml", &c); err != nil || len(c.Rootfiles) == 0 {
+		return Metadata{}, ErrInvalid
+	}
+	opfPath := c.Rootfiles[0].FullPath
+	var pkg opfPackage
+	if err := decodeEntry(files, opfPath, &pkg); err != nil {
+		return Metadata{}, ErrInvalid
+	}
+
+	m := pkg.Metadata
+	meta := Metadata{
+		Publisher:   strings.TrimSpace(m.Publisher),
+		Language:    strings.TrimSpace(m.Language),
+		Description: strings.TrimSpace(m.Description),
+	}
+	if len(m.Titles) > 0 {
+		meta.Title = strings.TrimSpace(m.Titles[0])
+	}
+	for _, creator := range m.Creators {
+		if name := strings.TrimSpace(creator.Name); name != "" && isAuthor(creator, m.Metas) {
+			meta.Authors = append(meta.Authors, name)
+		}
+	}
+	for _, subject := range m.Subjects {
+		if subject = strings.TrimSpace(subject); subject != "" {
+			meta.Subjects = append(meta.Subjects, subject)
+		}
+	}
+	meta.ISBN = findISBN(m.Identifiers)
+
+	if item, ok := coverItem(pkg); ok {
+		cover, err := readEntry(files, resolve(opfPath, item.Href))
+		if err == nil {
+			meta.Cover = cover
+			meta.CoverType = item.MediaType
+			if meta.CoverType == "" {
+				meta.CoverType = mime.TypeByExtension(path.Ext(item.Href))
+			}
+		}
+	}
+	return meta, nil
+}
+
+// isAuthor: без роли создатель считается автором; роль задаётся атрибутом (EPUB 2) или meta refines (EPUB 3)
+func isAuthor(creator opfCreator, metas []opfMeta) bool {
+	role := creator.Role
+	if creator.ID != "" {
+		for _, meta := range metas {
+			if meta.Property == "role" && meta.Refines == "#"+creator.ID {
+				role = strings.TrimSpace(meta.Value)
+			}
+		}
+	}
+	return role == "" || role == "aut"
+}
+
+func findISBN(identifiers []opfIdentifier) string {
+	for _, id := range identifiers {
+		value := strings.TrimSpace(id.Value)
+		lower := strings.ToLower(value)
+		switch {
+		case strings.EqualFold(id.Scheme, "isbn"):
+			return value
+		case strings.HasPrefix(lower, "urn:isbn:"):
+			return value[len("urn:isbn:"):]
+		case strings.HasPrefix(lower, "isbn:"):
+			return value[len("isbn:"):]
+		}
+	}
+	return ""
+}
+
+// coverItem: EPUB 3 помечает обложку properties="cover-image", EPUB 2 ссылается на неё из <meta name="cover">
+func coverItem(pkg opfPackage) (opfItem, bool) {
+	for _, item := range pkg.Manifest {
+		if strings.Contains(" "+item.Properties+" ", " cover-image ") {
+			return item, true
+		}
+	}
+	for _, meta := range pkg.Metadata.Metas {
+		if meta.Name != "cover" {
+			continue
+		}
+		for _, item := range pkg.Manifest {
+			if item.ID == meta.Content {
+				return item, true
+			}
+		}
+	}
+	return opfItem{}, false
+}
+
+// resolve - путь внутри архива для href относительно OPF
+func resolve(opfPath, href string) string {
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Join(path.Dir(opfPath), href)
+}
+
+func readEntry(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, ErrInvalid
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEntrySize {
+		return nil, ErrInvalid
+	}
+	return data, nil
+}
+
+func decodeEntry(files map[string]*zip.File, name string, v interface{}) error {
+	data, err := readEntry(files, name)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
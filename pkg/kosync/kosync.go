@@ -0,0 +1,41 @@
+// Package kosync
+package kosync
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// Коды ошибок протокола синхронизации KOReader
+const (
+	CodeUnknown         = 1000
+	CodeUnauthorized    = 2001
+	CodeUserExists      = 2002
+	CodeInvalidRequest  = 2003
+	CodeDocumentMissing = 2004
+)
+
+// Key - ключ x-auth-key, который KOReader отправляет вместо пароля: MD5 пароля в hex
+func Key(password string) string {
+	sum := md5.Sum([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// PartialMD5 - идентификатор документа KOReader (метод "binary"): MD5 от фрагментов по 1 КБ
+// со смещений 0 и 1024·4^i, i = 0..10. Смещение 0 первого фрагмента повторяет
+// поведение bit.lshift(1024, -2) в LuaJIT
+func PartialMD5(data []byte) string {
+	const step, size = 1024, 1024
+	hash := md5.New()
+	for i := -1; i <= 10; i++ {
+		offset := 0
+		if i >= 0 {
+			offset = step << (2 * i)
+		}
+		if offset >= len(data) {
+			break
+		}
+		hash.Write(data[offset:min(offset+size, len(data))])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
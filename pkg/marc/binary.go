@@ -0,0 +1,227 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Разделители ISO 2709
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+
+	leaderLength   = 24
+	directoryEntry = 12
+
+	// MaxFieldLength и MaxRecordLength - пределы, которые помещаются в 4 и 5 цифр справочника и лидера
+	MaxFieldLength  = 9999
+	MaxRecordLength = 99999
+)
+
+var (
+	ErrInvalidRecord = errors.New("invalid marc record")
+	// ErrTooLong - поле или запись не помещается в ISO 2709
+	ErrTooLong = errors.New("marc record too long")
+)
+
+// parseNumber разбирает число из справочника или лидера: только ASCII-цифры, без знака
+func parseNumber(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next читает следующую запись, в конце потока возвращает io.EOF
+func (rd *Reader) Next() (Record, error) {
+	for {
+		b, err := rd.r.Peek(1)
+		if err != nil {
+			return Record{}, err
+		}
+		// Между записями встречаются переводы строк и пробелы
+		if b[0] != '\n' && b[0] != '\r' && b[0] != ' ' {
+			break
+		}
+		rd.r.ReadByte()
+	}
+
+	head, err := rd.r.Peek(5)
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: truncated leader", ErrInvalidRecord)
+	}
+	length, ok := parseNumber(head)
+	if !ok || length < leaderLength+1 {
+		return Record{}, fmt.Errorf("%w: bad record length %q", ErrInvalidRecord, head)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return Record{}, fmt.Errorf("%w: truncated record", ErrInvalidRecord)
+	}
+	return Decode(data)
+}
+
+func ReadAll(r io.Reader) ([]Record, error) {
+	reader := NewReader(r)
+	var records []Record
+	for {
+		rec, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Decode разбирает одну запись MARC21 в формате ISO 2709
+func Decode(data []byte) (Record, error) {
+	if len(data) < leaderLength+1 {
+		return Record{}, fmt.Errorf("%w: too short", ErrInvalidRecord)
+	}
+	leader := string(data[:leaderLength])
+	base, ok := parseNumber(data[12:17])
+	if !ok || base <= leaderLength || base > len(data) {
+		return Record{}, fmt.Errorf("%w: bad base address", ErrInvalidRecord)
+	}
+
+	rec := Record{Leader: leader}
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntry != 0 {
+		return Record{}, fmt.Errorf("%w: bad directory", ErrInvalidRecord)
+	}
+
+	for i := 0; i < len(directory); i += directoryEntry {
+		entry := directory[i : i+directoryEntry]
+		tag := string(entry[:3])
+		length, ok1 := parseNumber(entry[3:7])
+		start, ok2 := parseNumber(entry[7:12])
+		if !ok1 || !ok2 || base+start+length > len(data) {
+			return Record{}, fmt.Errorf("%w: bad directory entry for %s", ErrInvalidRecord, tag)
+		}
+		value := bytes.TrimSuffix(data[base+start:base+start+length], []byte{fieldTerminator})
+
+		if isControlTag(tag) {
+			rec.AddControl(tag, string(value))
+			continue
+		}
+
+		field := DataField{Tag: tag, Ind1: " ", Ind2: " "}
+		if len(value) >= 2 {
+			field.Ind1, field.Ind2 = string(value[0]), string(value[1])
+			value = value[2:]
+		}
+		for _, part := range bytes.Split(value, []byte{subfieldDelimiter}) {
+			if len(part) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: string(part[0]), Value: string(part[1:])})
+		}
+		rec.DataFields = append(rec.DataFields, field)
+	}
+	return rec, nil
+}
+
+// Encode собирает запись в ISO 2709, длины и адрес данных в лидере пересчитываются.
+// Поле длиннее MaxFieldLength или запись длиннее MaxRecordLength дают ErrTooLong
+func Encode(rec Record) ([]byte, error) {
+	var directory, body bytes.Buffer
+
+	addField := func(tag string, value []byte) error {
+		value = append(value, fieldTerminator)
+		if len(value) > MaxFieldLength {
+			return fmt.Errorf("%w: field %s is %d bytes", ErrTooLong, tag, len(value))
+		}
+		if body.Len() > MaxRecordLength {
+			return fmt.Errorf("%w: field %s starts beyond %d bytes", ErrTooLong, tag, MaxRecordLength)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, len(value), body.Len())
+		body.Write(value)
+		return nil
+	}
+
+	for _, f := range rec.ControlFields {
+		if err := addField(f.Tag, []byte(f.Value)); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range rec.DataFields {
+		value := []byte(indicator(f.Ind1) + indicator(f.Ind2))
+		for _, sf := range f.Subfields {
+			value = append(value, subfieldDelimiter)
+			value = append(value, sf.Code...)
+			value = append(value, sf.Value...)
+		}
+		if err := addField(f.Tag, value); err != nil {
+			return nil, err
+		}
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + body.Len() + 1
+	if total > MaxRecordLength {
+		return nil, fmt.Errorf("%w: record is %d bytes", ErrTooLong, total)
+	}
+
+	leader := []byte(normalizeLeader(rec.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, body.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+func WriteBinary(w io.Writer, records ...Record) error {
+	for _, rec := range records {
+		data, err := Encode(rec)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Лидер по умолчанию: текстовый монографический ресурс в Unicode
+const defaultLeader = "00000nam a2200000 i 4500"
+
+func normalizeLeader(leader string) string {
+	if len(leader) != leaderLength {
+		leader = defaultLeader
+	}
+	b := []byte(leader)
+	// 10-11: счётчики индикаторов и подполей, 20-23: карта справочника
+	copy(b[10:12], "22")
+	copy(b[20:24], "4500")
+	// Пишем всегда UTF-8
+	b[9] = 'a'
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleRecord(title string) Record {
+	var rec Record
+	rec.AddControl("001", "42")
+	rec.AddField("100", "1", " ", "a", "Булгаков, Михаил")
+	rec.AddField("245", "1", "0", "a", title, "b", "роман")
+	rec.AddField("650", " ", "0", "a", "Fantasy")
+	return rec
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	rec := sampleRecord("Мастер и Маргарита")
+
+	data, err := Encode(rec)
+	assert.NoError(t, err)
+
+	decoded, err := Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", decoded.Control("001"))
+	assert.Equal(t, "Мастер и Маргарита", decoded.Field("245").Subfield("a"))
+	assert.Equal(t, "роман", decoded.Field("245").Subfield("b"))
+	assert.Equal(t, "1", decoded.Field("245").Ind1)
+	assert.Equal(t, rec.DataFields, decoded.DataFields)
+}
+
+func TestReadAll_SeveralRecords(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteBinary(&buf, sampleRecord("Первая"), sampleRecord("Вторая")))
+	buf.WriteString("\n")
+
+	records, err := ReadAll(&buf)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "Первая", records[0].Field("245").Subfield("a"))
+		assert.Equal(t, "Вторая", records[1].Field("245").Subfield("a"))
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	var field Record
+	field.AddField("520", " ", " ", "a", strings.Repeat("x", MaxFieldLength))
+	_, err := Encode(field)
+	assert.True(t, errors.Is(err, ErrTooLong))
+
+	var record Record
+	for i := 0; i < 12; i++ {
+		record.AddField("520", " ", " ", "a", strings.Repeat("x", 9000))
+	}
+	_, err = Encode(record)
+	assert.True(t, errors.Is(err, ErrTooLong))
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	valid, err := Encode(sampleRecord("Dune"))
+	assert.NoError(t, err)
+
+	// Смещения в справочнике: первая запись начинается сразу после лидера
+	entry := leaderLength
+	tests := []struct {
+		name   string
+		mutate func(data []byte)
+	}{
+		{"negative base", func(data []byte) { copy(data[12:17], "-0001") }},
+		{"non-digit base", func(data []byte) { copy(data[12:17], "00 4x") }},
+		{"negative length", func(data []byte) { copy(data[entry+3:entry+7], "-001") }},
+		{"negative start", func(data []byte) { copy(data[entry+7:entry+12], "-0001") }},
+		{"signed length", func(data []byte) { copy(data[entry+3:entry+7], "+001") }},
+		{"length past end", func(data []byte) { copy(data[entry+3:entry+7], "9999") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := append([]byte(nil), valid...)
+			tt.mutate(data)
+			assert.NotPanics(t, func() {
+				_, err := Decode(data)
+				assert.True(t, errors.Is(err, ErrInvalidRecord))
+			})
+		})
+	}
+
+	_, err = Decode([]byte("00010"))
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+	_, err = ReadAll(strings.NewReader("-0100nam a2200000 i 4500"))
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+}
@@ -0,0 +1,103 @@
+// Package marc
+package marc
+
+import "strings"
+
+type Subfield struct {
+	Code  string
+	Value string
+}
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      string
+	Ind2      string
+	Subfields []Subfield
+}
+
+// Record - запись MARC21. Контрольные поля (00X) и поля данных хранятся раздельно
+// в порядке появления
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+func (r *Record) Control(tag string) string {
+	for _, f := range r.ControlFields {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+func (r *Record) Field(tag string) *DataField {
+	for i := range r.DataFields {
+		if r.DataFields[i].Tag == tag {
+			return &r.DataFields[i]
+		}
+	}
+	return nil
+}
+
+func (r *Record) Fields(tag string) []DataField {
+	var fields []DataField
+	for _, f := range r.DataFields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func (r *Record) AddControl(tag, value string) {
+	r.ControlFields = append(r.ControlFields, ControlField{Tag: tag, Value: value})
+}
+
+// AddField добавляет поле, пары code/value с пустым значением пропускаются
+func (r *Record) AddField(tag, ind1, ind2 string, codeValues ...string) {
+	field := DataField{Tag: tag, Ind1: ind1, Ind2: ind2}
+	for i := 0; i+1 < len(codeValues); i += 2 {
+		if codeValues[i+1] != "" {
+			field.Subfields = append(field.Subfields, Subfield{Code: codeValues[i], Value: codeValues[i+1]})
+		}
+	}
+	if len(field.Subfields) > 0 {
+		r.DataFields = append(r.DataFields, field)
+	}
+}
+
+// Subfield возвращает первое значение подполя, nil-безопасно
+func (f *DataField) Subfield(code string) string {
+	if f == nil {
+		return ""
+	}
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// Clean убирает завершающую пунктуацию ISBD: "Go programming language /" -> "Go programming language"
+func Clean(value string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(value), " /:;,=."))
+}
+
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+func indicator(s string) string {
+	if s == "" {
+		return " "
+	}
+	return s[:1]
+}
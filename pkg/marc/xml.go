@@ -0,0 +1,151 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Xmlns         string            `xml:"xmlns,attr,omitempty"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+// ReadXML читает MARCXML: одиночный <record> или <collection> с записями
+func ReadXML(r io.Reader) ([]Record, error) {
+	decoder := xml.NewDecoder(r)
+	var records []Record
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return records, fmt.Errorf("%w: %s", ErrInvalidRecord, err.Error())
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var x xmlRecord
+		if err := decoder.DecodeElement(&x, &start); err != nil {
+			return records, fmt.Errorf("%w: %s", ErrInvalidRecord, err.Error())
+		}
+		records = append(records, fromXML(x))
+	}
+	return records, nil
+}
+
+func fromXML(x xmlRecord) Record {
+	rec := Record{Leader: x.Leader}
+	for _, f := range x.ControlFields {
+		rec.AddControl(f.Tag, f.Value)
+	}
+	for _, f := range x.DataFields {
+		field := DataField{Tag: f.Tag, Ind1: indicator(f.Ind1), Ind2: indicator(f.Ind2)}
+		for _, sf := range f.Subfields {
+			field.Subfields = append(field.Subfields, Subfield{Code: sf.Code, Value: sf.Value})
+		}
+		rec.DataFields = append(rec.DataFields, field)
+	}
+	return rec
+}
+
+func toXML(rec Record) xmlRecord {
+	x := xmlRecord{Leader: normalizeLeader(rec.Leader)}
+	for _, f := range rec.ControlFields {
+		x.ControlFields = append(x.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+	}
+	for _, f := range rec.DataFields {
+		field := xmlDataField{Tag: f.Tag, Ind1: indicator(f.Ind1), Ind2: indicator(f.Ind2)}
+		for _, sf := range f.Subfields {
+			field.Subfields = append(field.Subfields, xmlSubfield{Code: sf.Code, Value: sf.Value})
+		}
+		x.DataFields = append(x.DataFields, field)
+	}
+	return x
+}
+
+// WriteRecordXML пишет одну запись как самостоятельный документ
+func WriteRecordXML(w io.Writer, rec Record) error {
+	x := toXML(rec)
+	x.Xmlns = Namespace
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(x)
+}
+
+// XMLWriter потоково пишет <collection>, не держа все записи в памяти
+type XMLWriter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return &XMLWriter{w: w, encoder: encoder}
+}
+
+func (xw *XMLWriter) start() error {
+	if xw.started {
+		return nil
+	}
+	xw.started = true
+	if _, err := io.WriteString(xw.w, xml.Header); err != nil {
+		return err
+	}
+	return xw.encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+	})
+}
+
+func (xw *XMLWriter) Write(rec Record) error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	return xw.encoder.Encode(toXML(rec))
+}
+
+// Close закрывает <collection>, сам w не закрывается
+func (xw *XMLWriter) Close() error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	if err := xw.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	return xw.encoder.Flush()
+}
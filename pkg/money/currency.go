@@ -0,0 +1,160 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidCurrency = errors.New("money: invalid currency")
+	ErrInvalidRate     = errors.New("money: invalid exchange rate")
+)
+
+// currencyDigits - число знаков после точки (minor units) по ISO 4217 для поддерживаемых валют
+var currencyDigits = map[string]int{
+	"AED": 2, "ARS": 2, "AUD": 2, "BGN": 2, "BHD": 3, "BRL": 2, "BYN": 2, "CAD": 2,
+	"CHF": 2, "CLP": 0, "CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "GEL": 2,
+	"HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JPY": 0, "KRW": 0,
+	"KWD": 3, "KZT": 2, "MXN": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PLN": 2, "RON": 2,
+	"RSD": 2, "RUB": 2, "SEK": 2, "SGD": 2, "THB": 2, "TRY": 2, "UAH": 2, "USD": 2,
+	"UZS": 2, "VND": 0, "ZAR": 2,
+}
+
+// NormalizeCurrency приводит код валюты к верхнему регистру и проверяет, что он поддерживается
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencyDigits[code]; !ok {
+		return "", ErrInvalidCurrency
+	}
+	return code, nil
+}
+
+// Digits - число знаков после точки у валюты; для неизвестной валюты 2
+func Digits(currency string) int {
+	if d, ok := currencyDigits[currency]; ok {
+		return d
+	}
+	return 2
+}
+
+// Money - сумма в минимальных единицах валюты вместе с кодом валюты ISO 4217.
+// В JSON записывается объектом {"amount": "49.99", "currency": "USD"}, amount - с числом знаков валюты
+type Money struct {
+	Amount   Amount `json:"amount" swaggertype:"string" example:"49.99"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Decimal - сумма из запроса, пока неизвестна её валюта. В JSON принимается строкой "49.99" или числом;
+// в минимальные единицы переводится ParseIn, когда валюта уже известна
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		s = ""
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+// ParseIn разбирает десятичную сумму в минимальные единицы валюты: "1500" для JPY, "1.234" для KWD.
+// Знаков после точки больше, чем у валюты, - ошибка
+func ParseIn(s, currency string) (Money, error) {
+	code, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	amount, err := parseDigits(s, Digits(code))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: code}, nil
+}
+
+// Decimal - сумма без кода валюты, с числом знаков валюты: "49.99", "1500"
+func (m Money) Decimal() string {
+	return m.Amount.format(Digits(m.Currency))
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON - обратное к MarshalJSON: amount строкой или числом. Код валюты не проверяется,
+// для запросов с ценой нужен Decimal и ParseIn
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	code := strings.ToUpper(strings.TrimSpace(raw.Currency))
+	amount, err := parseDigits(strings.Trim(string(raw.Amount), `"`), Digits(code))
+	if err != nil {
+		return err
+	}
+	*m = Money{Amount: amount, Currency: code}
+	return nil
+}
+
+// ParseRate разбирает курс - положительное десятичное число вида "0.9215"
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || rate.Sign() <= 0 || strings.ContainsAny(s, "/eE") {
+		return nil, ErrInvalidRate
+	}
+	return rate, nil
+}
+
+// Convert пересчитывает сумму в валюту to по курсу rate (сколько единиц to за одну единицу m.Currency).
+// Счёт точный, результат округляется до минимальной единицы to, половина - от нуля
+func Convert(m Money, to string, rate *big.Rat) Money {
+	value := new(big.Rat).SetInt64(int64(m.Amount))
+	value.Mul(value, rate)
+	value.Mul(value, new(big.Rat).SetFrac64(pow10(Digits(to)), pow10(Digits(m.Currency))))
+	return Money{Amount: Amount(roundRat(value)), Currency: to}
+}
+
+// Prorate - доля part/whole от суммы m в той же валюте, с тем же округлением, что и Convert.
+// Так цена со скидкой переносится на цену в другой валюте: скидка в процентах сохраняется
+func Prorate(m Money, part, whole Amount) Money {
+	if whole == 0 || part == whole {
+		return m
+	}
+	num := new(big.Int).Mul(big.NewInt(int64(m.Amount)), big.NewInt(int64(part)))
+	value := new(big.Rat).SetFrac(num, big.NewInt(int64(whole)))
+	return Money{Amount: Amount(roundRat(value)), Currency: m.Currency}
+}
+
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+// FormatRate записывает курс десятичной строкой без лишних нулей
+func FormatRate(rate *big.Rat) string {
+	s := strings.TrimRight(rate.FloatString(10), "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,125 @@
+// Package money
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount - денежная сумма в минимальных единицах валюты (копейках, центах). Сложение и умножение
+// на количество точные, в отличие от float64. В JSON записывается строкой "19.99" - с двумя знаками
+// после точки; сумма в валюте с другим числом знаков (JPY, KWD) записывается через Money
+type Amount int64
+
+var ErrInvalidAmount = errors.New("money: invalid amount")
+
+// FromFloat переводит цену из float64 с округлением до копейки, половина - от нуля
+func FromFloat(v float64) Amount {
+	return Amount(math.Round(v * 100))
+}
+
+// Parse разбирает десятичную запись вида "19.99", "-5" или "0.5"; больше двух знаков после точки - ошибка
+func Parse(s string) (Amount, error) {
+	return parseDigits(s, 2)
+}
+
+// parseDigits разбирает десятичную запись в единицы с digits знаками после точки
+func parseDigits(s string, digits int) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if !isDigits(whole) || len(frac) > digits || (frac != "" && !isDigits(frac)) {
+		return 0, ErrInvalidAmount
+	}
+	for len(frac) < digits {
+		frac += "0"
+	}
+	scale := pow10(digits)
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	var minor int64
+	if digits > 0 {
+		if minor, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return 0, ErrInvalidAmount
+		}
+	}
+	if units > (math.MaxInt64-minor)/scale {
+		return 0, ErrInvalidAmount
+	}
+
+	a := Amount(units*scale + minor)
+	if negative {
+		a = -a
+	}
+	return a, nil
+}
+
+// isDigits: ParseInt сам принял бы знак, поэтому "--5" или "5.-1" отсекаются здесь
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+func (a Amount) Mul(n int) Amount {
+	return a * Amount(n)
+}
+
+// Float - приближённое значение для мест, где цена пока хранится в float64
+func (a Amount) Float() float64 {
+	return float64(a) / 100
+}
+
+func (a Amount) String() string {
+	return a.format(2)
+}
+
+// format записывает сумму с digits знаками после точки
+func (a Amount) format(digits int) string {
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+	if digits == 0 {
+		return fmt.Sprintf("%s%d", sign, a)
+	}
+	scale := Amount(pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d", sign, a/scale, digits, a%scale)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON принимает и строку "19.99", и число 19.99
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,60 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		ok   bool
+	}{
+		{"19.99", 1999, true},
+		{"-5", -500, true},
+		{"0.5", 50, true},
+		{" 7.05 ", 705, true},
+		{"92233720368547758.07", 9223372036854775807, true},
+		{"--5", 0, false},
+		{"-+5", 0, false},
+		{"+5", 0, false},
+		{"5.-1", 0, false},
+		{"5.+1", 0, false},
+		{"1,234.50", 0, false},
+		{"1 234", 0, false},
+		{"1.234", 0, false},
+		{".5", 0, false},
+		{"-", 0, false},
+		{"", 0, false},
+		{"1e3", 0, false},
+		{"92233720368547758.08", 0, false},
+		{"99999999999999999999", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.ok {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidAmount))
+			}
+		})
+	}
+}
+
+func TestParseIn_CurrencyDigits(t *testing.T) {
+	jpy, err := ParseIn("1500", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(1500), jpy.Amount)
+
+	_, err = ParseIn("1500.5", "JPY")
+	assert.Error(t, err)
+
+	kwd, err := ParseIn("1.250", "KWD")
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(1250), kwd.Amount)
+}
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"sync"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SendMailFunc совпадает по сигнатуре с smtp.SendMail
+type SendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+type EmailChannel struct {
+	cfg  SMTPConfig
+	send SendMailFunc
+}
+
+func NewSMTPChannel(cfg SMTPConfig) *EmailChannel {
+	return NewEmailChannel(cfg, smtp.SendMail)
+}
+
+// NewEmailChannel позволяет подменить отправку, например на Mailbox в тестах и локально
+func NewEmailChannel(cfg SMTPConfig, send SendMailFunc) *EmailChannel {
+	return &EmailChannel{cfg: cfg, send: send}
+}
+
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+func (c *EmailChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+	return c.send(c.cfg.Host+":"+c.cfg.Port, auth, c.cfg.From, []string{msg.To}, buildEmail(c.cfg.From, msg))
+}
+
+func buildEmail(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Subject)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// SentMail - письмо, перехваченное Mailbox
+type SentMail struct {
+	Addr string
+	From string
+	To   []string
+	Data []byte
+}
+
+// Mailbox - локальная замена SMTP-сервера: письма складываются в память
+type Mailbox struct {
+	mu   sync.Mutex
+	sent []SentMail
+}
+
+func NewMailbox() *Mailbox {
+	return &Mailbox{}
+}
+
+func (m *Mailbox) Send(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, SentMail{Addr: addr, From: from, To: to, Data: msg})
+	return nil
+}
+
+func (m *Mailbox) Sent() []SentMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentMail(nil), m.sent...)
+}
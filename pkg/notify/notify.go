@@ -0,0 +1,28 @@
+// Package notify
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoRecipient - у пользователя не настроен адрес для канала
+var ErrNoRecipient = errors.New("notify: recipient is not configured")
+
+// Message - уведомление для внешней доставки. To - адрес в терминах канала: email или URL вебхука
+type Message struct {
+	To        string    `json:"-"`
+	UserID    uint      `json:"user_id"`
+	Type      string    `json:"type"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	BookID    uint      `json:"book_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Channel доставляет уведомления во внешнюю систему
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader содержит HMAC-SHA256 тела запроса в hex
+const SignatureHeader = "X-Bookshelf-Signature"
+
+// ErrPrivateAddress - вебхук ведёт во внутреннюю сеть сервера
+var ErrPrivateAddress = errors.New("notify: webhook address is not public")
+
+// sharedAddressSpace - 100.64.0.0/10 (CGNAT), net.IP.IsPrivate её не считает частной
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PublicIP сообщает, можно ли слать вебхук на адрес. Адрес вебхука задаёт пользователь, поэтому
+// loopback, частные и link-local сети закрыты: иначе через вебхук можно обращаться к внутренним сервисам
+func PublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// CheckWebhookURL резолвит хост вебхука и проверяет, что все его адреса публичные
+func CheckWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("notify: resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !PublicIP(addr.IP) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// dialControl повторяет проверку при каждом соединении: DNS мог смениться после сохранения
+// адреса, а редирект - увести на другой хост
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+type WebhookChannel struct {
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhookChannel(secret string) *WebhookChannel {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialControl}
+	// Без прокси: через него соединение шло бы к прокси, и проверка адреса не сработала бы
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &WebhookChannel{secret: []byte(secret), client: &http.Client{Timeout: 10 * time.Second, Transport: transport}}
+}
+
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(c.secret, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook responded with %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookRecorder - локальный приёмник вебхуков: проверяет подпись и запоминает сообщения.
+// Подключается через httptest.NewServer или как обычный маршрут
+type WebhookRecorder struct {
+	secret   []byte
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewWebhookRecorder(secret string) *WebhookRecorder {
+	return &WebhookRecorder{secret: []byte(secret)}
+}
+
+func (rec *WebhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	if !hmac.Equal([]byte(r.Header.Get(SignatureHeader)), []byte(Sign(rec.secret, body))) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	rec.mu.Lock()
+	rec.messages = append(rec.messages, msg)
+	rec.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rec *WebhookRecorder) Messages() []Message {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Message(nil), rec.messages...)
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.public, PublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestCheckWebhookURL_Loopback(t *testing.T) {
+	err := CheckWebhookURL(context.Background(), "http://localhost:8080/hook")
+	assert.True(t, errors.Is(err, ErrPrivateAddress))
+
+	err = CheckWebhookURL(context.Background(), "http://169.254.169.254/latest/meta-data")
+	assert.True(t, errors.Is(err, ErrPrivateAddress))
+}
+
+func TestWebhookChannel_RefusesPrivateAddress(t *testing.T) {
+	recorder := NewWebhookRecorder("secret")
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// Соединение с loopback обрывается в dialer, до отправки запроса
+	err := NewWebhookChannel("secret").Send(context.Background(), Message{To: server.URL, Subject: "test"})
+	assert.True(t, errors.Is(err, ErrPrivateAddress))
+	assert.Empty(t, recorder.Messages())
+}
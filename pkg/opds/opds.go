@@ -0,0 +1,155 @@
+// Package opds
+package opds
+
+import "encoding/xml"
+
+// Типы ссылок и rel из спецификаций OPDS 1.2 и 2.0
+const (
+	AtomNamespace       = "http://www.w3.org/2005/Atom"
+	OPDSNamespace       = "http://opds-spec.org/2010/catalog"
+	DCNamespace         = "http://purl.org/dc/terms/"
+	OpenSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+
+	NavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	AcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	OpenSearchType  = "application/opensearchdescription+xml"
+	JSONType        = "application/opds+json"
+	PublicationType = "application/opds-publication+json"
+
+	RelSelf        = "self"
+	RelStart       = "start"
+	RelUp          = "up"
+	RelNext        = "next"
+	RelPrevious    = "previous"
+	RelFirst       = "first"
+	RelLast        = "last"
+	RelSearch      = "search"
+	RelSubsection  = "subsection"
+	RelAlternate   = "alternate"
+	RelNew         = "http://opds-spec.org/sort/new"
+	RelAcquisition = "http://opds-spec.org/acquisition"
+	RelBuy         = "http://opds-spec.org/acquisition/buy"
+	RelImage       = "http://opds-spec.org/image"
+	RelShelf       = "http://opds-spec.org/shelf"
+)
+
+// OPDS 1.2 (Atom)
+
+type Link struct {
+	Rel   string `xml:"rel,attr,omitempty" json:"rel,omitempty"`
+	Href  string `xml:"href,attr" json:"href"`
+	Type  string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Title string `xml:"title,attr,omitempty" json:"title,omitempty"`
+	// Templated используется только в OPDS 2.0
+	Templated bool `xml:"-" json:"templated,omitempty"`
+}
+
+type Author struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type Category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type Content struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Authors    []Author   `xml:"author,omitempty"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Categories []Category `xml:"category,omitempty"`
+	Content    *Content   `xml:"content,omitempty"`
+	Links      []Link     `xml:"link"`
+}
+
+type Feed struct {
+	XMLName      xml.Name `xml:"feed"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	XmlnsOPDS    string   `xml:"xmlns:opds,attr"`
+	XmlnsDC      string   `xml:"xmlns:dc,attr"`
+	XmlnsOS      string   `xml:"xmlns:opensearch,attr"`
+	ID           string   `xml:"id"`
+	Title        string   `xml:"title"`
+	Updated      string   `xml:"updated"`
+	Author       *Author  `xml:"author,omitempty"`
+	TotalResults int64    `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int      `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int      `xml:"opensearch:startIndex,omitempty"`
+	Links        []Link   `xml:"link"`
+	Entries      []Entry  `xml:"entry"`
+}
+
+// NewFeed заполняет пространства имён, которые ждут читалки
+func NewFeed(id, title, updated string) Feed {
+	return Feed{
+		Xmlns:     AtomNamespace,
+		XmlnsOPDS: OPDSNamespace,
+		XmlnsDC:   DCNamespace,
+		XmlnsOS:   OpenSearchNamespace,
+		ID:        id,
+		Title:     title,
+		Updated:   updated,
+	}
+}
+
+type OpenSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+type OpenSearchDescription struct {
+	XMLName        xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	ShortName      string          `xml:"ShortName"`
+	Description    string          `xml:"Description"`
+	InputEncoding  string          `xml:"InputEncoding"`
+	OutputEncoding string          `xml:"OutputEncoding"`
+	URLs           []OpenSearchURL `xml:"Url"`
+}
+
+// OPDS 2.0 (JSON)
+
+type Metadata struct {
+	Title         string `json:"title"`
+	NumberOfItems int64  `json:"numberOfItems,omitempty"`
+	ItemsPerPage  int    `json:"itemsPerPage,omitempty"`
+	CurrentPage   int    `json:"currentPage,omitempty"`
+}
+
+type Contributor struct {
+	Name string `json:"name"`
+}
+
+type Subject struct {
+	Name string `json:"name"`
+}
+
+type PublicationMetadata struct {
+	Type        string        `json:"@type"`
+	Identifier  string        `json:"identifier,omitempty"`
+	Title       string        `json:"title"`
+	Author      []Contributor `json:"author,omitempty"`
+	Subject     []Subject     `json:"subject,omitempty"`
+	Description string        `json:"description,omitempty"`
+}
+
+type Publication struct {
+	Metadata PublicationMetadata `json:"metadata"`
+	Links    []Link              `json:"links"`
+	Images   []Link              `json:"images,omitempty"`
+}
+
+type FeedV2 struct {
+	Metadata     Metadata      `json:"metadata"`
+	Links        []Link        `json:"links"`
+	Navigation   []Link        `json:"navigation,omitempty"`
+	Publications []Publication `json:"publications,omitempty"`
+}
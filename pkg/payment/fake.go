@@ -0,0 +1,224 @@
+package payment
+
+import (
+	"bookshelf/pkg/money"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// FakeSignatureHeader содержит HMAC-SHA256 тела вебхука фейкового провайдера в hex
+const FakeSignatureHeader = "X-Fake-Payment-Signature"
+
+// Статусы платежа фейкового провайдера, те же, что у Stripe
+const (
+	FakeRequiresConfirmation = "requires_confirmation"
+	FakeRequiresCapture      = "requires_capture"
+	FakeSucceeded            = "succeeded"
+	FakeFailed               = "canceled"
+	FakeRefunded             = "refunded"
+)
+
+// ErrInvalidState - операция не подходит к текущему статусу платежа
+var ErrInvalidState = errors.New("payment: operation is not allowed in current intent state")
+
+// FakeProvider - платёжный шлюз в памяти для разработки и тестов. Покупатель «платит» через Confirm,
+// а события приходят подписанными вебхуками в функцию из SetWebhook - тем же путём, что и от настоящего
+// провайдера
+type FakeProvider struct {
+	secret []byte
+
+	mu       sync.Mutex
+	intents  map[string]*Intent
+	byKey    map[string]string
+	seq      int
+	deliver  func(payload []byte, header http.Header)
+	delivery sync.WaitGroup
+}
+
+// NewFakeProvider создаёт провайдер; при пустом secret ключ подписи генерируется случайно
+func NewFakeProvider(secret string) *FakeProvider {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = []byte(randomHex(32))
+	}
+	return &FakeProvider{secret: key, intents: make(map[string]*Intent), byKey: make(map[string]string)}
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n/2)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// SetWebhook задаёт получателя вебхуков. События доставляются асинхронно, как у настоящего провайдера
+func (p *FakeProvider) SetWebhook(deliver func(payload []byte, header http.Header)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deliver = deliver
+}
+
+// Wait дожидается доставки отправленных вебхуков
+func (p *FakeProvider) Wait() {
+	p.delivery.Wait()
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, params IntentParams) (Intent, error) {
+	if params.Amount <= 0 {
+		return Intent{}, fmt.Errorf("payment: amount must be positive")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id, ok := p.byKey[params.IdempotencyKey]; ok && params.IdempotencyKey != "" {
+		return *p.intents[id], nil
+	}
+
+	p.seq++
+	id := fmt.Sprintf("fake_pi_%d", p.seq)
+	intent := &Intent{
+		ID:           id,
+		ClientSecret: id + "_secret_" + randomHex(16),
+		Status:       FakeRequiresConfirmation,
+		Amount:       params.Amount,
+		Currency:     strings.ToUpper(params.Currency),
+	}
+	p.intents[id] = intent
+	if params.IdempotencyKey != "" {
+		p.byKey[params.IdempotencyKey] = id
+	}
+	return *intent, nil
+}
+
+// Confirm заменяет платёжную форму: покупатель по client secret подтверждает оплату (succeed) или получает отказ
+func (p *FakeProvider) Confirm(clientSecret string, succeed bool) (Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var intent *Intent
+	for _, candidate := range p.intents {
+		if hmac.Equal([]byte(candidate.ClientSecret), []byte(clientSecret)) {
+			intent = candidate
+			break
+		}
+	}
+	if intent == nil {
+		return Intent{}, ErrIntentNotFound
+	}
+	if intent.Status != FakeRequiresConfirmation {
+		return Intent{}, ErrInvalidState
+	}
+
+	if succeed {
+		intent.Status = FakeRequiresCapture
+		p.emit(Event{Type: EventAuthorized, IntentID: intent.ID, Amount: intent.Amount, Currency: intent.Currency})
+	} else {
+		intent.Status = FakeFailed
+		p.emit(Event{Type: EventFailed, IntentID: intent.ID, Amount: intent.Amount, Currency: intent.Currency, Reason: "Your card was declined."})
+	}
+	return *intent, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, intentID string) (Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return Intent{}, ErrIntentNotFound
+	}
+	switch intent.Status {
+	case FakeSucceeded:
+		// повторное списание ничего не делает, как запрос с тем же ключом идемпотентности
+		return *intent, nil
+	case FakeRequiresCapture:
+		intent.Status = FakeSucceeded
+		p.emit(Event{Type: EventSucceeded, IntentID: intent.ID, Amount: intent.Amount, Currency: intent.Currency})
+		return *intent, nil
+	}
+	return Intent{}, ErrInvalidState
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, intentID string, amount money.Amount, idempotencyKey string) (Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return Refund{}, ErrIntentNotFound
+	}
+	if intent.Status != FakeSucceeded || amount <= 0 || amount > intent.Amount {
+		return Refund{}, ErrInvalidState
+	}
+	intent.Status = FakeRefunded
+	p.emit(Event{Type: EventRefunded, IntentID: intent.ID, Amount: amount, Currency: intent.Currency})
+	return Refund{ID: "fake_re_" + strings.TrimPrefix(intent.ID, "fake_pi_"), IntentID: intent.ID, Amount: amount, Status: FakeSucceeded}, nil
+}
+
+type fakeEvent struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	IntentID string       `json:"intent_id"`
+	Amount   money.Amount `json:"amount"`
+	Currency string       `json:"currency"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// emit подписывает событие и отправляет его получателю; вызывается под p.mu
+func (p *FakeProvider) emit(event Event) {
+	p.seq++
+	event.ID = fmt.Sprintf("fake_evt_%d", p.seq)
+	if p.deliver == nil {
+		return
+	}
+
+	payload, _ := json.Marshal(fakeEvent(event))
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(FakeSignatureHeader, p.sign(payload))
+
+	deliver := p.deliver
+	p.delivery.Add(1)
+	go func() {
+		defer p.delivery.Done()
+		deliver(payload, header)
+	}()
+}
+
+func (p *FakeProvider) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignWebhook подписывает тело так же, как провайдер подписывает свои вебхуки
+func (p *FakeProvider) SignWebhook(payload []byte) http.Header {
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, p.sign(payload))
+	return header
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (Event, error) {
+	if !hmac.Equal([]byte(header.Get(FakeSignatureHeader)), []byte(p.sign(payload))) {
+		return Event{}, ErrInvalidSignature
+	}
+	var event fakeEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+		return Event{}, ErrInvalidPayload
+	}
+	return Event(event), nil
+}
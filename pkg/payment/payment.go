@@ -0,0 +1,77 @@
+// Package payment
+package payment
+
+import (
+	"bookshelf/pkg/money"
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrInvalidSignature - вебхук не подписан провайдером или подпись устарела
+	ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+	// ErrInvalidPayload - тело вебхука не удалось разобрать
+	ErrInvalidPayload = errors.New("payment: invalid webhook payload")
+	// ErrIntentNotFound - провайдер не знает такого платежа
+	ErrIntentNotFound = errors.New("payment: intent not found")
+)
+
+// Типы событий вебхука, общие для всех провайдеров. События, которые приложению не интересны,
+// приходят с пустым типом
+const (
+	// EventAuthorized - покупатель подтвердил оплату, деньги заблокированы и ждут списания (Capture)
+	EventAuthorized = "payment.authorized"
+	EventSucceeded  = "payment.succeeded"
+	EventFailed     = "payment.failed"
+	EventRefunded   = "payment.refunded"
+)
+
+// IntentParams - параметры нового платежа. IdempotencyKey защищает от двойного платежа при повторе запроса
+type IntentParams struct {
+	Amount         money.Amount
+	Currency       string
+	OrderID        uint
+	IdempotencyKey string
+}
+
+// Intent - платёж на стороне провайдера. ClientSecret передаётся покупателю для подтверждения оплаты
+type Intent struct {
+	ID           string
+	ClientSecret string
+	Status       string
+	Amount       money.Amount
+	Currency     string
+}
+
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   money.Amount
+	Status   string
+}
+
+// Event - проверенное событие вебхука. ID уникален у провайдера и используется для защиты от повторной доставки
+type Event struct {
+	ID       string
+	Type     string
+	IntentID string
+	Amount   money.Amount
+	// Currency - код валюты суммы в верхнем регистре
+	Currency string
+	// Reason - причина отказа для EventFailed
+	Reason string
+}
+
+// Provider - платёжный шлюз. Платёж создаётся с ручным списанием: после подтверждения покупателем
+// приходит EventAuthorized, и приложение само решает, списывать ли деньги
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, params IntentParams) (Intent, error)
+	Capture(ctx context.Context, intentID string) (Intent, error)
+	// Refund возвращает amount по списанному платежу; о завершении сообщит EventRefunded.
+	// idempotencyKey должен меняться от попытки к попытке: провайдер запоминает и неудачный ответ
+	Refund(ctx context.Context, intentID string, amount money.Amount, idempotencyKey string) (Refund, error)
+	// VerifyWebhook проверяет подпись вебхука и разбирает событие
+	VerifyWebhook(payload []byte, header http.Header) (Event, error)
+}
@@ -0,0 +1,222 @@
+package payment
+
+import (
+	"bookshelf/pkg/money"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeAPI = "https://api.stripe.com"
+	// StripeSignatureHeader: "t=<unix>,v1=<hex hmac>", подписывается строка "<t>.<тело>"
+	StripeSignatureHeader = "Stripe-Signature"
+	// stripeTolerance - насколько старую подпись ещё принимаем, защита от повторной отправки перехваченного вебхука
+	stripeTolerance = 5 * time.Minute
+)
+
+// StripeProvider работает с Payment Intents API Stripe
+type StripeProvider struct {
+	secretKey     string
+	webhookSecret []byte
+	baseURL       string
+	client        *http.Client
+}
+
+func NewStripeProvider(secretKey, webhookSecret string) *StripeProvider {
+	return &StripeProvider{
+		secretKey:     secretKey,
+		webhookSecret: []byte(webhookSecret),
+		baseURL:       stripeAPI,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+type stripeIntent struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+}
+
+func (si stripeIntent) intent() Intent {
+	return Intent{
+		ID:           si.ID,
+		ClientSecret: si.ClientSecret,
+		Status:       si.Status,
+		Amount:       money.Amount(si.Amount),
+		Currency:     strings.ToUpper(si.Currency),
+	}
+}
+
+func (p *StripeProvider) CreateIntent(ctx context.Context, params IntentParams) (Intent, error) {
+	form := url.Values{
+		"amount":             {strconv.FormatInt(int64(params.Amount), 10)},
+		"currency":           {strings.ToLower(params.Currency)},
+		"capture_method":     {"manual"},
+		"metadata[order_id]": {strconv.FormatUint(uint64(params.OrderID), 10)},
+	}
+	var si stripeIntent
+	if err := p.post(ctx, "/v1/payment_intents", form, params.IdempotencyKey, &si); err != nil {
+		return Intent{}, err
+	}
+	return si.intent(), nil
+}
+
+func (p *StripeProvider) Capture(ctx context.Context, intentID string) (Intent, error) {
+	var si stripeIntent
+	path := "/v1/payment_intents/" + url.PathEscape(intentID) + "/capture"
+	if err := p.post(ctx, path, url.Values{}, "capture-"+intentID, &si); err != nil {
+		return Intent{}, err
+	}
+	return si.intent(), nil
+}
+
+func (p *StripeProvider) Refund(ctx context.Context, intentID string, amount money.Amount, idempotencyKey string) (Refund, error) {
+	form := url.Values{
+		"payment_intent": {intentID},
+		"amount":         {strconv.FormatInt(int64(amount), 10)},
+	}
+	var sr struct {
+		ID            string `json:"id"`
+		PaymentIntent string `json:"payment_intent"`
+		Amount        int64  `json:"amount"`
+		Status        string `json:"status"`
+	}
+	if err := p.post(ctx, "/v1/refunds", form, idempotencyKey, &sr); err != nil {
+		return Refund{}, err
+	}
+	return Refund{ID: sr.ID, IntentID: sr.PaymentIntent, Amount: money.Amount(sr.Amount), Status: sr.Status}, nil
+}
+
+func (p *StripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, into interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		if resp.StatusCode == http.StatusNotFound {
+			return ErrIntentNotFound
+		}
+		return fmt.Errorf("payment: stripe responded with %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, into)
+}
+
+func (p *StripeProvider) VerifyWebhook(payload []byte, header http.Header) (Event, error) {
+	if err := p.verifySignature(payload, header.Get(StripeSignatureHeader), time.Now()); err != nil {
+		return Event{}, err
+	}
+
+	var raw struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				ID               string `json:"id"`
+				Amount           int64  `json:"amount"`
+				AmountCapturable int64  `json:"amount_capturable"`
+				AmountRefunded   int64  `json:"amount_refunded"`
+				Currency         string `json:"currency"`
+				PaymentIntent    string `json:"payment_intent"`
+				LastPaymentError *struct {
+					Message string `json:"message"`
+				} `json:"last_payment_error"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil || raw.ID == "" {
+		return Event{}, ErrInvalidPayload
+	}
+
+	obj := raw.Data.Object
+	event := Event{ID: raw.ID, IntentID: obj.ID, Amount: money.Amount(obj.Amount), Currency: strings.ToUpper(obj.Currency)}
+	switch raw.Type {
+	case "payment_intent.amount_capturable_updated":
+		event.Type = EventAuthorized
+		event.Amount = money.Amount(obj.AmountCapturable)
+	case "payment_intent.succeeded":
+		event.Type = EventSucceeded
+	case "payment_intent.payment_failed", "payment_intent.canceled":
+		event.Type = EventFailed
+		event.Reason = strings.TrimPrefix(raw.Type, "payment_intent.")
+		if obj.LastPaymentError != nil {
+			event.Reason = obj.LastPaymentError.Message
+		}
+	case "charge.refunded":
+		// объект события - списание (charge), платёж указан в payment_intent
+		event.Type = EventRefunded
+		event.IntentID = obj.PaymentIntent
+		event.Amount = money.Amount(obj.AmountRefunded)
+	}
+	return event, nil
+}
+
+func (p *StripeProvider) verifySignature(payload []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, p.webhookSecret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,59 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stripeSignature(secret string, ts int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", ts)))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestStripeProvider_VerifySignature(t *testing.T) {
+	provider := NewStripeProvider("sk_test", "whsec_test")
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded"}`)
+	now := time.Unix(1700000000, 0)
+	ts := now.Unix()
+	valid := stripeSignature("whsec_test", ts, payload)
+
+	tests := []struct {
+		name   string
+		header string
+		ok     bool
+	}{
+		{"valid", fmt.Sprintf("t=%d,v1=%s", ts, valid), true},
+		{"spaces after commas", fmt.Sprintf("t=%d, v1=%s", ts, valid), true},
+		{"several v1, one valid", fmt.Sprintf("t=%d,v1=%s,v1=%s", ts, "deadbeef", valid), true},
+		{"valid after v0", fmt.Sprintf("t=%d,v0=%s,v1=%s", ts, valid, valid), true},
+		{"inside tolerance", fmt.Sprintf("t=%d,v1=%s", ts-299, stripeSignature("whsec_test", ts-299, payload)), true},
+		{"too old", fmt.Sprintf("t=%d,v1=%s", ts-301, stripeSignature("whsec_test", ts-301, payload)), false},
+		{"from the future", fmt.Sprintf("t=%d,v1=%s", ts+301, stripeSignature("whsec_test", ts+301, payload)), false},
+		{"several v1, none valid", fmt.Sprintf("t=%d,v1=deadbeef,v1=cafebabe", ts), false},
+		{"only v0", fmt.Sprintf("t=%d,v0=%s", ts, valid), false},
+		{"wrong secret", fmt.Sprintf("t=%d,v1=%s", ts, stripeSignature("whsec_other", ts, payload)), false},
+		{"timestamp not signed", fmt.Sprintf("t=%d,v1=%s", ts+1, valid), false},
+		{"bad timestamp", fmt.Sprintf("t=yesterday,v1=%s", valid), false},
+		{"missing timestamp", fmt.Sprintf("v1=%s", valid), false},
+		{"empty header", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.verifySignature(payload, tt.header, now)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidSignature))
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package scheduler
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job - фоновая задача. Ошибка только логируется, следующий запуск будет по расписанию
+type Job func(ctx context.Context) error
+
+type entry struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler запускает задачи с фиксированным интервалом, каждую в своей горутине.
+// Запуски одной задачи не пересекаются: следующий тик ждёт окончания предыдущего
+type Scheduler struct {
+	entries []entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every регистрирует задачу. Вызывать до Start
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.entries = append(s.entries, entry{name: name, interval: interval, job: job})
+}
+
+// Start сразу выполняет каждую задачу один раз, затем повторяет по интервалу до Stop
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go func(e entry) {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+}
+
+// Stop останавливает планировщик и ждёт завершения выполняющихся задач
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		run(ctx, e)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func run(ctx context.Context, e entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v", e.name, r)
+		}
+	}()
+
+	started := time.Now()
+	if err := e.job(ctx); err != nil {
+		log.Printf("Job %s failed: %s", e.name, err.Error())
+		return
+	}
+	log.Printf("Job %s finished in %s", e.name, time.Since(started).Round(time.Millisecond))
+}
@@ -0,0 +1,60 @@
+// Package storage
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotFound - объекта с таким ключом нет
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey - ключ пустой или выходит за пределы хранилища
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Object - открытый объект. Seek нужен для отдачи по частям (HTTP Range)
+type Object struct {
+	io.ReadSeekCloser
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage - хранилище файлов. Ключ - путь через "/", например "books/1/ab12cd.epub"
+type Storage interface {
+	// Put сохраняет объект целиком: читатели видят либо старое содержимое, либо новое
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (*Object, error)
+	// Delete не считает ошибкой отсутствие объекта
+	Delete(ctx context.Context, key string) error
+}
+
+// Signer подписывает короткоживущие ссылки: подпись - HMAC-SHA256 от ресурса и срока действия
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+func (s *Signer) Sign(resource string, expires time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(resource + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись и что срок expires (unix-время) ещё не прошёл к now
+func (s *Signer) Verify(resource string, expires int64, signature string, now time.Time) bool {
+	if now.Unix() > expires {
+		return false
+	}
+	expected := s.Sign(resource, time.Unix(expires, 0))
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
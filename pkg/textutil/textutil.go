@@ -0,0 +1,114 @@
+// Package textutil
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize приводит строку к нижнему регистру, убирает пунктуацию и лишние пробелы
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case r == '\'' || r == '’':
+			// "Ender's" и "Enders" должны совпадать
+		default:
+			if !space {
+				b.WriteRune(' ')
+				space = true
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Tokens возвращает нормализованные слова строки
+func Tokens(s string) []string {
+	return strings.Fields(Normalize(s))
+}
+
+// Similarity - похожесть строк от 0 до 1 на основе расстояния Левенштейна
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// NormalizeISBN убирает дефисы и мусор вроде ="..." из выгрузок Goodreads и приводит
+// корректный ISBN-10 к ISBN-13. Невалидное значение возвращается очищенным как есть
+func NormalizeISBN(raw string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(raw) {
+		if unicode.IsDigit(r) || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	isbn := b.String()
+
+	if len(isbn) == 10 && validISBN10(isbn) {
+		return isbn10To13(isbn)
+	}
+	return isbn
+}
+
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		var digit int
+		switch {
+		case r == 'X' && i == 9:
+			digit = 10
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+func isbn10To13(isbn string) string {
+	body := "978" + isbn[:9]
+	sum := 0
+	for i, r := range body {
+		digit := int(r - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	check := (10 - sum%10) % 10
+	return body + string(rune('0'+check))
+}
@@ -0,0 +1,151 @@
+// Package tfidf
+package tfidf
+
+import (
+	"bookshelf/pkg/textutil"
+	"math"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// Слова короче не несут смысла для сравнения текстов
+const minTokenLength = 3
+
+type Match struct {
+	ID    uint
+	Score float64
+}
+
+// Index - инвертированный индекс документов для поиска похожих по косинусу TF-IDF векторов.
+// Веса считаются при запросе, поэтому добавление и удаление документа не требуют пересчёта остальных
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[uint]map[string]int
+	postings map[string]map[uint]struct{}
+}
+
+func New() *Index {
+	return &Index{
+		docs:     make(map[uint]map[string]int),
+		postings: make(map[string]map[uint]struct{}),
+	}
+}
+
+func termFrequencies(text string) map[string]int {
+	tf := make(map[string]int)
+	for _, token := range textutil.Tokens(text) {
+		if utf8.RuneCountInString(token) >= minTokenLength {
+			tf[token]++
+		}
+	}
+	return tf
+}
+
+// Set добавляет документ или заменяет существующий
+func (idx *Index) Set(id uint, text string) {
+	tf := termFrequencies(text)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	if len(tf) == 0 {
+		return
+	}
+	idx.docs[id] = tf
+	for term := range tf {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uint]struct{})
+		}
+		idx.postings[term][id] = struct{}{}
+	}
+}
+
+func (idx *Index) Remove(id uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id uint) {
+	for term := range idx.docs[id] {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// Reset заменяет содержимое индекса целиком. Новый индекс строится без блокировки
+func (idx *Index) Reset(texts map[uint]string) {
+	fresh := New()
+	for id, text := range texts {
+		fresh.Set(id, text)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs, idx.postings = fresh.docs, fresh.postings
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+func (idx *Index) idf(term string) float64 {
+	return math.Log(1 + float64(len(idx.docs))/float64(len(idx.postings[term])))
+}
+
+func (idx *Index) weight(term string, count int) float64 {
+	return (1 + math.Log(float64(count))) * idx.idf(term)
+}
+
+func (idx *Index) norm(tf map[string]int) float64 {
+	var sum float64
+	for term, count := range tf {
+		w := idx.weight(term, count)
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// Similar возвращает до limit документов, ближайших к документу id, по убыванию похожести
+func (idx *Index) Similar(id uint, limit int) []Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	query, ok := idx.docs[id]
+	if !ok {
+		return nil
+	}
+
+	// Скалярные произведения копятся только для документов с общими словами
+	dots := make(map[uint]float64)
+	for term, count := range query {
+		qw := idx.weight(term, count)
+		for other := range idx.postings[term] {
+			if other != id {
+				dots[other] += qw * idx.weight(term, idx.docs[other][term])
+			}
+		}
+	}
+
+	queryNorm := idx.norm(query)
+	matches := make([]Match, 0, len(dots))
+	for other, dot := range dots {
+		if score := dot / (queryNorm * idx.norm(idx.docs[other])); score > 0 {
+			matches = append(matches, Match{ID: other, Score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches[:min(len(matches), limit)]
+}